@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/user"
+	"sort"
+	"time"
+
+	"github.com/scopweb/mcp-go-github/pkg/config"
+	"github.com/scopweb/mcp-go-github/pkg/safety"
+)
+
+// runApprove lists pending approval requests, or approves or denies one.
+// Exit codes: 0 done, 1 not decided (unknown, expired or already decided), 2 usage or config error.
+//
+//	github-mcp-server [--profile name] approve [--deny] [--by name] [<id>]
+func runApprove(args []string, safetyConfigPath string) int {
+	fs := flag.NewFlagSet("approve", flag.ContinueOnError)
+	deny := fs.Bool("deny", false, "Deny the request instead of approving it")
+	by := fs.String("by", "", "Approver recorded in the queue and audit log (default: current OS user)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	safetyConfig, err := config.LoadConfig(safetyConfigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if len(safetyConfig.ApprovalOperations) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: approval is not enabled in safety.json (set approval.enabled)")
+		return 2
+	}
+	queue := safety.NewApprovalQueue(safetyConfig.ApprovalQueuePath, safetyConfig.ApprovalOperations, safetyConfig.ApprovalExpiration)
+
+	if fs.NArg() == 0 {
+		pending, err := queue.List(true)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+		if len(pending) == 0 {
+			fmt.Println("No pending approval requests")
+			return 0
+		}
+		for _, req := range pending {
+			printApprovalRequest(req)
+		}
+		return 0
+	}
+
+	approver := *by
+	if approver == "" {
+		approver = currentUsername()
+	}
+
+	req, err := queue.Decide(fs.Arg(0), !*deny, approver)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	verb := "✅ Approved"
+	if *deny {
+		verb = "⛔ Denied"
+	}
+	fmt.Printf("%s %s (%s) as %s\n", verb, req.ID, req.Operation, approver)
+	if !*deny {
+		fmt.Printf("The agent can now retry with approval_id=%s before %s\n", req.ID, req.ExpiresAt.Format(time.RFC3339))
+	}
+	return 0
+}
+
+func printApprovalRequest(req *safety.ApprovalRequest) {
+	fmt.Printf("%s  %s  %s  expires %s\n", req.ID, req.RiskLevel, req.Operation, req.ExpiresAt.Format(time.RFC3339))
+	keys := make([]string, 0, len(req.Parameters))
+	for k := range req.Parameters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Printf("    %s: %v\n", k, req.Parameters[k])
+	}
+}
+
+func currentUsername() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "cli"
+}
+
+// startApprovalServer serves the approval endpoint on its loopback address
+// when one is configured. Without MCP_APPROVAL_TOKEN the endpoint is not
+// started and approvals go through the CLI only.
+func startApprovalServer(engine *safety.Engine) {
+	queue := engine.GetApprovals()
+	addr := engine.GetConfig().ApprovalHTTPAddr
+	if queue == nil || addr == "" {
+		return
+	}
+
+	handler, err := safety.NewApprovalHandler(queue, os.Getenv(safety.ApprovalTokenEnv))
+	if err != nil {
+		log.Printf("Warning: approval endpoint not started: %v", err)
+		// Approval messages must not point at an endpoint that is not there
+		engine.GetConfig().ApprovalHTTPAddr = ""
+		return
+	}
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		log.Printf("Approval endpoint listening on http://%s/approvals", addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("Warning: approval endpoint stopped: %v", err)
+		}
+	}()
+}
@@ -14,6 +14,7 @@ import (
 	ghclient "github.com/google/go-github/v81/github"
 	"golang.org/x/oauth2"
 
+	"github.com/scopweb/mcp-go-github/internal/hybrid"
 	"github.com/scopweb/mcp-go-github/internal/server"
 	"github.com/scopweb/mcp-go-github/pkg/admin"
 	"github.com/scopweb/mcp-go-github/pkg/git"
@@ -25,8 +26,20 @@ func main() {
 	// Procesar arguments de línea de commands
 	profile := flag.String("profile", "", "Profile name (optional)")
 	toolsetsFlag := flag.String("toolsets", "all", "Comma-separated toolsets to enable: git,github,admin,files (default: all)")
+	workspacesFile := flag.String("workspaces", git.DefaultWorkspaceStatePath, "State file for the named workspace registry (empty = in memory)")
 	flag.Parse()
 
+	// If --profile=foo is passed, prefer ./safety.foo.json, falling back to ./safety.json
+	safetyConfigPath := resolveSafetyConfigPath(*profile)
+
+	// Subcommands run and exit instead of serving
+	switch flag.Arg(0) {
+	case "verify":
+		os.Exit(runVerify(flag.Args()[1:], safetyConfigPath))
+	case "approve":
+		os.Exit(runApprove(flag.Args()[1:], safetyConfigPath))
+	}
+
 	if *profile != "" {
 		log.Printf("Starting MCP server with profile: %s", *profile)
 	}
@@ -73,18 +86,6 @@ func main() {
 	adminClient := admin.NewClient(&githubClient)
 
 	// Inicializar safety middleware (v3.0)
-	// If --profile=foo is passed, prefer ./safety.foo.json, falling back to ./safety.json
-	safetyConfigPath := "./safety.json"
-	if *profile != "" {
-		profilePath := fmt.Sprintf("./safety.%s.json", *profile)
-		if _, statErr := os.Stat(profilePath); statErr == nil {
-			safetyConfigPath = profilePath
-			log.Printf("Using profile-specific safety config: %s", profilePath)
-		} else {
-			log.Printf("Profile config %s not found, falling back to %s", profilePath, safetyConfigPath)
-		}
-	}
-
 	var safetyMiddleware *server.SafetyMiddleware
 	safetyMiddleware, err = server.NewSafetyMiddleware(safetyConfigPath)
 	if err != nil {
@@ -95,6 +96,24 @@ func main() {
 			log.Fatalf("Fatal: Cannot initialize safety middleware even with defaults: %v", err)
 		}
 	}
+	safetyMiddleware.SetAdminClient(adminClient)
+	startApprovalServer(safetyMiddleware.GetEngine())
+	hybrid.SetSecretScanner(safetyMiddleware.GetEngine().GetSecretScanner())
+	git.SetSecretScanner(safetyMiddleware.GetEngine().GetSecretScanner())
+	hybrid.SetSandbox(safetyMiddleware.GetEngine().GetSandbox())
+	git.SetSandbox(safetyMiddleware.GetEngine().GetSandbox())
+
+	// Registro de workspaces con nombre; el predeterminado pasa a ser el actual
+	workspaces, err := git.NewWorkspaceRegistry(*workspacesFile)
+	if err != nil {
+		log.Printf("Warning: Failed to load workspace registry (starting empty): %v", err)
+		workspaces, _ = git.NewWorkspaceRegistry("")
+	}
+	if def, ok := workspaces.Default(); ok && gitAvailable {
+		if _, err := gitClient.SetWorkspace(def.Path); err != nil {
+			log.Printf("Warning: Default workspace '%s' unavailable: %v", def.Name, err)
+		}
+	}
 
 	// Crear servidor MCP
 	mcpServer := &server.MCPServer{
@@ -105,15 +124,32 @@ func main() {
 		GitAvailable:    gitAvailable,
 		RawGitHubClient: &githubClient,
 		Toolsets:        toolsets,
+		Workspaces:      workspaces,
 	}
 
 	// Leer solicitudes JSON-RPC del stdin
 	scanner := bufio.NewScanner(os.Stdin)
 	buf := make([]byte, 0, 64*1024)
 	scanner.Buffer(buf, 10*1024*1024) // 10MB max to handle large file payloads
-	for scanner.Scan() {
-		line := scanner.Bytes()
 
+	// Las solicitudes se procesan de una en una; la lectura sigue en paralelo
+	// para aplicar notifications/cancelled a la que está en curso
+	inFlight := server.NewInFlight()
+	lines := make(chan []byte)
+	go func() {
+		defer close(lines)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			var note types.JSONRPCRequest
+			if json.Unmarshal(line, &note) == nil && note.Method == "notifications/cancelled" {
+				inFlight.Cancel(note.Params)
+				continue
+			}
+			lines <- line
+		}
+	}()
+
+	for line := range lines {
 		// Parsear solicitud JSON-RPC
 		var req types.JSONRPCRequest
 		if err := json.Unmarshal(line, &req); err != nil {
@@ -132,13 +168,16 @@ func main() {
 
 		// Notifications (no id field) must not receive a response per MCP spec
 		if req.ID == nil && strings.HasPrefix(req.Method, "notifications/") {
-			server.HandleRequest(mcpServer, req) // process for side effects
+			server.HandleRequest(context.Background(), mcpServer, req) // process for side effects
 			continue
 		}
 
 		// Procesar solicitud con recovery para evitar crash por panics
 		var response types.JSONRPCResponse
+		ctx, done := inFlight.Start(req.ID)
+		cancelled := false
 		func() {
+			defer done()
 			defer func() {
 				if r := recover(); r != nil {
 					log.Printf("Panic recovered processing request: %v", r)
@@ -156,9 +195,15 @@ func main() {
 					}
 				}
 			}()
-			response = server.HandleRequest(mcpServer, req)
+			response = server.HandleRequest(ctx, mcpServer, req)
+			cancelled = ctx.Err() != nil
 		}()
 
+		// A cancelled request gets no response, per MCP spec
+		if cancelled {
+			continue
+		}
+
 		// Enviar respuesta JSON-RPC
 		respBytes, err := json.Marshal(response)
 		if err != nil {
@@ -169,7 +214,27 @@ func main() {
 		fmt.Println(string(respBytes))
 	}
 
+	// Flush audit entries still queued for external sinks
+	if err := safetyMiddleware.GetEngine().GetLogger().Close(); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
 	if err := scanner.Err(); err != nil {
 		log.Fatalf("Scanner error: %v", err)
 	}
 }
+
+// resolveSafetyConfigPath returns ./safety.<profile>.json when it exists, else ./safety.json
+func resolveSafetyConfigPath(profile string) string {
+	safetyConfigPath := "./safety.json"
+	if profile != "" {
+		profilePath := fmt.Sprintf("./safety.%s.json", profile)
+		if _, statErr := os.Stat(profilePath); statErr == nil {
+			safetyConfigPath = profilePath
+			log.Printf("Using profile-specific safety config: %s", profilePath)
+		} else {
+			log.Printf("Profile config %s not found, falling back to %s", profilePath, safetyConfigPath)
+		}
+	}
+	return safetyConfigPath
+}
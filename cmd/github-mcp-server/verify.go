@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/scopweb/mcp-go-github/pkg/config"
+	"github.com/scopweb/mcp-go-github/pkg/safety"
+)
+
+// runVerify checks the audit log hash chain. Exit codes: 0 intact, 1 broken, 2 error.
+//
+//	github-mcp-server [--profile name] verify [--log path] [--key-file path]
+func runVerify(args []string, safetyConfigPath string) int {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	logPath := fs.String("log", "", "Audit log to verify (default: auditLogPath from safety.json)")
+	keyFile := fs.String("key-file", "", "Audit signing key file (default: auditSigningKeyFile from safety.json; MCP_AUDIT_KEY overrides)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	safetyConfig, err := config.LoadConfig(safetyConfigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if *logPath == "" {
+		*logPath = safetyConfig.AuditLogPath
+	}
+	if *keyFile == "" {
+		*keyFile = safetyConfig.AuditKeyPath
+	}
+
+	// Never create a key here: a missing key only means signatures go unchecked
+	key, err := safety.LoadAuditKey(*keyFile, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	report, err := safety.VerifyAuditChain(*logPath, key)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if len(report.Files) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no audit log found at %s\n", *logPath)
+		return 2
+	}
+
+	fmt.Printf("Files checked: %d (oldest first)\n", len(report.Files))
+	for _, f := range report.Files {
+		fmt.Printf("  %s\n", f)
+	}
+
+	if b := report.Broken; b != nil {
+		fmt.Printf("❌ Chain broken at %s:%d: %s\n", b.File, b.Line, b.Reason)
+		fmt.Printf("   %d entries verified before the break\n", report.Entries)
+		return 1
+	}
+
+	fmt.Printf("✅ Chain intact: %d entries verified", report.Entries)
+	if report.Signed > 0 {
+		fmt.Printf(", %d signatures valid", report.Signed)
+	}
+	fmt.Println()
+	if report.Legacy > 0 {
+		fmt.Printf("ℹ️  %d older entries predate the hash chain and were not verified\n", report.Legacy)
+	}
+	if report.UncheckedSigs > 0 {
+		fmt.Printf("⚠️  %d signatures not checked: no audit signing key available\n", report.UncheckedSigs)
+	}
+	if report.AnchoredAtHead {
+		fmt.Println("ℹ️  The oldest entry links to a rotated file that no longer exists; verification starts there")
+	}
+	if report.LastHash != "" {
+		fmt.Printf("Last hash: %s\n", report.LastHash)
+	}
+	return 0
+}
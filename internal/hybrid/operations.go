@@ -5,10 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/scopweb/mcp-go-github/pkg/interfaces"
+	"github.com/scopweb/mcp-go-github/pkg/safety"
+	"github.com/scopweb/mcp-go-github/pkg/types"
 )
 
 // stat is a variable that can be replaced in tests for mocking file existence
@@ -17,6 +18,51 @@ var stat = os.Stat
 // readFile is a variable that can be replaced in tests for mocking file reading
 var readFile = os.ReadFile
 
+// secretScanner checks content before it is written for a commit or uploaded
+// through the API; the server replaces it with the one configured in safety.json
+var secretScanner = safety.NewSecretScanner(safety.SecretScanConfig{})
+
+// SetSecretScanner installs the scanner used by the file operations
+func SetSecretScanner(scanner *safety.SecretScanner) {
+	if scanner != nil {
+		secretScanner = scanner
+	}
+}
+
+// sandbox confines the local paths read and written by the file operations;
+// the server replaces it with the one configured in safety.json
+var sandbox = safety.NewSandbox(nil)
+
+// SetSandbox installs the sandbox used by the file operations
+func SetSandbox(s *safety.Sandbox) {
+	if s != nil {
+		sandbox = s
+	}
+}
+
+// pendingFile is a file about to be written
+type pendingFile struct {
+	path    string
+	content string
+}
+
+// scanSecrets scans all files before any of them is written. In block mode
+// findings return a *safety.SecretsFoundError; in warn mode they come back as
+// a report to append to the result.
+func scanSecrets(files []pendingFile) (string, error) {
+	var findings []safety.SecretFinding
+	for _, f := range files {
+		findings = append(findings, secretScanner.Scan(f.path, []byte(f.content))...)
+	}
+	if len(findings) == 0 {
+		return "", nil
+	}
+	if secretScanner.Action() == safety.SecretScanBlock {
+		return "", &safety.SecretsFoundError{Findings: findings}
+	}
+	return "\n\n" + safety.FormatSecretReport(findings, false), nil
+}
+
 // SmartCreateFile: PRIORIZA Git local, fallback a GitHub API solo si es necesario
 func SmartCreateFile(gitOps interfaces.GitOperations, githubOps interfaces.GitHubOperations, args map[string]interface{}) (string, error) {
 	path, ok := args["path"].(string)
@@ -29,6 +75,11 @@ func SmartCreateFile(gitOps interfaces.GitOperations, githubOps interfaces.GitHu
 		return "", fmt.Errorf("parámetro 'content' requerido")
 	}
 
+	warning, err := scanSecrets([]pendingFile{{path, content}})
+	if err != nil {
+		return "", err
+	}
+
 	// 1. SIEMPRE intentar Git local primero (OPTIMIZACIÓN DE TOKENS)
 	if gitOps.HasGit() && gitOps.IsGitRepo() {
 		result, err := gitOps.CreateFile(path, content)
@@ -38,15 +89,19 @@ func SmartCreateFile(gitOps interfaces.GitOperations, githubOps interfaces.GitHu
 				message = m
 			}
 
-			return fmt.Sprintf("✅ ARCHIVO CREADO CON GIT LOCAL (0 tokens API)\n%s\n\n🔧 Siguiente paso: git_add('%s') -> git_commit('%s')",
-				result, path, message), nil
+			return fmt.Sprintf("✅ ARCHIVO CREADO CON GIT LOCAL (0 tokens API)\n%s\n\n🔧 Siguiente paso: git_add('%s') -> git_commit('%s')%s",
+				result, path, message, warning), nil
 		}
 		// Si falla Git local, continuar con API
 		return fmt.Sprintf("⚠️ Git local falló: %v\n⤵️ Intentando GitHub API...", err), fmt.Errorf("git_local_failed")
 	}
 
 	// 2. Solo si NO hay Git local, usar GitHub API
-	return createFileWithAPI(githubOps, args)
+	result, err := createFileWithAPI(githubOps, args)
+	if err != nil {
+		return "", err
+	}
+	return result + warning, nil
 }
 
 // SmartUpdateFile: PRIORIZA Git local, fallback a GitHub API solo si es necesario
@@ -61,10 +116,18 @@ func SmartUpdateFile(gitOps interfaces.GitOperations, githubOps interfaces.GitHu
 		return "", fmt.Errorf("parámetro 'content' requerido")
 	}
 
+	warning, err := scanSecrets([]pendingFile{{path, content}})
+	if err != nil {
+		return "", err
+	}
+
 	// 1. SIEMPRE intentar Git local primero (OPTIMIZACIÓN DE TOKENS)
 	if gitOps.HasGit() && gitOps.IsGitRepo() {
 		// Verificar si el archivo existe localmente
-		fullPath := filepath.Join(gitOps.GetRepoPath(), path)
+		fullPath, err := sandbox.ResolveIn(gitOps.GetRepoPath(), path)
+		if err != nil {
+			return "", fmt.Errorf("ruta no permitida: %w", err)
+		}
 		if _, err := stat(fullPath); err == nil {
 			// El SHA no es necesario para la operación local, pero lo mantenemos en la firma por consistencia
 			result, err := gitOps.UpdateFile(path, content, "")
@@ -74,8 +137,8 @@ func SmartUpdateFile(gitOps interfaces.GitOperations, githubOps interfaces.GitHu
 					message = m
 				}
 
-				return fmt.Sprintf("✅ ARCHIVO ACTUALIZADO CON GIT LOCAL (0 tokens API)\n%s\n\n🔧 Siguiente paso: git_add('%s') -> git_commit('%s')",
-					result, path, message), nil
+				return fmt.Sprintf("✅ ARCHIVO ACTUALIZADO CON GIT LOCAL (0 tokens API)\n%s\n\n🔧 Siguiente paso: git_add('%s') -> git_commit('%s')%s",
+					result, path, message, warning), nil
 			}
 			// Si UpdateFile falla, también consideramos que es un fallo local
 		}
@@ -84,24 +147,33 @@ func SmartUpdateFile(gitOps interfaces.GitOperations, githubOps interfaces.GitHu
 	}
 
 	// 2. Solo si NO hay Git local, usar GitHub API
-	return updateFileWithAPI(githubOps, args)
+	result, err := updateFileWithAPI(githubOps, args)
+	if err != nil {
+		return "", err
+	}
+	return result + warning, nil
 }
 
 // AutoDetectContext: Detecta automáticamente si usar Git local o GitHub API
 func AutoDetectContext(gitOps interfaces.GitOperations) string {
 	if gitOps.HasGit() && gitOps.IsGitRepo() {
+		// Un solo git status para la rama y los cambios; nil si falla
+		status, err := gitOps.StatusInfo()
+		if err != nil {
+			status = nil
+		}
 		return fmt.Sprintf(`🔧 MODO GIT LOCAL DETECTADO (OPTIMIZACIÓN DE TOKENS)
 📁 Repo: %s
 🌿 Rama: %s
 🔗 Remote: %s
-
+%s
 ✅ RECOMENDACIÓN: Usar commands git_* para operaciones sin costo de tokens
 - create_file/update_file: 0 tokens (Git local)
 - git_add + git_commit: 0 tokens
 - git_push: Solo si necesario sincronizar
 
 ❌ EVITAR: github_* APIs a menos que sea estrictamente necesario`,
-			gitOps.GetRepoPath(), gitOps.GetCurrentBranch(), gitOps.GetRemoteURL())
+			gitOps.GetRepoPath(), describeBranch(status, gitOps.GetCurrentBranch()), gitOps.GetRemoteURL(), describeChanges(status))
 	}
 
 	return `⚠️ MODO GITHUB API (COSTO TOKENS)
@@ -111,6 +183,34 @@ func AutoDetectContext(gitOps interfaces.GitOperations) string {
 💡 OPTIMIZACIÓN: Clona el repo localmente para reducir costos`
 }
 
+// describeBranch muestra la rama con su upstream y commits por delante/detrás;
+// sin estado, solo la rama actual
+func describeBranch(status *types.RepoStatus, currentBranch string) string {
+	if status == nil {
+		return currentBranch
+	}
+	branch := status.Branch
+	if status.Detached {
+		branch = "(HEAD separado)"
+	}
+	if status.Upstream == "" {
+		return branch + " (sin upstream)"
+	}
+	return fmt.Sprintf("%s → %s (↑%d ↓%d)", branch, status.Upstream, status.Ahead, status.Behind)
+}
+
+// describeChanges resume los cambios pendientes del árbol de trabajo
+func describeChanges(status *types.RepoStatus) string {
+	if status == nil {
+		return ""
+	}
+	if status.Clean {
+		return "📝 Cambios: ninguno (árbol limpio)\n"
+	}
+	return fmt.Sprintf("📝 Cambios: %d staged, %d sin preparar, %d sin seguimiento, %d en conflicto\n",
+		len(status.Staged), len(status.Unstaged), len(status.Untracked), len(status.Conflicted))
+}
+
 // createFileWithAPI: Función auxiliar para GitHub API
 func createFileWithAPI(githubOps interfaces.GitHubOperations, args map[string]interface{}) (string, error) {
 	owner, ok := args["owner"].(string)
@@ -192,6 +292,10 @@ func CreateFile(gitOps interfaces.GitOperations, githubOps interfaces.GitHubOper
 		return "", fmt.Errorf("parámetro 'content' requerido")
 	}
 
+	if _, err := scanSecrets([]pendingFile{{path, content}}); err != nil {
+		return "", err
+	}
+
 	// Si tenemos Git local, usar workflow Git
 	if gitOps.HasGit() && gitOps.IsGitRepo() {
 		result, err := gitOps.CreateFile(path, content)
@@ -247,6 +351,10 @@ func UpdateFile(gitOps interfaces.GitOperations, githubOps interfaces.GitHubOper
 		return "", fmt.Errorf("parámetro 'content' requerido")
 	}
 
+	if _, err := scanSecrets([]pendingFile{{path, content}}); err != nil {
+		return "", err
+	}
+
 	// Si tenemos Git local, usar workflow Git
 	if gitOps.HasGit() && gitOps.IsGitRepo() {
 		// El SHA no es necesario para la operación local
@@ -296,6 +404,19 @@ func UpdateFile(gitOps interfaces.GitOperations, githubOps interfaces.GitHubOper
 	return "File updated via GitHub API", nil
 }
 
+// PushBranch devuelve la rama a la que PushFiles hará push: el argumento 'branch',
+// o la rama actual, o "main" como último recurso.
+func PushBranch(gitOps interfaces.GitOperations, args map[string]interface{}) string {
+	branch := strings.TrimSpace(gitOps.GetCurrentBranch())
+	if b, ok := args["branch"].(string); ok && strings.TrimSpace(b) != "" {
+		branch = strings.TrimSpace(b)
+	}
+	if branch == "" {
+		branch = "main"
+	}
+	return branch
+}
+
 // PushFiles escribe múltiples archivos y realiza git add/commit/push en una sola llamada.
 // Soporta 3 modos:
 //   - files con content: contenido inline (modo original)
@@ -312,13 +433,7 @@ func PushFiles(gitOps interfaces.GitOperations, args map[string]interface{}) (st
 	}
 	commitMessage = strings.TrimSpace(commitMessage)
 
-	branch := strings.TrimSpace(gitOps.GetCurrentBranch())
-	if b, ok := args["branch"].(string); ok && strings.TrimSpace(b) != "" {
-		branch = strings.TrimSpace(b)
-	}
-	if branch == "" {
-		branch = "main"
-	}
+	branch := PushBranch(gitOps, args)
 
 	repoPath := gitOps.GetRepoPath()
 	if repoPath == "" {
@@ -337,7 +452,10 @@ func PushFiles(gitOps interfaces.GitOperations, args map[string]interface{}) (st
 	var staged []string
 	totalProcessed := 0
 
-	// Process files array (content or source_path mode)
+	// Resolve every file's content first, so the secret scan sees the whole
+	// change before anything is written
+	var pending []pendingFile
+	var targets []string
 	for idx, file := range rawFiles {
 		fileMap, ok := file.(map[string]interface{})
 		if !ok {
@@ -348,14 +466,22 @@ func PushFiles(gitOps interfaces.GitOperations, args map[string]interface{}) (st
 		if !ok || strings.TrimSpace(path) == "" {
 			return "", fmt.Errorf("files[%d].path requerido", idx)
 		}
+		target, err := sandbox.ResolveIn(repoPath, path)
+		if err != nil {
+			return "", fmt.Errorf("files[%d].path: ruta no permitida: %w", idx, err)
+		}
 
 		// Determine content: from inline content or source_path
 		var content string
 		if c, ok := fileMap["content"].(string); ok {
 			content = c
 		} else if srcPath, ok := fileMap["source_path"].(string); ok && strings.TrimSpace(srcPath) != "" {
-			// Read content from source_path on disk
-			data, err := readFile(srcPath)
+			// Read content from source_path on disk, inside the sandbox
+			resolved, err := sandbox.Resolve(srcPath)
+			if err != nil {
+				return "", fmt.Errorf("files[%d]: source_path no permitido: %w", idx, err)
+			}
+			data, err := readFile(resolved)
 			if err != nil {
 				return "", fmt.Errorf("files[%d]: error leyendo source_path '%s': %w", idx, srcPath, err)
 			}
@@ -363,37 +489,55 @@ func PushFiles(gitOps interfaces.GitOperations, args map[string]interface{}) (st
 		} else {
 			return "", fmt.Errorf("files[%d] requiere 'content' o 'source_path'", idx)
 		}
-
-		fullPath := filepath.Join(repoPath, path)
-		if _, err := stat(fullPath); err == nil {
-			if _, err := gitOps.UpdateFile(path, content, ""); err != nil {
-				return "", fmt.Errorf("error actualizando %s: %w", path, err)
-			}
-			updated = append(updated, path)
-		} else if errors.Is(err, os.ErrNotExist) {
-			if _, err := gitOps.CreateFile(path, content); err != nil {
-				return "", fmt.Errorf("error creando %s: %w", path, err)
-			}
-			created = append(created, path)
-		} else {
-			return "", fmt.Errorf("error accediendo a %s: %w", path, err)
-		}
-		totalProcessed++
+		pending = append(pending, pendingFile{path, content})
+		targets = append(targets, target)
 	}
 
-	// Process paths array (files already in workspace, just stage them)
+	// Validate paths array (files already in workspace, just stage them)
+	scanned := append([]pendingFile{}, pending...)
 	for idx, p := range rawPaths {
 		path, ok := p.(string)
 		if !ok || strings.TrimSpace(path) == "" {
 			return "", fmt.Errorf("paths[%d] debe ser un string no vacío", idx)
 		}
-		fullPath := filepath.Join(repoPath, path)
+		fullPath, err := sandbox.ResolveIn(repoPath, path)
+		if err != nil {
+			return "", fmt.Errorf("paths[%d]: ruta no permitida: %w", idx, err)
+		}
 		if _, err := stat(fullPath); err != nil {
 			return "", fmt.Errorf("paths[%d]: archivo '%s' no existe en el workspace", idx, path)
 		}
+		data, err := readFile(fullPath)
+		if err != nil {
+			return "", fmt.Errorf("paths[%d]: error leyendo '%s': %w", idx, path, err)
+		}
+		scanned = append(scanned, pendingFile{path, string(data)})
 		staged = append(staged, path)
+	}
+
+	warning, err := scanSecrets(scanned)
+	if err != nil {
+		return "", err
+	}
+
+	// Write files (content or source_path mode)
+	for i, f := range pending {
+		if _, err := stat(targets[i]); err == nil {
+			if _, err := gitOps.UpdateFile(f.path, f.content, ""); err != nil {
+				return "", fmt.Errorf("error actualizando %s: %w", f.path, err)
+			}
+			updated = append(updated, f.path)
+		} else if errors.Is(err, os.ErrNotExist) {
+			if _, err := gitOps.CreateFile(f.path, f.content); err != nil {
+				return "", fmt.Errorf("error creando %s: %w", f.path, err)
+			}
+			created = append(created, f.path)
+		} else {
+			return "", fmt.Errorf("error accediendo a %s: %w", f.path, err)
+		}
 		totalProcessed++
 	}
+	totalProcessed += len(staged)
 
 	// Git add: stage only the specific files processed, never use -A to avoid
 	// accidentally staging sensitive untracked files (e.g. .env, .mcp.json)
@@ -402,9 +546,7 @@ func PushFiles(gitOps interfaces.GitOperations, args map[string]interface{}) (st
 	toStage = append(toStage, updated...)
 	toStage = append(toStage, staged...)
 
-	var addResult string
-	var err error
-	addResult, err = gitOps.Add(strings.Join(toStage, " "))
+	addResult, err := gitOps.Add(strings.Join(toStage, " "))
 	if err != nil {
 		return "", fmt.Errorf("git add falló: %w", err)
 	}
@@ -437,5 +579,5 @@ func PushFiles(gitOps interfaces.GitOperations, args map[string]interface{}) (st
 		fmt.Sprintf("📌 git push (%s): %s", branch, pushResult),
 	)
 
-	return strings.Join(summary, "\n"), nil
+	return strings.Join(summary, "\n") + warning, nil
 }
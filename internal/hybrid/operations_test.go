@@ -9,6 +9,8 @@ import (
 	"testing"
 
 	"github.com/google/go-github/v81/github"
+	"github.com/scopweb/mcp-go-github/pkg/interfaces"
+	"github.com/scopweb/mcp-go-github/pkg/safety"
 	"github.com/scopweb/mcp-go-github/pkg/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -32,6 +34,8 @@ type mockGitOperations struct {
 	getFileSHAFunc      func(path string) (string, error)
 	GetChangedFilesFunc func(staged bool) (string, error)
 	initFunc            func(path string, branch string) (string, error)
+	statusInfo          *types.RepoStatus
+	statusCalls         int
 }
 
 func (m *mockGitOperations) HasGit() bool             { return m.hasGit }
@@ -40,6 +44,13 @@ func (m *mockGitOperations) GetRepoPath() string      { return m.repoPath }
 func (m *mockGitOperations) GetCurrentBranch() string { return m.currentBranch }
 func (m *mockGitOperations) GetRemoteURL() string     { return m.remoteURL }
 func (m *mockGitOperations) Status() (string, error)  { return "mock status", nil }
+func (m *mockGitOperations) StatusInfo() (*types.RepoStatus, error) {
+	m.statusCalls++
+	if m.statusInfo == nil {
+		return nil, errors.New("status not mocked")
+	}
+	return m.statusInfo, nil
+}
 func (m *mockGitOperations) Add(path string) (string, error) {
 	if m.addFunc != nil {
 		return m.addFunc(path)
@@ -62,9 +73,13 @@ func (m *mockGitOperations) Pull(_ string) (string, error) { return "mock pull",
 func (m *mockGitOperations) Checkout(_ string, _ bool) (string, error) {
 	return "mock checkout", nil
 }
-func (m *mockGitOperations) LogAnalysis(_ string) (string, error) { return "mock log", nil }
-func (m *mockGitOperations) DiffFiles(_ bool) (string, error)     { return "mock diff", nil }
-func (m *mockGitOperations) Stash(_, _ string) (string, error)    { return "mock stash", nil }
+func (m *mockGitOperations) LogAnalysis(_ string) (string, error)          { return "mock log", nil }
+func (m *mockGitOperations) DiffFiles(_ bool) (string, error)              { return "mock diff", nil }
+func (m *mockGitOperations) DiffPatch(_ types.DiffOptions) (string, error) { return "mock patch", nil }
+func (m *mockGitOperations) LogQuery(_ types.LogOptions) (string, error)   { return "mock log", nil }
+func (m *mockGitOperations) Blame(_ types.BlameOptions) (string, error)    { return "mock blame", nil }
+func (m *mockGitOperations) Show(_ types.ShowOptions) (string, error)      { return "mock show", nil }
+func (m *mockGitOperations) Stash(_, _ string) (string, error)             { return "mock stash", nil }
 func (m *mockGitOperations) Remote(_, _, _ string) (string, error) {
 	return "mock remote", nil
 }
@@ -105,6 +120,9 @@ func (m *mockGitOperations) SetWorkspace(path string) (string, error) {
 	}
 	return "mock set workspace", nil
 }
+func (m *mockGitOperations) WithContext(ctx context.Context) interfaces.GitOperations {
+	return m
+}
 func (m *mockGitOperations) GetFileSHA(path string) (string, error) {
 	if m.getFileSHAFunc != nil {
 		return m.getFileSHAFunc(path)
@@ -136,6 +154,24 @@ func (m *mockGitOperations) Merge(_ string, _ string) (string, error) {
 func (m *mockGitOperations) Rebase(_ string) (string, error) {
 	return "mock rebase", nil
 }
+func (m *mockGitOperations) RebaseInteractive(_ string, _ []types.RebaseStep) (string, error) {
+	return "mock rebase", nil
+}
+func (m *mockGitOperations) RebaseControl(_ string) (string, error) {
+	return "mock rebase", nil
+}
+func (m *mockGitOperations) CherryPick(_ types.CherryPickOptions) (string, error) {
+	return "mock cherry-pick", nil
+}
+func (m *mockGitOperations) CherryPickControl(_ string) (string, error) {
+	return "mock cherry-pick", nil
+}
+func (m *mockGitOperations) Revert(_ types.RevertOptions) (string, error) {
+	return "mock revert", nil
+}
+func (m *mockGitOperations) RevertControl(_ string) (string, error) {
+	return "mock revert", nil
+}
 
 // Enhanced pull/push operations
 func (m *mockGitOperations) PullWithStrategy(_ string, _ string) (string, error) {
@@ -171,6 +207,12 @@ func (m *mockGitOperations) ValidateCleanState() (bool, error) {
 func (m *mockGitOperations) DetectPotentialConflicts(_ string, _ string) (string, error) {
 	return "mock detect conflicts", nil
 }
+func (m *mockGitOperations) PreviewMerge(_ string, _ string) (string, error) {
+	return "mock preview merge", nil
+}
+func (m *mockGitOperations) PreviewRebase(_ string) (string, error) {
+	return "mock preview rebase", nil
+}
 func (m *mockGitOperations) CreateBackup(_ string) (string, error) {
 	return "mock create backup", nil
 }
@@ -223,9 +265,15 @@ func (m *mockGitHubOperations) UpdateFile(ctx context.Context, owner, repo, path
 func (m *mockGitHubOperations) CreateIssueComment(_ context.Context, _ string, _ string, _ int, _ string) (*github.IssueComment, error) {
 	return nil, nil
 }
+func (m *mockGitHubOperations) GetIssue(_ context.Context, _ string, _ string, _ int) (*github.Issue, error) {
+	return nil, nil
+}
 func (m *mockGitHubOperations) CloseIssue(_ context.Context, _ string, _ string, _ int, _ string) (*github.Issue, error) {
 	return nil, nil
 }
+func (m *mockGitHubOperations) GetPullRequest(_ context.Context, _ string, _ string, _ int) (*github.PullRequest, error) {
+	return nil, nil
+}
 func (m *mockGitHubOperations) CreatePRComment(_ context.Context, _ string, _ string, _ int, _ string) (*github.IssueComment, error) {
 	return nil, nil
 }
@@ -235,12 +283,24 @@ func (m *mockGitHubOperations) CreatePRReview(_ context.Context, _ string, _ str
 func (m *mockGitHubOperations) MergePullRequest(_ context.Context, _ string, _ string, _ int, _ string, _ string) (*github.PullRequestMergeResult, error) {
 	return nil, nil
 }
+func (m *mockGitHubOperations) GetWorkflowRun(_ context.Context, _ string, _ string, _ int64) (*github.WorkflowRun, error) {
+	return nil, nil
+}
 func (m *mockGitHubOperations) RerunWorkflow(_ context.Context, _ string, _ string, _ int64) error {
 	return nil
 }
 func (m *mockGitHubOperations) RerunFailedJobs(_ context.Context, _ string, _ string, _ int64) error {
 	return nil
 }
+func (m *mockGitHubOperations) GetDependabotAlert(_ context.Context, _ string, _ string, _ int) (*github.DependabotAlert, error) {
+	return nil, nil
+}
+func (m *mockGitHubOperations) GetCodeScanningAlert(_ context.Context, _ string, _ string, _ int64) (*github.Alert, error) {
+	return nil, nil
+}
+func (m *mockGitHubOperations) GetSecretScanningAlert(_ context.Context, _ string, _ string, _ int64) (*github.SecretScanningAlert, error) {
+	return nil, nil
+}
 func (m *mockGitHubOperations) DismissDependabotAlert(_ context.Context, _ string, _ string, _ int, _ string, _ string) (*github.DependabotAlert, error) {
 	return nil, nil
 }
@@ -278,6 +338,32 @@ func TestAutoDetectContext(t *testing.T) {
 		assert.Equal(t, expected, result)
 	})
 
+	t.Run("Git local with structured status", func(t *testing.T) {
+		mockGit := &mockGitOperations{
+			hasGit:    true,
+			isGitRepo: true,
+			repoPath:  "/path/to/repo",
+			statusInfo: &types.RepoStatus{
+				Branch:    "feature",
+				Upstream:  "origin/feature",
+				Ahead:     2,
+				Behind:    1,
+				Staged:    []types.StatusEntry{{Path: "a.go", Index: "M", WorkTree: "."}},
+				Untracked: []string{"new.txt"},
+			},
+		}
+
+		result := AutoDetectContext(mockGit)
+		assert.Contains(t, result, "🌿 Rama: feature → origin/feature (↑2 ↓1)")
+		assert.Contains(t, result, "📝 Cambios: 1 staged, 0 sin preparar, 1 sin seguimiento, 0 en conflicto")
+		assert.Equal(t, 1, mockGit.statusCalls, "status should be read once")
+
+		mockGit.statusInfo = &types.RepoStatus{Detached: true, Clean: true}
+		result = AutoDetectContext(mockGit)
+		assert.Contains(t, result, "🌿 Rama: (HEAD separado) (sin upstream)")
+		assert.Contains(t, result, "árbol limpio")
+	})
+
 	t.Run("No Git local detected", func(t *testing.T) {
 		mockGit := &mockGitOperations{
 			hasGit:    false,
@@ -473,7 +559,7 @@ func TestPushFiles(t *testing.T) {
 				return "updated", nil
 			},
 			addFunc: func(path string) (string, error) {
-				assert.Equal(t, "-A", path)
+				assert.Equal(t, "new/new.txt existing.txt", path)
 				return "added", nil
 			},
 			commitFunc: func(message string) (string, error) {
@@ -592,6 +678,38 @@ func TestPushFiles(t *testing.T) {
 		assert.Contains(t, err.Error(), "source_path")
 	})
 
+	t.Run("sandbox rejects source_path outside the roots and escaping paths", func(t *testing.T) {
+		root := t.TempDir()
+		outside := filepath.Join(t.TempDir(), "id_rsa")
+		assert.NoError(t, os.WriteFile(outside, []byte("key"), 0600))
+		SetSandbox(safety.NewSandbox([]string{root}))
+		defer SetSandbox(safety.NewSandbox(nil))
+
+		mockGit := &mockGitOperations{
+			hasGit:        true,
+			isGitRepo:     true,
+			repoPath:      root,
+			currentBranch: "main",
+			createFileFunc: func(_, _ string) (string, error) {
+				t.Error("nothing should be written")
+				return "", nil
+			},
+		}
+
+		for _, file := range []map[string]interface{}{
+			{"path": "dest.txt", "source_path": outside},
+			{"path": "../dest.txt", "content": "x"},
+		} {
+			_, err := PushFiles(mockGit, map[string]interface{}{"files": []interface{}{file}, "message": "test"})
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "no permitid")
+		}
+
+		_, err := PushFiles(mockGit, map[string]interface{}{"paths": []interface{}{"../../etc/passwd"}, "message": "test"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "ruta no permitida")
+	})
+
 	t.Run("paths mode stages existing files", func(t *testing.T) {
 		tmpDir, err := os.MkdirTemp("", "pushfiles-paths")
 		assert.NoError(t, err)
@@ -707,3 +825,78 @@ func TestPushFiles(t *testing.T) {
 		assert.Contains(t, err.Error(), "'content' o 'source_path'")
 	})
 }
+
+func TestSecretScanning(t *testing.T) {
+	awsKey := "AKIA" + "IOSFODNN7EXAMPLE"
+
+	t.Run("PushFiles blocks a source_path .env before writing", func(t *testing.T) {
+		srcDir := t.TempDir()
+		envFile := filepath.Join(srcDir, ".env")
+		assert.NoError(t, os.WriteFile(envFile, []byte("DEBUG=1\nAWS_ACCESS_KEY_ID="+awsKey+"\n"), 0600))
+
+		wrote := false
+		mockGit := &mockGitOperations{
+			hasGit:         true,
+			isGitRepo:      true,
+			repoPath:       t.TempDir(),
+			currentBranch:  "main",
+			createFileFunc: func(_, _ string) (string, error) { wrote = true; return "created", nil },
+			commitFunc:     func(_ string) (string, error) { t.Error("commit should not run"); return "", nil },
+		}
+
+		args := map[string]interface{}{
+			"files": []interface{}{
+				map[string]interface{}{"path": "README.md", "content": "docs"},
+				map[string]interface{}{"path": ".env", "source_path": envFile},
+			},
+			"message": "chore: config",
+		}
+
+		_, err := PushFiles(mockGit, args)
+		var secretsErr *safety.SecretsFoundError
+		assert.ErrorAs(t, err, &secretsErr)
+		assert.Contains(t, err.Error(), ".env:2 aws_access_key_id")
+		assert.False(t, wrote, "no file should be written when the scan blocks")
+	})
+
+	t.Run("PushFiles scans files staged by path", func(t *testing.T) {
+		repoDir := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(repoDir, "deploy.sh"), []byte("export AWS_ACCESS_KEY_ID="+awsKey), 0600))
+		mockGit := &mockGitOperations{hasGit: true, isGitRepo: true, repoPath: repoDir, currentBranch: "main"}
+
+		_, err := PushFiles(mockGit, map[string]interface{}{"paths": []interface{}{"deploy.sh"}, "message": "add script"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "deploy.sh:1")
+	})
+
+	t.Run("SmartCreateFile blocks before the API upload", func(t *testing.T) {
+		mockGit := &mockGitOperations{hasGit: false}
+		mockGitHub := &mockGitHubOperations{
+			createFileFunc: func(_ context.Context, _, _, _, _, _, _ string) (*github.RepositoryContentResponse, error) {
+				t.Error("API upload should not run")
+				return nil, nil
+			},
+		}
+		args := map[string]interface{}{"owner": "o", "repo": "r", "path": "keys.txt", "content": awsKey}
+
+		_, err := SmartCreateFile(mockGit, mockGitHub, args)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "keys.txt:1")
+	})
+
+	t.Run("Warn mode writes and reports", func(t *testing.T) {
+		previous := secretScanner
+		SetSecretScanner(safety.NewSecretScanner(safety.SecretScanConfig{Action: safety.SecretScanWarn}))
+		defer func() { secretScanner = previous }()
+
+		mockGit := &mockGitOperations{
+			hasGit:         true,
+			isGitRepo:      true,
+			createFileFunc: func(_, _ string) (string, error) { return "created", nil },
+		}
+		result, err := SmartCreateFile(mockGit, nil, map[string]interface{}{"path": "keys.txt", "content": awsKey})
+		assert.NoError(t, err)
+		assert.Contains(t, result, "ARCHIVO CREADO")
+		assert.Contains(t, result, "keys.txt:1 aws_access_key_id")
+	})
+}
@@ -33,6 +33,8 @@ func HandleAdminTool(s *MCPServer, name string, arguments map[string]interface{}
 			return handleArchiveRepository(s, ctx, arguments)
 		case "delete":
 			return handleDeleteRepository(s, ctx, arguments)
+		case "restore":
+			return handleRestoreBackup(s, ctx, arguments)
 		default:
 			return types.ToolCallResult{}, fmt.Errorf("unknown operation '%s' for github_admin_repo", operation)
 		}
@@ -141,7 +143,7 @@ func handleUpdateRepoSettings(s *MCPServer, ctx context.Context, args map[string
 		// Build settings map from arguments
 		settings := make(map[string]interface{})
 		for key, value := range args {
-			if key != "owner" && key != "repo" && key != "dry_run" && key != "confirmation_token" {
+			if key != "owner" && key != "repo" && key != "dry_run" && key != "confirmation_token" && key != "approval_id" {
 				settings[key] = value
 			}
 		}
@@ -221,113 +223,126 @@ func handleUpdateBranchProtection(s *MCPServer, ctx context.Context, args map[st
 
 	// HIGH risk - requires confirmation
 	return s.Safety.WrapExecution(ctx, "github_branch_protection:update", args, func() (string, error) {
-		// Build protection request
-		protectionReq := &github.ProtectionRequest{}
-
-		// Required pull request reviews
-		if requireReviews, ok := args["require_pull_request_reviews"].(bool); ok && requireReviews {
-			reviewCount := 1
-			if count, ok := args["required_approving_review_count"].(float64); ok {
-				reviewCount = int(count)
-			} else if count, ok := args["required_approving_review_count"].(int); ok {
-				reviewCount = count
-			}
+		protectionReq := buildProtectionRequest(args)
 
-			dismissStale := false
-			if dismiss, ok := args["dismiss_stale_reviews"].(bool); ok {
-				dismissStale = dismiss
-			}
+		protection, err := s.AdminClient.UpdateBranchProtection(ctx, owner, repo, branch, protectionReq)
+		if err != nil {
+			return "", err
+		}
 
-			protectionReq.RequiredPullRequestReviews = &github.PullRequestReviewsEnforcementRequest{
-				RequiredApprovingReviewCount: reviewCount,
-				DismissStaleReviews:          dismissStale,
-			}
+		result := fmt.Sprintf("✅ Updated branch protection for %s/%s @ %s\n\n", owner, repo, branch)
+		if protection.RequiredPullRequestReviews != nil {
+			result += fmt.Sprintf("Required Reviews: %d\n", protection.RequiredPullRequestReviews.RequiredApprovingReviewCount)
+		}
+		if protection.EnforceAdmins != nil {
+			result += fmt.Sprintf("Enforce Admins: %v\n", protection.EnforceAdmins.Enabled)
 		}
 
-		// Required status checks
-		if requireChecks, ok := args["require_status_checks"].(bool); ok && requireChecks {
-			strict := false
-			if s, ok := args["strict_status_checks"].(bool); ok {
-				strict = s
-			}
+		return result, nil
+	})
+}
 
-			contexts := []string{}
-			if ctxs, ok := args["required_status_checks"].([]interface{}); ok {
-				for _, ctx := range ctxs {
-					if str, ok := ctx.(string); ok {
-						contexts = append(contexts, str)
-					}
-				}
-			}
+// buildProtectionRequest maps github_branch_protection update arguments (or a
+// branch protection snapshot, which uses the same keys) to a ProtectionRequest
+func buildProtectionRequest(args map[string]interface{}) *github.ProtectionRequest {
+	protectionReq := &github.ProtectionRequest{}
 
-			protectionReq.RequiredStatusChecks = &github.RequiredStatusChecks{
-				Strict:   strict,
-				Contexts: &contexts,
-			}
+	// Required pull request reviews
+	if requireReviews, ok := args["require_pull_request_reviews"].(bool); ok && requireReviews {
+		reviewCount := 1
+		if count, ok := args["required_approving_review_count"].(float64); ok {
+			reviewCount = int(count)
+		} else if count, ok := args["required_approving_review_count"].(int); ok {
+			reviewCount = count
 		}
 
-		// Enforce admins
-		if enforce, ok := args["enforce_admins"].(bool); ok {
-			protectionReq.EnforceAdmins = enforce
+		dismissStale := false
+		if dismiss, ok := args["dismiss_stale_reviews"].(bool); ok {
+			dismissStale = dismiss
 		}
 
-		// Restrictions (who can push)
-		if restrict, ok := args["restrictions"].(map[string]interface{}); ok {
-			users := []string{}
-			teams := []string{}
+		codeOwners := false
+		if owners, ok := args["require_code_owner_reviews"].(bool); ok {
+			codeOwners = owners
+		}
 
-			if u, ok := restrict["users"].([]interface{}); ok {
-				for _, user := range u {
-					if str, ok := user.(string); ok {
-						users = append(users, str)
-					}
-				}
-			}
+		protectionReq.RequiredPullRequestReviews = &github.PullRequestReviewsEnforcementRequest{
+			RequiredApprovingReviewCount: reviewCount,
+			DismissStaleReviews:          dismissStale,
+			RequireCodeOwnerReviews:      codeOwners,
+		}
+	}
 
-			if t, ok := restrict["teams"].([]interface{}); ok {
-				for _, team := range t {
-					if str, ok := team.(string); ok {
-						teams = append(teams, str)
-					}
-				}
-			}
+	// Required status checks
+	if requireChecks, ok := args["require_status_checks"].(bool); ok && requireChecks {
+		strict := false
+		if s, ok := args["strict_status_checks"].(bool); ok {
+			strict = s
+		}
 
-			protectionReq.Restrictions = &github.BranchRestrictionsRequest{
-				Users: users,
-				Teams: teams,
+		contexts := []string{}
+		if ctxs, ok := args["required_status_checks"].([]interface{}); ok {
+			for _, ctx := range ctxs {
+				if str, ok := ctx.(string); ok {
+					contexts = append(contexts, str)
+				}
 			}
 		}
 
-		// Required linear history
-		if linear, ok := args["required_linear_history"].(bool); ok {
-			protectionReq.RequireLinearHistory = &linear
+		protectionReq.RequiredStatusChecks = &github.RequiredStatusChecks{
+			Strict:   strict,
+			Contexts: &contexts,
 		}
+	}
 
-		// Allow force pushes
-		if allowForce, ok := args["allow_force_pushes"].(bool); ok {
-			protectionReq.AllowForcePushes = &allowForce
-		}
+	// Enforce admins
+	if enforce, ok := args["enforce_admins"].(bool); ok {
+		protectionReq.EnforceAdmins = enforce
+	}
 
-		// Allow deletions
-		if allowDeletions, ok := args["allow_deletions"].(bool); ok {
-			protectionReq.AllowDeletions = &allowDeletions
-		}
+	// Restrictions (who can push)
+	if restrict, ok := args["restrictions"].(map[string]interface{}); ok {
+		users := []string{}
+		teams := []string{}
 
-		protection, err := s.AdminClient.UpdateBranchProtection(ctx, owner, repo, branch, protectionReq)
-		if err != nil {
-			return "", err
+		if u, ok := restrict["users"].([]interface{}); ok {
+			for _, user := range u {
+				if str, ok := user.(string); ok {
+					users = append(users, str)
+				}
+			}
 		}
 
-		result := fmt.Sprintf("✅ Updated branch protection for %s/%s @ %s\n\n", owner, repo, branch)
-		if protection.RequiredPullRequestReviews != nil {
-			result += fmt.Sprintf("Required Reviews: %d\n", protection.RequiredPullRequestReviews.RequiredApprovingReviewCount)
+		if t, ok := restrict["teams"].([]interface{}); ok {
+			for _, team := range t {
+				if str, ok := team.(string); ok {
+					teams = append(teams, str)
+				}
+			}
 		}
-		if protection.EnforceAdmins != nil {
-			result += fmt.Sprintf("Enforce Admins: %v\n", protection.EnforceAdmins.Enabled)
+
+		protectionReq.Restrictions = &github.BranchRestrictionsRequest{
+			Users: users,
+			Teams: teams,
 		}
+	}
 
-		return result, nil
-	})
+	// Required linear history
+	if linear, ok := args["required_linear_history"].(bool); ok {
+		protectionReq.RequireLinearHistory = &linear
+	}
+
+	// Allow force pushes
+	if allowForce, ok := args["allow_force_pushes"].(bool); ok {
+		protectionReq.AllowForcePushes = &allowForce
+	}
+
+	// Allow deletions
+	if allowDeletions, ok := args["allow_deletions"].(bool); ok {
+		protectionReq.AllowDeletions = &allowDeletions
+	}
+
+	return protectionReq
 }
 
 func handleDeleteBranchProtection(s *MCPServer, ctx context.Context, args map[string]interface{}) (types.ToolCallResult, error) {
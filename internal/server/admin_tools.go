@@ -16,12 +16,13 @@ func ListAdminTools() []types.Tool {
 				"get_settings (view repo configuration), " +
 				"update_settings (modify name, description, visibility, features, default_branch, merge options), " +
 				"archive (make repo read-only - CRITICAL), " +
-				"delete (permanently delete repo - CRITICAL, requires confirmation_token).",
+				"delete (permanently delete repo - CRITICAL, requires confirmation_token), " +
+				"restore (re-apply a pre-state backup taken before a settings, branch protection, webhook or collaborator change; dry-run shows the diff - HIGH, requires confirmation_token).",
 			Annotations: DestructiveAnnotation(),
 			InputSchema: types.ToolInputSchema{
 				Type: "object",
 				Properties: map[string]types.Property{
-					"operation":              {Type: "string", Description: "Operation to perform: get_settings, update_settings, archive, delete"},
+					"operation":              {Type: "string", Description: "Operation to perform: get_settings, update_settings, archive, delete, restore"},
 					"owner":                  {Type: "string", Description: "Repository owner (username or organization)"},
 					"repo":                   {Type: "string", Description: "Repository name"},
 					"name":                   {Type: "string", Description: "New repository name (update_settings only, optional)"},
@@ -37,7 +38,9 @@ func ListAdminTools() []types.Tool {
 					"allow_rebase_merge":     {Type: "boolean", Description: "Allow rebase merging (update_settings only, optional)"},
 					"delete_branch_on_merge": {Type: "boolean", Description: "Auto-delete branches after merge (update_settings only, optional)"},
 					"dry_run":               {Type: "boolean", Description: "Preview changes without applying (default: true)"},
-					"confirmation_token":     {Type: "string", Description: "Confirmation token for archive/delete operations"},
+					"backup":                 {Type: "string", Description: "Backup file name from .mcp-backups (restore only; omit to list available backups)"},
+					"confirmation_token":     {Type: "string", Description: "Confirmation token for archive/delete/restore operations"},
+					"approval_id":            {Type: "string", Description: "ID of a human-approved request (archive/delete when two-person approval is enabled)"},
 				},
 				Required: []string{"operation", "owner", "repo"},
 			},
@@ -69,6 +72,7 @@ func ListAdminTools() []types.Tool {
 					"strict_status_checks":            {Type: "boolean", Description: "Require branches to be up to date (update only)"},
 					"dry_run":                         {Type: "boolean", Description: "Preview changes (default: true)"},
 					"confirmation_token":              {Type: "string", Description: "Confirmation token for delete/high-risk changes"},
+					"approval_id":                     {Type: "string", Description: "ID of a human-approved request (delete when two-person approval is enabled)"},
 				},
 				Required: []string{"operation", "owner", "repo", "branch"},
 			},
@@ -5,8 +5,10 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 // getIntArg extracts an int from arguments map. Accepts float64 (JSON default),
@@ -83,3 +85,48 @@ func getStringArg(args map[string]interface{}, key string) (string, error) {
 	}
 	return s, nil
 }
+
+// getListArg extracts an optional list of strings, given either as a JSON
+// array or as a comma-separated string. Empty entries are dropped.
+func getListArg(args map[string]interface{}, key string) []string {
+	var raw []string
+	switch v := args[key].(type) {
+	case string:
+		raw = strings.Split(v, ",")
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				raw = append(raw, s)
+			}
+		}
+	}
+	var list []string
+	for _, item := range raw {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getJSONArg decodes an optional structured argument into out. The value may
+// arrive already decoded (JSON array or object) or as a JSON string, which
+// some clients send for nested parameters. Returns false if the key is absent.
+func getJSONArg(args map[string]interface{}, key string, out interface{}) (bool, error) {
+	raw, exists := args[key]
+	if !exists || raw == nil {
+		return false, nil
+	}
+	data, ok := raw.(string)
+	if !ok {
+		encoded, err := json.Marshal(raw)
+		if err != nil {
+			return true, fmt.Errorf("parameter '%s' is not valid JSON: %v", key, err)
+		}
+		data = string(encoded)
+	}
+	if err := json.Unmarshal([]byte(data), out); err != nil {
+		return true, fmt.Errorf("parameter '%s' has an invalid format: %v", key, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scopweb/mcp-go-github/pkg/safety"
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+const (
+	defaultAuditLimit = 20
+	maxAuditLimit     = 200
+)
+
+// HandleAuditTool serves the read-only github_audit tool
+func HandleAuditTool(s *MCPServer, arguments map[string]interface{}) (types.ToolCallResult, error) {
+	if s.Safety == nil {
+		return types.ToolCallResult{}, fmt.Errorf("safety middleware not initialized")
+	}
+
+	operation, _ := arguments["operation"].(string)
+	if operation == "" {
+		return types.ToolCallResult{}, fmt.Errorf("parameter 'operation' required for github_audit")
+	}
+
+	config := s.Safety.GetEngine().GetConfig()
+	if !config.EnableAuditLog {
+		return auditErrorResult("Audit logging is disabled (globalSettings.enableAuditLog is false)"), nil
+	}
+
+	query, err := auditQueryFromArgs(arguments, time.Now())
+	if err != nil {
+		return auditErrorResult(err.Error()), nil
+	}
+
+	var result interface{}
+	switch operation {
+	case "recent":
+		// recent ignores filters other than limit
+		entries, err := safety.SearchAuditLog(config.AuditLogPath, safety.AuditQuery{Limit: query.Limit})
+		if err != nil {
+			return types.ToolCallResult{}, err
+		}
+		result = auditEntriesResult(entries)
+	case "search":
+		entries, err := safety.SearchAuditLog(config.AuditLogPath, query)
+		if err != nil {
+			return types.ToolCallResult{}, err
+		}
+		result = auditEntriesResult(entries)
+	case "stats":
+		query.Limit = 0
+		entries, err := safety.SearchAuditLog(config.AuditLogPath, query)
+		if err != nil {
+			return types.ToolCallResult{}, err
+		}
+		result = safety.AuditStatistics(entries)
+	default:
+		return types.ToolCallResult{}, fmt.Errorf("unknown operation '%s' for github_audit", operation)
+	}
+
+	jsonOutput, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return types.ToolCallResult{}, fmt.Errorf("failed to marshal audit result: %w", err)
+	}
+	return types.ToolCallResult{
+		Content: []types.Content{{Type: "text", Text: string(jsonOutput)}},
+	}, nil
+}
+
+func auditEntriesResult(entries []*safety.AuditEntry) map[string]interface{} {
+	if entries == nil {
+		entries = []*safety.AuditEntry{}
+	}
+	return map[string]interface{}{
+		"count":   len(entries),
+		"entries": entries,
+	}
+}
+
+func auditErrorResult(message string) types.ToolCallResult {
+	return types.ToolCallResult{
+		Content: []types.Content{{Type: "text", Text: message}},
+		IsError: true,
+	}
+}
+
+// auditQueryFromArgs builds the search filters from the tool arguments
+func auditQueryFromArgs(args map[string]interface{}, now time.Time) (safety.AuditQuery, error) {
+	query := safety.AuditQuery{Limit: defaultAuditLimit}
+
+	if _, ok := args["limit"]; ok {
+		limit, err := getIntArg(args, "limit")
+		if err != nil {
+			return query, err
+		}
+		if limit < 1 || limit > maxAuditLimit {
+			return query, fmt.Errorf("limit must be between 1 and %d", maxAuditLimit)
+		}
+		query.Limit = limit
+	}
+
+	if since, _ := args["since"].(string); since != "" {
+		t, err := parseAuditTime(since, now, false)
+		if err != nil {
+			return query, fmt.Errorf("invalid since: %w", err)
+		}
+		query.Since = t
+	}
+	if until, _ := args["until"].(string); until != "" {
+		t, err := parseAuditTime(until, now, true)
+		if err != nil {
+			return query, fmt.Errorf("invalid until: %w", err)
+		}
+		query.Until = t
+	}
+	if !query.Since.IsZero() && !query.Until.IsZero() && !query.Since.Before(query.Until) {
+		return query, fmt.Errorf("since must be before until")
+	}
+
+	query.Operation, _ = args["filter_operation"].(string)
+	query.Repo, _ = args["repo"].(string)
+	query.Result, _ = args["result"].(string)
+	query.RiskLevel, _ = args["risk_level"].(string)
+	return query, nil
+}
+
+// parseAuditTime accepts an RFC 3339 timestamp, a local date, or an age
+// relative to now ("90m", "24h", "7d"). A date used as the end of a range
+// covers that whole day.
+func parseAuditTime(value string, now time.Time, endOfRange bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		if endOfRange {
+			return t.AddDate(0, 0, 1), nil
+		}
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not an RFC 3339 timestamp, a date (2006-01-02) or an age such as 24h or 7d", value)
+}
@@ -6,9 +6,9 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/google/go-github/v81/github"
+	"github.com/scopweb/mcp-go-github/pkg/safety"
 	"github.com/scopweb/mcp-go-github/pkg/types"
 )
 
@@ -129,6 +129,10 @@ func handleDownloadFile(s *MCPServer, ctx context.Context, args map[string]inter
 	if localPath == "" {
 		localPath = path
 	}
+	target, err := fileSandbox(s).Resolve(localPath)
+	if err != nil {
+		return types.ToolCallResult{}, fmt.Errorf("local_path not allowed: %w", err)
+	}
 
 	opts := &github.RepositoryContentGetOptions{}
 	if branch != "" {
@@ -148,15 +152,13 @@ func handleDownloadFile(s *MCPServer, ctx context.Context, args map[string]inter
 	}
 
 	// Create directories if needed
-	dir := filepath.Dir(localPath)
-	if dir != "." && dir != "" {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return types.ToolCallResult{}, fmt.Errorf("failed to create directory %s: %w", dir, err)
-		}
+	dir := filepath.Dir(target)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return types.ToolCallResult{}, fmt.Errorf("failed to create directory %s: %w", dir, err)
 	}
 
 	// Write file
-	if err := os.WriteFile(localPath, content, 0644); err != nil {
+	if err := os.WriteFile(target, content, 0644); err != nil {
 		return types.ToolCallResult{}, fmt.Errorf("failed to write file %s: %w", localPath, err)
 	}
 
@@ -186,6 +188,11 @@ func handleDownloadRepo(s *MCPServer, ctx context.Context, args map[string]inter
 	if localDir == "" {
 		localDir = repo
 	}
+	sandbox := fileSandbox(s)
+	targetDir, err := sandbox.Resolve(localDir)
+	if err != nil {
+		return types.ToolCallResult{}, fmt.Errorf("local_dir not allowed: %w", err)
+	}
 
 	// Get the full tree recursively
 	ref, _, err := client.Git.GetRef(ctx, owner, repo, "refs/heads/"+branch)
@@ -200,7 +207,7 @@ func handleDownloadRepo(s *MCPServer, ctx context.Context, args map[string]inter
 	}
 
 	// Create local directory
-	if err := os.MkdirAll(localDir, 0755); err != nil {
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
 		return types.ToolCallResult{}, fmt.Errorf("failed to create directory %s: %w", localDir, err)
 	}
 
@@ -215,7 +222,11 @@ func handleDownloadRepo(s *MCPServer, ctx context.Context, args map[string]inter
 	for _, entry := range tree.Entries {
 		if entry.GetType() == "tree" {
 			// Create directory
-			dirPath := filepath.Join(localDir, entry.GetPath())
+			dirPath, err := sandbox.ResolveIn(targetDir, entry.GetPath())
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("%s: %v", entry.GetPath(), err))
+				continue
+			}
 			os.MkdirAll(dirPath, 0755)
 			continue
 		}
@@ -231,7 +242,11 @@ func handleDownloadRepo(s *MCPServer, ctx context.Context, args map[string]inter
 		}
 
 		filePath := entry.GetPath()
-		localFilePath := filepath.Join(localDir, filePath)
+		localFilePath, err := sandbox.ResolveIn(targetDir, filePath)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", filePath, err))
+			continue
+		}
 
 		// Create parent directory
 		parentDir := filepath.Dir(localFilePath)
@@ -308,9 +323,14 @@ func handlePullRepo(s *MCPServer, ctx context.Context, args map[string]interface
 	if localDir == "" {
 		localDir = repo
 	}
+	sandbox := fileSandbox(s)
+	targetDir, err := sandbox.Resolve(localDir)
+	if err != nil {
+		return types.ToolCallResult{}, fmt.Errorf("local_dir not allowed: %w", err)
+	}
 
 	// Check if local directory exists
-	if _, err := os.Stat(localDir); os.IsNotExist(err) {
+	if _, err := os.Stat(targetDir); os.IsNotExist(err) {
 		return types.ToolCallResult{}, fmt.Errorf("local directory '%s' does not exist. Use github_download_repo first", localDir)
 	}
 
@@ -336,7 +356,11 @@ func handlePullRepo(s *MCPServer, ctx context.Context, args map[string]interface
 
 	for _, entry := range tree.Entries {
 		if entry.GetType() == "tree" {
-			dirPath := filepath.Join(localDir, entry.GetPath())
+			dirPath, err := sandbox.ResolveIn(targetDir, entry.GetPath())
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("%s: %v", entry.GetPath(), err))
+				continue
+			}
 			os.MkdirAll(dirPath, 0755)
 			continue
 		}
@@ -351,7 +375,11 @@ func handlePullRepo(s *MCPServer, ctx context.Context, args map[string]interface
 		}
 
 		filePath := entry.GetPath()
-		localFilePath := filepath.Join(localDir, filePath)
+		localFilePath, err := sandbox.ResolveIn(targetDir, filePath)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", filePath, err))
+			continue
+		}
 
 		// Check if file exists and compare SHA
 		needsUpdate := false
@@ -454,15 +482,11 @@ func formatSize(bytes int) string {
 	return fmt.Sprintf("%.1f MB", float64(bytes)/(1024*1024))
 }
 
-// sanitizePath prevents path traversal in file operations
-func sanitizePath(path string) string {
-	// Remove leading slashes and dots
-	path = strings.TrimLeft(path, "/\\.")
-	// Clean the path
-	path = filepath.Clean(path)
-	// Prevent path traversal
-	if strings.Contains(path, "..") {
-		return ""
-	}
-	return path
+// fileSandbox returns the sandbox confining local paths; without the safety
+// middleware paths are unrestricted but traversal is still rejected
+func fileSandbox(s *MCPServer) *safety.Sandbox {
+	if s.Safety == nil {
+		return nil
+	}
+	return s.Safety.GetEngine().GetSandbox()
 }
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+// wrapGitWrite runs a local git write operation through the safety middleware so
+// policy rules can match on it. The parameters handed to the engine carry the
+// effective branch and the owner/repo parsed from the remote, since the tool
+// arguments usually omit them. Git failures stay tool-level errors (IsError),
+// as they were before the middleware was involved.
+func wrapGitWrite(s *MCPServer, ctx context.Context, operation string, arguments map[string]interface{}, branch string, executor func() (string, error)) (types.ToolCallResult, error) {
+	var result types.ToolCallResult
+	var err error
+
+	if s.Safety == nil {
+		var text string
+		text, err = executor()
+		result = types.ToolCallResult{Content: []types.Content{{Type: "text", Text: text}}}
+	} else {
+		result, err = s.Safety.WrapExecution(ctx, operation, gitSafetyParams(s, arguments, branch), executor)
+	}
+
+	if err != nil {
+		return types.ToolCallResult{
+			Content: []types.Content{{Type: "text", Text: err.Error()}},
+			IsError: true,
+		}, nil
+	}
+	return result, nil
+}
+
+// gitSafetyParams copies the tool arguments and fills in branch, owner and repo.
+// File payloads are reduced to their paths so content never reaches the audit log.
+func gitSafetyParams(s *MCPServer, arguments map[string]interface{}, branch string) map[string]interface{} {
+	params := make(map[string]interface{}, len(arguments)+3)
+	for key, value := range arguments {
+		if key == "files" {
+			value = filePaths(value)
+		}
+		params[key] = value
+	}
+
+	if branch == "" {
+		branch = s.GitClient.GetCurrentBranch()
+	}
+	if branch != "" {
+		params["branch"] = branch
+	}
+
+	if _, ok := params["owner"]; !ok {
+		if owner, repo := parseRemoteOwnerRepo(s.GitClient.GetRemoteURL()); owner != "" {
+			params["owner"] = owner
+			params["repo"] = repo
+		}
+	}
+
+	return params
+}
+
+// filePaths reduces a gh_push_files "files" array to the list of paths
+func filePaths(value interface{}) interface{} {
+	files, ok := value.([]interface{})
+	if !ok {
+		return value
+	}
+	paths := make([]interface{}, 0, len(files))
+	for _, file := range files {
+		if fileMap, ok := file.(map[string]interface{}); ok {
+			paths = append(paths, fileMap["path"])
+		}
+	}
+	return paths
+}
+
+// parseRemoteOwnerRepo extracts owner and repo from a GitHub remote URL.
+// Handles https://host/owner/repo(.git), git@host:owner/repo(.git) and
+// ssh://git@host/owner/repo(.git). Returns empty strings when unparseable.
+func parseRemoteOwnerRepo(remoteURL string) (string, string) {
+	remoteURL = strings.TrimSpace(remoteURL)
+	if remoteURL == "" {
+		return "", ""
+	}
+
+	path := remoteURL
+	if idx := strings.Index(path, "://"); idx >= 0 {
+		path = path[idx+3:]
+		if slash := strings.Index(path, "/"); slash >= 0 {
+			path = path[slash+1:]
+		} else {
+			return "", ""
+		}
+	} else if colon := strings.Index(path, ":"); colon >= 0 {
+		path = path[colon+1:]
+	} else {
+		return "", ""
+	}
+
+	path = strings.TrimSuffix(strings.TrimSuffix(path, "/"), ".git")
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 {
+		return "", ""
+	}
+	owner, repo := parts[len(parts)-2], parts[len(parts)-1]
+	if owner == "" || repo == "" {
+		return "", ""
+	}
+	return owner, repo
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scopweb/mcp-go-github/pkg/git"
+)
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestCallTool_RebaseInteractiveProtectedBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	// The safety engine keeps its state files next to safety.json by default
+	work := t.TempDir()
+	t.Chdir(work)
+	if err := os.WriteFile("safety.json", []byte(`{"mode": "moderate", "protectedBranches": {"patterns": ["main"]}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	middleware, err := NewSafetyMiddleware("safety.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := initRepo(t)
+	runGit(t, dir, "checkout", "-q", "-b", "main")
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, dir, "add", name)
+		runGit(t, dir, "commit", "-q", "-m", "add "+name)
+	}
+	head := runGit(t, dir, "rev-parse", "HEAD")
+
+	registry, err := git.NewWorkspaceRegistry("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := registry.Add("repo", dir); err != nil {
+		t.Fatal(err)
+	}
+	client, err := registry.Client("repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &MCPServer{GitClient: client, GitAvailable: true, Safety: middleware}
+
+	result, err := CallTool(context.Background(), s, map[string]interface{}{
+		"name": "git_branch",
+		"arguments": map[string]interface{}{
+			"operation": "rebase_interactive",
+			"branch":    "HEAD~1",
+			"todo":      []interface{}{map[string]interface{}{"action": "drop", "commit": head}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Content) == 0 || !strings.Contains(result.Content[0].Text, "refused") {
+		t.Errorf("rewriting main should be refused: %+v", result)
+	}
+	if got := runGit(t, dir, "rev-parse", "HEAD"); got != head {
+		t.Errorf("HEAD moved to %s, want %s", got, head)
+	}
+}
@@ -0,0 +1,395 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+// HandleRepairTool routes github_repair and github_respond calls through safety middleware.
+// Uses composite key "toolName:operation" (plus ":alert_type" for dismiss_alert)
+// for risk classification.
+func HandleRepairTool(s *MCPServer, name string, arguments map[string]interface{}) (types.ToolCallResult, error) {
+	ctx := context.Background()
+
+	if s.Safety == nil {
+		return types.ToolCallResult{}, fmt.Errorf("safety middleware not initialized")
+	}
+
+	operation, _ := arguments["operation"].(string)
+	if operation == "" {
+		return types.ToolCallResult{}, fmt.Errorf("parameter 'operation' required for %s", name)
+	}
+
+	owner, _ := arguments["owner"].(string)
+	repo, _ := arguments["repo"].(string)
+	if owner == "" || repo == "" {
+		return types.ToolCallResult{}, fmt.Errorf("parameters 'owner' and 'repo' required for %s %s", name, operation)
+	}
+
+	switch name {
+	case "github_repair":
+		switch operation {
+		case "close_issue":
+			return handleCloseIssue(s, ctx, arguments)
+		case "merge_pr":
+			return handleMergePR(s, ctx, arguments)
+		case "rerun_workflow":
+			return handleRerunWorkflow(s, ctx, arguments)
+		case "dismiss_alert":
+			return handleDismissAlert(s, ctx, arguments)
+		default:
+			return types.ToolCallResult{}, fmt.Errorf("unknown operation '%s' for github_repair", operation)
+		}
+
+	case "github_respond":
+		switch operation {
+		case "comment_issue":
+			return handleCommentIssue(s, ctx, arguments)
+		case "comment_pr":
+			return handleCommentPR(s, ctx, arguments)
+		case "review_pr":
+			return handleReviewPR(s, ctx, arguments)
+		default:
+			return types.ToolCallResult{}, fmt.Errorf("unknown operation '%s' for github_respond", operation)
+		}
+
+	default:
+		return types.ToolCallResult{}, fmt.Errorf("unknown repair tool: %s", name)
+	}
+}
+
+// ============================================================================
+// github_repair Handlers
+// ============================================================================
+
+func handleCloseIssue(s *MCPServer, ctx context.Context, args map[string]interface{}) (types.ToolCallResult, error) {
+	owner, _ := args["owner"].(string)
+	repo, _ := args["repo"].(string)
+	number, err := getIntArg(args, "number")
+	if err != nil {
+		return types.ToolCallResult{}, err
+	}
+	comment, _ := args["comment"].(string)
+
+	preview := func() (string, error) {
+		issue, err := s.GithubClient.GetIssue(ctx, owner, repo, number)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch issue #%d: %w", number, err)
+		}
+		text := fmt.Sprintf("Issue #%d: %s\n", number, issue.GetTitle())
+		text += fmt.Sprintf("State: %s\n", issue.GetState())
+		text += fmt.Sprintf("Author: %s\n", issue.GetUser().GetLogin())
+		text += fmt.Sprintf("Comments: %d\n", issue.GetComments())
+		text += fmt.Sprintf("URL: %s\n", issue.GetHTMLURL())
+		if issue.GetState() == "closed" {
+			text += "\n⚠️  Issue is already closed"
+		} else {
+			text += "\nWill change state: open → closed"
+		}
+		return text, nil
+	}
+
+	// MEDIUM risk - dry-run preview
+	return s.Safety.WrapExecutionWithPreview(ctx, "github_repair:close_issue", args, preview, func() (string, error) {
+		issue, err := s.GithubClient.CloseIssue(ctx, owner, repo, number, comment)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Issue #%d closed\n%s", number, issue.GetHTMLURL()), nil
+	})
+}
+
+func handleMergePR(s *MCPServer, ctx context.Context, args map[string]interface{}) (types.ToolCallResult, error) {
+	owner, _ := args["owner"].(string)
+	repo, _ := args["repo"].(string)
+	number, err := getIntArg(args, "number")
+	if err != nil {
+		return types.ToolCallResult{}, err
+	}
+	commitMessage, _ := args["commit_message"].(string)
+	mergeMethod, _ := args["merge_method"].(string)
+	if mergeMethod == "" {
+		mergeMethod = "merge"
+	}
+
+	preview := func() (string, error) {
+		pr, err := s.GithubClient.GetPullRequest(ctx, owner, repo, number)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch PR #%d: %w", number, err)
+		}
+		text := fmt.Sprintf("PR #%d: %s\n", number, pr.GetTitle())
+		text += fmt.Sprintf("State: %s\n", pr.GetState())
+		text += fmt.Sprintf("Author: %s\n", pr.GetUser().GetLogin())
+		text += fmt.Sprintf("Merge: %s → %s (method: %s)\n", pr.GetHead().GetRef(), pr.GetBase().GetRef(), mergeMethod)
+		text += fmt.Sprintf("Head SHA: %s\n", pr.GetHead().GetSHA())
+		text += fmt.Sprintf("Commits: %d, Files: %d (+%d/-%d)\n", pr.GetCommits(), pr.GetChangedFiles(), pr.GetAdditions(), pr.GetDeletions())
+		if pr.Mergeable != nil {
+			text += fmt.Sprintf("Mergeable: %v (%s)\n", pr.GetMergeable(), pr.GetMergeableState())
+		}
+		text += fmt.Sprintf("URL: %s\n", pr.GetHTMLURL())
+		if pr.GetMerged() {
+			text += "\n⚠️  PR is already merged"
+		} else if pr.GetState() == "closed" {
+			text += "\n⚠️  PR is closed and cannot be merged"
+		}
+		return text, nil
+	}
+
+	// HIGH risk - dry-run preview + confirmation
+	return s.Safety.WrapExecutionWithPreview(ctx, "github_repair:merge_pr", args, preview, func() (string, error) {
+		result, err := s.GithubClient.MergePullRequest(ctx, owner, repo, number, commitMessage, mergeMethod)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("PR #%d merged successfully\nMerged: %v\nSHA: %s",
+			number, result.GetMerged(), result.GetSHA()), nil
+	})
+}
+
+func handleRerunWorkflow(s *MCPServer, ctx context.Context, args map[string]interface{}) (types.ToolCallResult, error) {
+	owner, _ := args["owner"].(string)
+	repo, _ := args["repo"].(string)
+	runID, err := getInt64Arg(args, "run_id")
+	if err != nil {
+		return types.ToolCallResult{}, err
+	}
+	failedOnly, _ := args["failed_jobs_only"].(bool)
+
+	preview := func() (string, error) {
+		run, err := s.GithubClient.GetWorkflowRun(ctx, owner, repo, runID)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch workflow run %d: %w", runID, err)
+		}
+		text := fmt.Sprintf("Workflow: %s (run #%d)\n", run.GetName(), run.GetRunNumber())
+		text += fmt.Sprintf("Event: %s, Branch: %s\n", run.GetEvent(), run.GetHeadBranch())
+		text += fmt.Sprintf("Status: %s, Conclusion: %s\n", run.GetStatus(), run.GetConclusion())
+		text += fmt.Sprintf("Head SHA: %s\n", run.GetHeadSHA())
+		text += fmt.Sprintf("URL: %s\n", run.GetHTMLURL())
+		if failedOnly {
+			text += "\nWill re-run failed jobs only"
+		} else {
+			text += "\nWill re-run ALL jobs (deployment jobs included)"
+		}
+		return text, nil
+	}
+
+	// MEDIUM risk - dry-run preview
+	return s.Safety.WrapExecutionWithPreview(ctx, "github_repair:rerun_workflow", args, preview, func() (string, error) {
+		if failedOnly {
+			if err := s.GithubClient.RerunFailedJobs(ctx, owner, repo, runID); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Re-running failed jobs for workflow run %d", runID), nil
+		}
+		if err := s.GithubClient.RerunWorkflow(ctx, owner, repo, runID); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Re-running full workflow run %d", runID), nil
+	})
+}
+
+func handleDismissAlert(s *MCPServer, ctx context.Context, args map[string]interface{}) (types.ToolCallResult, error) {
+	owner, _ := args["owner"].(string)
+	repo, _ := args["repo"].(string)
+	alertType, _ := args["alert_type"].(string)
+	comment, _ := args["comment"].(string)
+
+	switch alertType {
+	case "dependabot":
+		number, err := getIntArg(args, "number")
+		if err != nil {
+			return types.ToolCallResult{}, err
+		}
+		reason, _ := args["reason"].(string)
+		if reason == "" {
+			return types.ToolCallResult{}, fmt.Errorf("parameter 'reason' required for dependabot alerts")
+		}
+
+		preview := func() (string, error) {
+			alert, err := s.GithubClient.GetDependabotAlert(ctx, owner, repo, number)
+			if err != nil {
+				return "", fmt.Errorf("failed to fetch Dependabot alert #%d: %w", number, err)
+			}
+			advisory := alert.GetSecurityAdvisory()
+			text := fmt.Sprintf("Dependabot alert #%d: %s\n", number, advisory.GetSummary())
+			text += fmt.Sprintf("Package: %s (%s)\n", alert.GetDependency().GetPackage().GetName(), alert.GetDependency().GetPackage().GetEcosystem())
+			text += fmt.Sprintf("Severity: %s\n", advisory.GetSeverity())
+			text += fmt.Sprintf("State: %s\n", alert.GetState())
+			text += fmt.Sprintf("URL: %s\n", alert.GetHTMLURL())
+			text += fmt.Sprintf("\nWill dismiss with reason: %s", reason)
+			return text, nil
+		}
+
+		// HIGH risk - dry-run preview + confirmation
+		return s.Safety.WrapExecutionWithPreview(ctx, "github_repair:dismiss_alert:dependabot", args, preview, func() (string, error) {
+			alert, err := s.GithubClient.DismissDependabotAlert(ctx, owner, repo, number, reason, comment)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Dependabot alert #%d dismissed (reason: %s)\n%s", number, reason, alert.GetHTMLURL()), nil
+		})
+
+	case "code":
+		number, err := getInt64Arg(args, "number")
+		if err != nil {
+			return types.ToolCallResult{}, err
+		}
+		reason, _ := args["reason"].(string)
+		if reason == "" {
+			return types.ToolCallResult{}, fmt.Errorf("parameter 'reason' required for code scanning alerts")
+		}
+
+		preview := func() (string, error) {
+			alert, err := s.GithubClient.GetCodeScanningAlert(ctx, owner, repo, number)
+			if err != nil {
+				return "", fmt.Errorf("failed to fetch code scanning alert #%d: %w", number, err)
+			}
+			text := fmt.Sprintf("Code scanning alert #%d: %s\n", number, alert.GetRule().GetDescription())
+			text += fmt.Sprintf("Tool: %s, Rule: %s\n", alert.GetTool().GetName(), alert.GetRule().GetID())
+			text += fmt.Sprintf("Severity: %s\n", alert.GetRule().GetSecuritySeverityLevel())
+			text += fmt.Sprintf("State: %s\n", alert.GetState())
+			if loc := alert.GetMostRecentInstance().GetLocation(); loc != nil {
+				text += fmt.Sprintf("Location: %s:%d\n", loc.GetPath(), loc.GetStartLine())
+			}
+			text += fmt.Sprintf("URL: %s\n", alert.GetHTMLURL())
+			text += fmt.Sprintf("\nWill dismiss with reason: %s", reason)
+			return text, nil
+		}
+
+		// HIGH risk - dry-run preview + confirmation
+		return s.Safety.WrapExecutionWithPreview(ctx, "github_repair:dismiss_alert:code", args, preview, func() (string, error) {
+			alert, err := s.GithubClient.DismissCodeScanningAlert(ctx, owner, repo, number, reason, comment)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Code scanning alert #%d dismissed (reason: %s)\n%s", number, reason, alert.GetHTMLURL()), nil
+		})
+
+	case "secret":
+		number, err := getInt64Arg(args, "number")
+		if err != nil {
+			return types.ToolCallResult{}, err
+		}
+		resolution, _ := args["resolution"].(string)
+		if resolution == "" {
+			return types.ToolCallResult{}, fmt.Errorf("parameter 'resolution' required for secret scanning alerts")
+		}
+
+		preview := func() (string, error) {
+			alert, err := s.GithubClient.GetSecretScanningAlert(ctx, owner, repo, number)
+			if err != nil {
+				return "", fmt.Errorf("failed to fetch secret scanning alert #%d: %w", number, err)
+			}
+			// Never echo the secret value itself into the preview
+			text := fmt.Sprintf("Secret scanning alert #%d: %s\n", number, alert.GetSecretTypeDisplayName())
+			text += fmt.Sprintf("State: %s\n", alert.GetState())
+			if alert.Validity != nil {
+				text += fmt.Sprintf("Validity: %s\n", alert.GetValidity())
+			}
+			text += fmt.Sprintf("URL: %s\n", alert.GetHTMLURL())
+			text += fmt.Sprintf("\nWill resolve as: %s", resolution)
+			if resolution != "revoked" {
+				text += "\n⚠️  Resolving without revoking leaves the leaked credential usable"
+			}
+			return text, nil
+		}
+
+		// CRITICAL risk - dry-run preview + confirmation
+		return s.Safety.WrapExecutionWithPreview(ctx, "github_repair:dismiss_alert:secret", args, preview, func() (string, error) {
+			alert, err := s.GithubClient.DismissSecretScanningAlert(ctx, owner, repo, number, resolution)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Secret scanning alert #%d resolved (%s)\n%s", number, resolution, alert.GetHTMLURL()), nil
+		})
+
+	default:
+		return types.ToolCallResult{}, fmt.Errorf("unknown alert_type '%s' for github_repair dismiss_alert (use: dependabot, code, secret)", alertType)
+	}
+}
+
+// ============================================================================
+// github_respond Handlers
+// ============================================================================
+
+func handleCommentIssue(s *MCPServer, ctx context.Context, args map[string]interface{}) (types.ToolCallResult, error) {
+	owner, _ := args["owner"].(string)
+	repo, _ := args["repo"].(string)
+	number, err := getIntArg(args, "number")
+	if err != nil {
+		return types.ToolCallResult{}, err
+	}
+	body, _ := args["body"].(string)
+
+	// LOW risk - audited only
+	return s.Safety.WrapExecution(ctx, "github_respond:comment_issue", args, func() (string, error) {
+		comment, err := s.GithubClient.CreateIssueComment(ctx, owner, repo, number, body)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Comment added to issue #%d\n%s", number, comment.GetHTMLURL()), nil
+	})
+}
+
+func handleCommentPR(s *MCPServer, ctx context.Context, args map[string]interface{}) (types.ToolCallResult, error) {
+	owner, _ := args["owner"].(string)
+	repo, _ := args["repo"].(string)
+	number, err := getIntArg(args, "number")
+	if err != nil {
+		return types.ToolCallResult{}, err
+	}
+	body, _ := args["body"].(string)
+
+	// LOW risk - audited only
+	return s.Safety.WrapExecution(ctx, "github_respond:comment_pr", args, func() (string, error) {
+		comment, err := s.GithubClient.CreatePRComment(ctx, owner, repo, number, body)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Comment added to PR #%d\n%s", number, comment.GetHTMLURL()), nil
+	})
+}
+
+func handleReviewPR(s *MCPServer, ctx context.Context, args map[string]interface{}) (types.ToolCallResult, error) {
+	owner, _ := args["owner"].(string)
+	repo, _ := args["repo"].(string)
+	number, err := getIntArg(args, "number")
+	if err != nil {
+		return types.ToolCallResult{}, err
+	}
+	event, _ := args["event"].(string)
+	body, _ := args["body"].(string)
+
+	preview := func() (string, error) {
+		pr, err := s.GithubClient.GetPullRequest(ctx, owner, repo, number)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch PR #%d: %w", number, err)
+		}
+		text := fmt.Sprintf("PR #%d: %s\n", number, pr.GetTitle())
+		text += fmt.Sprintf("Author: %s\n", pr.GetUser().GetLogin())
+		text += fmt.Sprintf("Head SHA: %s\n", pr.GetHead().GetSHA())
+		text += fmt.Sprintf("URL: %s\n", pr.GetHTMLURL())
+		text += fmt.Sprintf("\nWill submit review: %s", event)
+		return text, nil
+	}
+
+	// MEDIUM risk - approvals count toward branch protection
+	return s.Safety.WrapExecutionWithPreview(ctx, "github_respond:review_pr", args, preview, func() (string, error) {
+		review, err := s.GithubClient.CreatePRReview(ctx, owner, repo, number, event, body)
+		if err != nil {
+			return "", err
+		}
+		var eventLabel string
+		switch event {
+		case "APPROVE":
+			eventLabel = "Approved"
+		case "REQUEST_CHANGES":
+			eventLabel = "Changes requested"
+		default:
+			eventLabel = "Comment"
+		}
+		return fmt.Sprintf("%s PR #%d\n%s", eventLabel, number, review.GetHTMLURL()), nil
+	})
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// InFlight tracks the requests being processed so that a
+// notifications/cancelled can stop them, git subprocesses included
+type InFlight struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+// NewInFlight creates an empty request tracker
+func NewInFlight() *InFlight {
+	return &InFlight{cancels: make(map[string]context.CancelFunc)}
+}
+
+// Start returns the context for request id and the function to call once
+// its response has been built
+func (f *InFlight) Start(id interface{}) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if id == nil {
+		return ctx, cancel
+	}
+	key := fmt.Sprint(id)
+	f.mu.Lock()
+	f.cancels[key] = cancel
+	f.mu.Unlock()
+	return ctx, func() {
+		f.mu.Lock()
+		delete(f.cancels, key)
+		f.mu.Unlock()
+		cancel()
+	}
+}
+
+// Cancel cancels the request named by the params of a notifications/cancelled.
+// It returns false if that request is not in flight.
+func (f *InFlight) Cancel(params map[string]interface{}) bool {
+	id, ok := params["requestId"]
+	if !ok || id == nil {
+		return false
+	}
+	f.mu.Lock()
+	cancel, ok := f.cancels[fmt.Sprint(id)]
+	f.mu.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	"github.com/scopweb/mcp-go-github/pkg/git"
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+func TestInFlight_Cancel(t *testing.T) {
+	inFlight := NewInFlight()
+	ctx, done := inFlight.Start(float64(7))
+
+	if inFlight.Cancel(map[string]interface{}{"requestId": float64(8)}) || ctx.Err() != nil {
+		t.Fatal("cancelling another request should not touch this one")
+	}
+	if !inFlight.Cancel(map[string]interface{}{"requestId": float64(7)}) || ctx.Err() == nil {
+		t.Fatal("request 7 should be cancelled")
+	}
+	done()
+	if inFlight.Cancel(map[string]interface{}{"requestId": float64(7)}) {
+		t.Error("a finished request is no longer in flight")
+	}
+}
+
+func TestHandleRequest_CancelledContext(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	registry, err := git.NewWorkspaceRegistry("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := registry.Add("repo", initRepo(t)); err != nil {
+		t.Fatal(err)
+	}
+	client, err := registry.Client("repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &MCPServer{GitClient: client, GitAvailable: true}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	response := HandleRequest(ctx, s, types.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      float64(1),
+		Method:  "tools/call",
+		Params: map[string]interface{}{
+			"name":      "git_info",
+			"arguments": map[string]interface{}{"operation": "changed_files"},
+		},
+	})
+	result, _ := response.Result.(types.ToolCallResult)
+	if response.Error == nil && !result.IsError {
+		t.Errorf("changed_files with a cancelled context should fail: %+v", response)
+	}
+
+	response = HandleRequest(context.Background(), s, types.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      float64(2),
+		Method:  "tools/call",
+		Params: map[string]interface{}{
+			"name":      "git_info",
+			"arguments": map[string]interface{}{"operation": "changed_files"},
+		},
+	})
+	if response.Error != nil {
+		t.Errorf("the shared client should not stay cancelled: %v", response.Error.Message)
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"path/filepath"
 	"time"
 
 	"github.com/scopweb/mcp-go-github/pkg/config"
+	"github.com/scopweb/mcp-go-github/pkg/interfaces"
 	"github.com/scopweb/mcp-go-github/pkg/safety"
 	"github.com/scopweb/mcp-go-github/pkg/types"
 )
@@ -14,6 +16,7 @@ import (
 // SafetyMiddleware wraps tool execution with safety checks
 type SafetyMiddleware struct {
 	engine *safety.Engine
+	admin  interfaces.AdminOperations // reads state for backups and change diffs; nil disables capture
 }
 
 // NewSafetyMiddleware creates a new safety middleware instance
@@ -43,6 +46,19 @@ func (m *SafetyMiddleware) WrapExecution(
 	operation string,
 	parameters map[string]interface{},
 	executor func() (string, error),
+) (types.ToolCallResult, error) {
+	return m.WrapExecutionWithPreview(ctx, operation, parameters, nil, executor)
+}
+
+// WrapExecutionWithPreview behaves like WrapExecution, but when the safety check
+// stops at the dry-run gate it renders previewFunc (typically a fetch of the
+// target PR, issue or alert) instead of the bare check message.
+func (m *SafetyMiddleware) WrapExecutionWithPreview(
+	ctx context.Context,
+	operation string,
+	parameters map[string]interface{},
+	previewFunc func() (string, error),
+	executor func() (string, error),
 ) (types.ToolCallResult, error) {
 	startTime := time.Now()
 
@@ -52,8 +68,16 @@ func (m *SafetyMiddleware) WrapExecution(
 		return types.ToolCallResult{}, err
 	}
 
-	// If operation cannot proceed, return check message
+	// If operation cannot proceed, return check message (or the preview)
 	if !check.CanProceed {
+		if check.PolicyAction == safety.PolicyDeny {
+			if logErr := m.engine.LogCheckResult(check, parameters, "denied", nil, "", time.Since(startTime), nil); logErr != nil {
+				log.Printf("Warning: Failed to log operation: %v", logErr)
+			}
+		}
+		if check.DryRun && previewFunc != nil {
+			return m.HandleDryRun(operation, parameters, previewFunc)
+		}
 		return types.ToolCallResult{
 			Content: []types.Content{
 				{
@@ -64,15 +88,23 @@ func (m *SafetyMiddleware) WrapExecution(
 		}, nil
 	}
 
-	// Create backup before destructive operations
-	if check.RequiresBackup {
-		if backupPath, backupErr := m.engine.CreateBackup(operation, parameters); backupErr != nil {
-			log.Printf("Warning: backup failed for %s: %v", operation, backupErr)
-		} else if backupPath != "" {
-			log.Printf("Backup created: %s", backupPath)
+	// Capture the pre-state once: it feeds both the backup and the change diff
+	var before *safety.Snapshot
+	if m.admin != nil {
+		captured, err := captureSnapshot(ctx, m.admin, operation, parameters)
+		if err != nil {
+			log.Printf("Warning: could not capture pre-state for %s: %v", operation, err)
+		} else {
+			before = captured
 		}
 	}
 
+	var backupPath string
+	restorable := false
+	if check.RequiresBackup || safety.IsStatefulOperation(operation) {
+		backupPath, restorable = m.createBackup(operation, parameters, before)
+	}
+
 	// Execute the operation
 	result, execErr := executor()
 	executionTime := time.Since(startTime)
@@ -85,14 +117,21 @@ func (m *SafetyMiddleware) WrapExecution(
 
 	// Log operation result
 	rollbackCmd := safety.FormatRollbackCommand(operation, parameters)
-	// Note: structured per-field diff would be ideal here but requires pre/post
-	// snapshots from each handler. For now we log the human-readable result and
-	// leave changes empty rather than misrepresent the result text as a "change".
+	if restorable {
+		owner, _ := parameters["owner"].(string)
+		repo, _ := parameters["repo"].(string)
+		rollbackCmd = safety.FormatRestoreCommand(owner, repo, backupPath)
+	}
 	var changes []string
+	if execErr == nil && before != nil && before.Kind != "" {
+		changes, err = captureChanges(ctx, m.admin, before)
+		if err != nil {
+			log.Printf("Warning: could not read state after %s: %v", operation, err)
+		}
+	}
 
-	logErr := m.engine.LogOperationResult(
-		operation,
-		check.Risk,
+	logErr := m.engine.LogCheckResult(
+		check,
 		parameters,
 		resultStatus,
 		changes,
@@ -112,6 +151,15 @@ func (m *SafetyMiddleware) WrapExecution(
 
 	// Format success response with rollback instructions
 	responseText := result
+	if len(changes) > 0 {
+		responseText += "\n\n📝 Changes:"
+		for _, change := range changes {
+			responseText += "\n  " + change
+		}
+	}
+	if restorable {
+		responseText += fmt.Sprintf("\n\n💾 Pre-state backup: %s", filepath.Base(backupPath))
+	}
 	if check.Risk.Level >= safety.RiskHigh && rollbackCmd != "# No automatic rollback available" {
 		responseText += fmt.Sprintf("\n\n🔄 Rollback command:\n%s", rollbackCmd)
 	}
@@ -126,6 +174,27 @@ func (m *SafetyMiddleware) WrapExecution(
 	}, nil
 }
 
+// createBackup writes the captured pre-state to the backup directory. Without
+// a captured snapshot it falls back to a parameters-only backup; restorable
+// reports whether the backup can be re-applied.
+func (m *SafetyMiddleware) createBackup(operation string, parameters map[string]interface{}, snapshot *safety.Snapshot) (string, bool) {
+	if snapshot == nil {
+		snapshot = &safety.Snapshot{Operation: operation, Parameters: parameters}
+	}
+
+	backupPath, err := m.engine.CreateBackup(snapshot)
+	if err != nil {
+		log.Printf("Warning: backup failed for %s: %v", operation, err)
+		return "", false
+	}
+	if backupPath == "" {
+		return "", false
+	}
+	log.Printf("Backup created: %s (%s)", backupPath, backupSummary(snapshot))
+
+	return backupPath, snapshot.Kind != "" && safety.IsRestorable(operation)
+}
+
 // HandleDryRun processes dry-run requests
 func (m *SafetyMiddleware) HandleDryRun(
 	operation string,
@@ -167,6 +236,23 @@ To execute this operation:
 	}, nil
 }
 
+// SetAdminClient gives the middleware read access to GitHub for pre-state
+// backups, and lets the protected-branch guard consult GitHub branch protection
+// when protectedBranches.mirrorRemote is enabled.
+func (m *SafetyMiddleware) SetAdminClient(adminClient interfaces.AdminOperations) {
+	m.admin = adminClient
+	m.engine.SetRemoteProtectionLookup(func(ctx context.Context, owner, repo, branch string) (bool, error) {
+		_, err := adminClient.GetBranchProtection(ctx, owner, repo, branch)
+		if err == nil {
+			return true, nil
+		}
+		if isNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	})
+}
+
 // GetEngine returns the underlying safety engine
 func (m *SafetyMiddleware) GetEngine() *safety.Engine {
 	return m.engine
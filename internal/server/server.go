@@ -9,6 +9,7 @@ import (
 
 	"github.com/scopweb/mcp-go-github/internal/hybrid"
 	"github.com/scopweb/mcp-go-github/pkg/dashboard"
+	"github.com/scopweb/mcp-go-github/pkg/git"
 	"github.com/scopweb/mcp-go-github/pkg/interfaces"
 	"github.com/scopweb/mcp-go-github/pkg/types"
 )
@@ -29,10 +30,12 @@ type MCPServer struct {
 	GitAvailable    bool                       // v3.0: Whether git binary is installed
 	RawGitHubClient interface{}                // v3.0: Raw *github.Client for file operations
 	Toolsets        []string                   // Active toolsets filter (nil = all)
+	Workspaces      *git.WorkspaceRegistry     // Named workspaces selectable per call
 }
 
-// HandleRequest procesa las peticiones JSON-RPC del protocolo MCP
-func HandleRequest(s *MCPServer, req types.JSONRPCRequest) types.JSONRPCResponse {
+// HandleRequest procesa las peticiones JSON-RPC del protocolo MCP. Cancelar
+// ctx detiene los comandos git que lance la petición.
+func HandleRequest(ctx context.Context, s *MCPServer, req types.JSONRPCRequest) types.JSONRPCResponse {
 	id := req.ID
 	if id == nil {
 		id = 0
@@ -82,14 +85,14 @@ func HandleRequest(s *MCPServer, req types.JSONRPCRequest) types.JSONRPCResponse
 		// Notification — no response needed (handled in main.go)
 		response.Result = map[string]interface{}{}
 	case "notifications/cancelled":
-		// Notification — silently acknowledge cancellation
+		// Notification — the request is cancelled through its context (see InFlight)
 		response.Result = map[string]interface{}{}
 	case "ping":
 		response.Result = map[string]interface{}{}
 	case "tools/list":
 		response.Result = ListTools(s.GitAvailable, s.Toolsets)
 	case "tools/call":
-		result, err := CallTool(s, req.Params)
+		result, err := CallTool(ctx, s, req.Params)
 		if err != nil {
 			response.Error = &types.JSONRPCError{
 				Code:    -32603,
@@ -113,6 +116,75 @@ func isGitTool(name string) bool {
 	return strings.HasPrefix(name, "git_")
 }
 
+// acceptsWorkspace returns true if the tool can be routed to a named
+// workspace through its optional "workspace" argument
+func acceptsWorkspace(name string) bool {
+	if name == "git_workspaces" || name == "git_set_workspace" {
+		return false
+	}
+	return strings.HasPrefix(name, "git_") || strings.HasPrefix(name, "gh_")
+}
+
+// withWorkspaceArg adds the optional "workspace" argument to the tools that accept it
+func withWorkspaceArg(tools []types.Tool) []types.Tool {
+	for i := range tools {
+		if !acceptsWorkspace(tools[i].Name) {
+			continue
+		}
+		if tools[i].InputSchema.Properties == nil {
+			tools[i].InputSchema.Properties = make(map[string]types.Property)
+		}
+		tools[i].InputSchema.Properties["workspace"] = types.Property{
+			Type:        "string",
+			Description: "Named workspace to run in (see git_workspaces). Default: current workspace",
+		}
+	}
+	return tools
+}
+
+// forWorkspace returns a copy of the server whose Git client points at the
+// named workspace; the shared server and its current workspace are untouched
+func (s *MCPServer) forWorkspace(name string) (*MCPServer, error) {
+	if s.Workspaces == nil {
+		return nil, fmt.Errorf("workspace registry not configured")
+	}
+	client, err := s.Workspaces.Client(name)
+	if err != nil {
+		return nil, err
+	}
+	scoped := *s
+	scoped.GitClient = client
+	return &scoped, nil
+}
+
+// hasAnyArg returns true if any of the keys is present in arguments
+func hasAnyArg(arguments map[string]interface{}, keys ...string) bool {
+	for _, key := range keys {
+		if _, ok := arguments[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// wantsPatch returns true if a git_history diff call asks for full patches
+// rather than the name-status summary
+func wantsPatch(arguments map[string]interface{}) bool {
+	if patch, _ := arguments["patch"].(bool); patch {
+		return true
+	}
+	return hasAnyArg(arguments, "range", "paths", "cursor", "word_diff", "context", "page_size")
+}
+
+// wantsStructuredLog returns true if a git_history log call asks for
+// filtered, structured commits rather than the graph/shortlog analysis
+func wantsStructuredLog(arguments map[string]interface{}) bool {
+	if structured, _ := arguments["structured"].(bool); structured {
+		return true
+	}
+	return hasAnyArg(arguments, "range", "paths", "cursor", "author", "since", "until", "grep", "pickaxe", "pickaxe_regex", "first_parent")
+}
+
 // hasToolset returns true if the given toolset is active (nil = all active)
 func hasToolset(toolsets []string, name string) bool {
 	if len(toolsets) == 0 {
@@ -154,8 +226,11 @@ func ListTools(gitAvailable bool, toolsets []string) types.ToolsListResult {
 	// Administrative tools
 	if hasToolset(toolsets, "admin") {
 		allTools = append(allTools, ListAdminTools()...)
+		allTools = append(allTools, ListAuditTools()...)
 	}
 
+	allTools = withWorkspaceArg(allTools)
+
 	// Filter out Git tools if Git is not available
 	if !gitAvailable {
 		var filtered []types.Tool
@@ -171,7 +246,7 @@ func ListTools(gitAvailable bool, toolsets []string) types.ToolsListResult {
 }
 
 // CallTool ejecuta la herramienta solicitada
-func CallTool(s *MCPServer, params map[string]interface{}) (types.ToolCallResult, error) {
+func CallTool(ctx context.Context, s *MCPServer, params map[string]interface{}) (types.ToolCallResult, error) {
 	name, ok := params["name"].(string)
 	if !ok {
 		return types.ToolCallResult{}, fmt.Errorf("tool name required")
@@ -182,7 +257,6 @@ func CallTool(s *MCPServer, params map[string]interface{}) (types.ToolCallResult
 		arguments = make(map[string]interface{})
 	}
 
-	ctx := context.Background()
 	var text string
 	var err error
 
@@ -193,6 +267,22 @@ func CallTool(s *MCPServer, params map[string]interface{}) (types.ToolCallResult
 		}, nil
 	}
 
+	// Route the call to a named workspace when one is given
+	if workspace, _ := arguments["workspace"].(string); workspace != "" && acceptsWorkspace(name) {
+		scoped, wsErr := s.forWorkspace(workspace)
+		if wsErr != nil {
+			return types.ToolCallResult{}, wsErr
+		}
+		s = scoped
+	}
+
+	// Bind the Git client to the request so cancelling it stops its commands
+	if s.GitClient != nil {
+		scoped := *s
+		scoped.GitClient = s.GitClient.WithContext(ctx)
+		s = &scoped
+	}
+
 	switch name {
 	// =================================================================
 	// git_info (consolidated: status, file_sha, last_commit, file_content,
@@ -239,6 +329,8 @@ func CallTool(s *MCPServer, params map[string]interface{}) (types.ToolCallResult
 	case "git_set_workspace":
 		path, _ := arguments["path"].(string)
 		text, err = s.GitClient.SetWorkspace(path)
+	case "git_workspaces":
+		text, err = handleWorkspaces(s, arguments)
 
 	// =================================================================
 	// Individual Git tools (frequent workflow)
@@ -261,17 +353,89 @@ func CallTool(s *MCPServer, params map[string]interface{}) (types.ToolCallResult
 		operation, _ := arguments["operation"].(string)
 		switch operation {
 		case "log":
-			limit, _ := arguments["limit"].(string)
-			text, err = s.GitClient.LogAnalysis(limit)
+			if !wantsStructuredLog(arguments) {
+				limit, _ := arguments["limit"].(string)
+				text, err = s.GitClient.LogAnalysis(limit)
+				break
+			}
+			opts := types.LogOptions{Paths: getListArg(arguments, "paths")}
+			opts.Range, _ = arguments["range"].(string)
+			opts.Author, _ = arguments["author"].(string)
+			opts.Since, _ = arguments["since"].(string)
+			opts.Until, _ = arguments["until"].(string)
+			opts.Grep, _ = arguments["grep"].(string)
+			opts.PickaxeString, _ = arguments["pickaxe"].(string)
+			opts.PickaxeRegex, _ = arguments["pickaxe_regex"].(string)
+			opts.FirstParent, _ = arguments["first_parent"].(bool)
+			opts.Cursor, _ = arguments["cursor"].(string)
+			if _, ok := arguments["limit"]; ok {
+				if opts.Limit, err = getIntArg(arguments, "limit"); err != nil {
+					return types.ToolCallResult{}, err
+				}
+			}
+			text, err = s.GitClient.LogQuery(opts)
 		case "diff":
 			staged, _ := arguments["staged"].(bool)
-			text, err = s.GitClient.DiffFiles(staged)
+			if !wantsPatch(arguments) {
+				text, err = s.GitClient.DiffFiles(staged)
+				break
+			}
+			opts := types.DiffOptions{Staged: staged, Context: -1, Paths: getListArg(arguments, "paths")}
+			opts.Range, _ = arguments["range"].(string)
+			opts.WordDiff, _ = arguments["word_diff"].(bool)
+			opts.Cursor, _ = arguments["cursor"].(string)
+			if _, ok := arguments["context"]; ok {
+				if opts.Context, err = getIntArg(arguments, "context"); err != nil {
+					return types.ToolCallResult{}, err
+				}
+			}
+			if _, ok := arguments["page_size"]; ok {
+				if opts.PageSize, err = getIntArg(arguments, "page_size"); err != nil {
+					return types.ToolCallResult{}, err
+				}
+			}
+			text, err = s.GitClient.DiffPatch(opts)
+		case "blame":
+			opts := types.BlameOptions{}
+			opts.Path, _ = arguments["path"].(string)
+			opts.Ref, _ = arguments["ref"].(string)
+			opts.IgnoreWhitespace, _ = arguments["ignore_whitespace"].(bool)
+			opts.DetectMoves, _ = arguments["detect_moves"].(bool)
+			opts.DetectCopies, _ = arguments["detect_copies"].(bool)
+			if _, ok := arguments["start_line"]; ok {
+				if opts.StartLine, err = getIntArg(arguments, "start_line"); err != nil {
+					return types.ToolCallResult{}, err
+				}
+			}
+			if _, ok := arguments["end_line"]; ok {
+				if opts.EndLine, err = getIntArg(arguments, "end_line"); err != nil {
+					return types.ToolCallResult{}, err
+				}
+			}
+			text, err = s.GitClient.Blame(opts)
+		case "show":
+			opts := types.ShowOptions{Context: -1, Paths: getListArg(arguments, "paths")}
+			opts.Ref, _ = arguments["ref"].(string)
+			opts.WordDiff, _ = arguments["word_diff"].(bool)
+			opts.Cursor, _ = arguments["cursor"].(string)
+			if _, ok := arguments["context"]; ok {
+				if opts.Context, err = getIntArg(arguments, "context"); err != nil {
+					return types.ToolCallResult{}, err
+				}
+			}
+			if _, ok := arguments["page_size"]; ok {
+				if opts.PageSize, err = getIntArg(arguments, "page_size"); err != nil {
+					return types.ToolCallResult{}, err
+				}
+			}
+			text, err = s.GitClient.Show(opts)
 		default:
 			return types.ToolCallResult{}, fmt.Errorf("unknown operation '%s' for git_history", operation)
 		}
 
 	// =================================================================
-	// git_branch (consolidated: checkout, checkout_remote, list, merge, rebase, backup)
+	// git_branch (consolidated: checkout, checkout_remote, list, merge, rebase, rebase_interactive, rebase_continue/skip/abort,
+	// cherry_pick, cherry_pick_continue/skip/abort, revert, revert_continue/skip/abort, backup)
 	// =================================================================
 	case "git_branch":
 		operation, _ := arguments["operation"].(string)
@@ -300,10 +464,63 @@ func CallTool(s *MCPServer, params map[string]interface{}) (types.ToolCallResult
 		case "merge":
 			sourceBranch, _ := arguments["source_branch"].(string)
 			targetBranch, _ := arguments["target_branch"].(string)
-			text, err = s.GitClient.Merge(sourceBranch, targetBranch)
+			if dryRun, _ := arguments["dry_run"].(bool); dryRun {
+				text, err = s.GitClient.PreviewMerge(sourceBranch, targetBranch)
+				break
+			}
+			return wrapGitWrite(s, ctx, "git_branch:merge", arguments, targetBranch, func() (string, error) {
+				return s.GitClient.Merge(sourceBranch, targetBranch)
+			})
 		case "rebase":
 			branch, _ := arguments["branch"].(string)
+			if dryRun, _ := arguments["dry_run"].(bool); dryRun {
+				text, err = s.GitClient.PreviewRebase(branch)
+				break
+			}
 			text, err = s.GitClient.Rebase(branch)
+		case "rebase_interactive":
+			onto, _ := arguments["branch"].(string)
+			var steps []types.RebaseStep
+			if found, todoErr := getJSONArg(arguments, "todo", &steps); todoErr != nil {
+				return types.ToolCallResult{}, todoErr
+			} else if !found {
+				return types.ToolCallResult{}, fmt.Errorf("required parameter 'todo' is missing")
+			}
+			// Rewrites the current branch, which the guard resolves when branch is
+			// empty; the "branch" argument here is the upstream to rebase onto
+			return wrapGitWrite(s, ctx, "git_branch:rebase_interactive", arguments, "", func() (string, error) {
+				return s.GitClient.RebaseInteractive(onto, steps)
+			})
+		case "rebase_continue", "rebase_skip", "rebase_abort":
+			text, err = s.GitClient.RebaseControl(strings.TrimPrefix(operation, "rebase_"))
+		case "cherry_pick":
+			opts := types.CherryPickOptions{}
+			opts.Commits, _ = arguments["commits"].(string)
+			opts.RecordOrigin, _ = arguments["record_origin"].(bool)
+			if _, ok := arguments["mainline"]; ok {
+				if opts.Mainline, err = getIntArg(arguments, "mainline"); err != nil {
+					return types.ToolCallResult{}, err
+				}
+			}
+			// Writes to the current branch, which the guard resolves when branch is empty
+			return wrapGitWrite(s, ctx, "git_branch:cherry_pick", arguments, "", func() (string, error) {
+				return s.GitClient.CherryPick(opts)
+			})
+		case "cherry_pick_continue", "cherry_pick_skip", "cherry_pick_abort":
+			text, err = s.GitClient.CherryPickControl(strings.TrimPrefix(operation, "cherry_pick_"))
+		case "revert":
+			opts := types.RevertOptions{}
+			opts.Commits, _ = arguments["commits"].(string)
+			if _, ok := arguments["mainline"]; ok {
+				if opts.Mainline, err = getIntArg(arguments, "mainline"); err != nil {
+					return types.ToolCallResult{}, err
+				}
+			}
+			return wrapGitWrite(s, ctx, "git_branch:revert", arguments, "", func() (string, error) {
+				return s.GitClient.Revert(opts)
+			})
+		case "revert_continue", "revert_skip", "revert_abort":
+			text, err = s.GitClient.RevertControl(strings.TrimPrefix(operation, "revert_"))
 		case "backup":
 			backupName, _ := arguments["name"].(string)
 			text, err = s.GitClient.CreateBackup(backupName)
@@ -319,17 +536,23 @@ func CallTool(s *MCPServer, params map[string]interface{}) (types.ToolCallResult
 		switch operation {
 		case "push":
 			branch, _ := arguments["branch"].(string)
-			text, err = s.GitClient.Push(branch)
+			return wrapGitWrite(s, ctx, "git_sync:push", arguments, branch, func() (string, error) {
+				return s.GitClient.Push(branch)
+			})
 		case "pull":
 			branch, _ := arguments["branch"].(string)
 			text, err = s.GitClient.Pull(branch)
 		case "force_push":
 			branch, _ := arguments["branch"].(string)
 			force, _ := arguments["force"].(bool)
-			text, err = s.GitClient.ForcePush(branch, force)
+			return wrapGitWrite(s, ctx, "git_sync:force_push", arguments, branch, func() (string, error) {
+				return s.GitClient.ForcePush(branch, force)
+			})
 		case "push_upstream":
 			branch, _ := arguments["branch"].(string)
-			text, err = s.GitClient.PushUpstream(branch)
+			return wrapGitWrite(s, ctx, "git_sync:push_upstream", arguments, branch, func() (string, error) {
+				return s.GitClient.PushUpstream(branch)
+			})
 		case "sync":
 			remoteBranch, _ := arguments["remote_branch"].(string)
 			text, err = s.GitClient.SyncWithRemote(remoteBranch)
@@ -366,7 +589,13 @@ func CallTool(s *MCPServer, params map[string]interface{}) (types.ToolCallResult
 		case "safe_merge":
 			source, _ := arguments["source"].(string)
 			target, _ := arguments["target"].(string)
-			text, err = s.GitClient.SafeMerge(source, target)
+			if dryRun, _ := arguments["dry_run"].(bool); dryRun {
+				text, err = s.GitClient.PreviewMerge(source, target)
+				break
+			}
+			return wrapGitWrite(s, ctx, "git_conflict:safe_merge", arguments, target, func() (string, error) {
+				return s.GitClient.SafeMerge(source, target)
+			})
 		default:
 			return types.ToolCallResult{}, fmt.Errorf("unknown operation '%s' for git_conflict", operation)
 		}
@@ -418,7 +647,9 @@ func CallTool(s *MCPServer, params map[string]interface{}) (types.ToolCallResult
 	case "gh_update_file":
 		text, err = hybrid.SmartUpdateFile(s.GitClient, s.GithubClient, arguments)
 	case "gh_push_files":
-		text, err = hybrid.PushFiles(s.GitClient, arguments)
+		return wrapGitWrite(s, ctx, "gh_push_files", arguments, hybrid.PushBranch(s.GitClient, arguments), func() (string, error) {
+			return hybrid.PushFiles(s.GitClient, arguments)
+		})
 
 	// =================================================================
 	// github_repo (consolidated: list_repos, create_repo, list_prs, create_pr)
@@ -631,170 +862,10 @@ func CallTool(s *MCPServer, params map[string]interface{}) (types.ToolCallResult
 		}
 
 	// =================================================================
-	// github_respond (consolidated: comment_issue, comment_pr, review_pr)
-	// =================================================================
-	case "github_respond":
-		operation, _ := arguments["operation"].(string)
-		switch operation {
-		case "comment_issue":
-			owner, _ := arguments["owner"].(string)
-			repo, _ := arguments["repo"].(string)
-			number, numErr := getIntArg(arguments, "number")
-			if numErr != nil {
-				return types.ToolCallResult{}, numErr
-			}
-			body, _ := arguments["body"].(string)
-			comment, commentErr := s.GithubClient.CreateIssueComment(ctx, owner, repo, number, body)
-			if commentErr != nil {
-				err = commentErr
-			} else {
-				text = fmt.Sprintf("Comment added to issue #%d\n%s", number, comment.GetHTMLURL())
-			}
-		case "comment_pr":
-			owner, _ := arguments["owner"].(string)
-			repo, _ := arguments["repo"].(string)
-			number, numErr := getIntArg(arguments, "number")
-			if numErr != nil {
-				return types.ToolCallResult{}, numErr
-			}
-			body, _ := arguments["body"].(string)
-			comment, commentErr := s.GithubClient.CreatePRComment(ctx, owner, repo, number, body)
-			if commentErr != nil {
-				err = commentErr
-			} else {
-				text = fmt.Sprintf("Comment added to PR #%d\n%s", number, comment.GetHTMLURL())
-			}
-		case "review_pr":
-			owner, _ := arguments["owner"].(string)
-			repo, _ := arguments["repo"].(string)
-			number, numErr := getIntArg(arguments, "number")
-			if numErr != nil {
-				return types.ToolCallResult{}, numErr
-			}
-			event, _ := arguments["event"].(string)
-			body, _ := arguments["body"].(string)
-			review, reviewErr := s.GithubClient.CreatePRReview(ctx, owner, repo, number, event, body)
-			if reviewErr != nil {
-				err = reviewErr
-			} else {
-				var eventLabel string
-				switch event {
-				case "APPROVE":
-					eventLabel = "Approved"
-				case "REQUEST_CHANGES":
-					eventLabel = "Changes requested"
-				default:
-					eventLabel = "Comment"
-				}
-				text = fmt.Sprintf("%s PR #%d\n%s", eventLabel, number, review.GetHTMLURL())
-			}
-		default:
-			return types.ToolCallResult{}, fmt.Errorf("unknown operation '%s' for github_respond", operation)
-		}
-
-	// =================================================================
-	// github_repair (consolidated: close_issue, merge_pr, rerun_workflow, dismiss_alert)
+	// github_respond / github_repair (routed through safety middleware)
 	// =================================================================
-	case "github_repair":
-		operation, _ := arguments["operation"].(string)
-		switch operation {
-		case "close_issue":
-			owner, _ := arguments["owner"].(string)
-			repo, _ := arguments["repo"].(string)
-			number, numErr := getIntArg(arguments, "number")
-			if numErr != nil {
-				return types.ToolCallResult{}, numErr
-			}
-			comment, _ := arguments["comment"].(string)
-			issue, closeErr := s.GithubClient.CloseIssue(ctx, owner, repo, number, comment)
-			if closeErr != nil {
-				err = closeErr
-			} else {
-				text = fmt.Sprintf("Issue #%d closed\n%s", number, issue.GetHTMLURL())
-			}
-		case "merge_pr":
-			owner, _ := arguments["owner"].(string)
-			repo, _ := arguments["repo"].(string)
-			number, numErr := getIntArg(arguments, "number")
-			if numErr != nil {
-				return types.ToolCallResult{}, numErr
-			}
-			commitMessage, _ := arguments["commit_message"].(string)
-			mergeMethod, _ := arguments["merge_method"].(string)
-			if mergeMethod == "" {
-				mergeMethod = "merge"
-			}
-			result, mergeErr := s.GithubClient.MergePullRequest(ctx, owner, repo, number, commitMessage, mergeMethod)
-			if mergeErr != nil {
-				err = mergeErr
-			} else {
-				text = fmt.Sprintf("PR #%d merged successfully\nMerged: %v\nSHA: %s",
-					number, result.GetMerged(), result.GetSHA())
-			}
-		case "rerun_workflow":
-			owner, _ := arguments["owner"].(string)
-			repo, _ := arguments["repo"].(string)
-			runID, runErr := getInt64Arg(arguments, "run_id")
-			if runErr != nil {
-				return types.ToolCallResult{}, runErr
-			}
-			failedOnly, _ := arguments["failed_jobs_only"].(bool)
-			if failedOnly {
-				err = s.GithubClient.RerunFailedJobs(ctx, owner, repo, runID)
-				text = fmt.Sprintf("Re-running failed jobs for workflow run %d", runID)
-			} else {
-				err = s.GithubClient.RerunWorkflow(ctx, owner, repo, runID)
-				text = fmt.Sprintf("Re-running full workflow run %d", runID)
-			}
-		case "dismiss_alert":
-			owner, _ := arguments["owner"].(string)
-			repo, _ := arguments["repo"].(string)
-			alertType, _ := arguments["alert_type"].(string)
-			switch alertType {
-			case "dependabot":
-				number, numErr := getIntArg(arguments, "number")
-				if numErr != nil {
-					return types.ToolCallResult{}, numErr
-				}
-				reason, _ := arguments["reason"].(string)
-				comment, _ := arguments["comment"].(string)
-				alert, dismissErr := s.GithubClient.DismissDependabotAlert(ctx, owner, repo, number, reason, comment)
-				if dismissErr != nil {
-					err = dismissErr
-				} else {
-					text = fmt.Sprintf("Dependabot alert #%d dismissed (reason: %s)\n%s", number, reason, alert.GetHTMLURL())
-				}
-			case "code":
-				number, numErr := getInt64Arg(arguments, "number")
-				if numErr != nil {
-					return types.ToolCallResult{}, numErr
-				}
-				reason, _ := arguments["reason"].(string)
-				comment, _ := arguments["comment"].(string)
-				alert, dismissErr := s.GithubClient.DismissCodeScanningAlert(ctx, owner, repo, number, reason, comment)
-				if dismissErr != nil {
-					err = dismissErr
-				} else {
-					text = fmt.Sprintf("Code scanning alert #%d dismissed (reason: %s)\n%s", number, reason, alert.GetHTMLURL())
-				}
-			case "secret":
-				number, numErr := getInt64Arg(arguments, "number")
-				if numErr != nil {
-					return types.ToolCallResult{}, numErr
-				}
-				resolution, _ := arguments["resolution"].(string)
-				alert, dismissErr := s.GithubClient.DismissSecretScanningAlert(ctx, owner, repo, number, resolution)
-				if dismissErr != nil {
-					err = dismissErr
-				} else {
-					text = fmt.Sprintf("Secret scanning alert #%d resolved (%s)\n%s", number, resolution, alert.GetHTMLURL())
-				}
-			default:
-				return types.ToolCallResult{}, fmt.Errorf("unknown alert_type '%s' for github_repair dismiss_alert (use: dependabot, code, secret)", alertType)
-			}
-		default:
-			return types.ToolCallResult{}, fmt.Errorf("unknown operation '%s' for github_repair", operation)
-		}
+	case "github_respond", "github_repair":
+		return HandleRepairTool(s, name, arguments)
 
 	// =================================================================
 	// Administrative tools (v3.0)
@@ -802,6 +873,12 @@ func CallTool(s *MCPServer, params map[string]interface{}) (types.ToolCallResult
 	case "github_admin_repo", "github_branch_protection", "github_webhooks", "github_collaborators":
 		return HandleAdminTool(s, name, arguments)
 
+	// =================================================================
+	// Audit log (read-only)
+	// =================================================================
+	case "github_audit":
+		return HandleAuditTool(s, arguments)
+
 	// =================================================================
 	// File operations (v3.0 - work without Git)
 	// =================================================================
@@ -0,0 +1,448 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v81/github"
+	"github.com/scopweb/mcp-go-github/pkg/interfaces"
+	"github.com/scopweb/mcp-go-github/pkg/safety"
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+// captureSnapshot reads the current GitHub state an operation is about to change.
+// Returns a parameters-only snapshot for operations whose state cannot be read.
+func captureSnapshot(ctx context.Context, admin interfaces.AdminOperations, operation string, params map[string]interface{}) (*safety.Snapshot, error) {
+	owner, _ := params["owner"].(string)
+	repo, _ := params["repo"].(string)
+
+	snapshot := &safety.Snapshot{
+		Operation:  operation,
+		Kind:       safety.DiffKind(operation),
+		Owner:      owner,
+		Repo:       repo,
+		Parameters: params,
+	}
+
+	switch snapshot.Kind {
+	case "":
+		return snapshot, nil
+	case safety.SnapshotBranchProtection:
+		snapshot.Target, _ = params["branch"].(string)
+	case safety.SnapshotWebhook:
+		hookID, err := getInt64Arg(params, "hook_id")
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Target = strconv.FormatInt(hookID, 10)
+	case safety.SnapshotCollaborator:
+		snapshot.Target, _ = params["username"].(string)
+	}
+
+	exists, state, err := captureState(ctx, admin, snapshot.Kind, owner, repo, snapshot.Target)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Exists = exists
+	snapshot.State = state
+	return snapshot, nil
+}
+
+// captureChanges reads the state back after an operation and lists the fields
+// it changed. A deleted repository reads back as absent.
+func captureChanges(ctx context.Context, admin interfaces.AdminOperations, before *safety.Snapshot) ([]string, error) {
+	exists, after, err := captureState(ctx, admin, before.Kind, before.Owner, before.Repo, before.Target)
+	if err != nil {
+		if !isNotFound(err) {
+			return nil, err
+		}
+		exists, after = false, nil
+	}
+	return safety.StateChanges(before, exists, after), nil
+}
+
+// captureState reads one piece of state. State keys match the arguments of the
+// corresponding update operation so a restore can feed them back unchanged.
+func captureState(ctx context.Context, admin interfaces.AdminOperations, kind, owner, repo, target string) (bool, map[string]interface{}, error) {
+	switch kind {
+	case safety.SnapshotRepoSettings:
+		repository, err := admin.GetRepositorySettings(ctx, owner, repo)
+		if err != nil {
+			return false, nil, fmt.Errorf("failed to read repository settings: %w", err)
+		}
+		return true, repoSettingsState(repository), nil
+
+	case safety.SnapshotBranchProtection:
+		protection, err := admin.GetBranchProtection(ctx, owner, repo, target)
+		if err != nil {
+			if isNotFound(err) {
+				return false, nil, nil
+			}
+			return false, nil, fmt.Errorf("failed to read branch protection: %w", err)
+		}
+		return true, protectionState(protection), nil
+
+	case safety.SnapshotWebhook:
+		hookID, err := strconv.ParseInt(target, 10, 64)
+		if err != nil {
+			return false, nil, fmt.Errorf("invalid webhook id %q", target)
+		}
+		hook, err := admin.GetWebhook(ctx, owner, repo, hookID)
+		if err != nil {
+			if isNotFound(err) {
+				return false, nil, nil
+			}
+			return false, nil, fmt.Errorf("failed to read webhook: %w", err)
+		}
+		return true, webhookState(hook), nil
+
+	case safety.SnapshotCollaborator:
+		permission, err := admin.GetCollaboratorPermission(ctx, owner, repo, target)
+		if err != nil {
+			if isNotFound(err) {
+				return false, nil, nil
+			}
+			return false, nil, fmt.Errorf("failed to read collaborator permission: %w", err)
+		}
+		if permission == "" || permission == "none" {
+			return false, nil, nil
+		}
+		return true, map[string]interface{}{"permission": collaboratorPermission(permission)}, nil
+
+	default:
+		return false, nil, fmt.Errorf("unknown snapshot kind: %s", kind)
+	}
+}
+
+func repoSettingsState(r *github.Repository) map[string]interface{} {
+	return map[string]interface{}{
+		"name":                   r.GetName(),
+		"description":            r.GetDescription(),
+		"homepage":               r.GetHomepage(),
+		"private":                r.GetPrivate(),
+		"has_issues":             r.GetHasIssues(),
+		"has_wiki":               r.GetHasWiki(),
+		"has_projects":           r.GetHasProjects(),
+		"default_branch":         r.GetDefaultBranch(),
+		"allow_squash_merge":     r.GetAllowSquashMerge(),
+		"allow_merge_commit":     r.GetAllowMergeCommit(),
+		"allow_rebase_merge":     r.GetAllowRebaseMerge(),
+		"delete_branch_on_merge": r.GetDeleteBranchOnMerge(),
+		"archived":               r.GetArchived(),
+	}
+}
+
+func protectionState(p *github.Protection) map[string]interface{} {
+	state := map[string]interface{}{
+		"require_pull_request_reviews": p.RequiredPullRequestReviews != nil,
+		"require_status_checks":        p.RequiredStatusChecks != nil,
+		"enforce_admins":               p.EnforceAdmins != nil && p.EnforceAdmins.Enabled,
+		"required_linear_history":      p.RequireLinearHistory != nil && p.RequireLinearHistory.Enabled,
+		"allow_force_pushes":           p.AllowForcePushes != nil && p.AllowForcePushes.Enabled,
+		"allow_deletions":              p.AllowDeletions != nil && p.AllowDeletions.Enabled,
+	}
+
+	if reviews := p.RequiredPullRequestReviews; reviews != nil {
+		state["required_approving_review_count"] = reviews.RequiredApprovingReviewCount
+		state["dismiss_stale_reviews"] = reviews.DismissStaleReviews
+		state["require_code_owner_reviews"] = reviews.RequireCodeOwnerReviews
+	}
+
+	if checks := p.RequiredStatusChecks; checks != nil {
+		state["strict_status_checks"] = checks.Strict
+		contexts := []string{}
+		if checks.Contexts != nil {
+			contexts = append(contexts, *checks.Contexts...)
+		}
+		sort.Strings(contexts)
+		state["required_status_checks"] = contexts
+	}
+
+	if restrictions := p.Restrictions; restrictions != nil {
+		users := []string{}
+		for _, u := range restrictions.Users {
+			users = append(users, u.GetLogin())
+		}
+		teams := []string{}
+		for _, t := range restrictions.Teams {
+			teams = append(teams, t.GetSlug())
+		}
+		state["restrictions"] = map[string]interface{}{"users": users, "teams": teams}
+	}
+
+	return state
+}
+
+// webhookState captures the webhook configuration. The secret is never stored:
+// GitHub only returns it masked, and a backup file must not hold credentials.
+func webhookState(h *github.Hook) map[string]interface{} {
+	state := map[string]interface{}{
+		"active": h.GetActive(),
+		"events": append([]string{}, h.Events...),
+	}
+	if cfg := h.Config; cfg != nil {
+		state["url"] = cfg.GetURL()
+		state["content_type"] = cfg.GetContentType()
+		state["insecure_ssl"] = cfg.GetInsecureSSL()
+		state["secret_configured"] = cfg.GetSecret() != ""
+	}
+	return state
+}
+
+// collaboratorPermission maps a role name from the permission API to the value
+// accepted when adding a collaborator
+func collaboratorPermission(role string) string {
+	switch role {
+	case "read":
+		return "pull"
+	case "write":
+		return "push"
+	default:
+		return role
+	}
+}
+
+func isNotFound(err error) bool {
+	if errors.Is(err, github.ErrBranchNotProtected) {
+		return true
+	}
+	var ghErr *github.ErrorResponse
+	return errors.As(err, &ghErr) && ghErr.Response != nil && ghErr.Response.StatusCode == http.StatusNotFound
+}
+
+// ============================================================================
+// Restore
+// ============================================================================
+
+// handleRestoreBackup re-applies a pre-state backup. The dry-run preview shows
+// the diff between the current state and the backup.
+func handleRestoreBackup(s *MCPServer, ctx context.Context, args map[string]interface{}) (types.ToolCallResult, error) {
+	owner, _ := args["owner"].(string)
+	repo, _ := args["repo"].(string)
+	name, _ := args["backup"].(string)
+
+	engine := s.Safety.GetEngine()
+	if name == "" {
+		names, err := engine.ListBackups()
+		if err != nil {
+			return types.ToolCallResult{}, err
+		}
+		text := "Parameter 'backup' required. Available backups (newest first):\n"
+		if len(names) == 0 {
+			text += "  (none)\n"
+		}
+		for _, n := range names {
+			text += fmt.Sprintf("  - %s\n", n)
+		}
+		return types.ToolCallResult{Content: []types.Content{{Type: "text", Text: text}}, IsError: true}, nil
+	}
+
+	snapshot, err := engine.LoadBackup(name)
+	if err != nil {
+		return types.ToolCallResult{}, err
+	}
+	if snapshot.Owner != owner || snapshot.Repo != repo {
+		return types.ToolCallResult{}, fmt.Errorf("backup %s belongs to %s/%s, not %s/%s", name, snapshot.Owner, snapshot.Repo, owner, repo)
+	}
+	if !safety.IsRestorable(snapshot.Operation) || snapshot.Kind == "" {
+		return types.ToolCallResult{}, fmt.Errorf("backup %s (%s) cannot be restored automatically", name, snapshot.Operation)
+	}
+	// A backup file is local input: re-check a webhook URL before recreating it
+	if url, ok := snapshot.State["url"].(string); ok && snapshot.Kind == safety.SnapshotWebhook {
+		if err := safety.CheckWebhookURL(url, engine.GetConfig().WebhookAllowlist); err != nil {
+			return types.ToolCallResult{}, fmt.Errorf("backup %s: webhook url %s: %w", name, url, err)
+		}
+	}
+
+	preview := func() (string, error) {
+		return restorePreview(ctx, s.AdminClient, snapshot)
+	}
+
+	return s.Safety.WrapExecutionWithPreview(ctx, "github_admin_repo:restore", args, preview, func() (string, error) {
+		return restoreSnapshot(ctx, s.AdminClient, snapshot)
+	})
+}
+
+// restorePreview describes what restoring the snapshot would change
+func restorePreview(ctx context.Context, admin interfaces.AdminOperations, snapshot *safety.Snapshot) (string, error) {
+	exists, current, err := captureState(ctx, admin, snapshot.Kind, snapshot.Owner, snapshot.Repo, snapshot.Target)
+	if err != nil {
+		return "", err
+	}
+
+	text := fmt.Sprintf("Restore %s of %s/%s", snapshot.Kind, snapshot.Owner, snapshot.Repo)
+	if snapshot.Target != "" {
+		text += fmt.Sprintf(" (%s)", snapshot.Target)
+	}
+	text += fmt.Sprintf("\nCaptured before %s at %s\n\n", snapshot.Operation, snapshot.Timestamp.Format("2006-01-02 15:04:05 MST"))
+
+	switch {
+	case !snapshot.Exists && !exists:
+		text += "No changes: the state is absent now and was absent in the backup."
+		return text, nil
+	case !snapshot.Exists:
+		text += fmt.Sprintf("Will remove the current %s (it did not exist when the backup was taken).", snapshot.Kind)
+		return text, nil
+	case !exists:
+		text += fmt.Sprintf("Will recreate the %s from the backup.\n", snapshot.Kind)
+	}
+
+	diff := safety.DiffState(current, restorableState(snapshot))
+	if len(diff) == 0 {
+		text += "No changes: current state matches the backup."
+	} else {
+		text += "Changes (current → backup):\n"
+		for _, line := range diff {
+			text += "  " + line + "\n"
+		}
+	}
+	if snapshot.Kind == safety.SnapshotWebhook && snapshot.State["secret_configured"] == true {
+		text += "\n⚠️  The webhook secret is not stored in backups; set it again after restoring."
+	}
+	return text, nil
+}
+
+// restorableState is the snapshot state without informational keys
+func restorableState(snapshot *safety.Snapshot) map[string]interface{} {
+	state := make(map[string]interface{}, len(snapshot.State))
+	for k, v := range snapshot.State {
+		if k == "secret_configured" {
+			continue
+		}
+		state[k] = v
+	}
+	return state
+}
+
+// restoreSnapshot re-applies the snapshot through the admin client
+func restoreSnapshot(ctx context.Context, admin interfaces.AdminOperations, snapshot *safety.Snapshot) (string, error) {
+	owner, repo, target := snapshot.Owner, snapshot.Repo, snapshot.Target
+	state := restorableState(snapshot)
+
+	switch snapshot.Kind {
+	case safety.SnapshotRepoSettings:
+		current, err := admin.GetRepositorySettings(ctx, owner, repo)
+		if err != nil {
+			return "", fmt.Errorf("failed to read repository settings: %w", err)
+		}
+		// Archived repositories are read-only: unarchive before editing
+		if current.GetArchived() && state["archived"] == false {
+			if _, err := admin.UnarchiveRepository(ctx, owner, repo); err != nil {
+				return "", fmt.Errorf("failed to unarchive repository: %w", err)
+			}
+		}
+		settings := make(map[string]interface{}, len(state))
+		for k, v := range state {
+			if k != "archived" {
+				settings[k] = v
+			}
+		}
+		if _, err := admin.UpdateRepositorySettings(ctx, owner, repo, settings); err != nil {
+			return "", err
+		}
+		if !current.GetArchived() && state["archived"] == true {
+			if _, err := admin.ArchiveRepository(ctx, owner, repo); err != nil {
+				return "", fmt.Errorf("failed to re-archive repository: %w", err)
+			}
+		}
+		return fmt.Sprintf("♻️  Restored repository settings for %s/%s", owner, repo), nil
+
+	case safety.SnapshotBranchProtection:
+		if !snapshot.Exists {
+			if err := admin.DeleteBranchProtection(ctx, owner, repo, target); err != nil && !isNotFound(err) {
+				return "", err
+			}
+			return fmt.Sprintf("♻️  Removed branch protection from %s/%s @ %s (the branch was unprotected in the backup)", owner, repo, target), nil
+		}
+		if _, err := admin.UpdateBranchProtection(ctx, owner, repo, target, buildProtectionRequest(state)); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("♻️  Restored branch protection for %s/%s @ %s", owner, repo, target), nil
+
+	case safety.SnapshotWebhook:
+		hookID, err := strconv.ParseInt(target, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid webhook id %q", target)
+		}
+		config := webhookConfig(state)
+		if _, err := admin.GetWebhook(ctx, owner, repo, hookID); err != nil {
+			if !isNotFound(err) {
+				return "", err
+			}
+			hook, err := admin.CreateWebhook(ctx, owner, repo, config)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("♻️  Recreated webhook for %s/%s (new ID: %d, was %d)%s", owner, repo, hook.GetID(), hookID, secretReminder(snapshot)), nil
+		}
+		if _, err := admin.UpdateWebhook(ctx, owner, repo, hookID, config); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("♻️  Restored webhook %d for %s/%s%s", hookID, owner, repo, secretReminder(snapshot)), nil
+
+	case safety.SnapshotCollaborator:
+		if !snapshot.Exists {
+			if err := admin.RemoveCollaborator(ctx, owner, repo, target); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("♻️  Removed @%s from %s/%s (no access in the backup)", target, owner, repo), nil
+		}
+		permission, _ := state["permission"].(string)
+		invitation, err := admin.AddCollaborator(ctx, owner, repo, target, permission)
+		if err != nil {
+			return "", err
+		}
+		text := fmt.Sprintf("♻️  Restored @%s on %s/%s with '%s' permission", target, owner, repo, permission)
+		if invitation != nil && invitation.ID != nil {
+			text += fmt.Sprintf("\nA new invitation was sent (ID: %d); access returns once it is accepted.", invitation.GetID())
+		}
+		return text, nil
+
+	default:
+		return "", fmt.Errorf("unknown snapshot kind: %s", snapshot.Kind)
+	}
+}
+
+// webhookConfig converts a webhook snapshot to the config map the admin client expects
+func webhookConfig(state map[string]interface{}) map[string]interface{} {
+	config := make(map[string]interface{})
+	for _, key := range []string{"url", "content_type", "insecure_ssl", "active"} {
+		if v, ok := state[key]; ok {
+			config[key] = v
+		}
+	}
+	if raw, ok := state["events"].([]interface{}); ok {
+		events := make([]string, 0, len(raw))
+		for _, e := range raw {
+			if str, ok := e.(string); ok {
+				events = append(events, str)
+			}
+		}
+		config["events"] = events
+	}
+	return config
+}
+
+func secretReminder(snapshot *safety.Snapshot) string {
+	if snapshot.State["secret_configured"] == true {
+		return "\n⚠️  The webhook secret was not restored; set it again with github_webhooks update."
+	}
+	return ""
+}
+
+// backupSummary is the one-line description of a snapshot shown after an operation
+func backupSummary(snapshot *safety.Snapshot) string {
+	parts := []string{snapshot.Kind}
+	if snapshot.Target != "" {
+		parts = append(parts, snapshot.Target)
+	}
+	if !snapshot.Exists {
+		parts = append(parts, "absent")
+	}
+	return strings.Join(parts, " ")
+}
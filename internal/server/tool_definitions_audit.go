@@ -0,0 +1,33 @@
+package server
+
+import "github.com/scopweb/mcp-go-github/pkg/types"
+
+// ListAuditTools returns the read-only audit log tool
+func ListAuditTools() []types.Tool {
+	return []types.Tool{
+		{
+			Name:  "github_audit",
+			Title: "Audit Log",
+			Description: "Query the audit log of operations performed through this server, including rotated log files. Operations: " +
+				"recent (latest entries, newest first), " +
+				"search (filter by since/until, filter_operation, repo, result, risk_level), " +
+				"stats (counts by operation, result, risk level and repository; accepts the same filters). " +
+				"Read-only. Use it to answer questions like \"what did you change yesterday?\".",
+			Annotations: ReadOnlyAnnotation(),
+			InputSchema: types.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]types.Property{
+					"operation":        {Type: "string", Description: "Operation to perform: recent, search, stats"},
+					"since":            {Type: "string", Description: "Start of the time range: RFC 3339 timestamp, date (2006-01-02, local time) or age such as 24h or 7d"},
+					"until":            {Type: "string", Description: "End of the time range (exclusive); a date includes that whole day"},
+					"filter_operation": {Type: "string", Description: "Logged operation, e.g. github_webhooks:delete, or a tool name such as git_sync to match all its operations"},
+					"repo":             {Type: "string", Description: "Repository as owner/repo, or just the repo name"},
+					"result":           {Type: "string", Description: "Result: success, failed, denied"},
+					"risk_level":       {Type: "string", Description: "Risk level: LOW, MEDIUM, HIGH, CRITICAL"},
+					"limit":            {Type: "number", Description: "Maximum entries to return for recent/search (default: 20, max: 200)"},
+				},
+				Required: []string{"operation"},
+			},
+		},
+	}
+}
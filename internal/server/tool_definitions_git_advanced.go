@@ -7,32 +7,60 @@ func ListGitAdvancedTools() []types.Tool {
 	return []types.Tool{
 		{
 			Name:        "git_history",
-			Description: "Consolidated Git history tool. Operations: log (commit history with analysis), diff (modified files with statistics). Use 'log' to view commit history and 'diff' to see file changes.",
+			Description: "Consolidated Git history tool. Operations: log (commit history with analysis), diff (modified files with statistics), blame (who last changed each line), show (commit metadata and patch, or a file at a revision). Use 'log' to view commit history and 'diff' to see file changes. log with structured=true (or any of range, paths, author, since, until, grep, pickaxe, pickaxe_regex, first_parent, cursor) returns commits with sha, parents, author, committer, dates, subject, body, trailers and files changed, paged by limit. diff with patch=true (or any of range, paths, context, word_diff, page_size, cursor) returns unified patches paged per file and hunk; pass nextCursor back as cursor to get the next page. blame returns per-line commit, author, date and summary for path, optionally limited to start_line..end_line. show takes a commit ref and returns its metadata and paged patch; ref 'rev:path' returns the file content at that revision.",
 			InputSchema: types.ToolInputSchema{
 				Type: "object",
 				Properties: map[string]types.Property{
-					"operation": {Type: "string", Description: "Operation to perform: log, diff"},
-					"limit":     {Type: "string", Description: "Number of commits to show (for log, default: 20)"},
-					"staged":    {Type: "boolean", Description: "Show staged files (for diff, default: false)"},
+					"operation":         {Type: "string", Description: "Operation to perform: log, diff, blame, show"},
+					"limit":             {Type: "string", Description: "Number of commits to show (for log, default: 20; structured log: per page, max 200)"},
+					"structured":        {Type: "boolean", Description: "Return structured commits instead of the graph and author analysis (for log)"},
+					"author":            {Type: "string", Description: "Author name or email pattern (for log)"},
+					"since":             {Type: "string", Description: "Only commits after this date, e.g. '2026-01-01' or '2 weeks ago' (for log)"},
+					"until":             {Type: "string", Description: "Only commits before this date (for log)"},
+					"grep":              {Type: "string", Description: "Pattern to match in commit messages (for log)"},
+					"pickaxe":           {Type: "string", Description: "Commits that add or remove this string, git log -S (for log)"},
+					"pickaxe_regex":     {Type: "string", Description: "Commits whose diff matches this regex, git log -G (for log)"},
+					"first_parent":      {Type: "boolean", Description: "Follow only the first parent of merges (for log)"},
+					"staged":            {Type: "boolean", Description: "Show staged files (for diff, default: false)"},
+					"patch":             {Type: "boolean", Description: "Return full unified patches instead of the file summary (for diff)"},
+					"path":              {Type: "string", Description: "File to annotate (for blame)"},
+					"ref":               {Type: "string", Description: "Revision to annotate (for blame, default: working tree); commit, or 'rev:path' to read a file (for show, default: HEAD)"},
+					"start_line":        {Type: "number", Description: "First line to annotate, 1-based (for blame)"},
+					"end_line":          {Type: "number", Description: "Last line to annotate (for blame, default: end of file)"},
+					"ignore_whitespace": {Type: "boolean", Description: "Ignore whitespace changes, git blame -w (for blame)"},
+					"detect_moves":      {Type: "boolean", Description: "Follow lines moved within the file, git blame -M (for blame)"},
+					"detect_copies":     {Type: "boolean", Description: "Follow lines moved or copied from other files, git blame -C (for blame)"},
+					"range":             {Type: "string", Description: "Ref or range: 'main', 'main..feature', 'main...feature' (for log, diff)"},
+					"paths":             {Type: "string", Description: "Comma-separated paths to limit the history or diff to (for log, diff, show)"},
+					"context":           {Type: "number", Description: "Lines of context around changes (for diff, show, default: 3)"},
+					"word_diff":         {Type: "boolean", Description: "Show changes word by word (for diff, show)"},
+					"page_size":         {Type: "number", Description: "Maximum bytes per page, 1024-262144 (for diff, show, default: 32768)"},
+					"cursor":            {Type: "string", Description: "nextCursor from the previous page (for log, diff, show)"},
 				},
 				Required: []string{"operation"},
 			},
 		},
 		{
 			Name:        "git_branch",
-			Description: "Consolidated Git branch management tool. Operations: checkout (switch or create branch), checkout_remote (checkout remote branch with local tracking), list (list all branches), merge (merge branches with safety validations), rebase (rebase onto specified branch), backup (create backup tag of current state).",
+			Description: "Consolidated Git branch management tool. Operations: checkout (switch or create branch), checkout_remote (checkout remote branch with local tracking), list (list all branches), merge (merge branches with safety validations), rebase (rebase onto specified branch), rebase_interactive (rewrite the commits since branch following a todo list), rebase_continue / rebase_skip / rebase_abort (drive a stopped rebase), cherry_pick (apply commits onto the current branch), revert (create commits undoing others), cherry_pick_continue / cherry_pick_skip / cherry_pick_abort and revert_continue / revert_skip / revert_abort (drive a stopped cherry-pick or revert), backup (create backup tag of current state). merge and rebase accept dry_run=true to preview the result (conflicted files with hunks, files changed, fast-forward) without touching the worktree. rebase_interactive takes todo, a JSON array of {action, commit, message} with action pick, reword, edit, squash, fixup or drop; every commit in branch..HEAD must appear once (drop removes it), message is required for reword and optional for squash. On a conflict or an edit step the rebase stops and reports its state; resolve with git_conflict and call rebase_continue. cherry_pick and revert take commits, a single SHA or a range a..b; a merge commit needs mainline (the parent number to diff against). They stop the same way on a conflict or a commit left empty.",
 			InputSchema: types.ToolInputSchema{
 				Type: "object",
 				Properties: map[string]types.Property{
-					"operation":     {Type: "string", Description: "Operation to perform: checkout, checkout_remote, list, merge, rebase, backup"},
-					"branch":        {Type: "string", Description: "Branch name (for checkout, rebase)"},
-					"create":        {Type: "boolean", Description: "Create new branch (for checkout)"},
-					"remote_branch": {Type: "string", Description: "Remote branch name (for checkout_remote)"},
-					"local_branch":  {Type: "string", Description: "Local branch name (for checkout_remote, optional)"},
-					"source_branch": {Type: "string", Description: "Source branch for merge (for merge)"},
-					"target_branch": {Type: "string", Description: "Target branch for merge (for merge, optional - uses current)"},
-					"remote":        {Type: "boolean", Description: "Include remote branches (for list, default: false)"},
-					"name":          {Type: "string", Description: "Backup name (for backup)"},
+					"operation":          {Type: "string", Description: "Operation to perform: checkout, checkout_remote, list, merge, rebase, rebase_interactive, rebase_continue, rebase_skip, rebase_abort, cherry_pick, cherry_pick_continue, cherry_pick_skip, cherry_pick_abort, revert, revert_continue, revert_skip, revert_abort, backup"},
+					"branch":             {Type: "string", Description: "Branch name (for checkout, rebase; for rebase_interactive, the base whose descendants are rewritten)"},
+					"create":             {Type: "boolean", Description: "Create new branch (for checkout)"},
+					"remote_branch":      {Type: "string", Description: "Remote branch name (for checkout_remote)"},
+					"local_branch":       {Type: "string", Description: "Local branch name (for checkout_remote, optional)"},
+					"source_branch":      {Type: "string", Description: "Source branch for merge (for merge)"},
+					"target_branch":      {Type: "string", Description: "Target branch for merge (for merge, optional - uses current)"},
+					"remote":             {Type: "boolean", Description: "Include remote branches (for list, default: false)"},
+					"name":               {Type: "string", Description: "Backup name (for backup)"},
+					"todo":               {Type: "array", Description: "Rebase todo list: [{\"action\": \"reword\", \"commit\": \"abc123\", \"message\": \"new message\"}, ...] in the new order (for rebase_interactive)"},
+					"commits":            {Type: "string", Description: "Commit SHA or range a..b, oldest first (for cherry_pick, revert)"},
+					"record_origin":      {Type: "boolean", Description: "Append a \"(cherry picked from commit ...)\" line to each message, like git cherry-pick -x (for cherry_pick, default: false)"},
+					"mainline":           {Type: "number", Description: "Parent number (1, 2...) to diff a merge commit against (for cherry_pick, revert; required for merges)"},
+					"dry_run":            {Type: "boolean", Description: "Preview the result with git merge-tree without changing anything (for merge, rebase, default: false)"},
+					"confirmation_token": {Type: "string", Description: "Confirmation token when writing to a protected branch or a safety policy rule requires one (for merge, cherry_pick, revert, rebase_interactive)"},
 				},
 				Required: []string{"operation"},
 			},
@@ -43,27 +71,30 @@ func ListGitAdvancedTools() []types.Tool {
 			InputSchema: types.ToolInputSchema{
 				Type: "object",
 				Properties: map[string]types.Property{
-					"operation":     {Type: "string", Description: "Operation to perform: push, pull, force_push, push_upstream, sync, pull_strategy"},
-					"branch":        {Type: "string", Description: "Branch name (optional, uses current branch)"},
-					"force":         {Type: "boolean", Description: "Use --force-with-lease (for force_push)"},
-					"remote_branch": {Type: "string", Description: "Remote branch name (for sync, optional)"},
-					"strategy":      {Type: "string", Description: "Pull strategy: merge, rebase, ff-only (for pull_strategy)"},
+					"operation":          {Type: "string", Description: "Operation to perform: push, pull, force_push, push_upstream, sync, pull_strategy"},
+					"branch":             {Type: "string", Description: "Branch name (optional, uses current branch)"},
+					"force":              {Type: "boolean", Description: "Use --force-with-lease (for force_push)"},
+					"remote_branch":      {Type: "string", Description: "Remote branch name (for sync, optional)"},
+					"strategy":           {Type: "string", Description: "Pull strategy: merge, rebase, ff-only (for pull_strategy)"},
+					"confirmation_token": {Type: "string", Description: "Confirmation token when pushing to a protected branch or a safety policy rule requires one (for push, force_push, push_upstream)"},
 				},
 				Required: []string{"operation"},
 			},
 		},
 		{
 			Name:        "git_conflict",
-			Description: "Consolidated Git conflict management tool. Operations: status (detailed conflict state in merge/rebase), resolve (automatic conflict resolution with strategies: theirs, ours, abort, manual), detect (detect potential conflicts between branches before merging), safe_merge (merge with automatic backup and conflict detection).",
+			Description: "Consolidated Git conflict management tool. Operations: status (detailed conflict state in merge/rebase/cherry-pick/revert), resolve (automatic conflict resolution with strategies: theirs, ours, abort, manual), detect (detect potential conflicts between branches before merging), safe_merge (merge with automatic backup and conflict detection; dry_run=true previews the exact result).",
 			InputSchema: types.ToolInputSchema{
 				Type: "object",
 				Properties: map[string]types.Property{
-					"operation":     {Type: "string", Description: "Operation to perform: status, resolve, detect, safe_merge"},
-					"strategy":      {Type: "string", Description: "Resolution strategy: theirs, ours, abort, manual (for resolve)"},
-					"source_branch": {Type: "string", Description: "Source branch (for detect)"},
-					"target_branch": {Type: "string", Description: "Target branch (for detect)"},
-					"source":        {Type: "string", Description: "Source branch (for safe_merge)"},
-					"target":        {Type: "string", Description: "Target branch (for safe_merge, optional - uses current)"},
+					"operation":          {Type: "string", Description: "Operation to perform: status, resolve, detect, safe_merge"},
+					"strategy":           {Type: "string", Description: "Resolution strategy: theirs, ours, abort, manual (for resolve)"},
+					"source_branch":      {Type: "string", Description: "Source branch (for detect)"},
+					"target_branch":      {Type: "string", Description: "Target branch (for detect)"},
+					"source":             {Type: "string", Description: "Source branch (for safe_merge)"},
+					"target":             {Type: "string", Description: "Target branch (for safe_merge, optional - uses current)"},
+					"dry_run":            {Type: "boolean", Description: "Preview the merge with git merge-tree without changing anything (for safe_merge, default: false)"},
+					"confirmation_token": {Type: "string", Description: "Confirmation token when merging into a protected branch or a safety policy rule requires one (for safe_merge)"},
 				},
 				Required: []string{"operation"},
 			},
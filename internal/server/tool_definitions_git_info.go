@@ -7,7 +7,7 @@ func ListGitInfoTools() []types.Tool {
 	return []types.Tool{
 		{
 			Name:        "git_info",
-			Description: "Git repository information and queries. Operations: status (repo state and config), file_sha (get SHA of a file), last_commit (latest commit SHA), file_content (read file at ref), changed_files (modified files list), validate_repo (check if valid git repo), list_files (all tracked files), context (auto-detect Git local vs API mode), validate_clean (check for uncommitted changes)",
+			Description: "Git repository information and queries. Operations: status (config plus branch, upstream, ahead/behind, staged/unstaged/untracked/conflicted files), file_sha (get SHA of a file), last_commit (latest commit SHA), file_content (read file at ref), changed_files (modified files list), validate_repo (check if valid git repo), list_files (all tracked files), context (auto-detect Git local vs API mode), validate_clean (check for uncommitted changes)",
 			InputSchema: types.ToolInputSchema{
 				Type: "object",
 				Properties: map[string]types.Property{
@@ -30,5 +30,18 @@ func ListGitInfoTools() []types.Tool {
 				Required: []string{"path"},
 			},
 		},
+		{
+			Name:        "git_workspaces",
+			Description: "Registry of named workspaces, persisted across sessions. Operations: list, add (register name -> repository path), remove, default (run calls without a workspace argument in this one). Every git_* and gh_* tool accepts an optional 'workspace' argument naming a registered workspace",
+			InputSchema: types.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]types.Property{
+					"operation": {Type: "string", Description: "Operation: list, add, remove, default"},
+					"name":      {Type: "string", Description: "Workspace name, e.g. 'service' or 'infra' (for add, remove, default)"},
+					"path":      {Type: "string", Description: "Path to Git repository directory (for add)"},
+				},
+				Required: []string{"operation"},
+			},
+		},
 	}
 }
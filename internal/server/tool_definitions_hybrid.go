@@ -46,10 +46,11 @@ func ListHybridTools() []types.Tool {
 			InputSchema: types.ToolInputSchema{
 				Type: "object",
 				Properties: map[string]types.Property{
-					"files":   {Type: "array", Description: "File list: [{path, content}] or [{path, source_path}]. source_path reads from disk without sending content."},
-					"paths":   {Type: "array", Description: "Files already present in the workspace: only git add/commit/push (no content transferred)."},
-					"message": {Type: "string", Description: "Commit message"},
-					"branch":  {Type: "string", Description: "Branch to push to (optional, uses current branch if omitted)"},
+					"files":              {Type: "array", Description: "File list: [{path, content}] or [{path, source_path}]. source_path reads from disk without sending content."},
+					"paths":              {Type: "array", Description: "Files already present in the workspace: only git add/commit/push (no content transferred)."},
+					"message":            {Type: "string", Description: "Commit message"},
+					"branch":             {Type: "string", Description: "Branch to push to (optional, uses current branch if omitted)"},
+					"confirmation_token": {Type: "string", Description: "Confirmation token when pushing to a protected branch or a safety policy rule requires one"},
 				},
 				Required: []string{"message"},
 			},
@@ -10,16 +10,19 @@ func ListResponseTools() []types.Tool {
 			Description: "Respond to GitHub issues and PRs. Operations: " +
 				"comment_issue (add comment to issue; requires owner, repo, number, body), " +
 				"comment_pr (add comment to PR; requires owner, repo, number, body), " +
-				"review_pr (create PR review; requires owner, repo, number, event: APPROVE/REQUEST_CHANGES/COMMENT; optional body).",
+				"review_pr (create PR review; requires owner, repo, number, event: APPROVE/REQUEST_CHANGES/COMMENT; optional body). " +
+				"All operations are audited; review_pr is MEDIUM risk.",
 			InputSchema: types.ToolInputSchema{
 				Type: "object",
 				Properties: map[string]types.Property{
-					"operation": {Type: "string", Description: "Operation: comment_issue, comment_pr, review_pr"},
-					"owner":     {Type: "string", Description: "Repository owner"},
-					"repo":      {Type: "string", Description: "Repository name"},
-					"number":    {Type: "number", Description: "Issue or PR number"},
-					"body":      {Type: "string", Description: "Comment text or review body (supports Markdown)"},
-					"event":     {Type: "string", Description: "Review type: APPROVE, REQUEST_CHANGES, COMMENT (for review_pr)"},
+					"operation":          {Type: "string", Description: "Operation: comment_issue, comment_pr, review_pr"},
+					"owner":              {Type: "string", Description: "Repository owner"},
+					"repo":               {Type: "string", Description: "Repository name"},
+					"number":             {Type: "number", Description: "Issue or PR number"},
+					"body":               {Type: "string", Description: "Comment text or review body (supports Markdown)"},
+					"event":              {Type: "string", Description: "Review type: APPROVE, REQUEST_CHANGES, COMMENT (for review_pr)"},
+					"dry_run":            {Type: "boolean", Description: "Preview the target PR without submitting (for review_pr in strict mode)"},
+					"confirmation_token": {Type: "string", Description: "Confirmation token when the safety mode requires one"},
 				},
 				Required: []string{"operation", "owner", "repo", "number"},
 			},
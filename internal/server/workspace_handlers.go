@@ -0,0 +1,56 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// handleWorkspaces serves git_workspaces: the registry of named workspaces
+// that git_* and gh_* tools select with their "workspace" argument
+func handleWorkspaces(s *MCPServer, arguments map[string]interface{}) (string, error) {
+	if s.Workspaces == nil {
+		return "", fmt.Errorf("workspace registry not configured")
+	}
+
+	operation, _ := arguments["operation"].(string)
+	name, _ := arguments["name"].(string)
+	if operation != "list" && name == "" {
+		return "", fmt.Errorf("parameter 'name' required for git_workspaces %s", operation)
+	}
+
+	switch operation {
+	case "list":
+		jsonOutput, err := json.MarshalIndent(s.Workspaces.List(), "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal workspace list: %w", err)
+		}
+		return string(jsonOutput), nil
+	case "add":
+		path, _ := arguments["path"].(string)
+		if path == "" {
+			return "", fmt.Errorf("parameter 'path' required for git_workspaces add")
+		}
+		workspace, err := s.Workspaces.Add(name, path)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Workspace '%s' registered: %s", workspace.Name, workspace.Path), nil
+	case "remove":
+		if err := s.Workspaces.Remove(name); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Workspace '%s' removed", name), nil
+	case "default":
+		workspace, err := s.Workspaces.SetDefault(name)
+		if err != nil {
+			return "", err
+		}
+		// Calls without a workspace argument now run in the default workspace
+		if _, err := s.GitClient.SetWorkspace(workspace.Path); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Default workspace set to '%s': %s", workspace.Name, workspace.Path), nil
+	default:
+		return "", fmt.Errorf("unknown operation '%s' for git_workspaces", operation)
+	}
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scopweb/mcp-go-github/pkg/git"
+)
+
+func initRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	return dir
+}
+
+func TestCallTool_Workspace(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	registry, err := git.NewWorkspaceRegistry(filepath.Join(t.TempDir(), "workspaces.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a", "b"} {
+		if _, err := registry.Add(name, initRepo(t)); err != nil {
+			t.Fatalf("Add(%s) error = %v", name, err)
+		}
+	}
+	clientA, err := registry.Client("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientB, err := registry.Client("b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &MCPServer{GitClient: clientA, GitAvailable: true, Workspaces: registry}
+
+	status := func(arguments map[string]interface{}) string {
+		t.Helper()
+		arguments["operation"] = "status"
+		result, err := CallTool(context.Background(), s, map[string]interface{}{"name": "git_info", "arguments": arguments})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.IsError || len(result.Content) == 0 {
+			t.Fatalf("result = %+v", result)
+		}
+		return result.Content[0].Text
+	}
+
+	text := status(map[string]interface{}{"workspace": "b"})
+	if !strings.Contains(text, clientB.GetRepoPath()) || strings.Contains(text, clientA.GetRepoPath()) {
+		t.Errorf("status should come from workspace b:\n%s", text)
+	}
+	if s.GitClient.GetRepoPath() != clientA.GetRepoPath() {
+		t.Errorf("server client moved to %s, want it on workspace a", s.GitClient.GetRepoPath())
+	}
+	if text := status(map[string]interface{}{}); !strings.Contains(text, clientA.GetRepoPath()) {
+		t.Errorf("status without workspace should come from workspace a:\n%s", text)
+	}
+
+	if _, err := CallTool(context.Background(), s, map[string]interface{}{
+		"name":      "git_info",
+		"arguments": map[string]interface{}{"operation": "status", "workspace": "missing"},
+	}); err == nil || !strings.Contains(err.Error(), "no registrado") {
+		t.Errorf("unknown workspace should fail: %v", err)
+	}
+}
+
+func TestListTools_WorkspaceArg(t *testing.T) {
+	tools := map[string]bool{}
+	for _, tool := range ListTools(true, nil).Tools {
+		_, ok := tool.InputSchema.Properties["workspace"]
+		tools[tool.Name] = ok
+	}
+
+	for _, name := range []string{"git_info", "git_branch", "gh_push_files"} {
+		if has, listed := tools[name]; !listed || !has {
+			t.Errorf("%s should accept workspace (listed: %v)", name, listed)
+		}
+	}
+	for _, name := range []string{"git_workspaces", "git_set_workspace"} {
+		if has, listed := tools[name]; !listed || has {
+			t.Errorf("%s should not accept workspace (listed: %v)", name, listed)
+		}
+	}
+}
@@ -86,6 +86,17 @@ func (c *Client) ArchiveRepository(ctx context.Context, owner, repo string) (*gi
 	return repository, err
 }
 
+// UnarchiveRepository makes an archived repository writable again
+func (c *Client) UnarchiveRepository(ctx context.Context, owner, repo string) (*github.Repository, error) {
+	archived := false
+	repoUpdate := &github.Repository{
+		Archived: &archived,
+	}
+
+	repository, _, err := c.client.Repositories.Edit(ctx, owner, repo, repoUpdate)
+	return repository, err
+}
+
 // DeleteRepository permanently deletes a repository
 func (c *Client) DeleteRepository(ctx context.Context, owner, repo string) error {
 	_, err := c.client.Repositories.Delete(ctx, owner, repo)
@@ -124,6 +135,12 @@ func (c *Client) ListWebhooks(ctx context.Context, owner, repo string) ([]*githu
 	return hooks, err
 }
 
+// GetWebhook retrieves a single repository webhook
+func (c *Client) GetWebhook(ctx context.Context, owner, repo string, hookID int64) (*github.Hook, error) {
+	hook, _, err := c.client.Repositories.GetHook(ctx, owner, repo, hookID)
+	return hook, err
+}
+
 // CreateWebhook creates a new repository webhook
 func (c *Client) CreateWebhook(ctx context.Context, owner, repo string, config map[string]interface{}) (*github.Hook, error) {
 	// Convert config map to HookConfig struct
@@ -252,6 +269,19 @@ func (c *Client) RemoveCollaborator(ctx context.Context, owner, repo, username s
 	return err
 }
 
+// GetCollaboratorPermission returns a user's role on a repository
+// (admin, maintain, write, triage, read), or "none" without access
+func (c *Client) GetCollaboratorPermission(ctx context.Context, owner, repo, username string) (string, error) {
+	level, _, err := c.client.Repositories.GetPermissionLevel(ctx, owner, repo, username)
+	if err != nil {
+		return "", err
+	}
+	if level.GetRoleName() != "" {
+		return level.GetRoleName(), nil
+	}
+	return level.GetPermission(), nil
+}
+
 // CheckCollaborator checks if a user is a collaborator
 func (c *Client) CheckCollaborator(ctx context.Context, owner, repo, username string) (bool, error) {
 	isCollaborator, _, err := c.client.Repositories.IsCollaborator(ctx, owner, repo, username)
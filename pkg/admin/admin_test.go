@@ -59,6 +59,7 @@ func TestClient_MethodSignatures(t *testing.T) {
 		var _ func(context.Context, string, string) (*github.Repository, error) = client.GetRepositorySettings
 		var _ func(context.Context, string, string, map[string]interface{}) (*github.Repository, error) = client.UpdateRepositorySettings
 		var _ func(context.Context, string, string) (*github.Repository, error) = client.ArchiveRepository
+		var _ func(context.Context, string, string) (*github.Repository, error) = client.UnarchiveRepository
 		var _ func(context.Context, string, string) error = client.DeleteRepository
 	})
 
@@ -70,6 +71,7 @@ func TestClient_MethodSignatures(t *testing.T) {
 
 	t.Run("Webhook methods", func(t *testing.T) {
 		var _ func(context.Context, string, string) ([]*github.Hook, error) = client.ListWebhooks
+		var _ func(context.Context, string, string, int64) (*github.Hook, error) = client.GetWebhook
 		var _ func(context.Context, string, string, map[string]interface{}) (*github.Hook, error) = client.CreateWebhook
 		var _ func(context.Context, string, string, int64, map[string]interface{}) (*github.Hook, error) = client.UpdateWebhook
 		var _ func(context.Context, string, string, int64) error = client.DeleteWebhook
@@ -82,6 +84,7 @@ func TestClient_MethodSignatures(t *testing.T) {
 		var _ func(context.Context, string, string, string, string) (*github.CollaboratorInvitation, error) = client.UpdateCollaboratorPermission
 		var _ func(context.Context, string, string, string) error = client.RemoveCollaborator
 		var _ func(context.Context, string, string, string) (bool, error) = client.CheckCollaborator
+		var _ func(context.Context, string, string, string) (string, error) = client.GetCollaboratorPermission
 	})
 
 	t.Run("Invitation methods", func(t *testing.T) {
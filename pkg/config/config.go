@@ -4,18 +4,32 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"net/netip"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/scopweb/mcp-go-github/pkg/safety"
 )
 
 // Config represents the complete MCP server configuration
 type Config struct {
-	Version        string              `json:"version"`
-	SafetyMode     string              `json:"safetyMode"`
-	GlobalSettings GlobalSettings      `json:"globalSettings"`
-	Modes          map[string]ModeSettings `json:"modes,omitempty"`
+	Version            string                       `json:"version"`
+	SafetyMode         string                       `json:"safetyMode"`
+	GlobalSettings     GlobalSettings               `json:"globalSettings"`
+	Modes              map[string]ModeSettings      `json:"modes,omitempty"`
 	OperationOverrides map[string]OperationOverride `json:"operationOverrides,omitempty"`
+	Rules              []RuleConfig                 `json:"rules,omitempty"`
+	ProtectedBranches  *ProtectedBranchesConfig     `json:"protectedBranches,omitempty"`
+	AuditSinks         []AuditSinkConfig            `json:"auditSinks,omitempty"`
+	RateLimits         []RateLimitConfig            `json:"rateLimits,omitempty"`
+	Approval           *ApprovalConfig              `json:"approval,omitempty"`
+	SecretScanning     *SecretScanningConfig        `json:"secretScanning,omitempty"`
+	ChangeFreezes      []ChangeFreezeConfig         `json:"changeFreezes,omitempty"`
+	Sandbox            *SandboxConfig               `json:"sandbox,omitempty"`
+	WebhookAllowlist   *WebhookAllowlistConfig      `json:"webhookAllowlist,omitempty"`
 }
 
 // GlobalSettings contains global configuration settings
@@ -25,6 +39,20 @@ type GlobalSettings struct {
 	RequireConfirmationAbove string `json:"requireConfirmationAbove"`
 	EnableAutoBackup         bool   `json:"enableAutoBackup"`
 	BackupPath               string `json:"backupPath"`
+
+	// Confirmation tokens are signed with the key in ConfirmationKeyFile (created
+	// on first use; MCP_CONFIRMATION_KEY overrides it) and their nonces recorded in
+	// ConfirmationReplayFile. TokenExpiration maps risk levels to durations ("10m").
+	ConfirmationKeyFile    string            `json:"confirmationKeyFile,omitempty"`
+	ConfirmationReplayFile string            `json:"confirmationReplayFile,omitempty"`
+	TokenExpiration        map[string]string `json:"tokenExpiration,omitempty"`
+
+	// Audit entries are hash-chained; with AuditSigningKeyFile (or MCP_AUDIT_KEY)
+	// each entry is also HMAC-signed. The key file is created on first use.
+	AuditSigningKeyFile string `json:"auditSigningKeyFile,omitempty"`
+
+	// RateLimitStateFile keeps rate limit counters across restarts
+	RateLimitStateFile string `json:"rateLimitStateFile,omitempty"`
 }
 
 // ModeSettings contains settings for a specific safety mode
@@ -42,6 +70,112 @@ type OperationOverride struct {
 	CustomMessage       string `json:"customMessage,omitempty"`
 }
 
+// RuleConfig is a policy rule as written in safety.json. Rules are evaluated
+// in order and the first match decides.
+type RuleConfig struct {
+	Name       string   `json:"name,omitempty"`
+	Action     string   `json:"action"`               // allow, deny, require_confirmation
+	Operations []string `json:"operations,omitempty"` // "tool:operation" globs, e.g. "*:delete"
+	Repos      []string `json:"repos,omitempty"`      // "owner/repo" globs, e.g. "acme/*"
+	Branches   []string `json:"branches,omitempty"`   // branch globs, e.g. "release/*"
+	MinRisk    string   `json:"minRisk,omitempty"`    // low, medium, high, critical
+}
+
+// ProtectedBranchesConfig configures the local protected-branch guard for
+// git_sync push/force_push/push_upstream, gh_push_files, git_branch
+// merge/cherry_pick/revert/rebase_interactive and git_conflict safe_merge
+type ProtectedBranchesConfig struct {
+	Patterns     []string `json:"patterns"`               // branch globs, e.g. "main", "release/*"
+	Action       string   `json:"action,omitempty"`       // deny (default) or require_confirmation
+	MirrorRemote bool     `json:"mirrorRemote,omitempty"` // also honour GitHub branch protection
+}
+
+// RateLimitConfig is an operation budget as written in safety.json, e.g.
+// {"riskLevel": "critical", "max": 3, "window": "1h"}
+type RateLimitConfig struct {
+	Name       string   `json:"name,omitempty"`
+	Operations []string `json:"operations,omitempty"` // globs, as in rules
+	RiskLevel  string   `json:"riskLevel,omitempty"`  // count only this risk level
+	Max        int      `json:"max"`
+	Window     string   `json:"window"` // e.g. "10m", "1h"
+}
+
+// ApprovalConfig enables two-person approval: confirmation tokens for the
+// listed operations are never returned to the agent but queued until a human
+// approves them with `github-mcp-server approve <id>` or the local endpoint
+type ApprovalConfig struct {
+	Enabled    bool     `json:"enabled"`
+	Operations []string `json:"operations,omitempty"` // "tool:operation"; default: repo delete/archive, branch protection delete
+	QueueFile  string   `json:"queueFile,omitempty"`  // default ./.mcp-approvals.json
+	HTTPAddr   string   `json:"httpAddr,omitempty"`   // loopback host:port, e.g. "127.0.0.1:8787"; empty disables the endpoint
+	Expiration string   `json:"expiration,omitempty"` // how long a request waits, default "30m"
+}
+
+// ChangeFreezeConfig is a change-freeze window as written in safety.json: a
+// date range (start/end) or a recurring window (cron/duration), e.g.
+// {"name": "weekend", "cron": "0 18 * * 5", "duration": "63h", "timeZone": "Europe/Madrid"}
+type ChangeFreezeConfig struct {
+	Name       string   `json:"name,omitempty"`
+	Action     string   `json:"action,omitempty"`     // deny (default) or require_confirmation
+	Operations []string `json:"operations,omitempty"` // globs; default: merges, pushes to protected branches, branch protection and repo settings edits
+	Repos      []string `json:"repos,omitempty"`      // "owner/repo" globs
+	Branches   []string `json:"branches,omitempty"`   // branch globs, for operations with a branch
+	Start      string   `json:"start,omitempty"`      // RFC 3339, or "2006-01-02 15:04" / "2006-01-02" in timeZone
+	End        string   `json:"end,omitempty"`
+	Cron       string   `json:"cron,omitempty"`     // five-field cron: when each window starts
+	Duration   string   `json:"duration,omitempty"` // length of each cron window, e.g. "63h"
+	TimeZone   string   `json:"timeZone,omitempty"` // IANA name, default UTC
+}
+
+// SandboxConfig confines local file access (workspace, init and validate
+// paths, source_path, download targets) to AllowedRoots. Paths are resolved
+// through symlinks before the check. Without roots any directory is allowed.
+type SandboxConfig struct {
+	AllowedRoots []string `json:"allowedRoots"` // absolute directories
+}
+
+// WebhookAllowlistConfig exempts internal webhook receivers (for example a
+// GitHub Enterprise integration host) from the SSRF check, which otherwise
+// rejects URLs resolving to loopback, private, link-local or metadata ranges
+type WebhookAllowlistConfig struct {
+	Hosts    []string `json:"hosts,omitempty"`    // host globs, e.g. "*.ci.corp.example"
+	Networks []string `json:"networks,omitempty"` // CIDRs or single addresses
+}
+
+// SecretScanningConfig tunes the scan of file content before local commits
+// and API uploads. Without this section the built-in rules block.
+type SecretScanningConfig struct {
+	Action           string             `json:"action,omitempty"`           // block (default), warn, off
+	Rules            []SecretRuleConfig `json:"rules,omitempty"`            // extra patterns
+	Allowlist        []string           `json:"allowlist,omitempty"`        // regexes; matching secrets or lines are ignored
+	AllowPaths       []string           `json:"allowPaths,omitempty"`       // file globs never scanned, e.g. "testdata/**"
+	EntropyThreshold float64            `json:"entropyThreshold,omitempty"` // bits per character, default 3.5
+}
+
+// SecretRuleConfig is a custom secret pattern; a capture group, if any, marks the secret
+type SecretRuleConfig struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+}
+
+// AuditSinkConfig is an external audit destination as written in safety.json.
+// Entries are forwarded in the background; a failing sink never blocks the
+// local audit log.
+type AuditSinkConfig struct {
+	Type          string            `json:"type"`                    // syslog, http, otlp
+	Name          string            `json:"name,omitempty"`          // label for warnings
+	Network       string            `json:"network,omitempty"`       // syslog: udp (default) or tcp
+	Address       string            `json:"address,omitempty"`       // syslog: host:port
+	URL           string            `json:"url,omitempty"`           // http, otlp endpoint
+	Headers       map[string]string `json:"headers,omitempty"`       // http, otlp; values expand ${ENV_VARS}
+	AppName       string            `json:"appName,omitempty"`       // syslog APP-NAME / OTLP service.name
+	BatchSize     int               `json:"batchSize,omitempty"`     // default 50
+	FlushInterval string            `json:"flushInterval,omitempty"` // default "2s"
+	MaxRetries    int               `json:"maxRetries,omitempty"`    // default 3; -1 disables retries
+	Timeout       string            `json:"timeout,omitempty"`       // per attempt, default "5s"
+	QueueSize     int               `json:"queueSize,omitempty"`     // default 1000; entries beyond are dropped
+}
+
 const (
 	// DefaultConfigPath is the default location for safety.json
 	DefaultConfigPath = "./safety.json"
@@ -110,6 +244,77 @@ func convertToSafetyConfig(cfg *Config) (*safety.SafetyConfig, error) {
 		RequireConfirmationAbove: confirmLevel,
 		EnableAutoBackup:         cfg.GlobalSettings.EnableAutoBackup,
 		BackupPath:               cfg.GlobalSettings.BackupPath,
+		AuditKeyPath:             cfg.GlobalSettings.AuditSigningKeyFile,
+	}
+
+	expirations, err := convertTokenExpirations(cfg.GlobalSettings.TokenExpiration)
+	if err != nil {
+		return nil, err
+	}
+	safetyConfig.TokenExpirations = expirations
+
+	limits, err := convertRateLimits(cfg.RateLimits)
+	if err != nil {
+		return nil, err
+	}
+	safetyConfig.RateLimits = limits
+
+	sinks, err := convertAuditSinks(cfg.AuditSinks)
+	if err != nil {
+		return nil, err
+	}
+	safetyConfig.AuditSinks = sinks
+
+	rules, err := convertRules(cfg.Rules)
+	if err != nil {
+		return nil, err
+	}
+	safetyConfig.Rules = rules
+
+	if err := convertApproval(cfg.Approval, safetyConfig); err != nil {
+		return nil, err
+	}
+
+	secretScan, err := convertSecretScanning(cfg.SecretScanning)
+	if err != nil {
+		return nil, err
+	}
+	safetyConfig.SecretScan = secretScan
+
+	freezes, err := convertChangeFreezes(cfg.ChangeFreezes)
+	if err != nil {
+		return nil, err
+	}
+	safetyConfig.Freezes = freezes
+
+	roots, err := convertSandbox(cfg.Sandbox)
+	if err != nil {
+		return nil, err
+	}
+	safetyConfig.AllowedRoots = roots
+
+	allowlist, err := convertWebhookAllowlist(cfg.WebhookAllowlist)
+	if err != nil {
+		return nil, err
+	}
+	safetyConfig.WebhookAllowlist = allowlist
+
+	if pb := cfg.ProtectedBranches; pb != nil {
+		switch pb.Action {
+		case "", string(safety.PolicyDeny):
+			safetyConfig.ProtectedBranchAction = safety.PolicyDeny
+		case string(safety.PolicyRequireConfirmation):
+			safetyConfig.ProtectedBranchAction = safety.PolicyRequireConfirmation
+		default:
+			return nil, fmt.Errorf("invalid protectedBranches action: %s (allowed: deny, require_confirmation)", pb.Action)
+		}
+		for _, pattern := range pb.Patterns {
+			if strings.TrimSpace(pattern) == "" {
+				return nil, fmt.Errorf("protectedBranches: empty pattern")
+			}
+		}
+		safetyConfig.ProtectedBranches = pb.Patterns
+		safetyConfig.MirrorRemoteProtection = pb.MirrorRemote
 	}
 
 	// Set defaults if not specified
@@ -119,10 +324,387 @@ func convertToSafetyConfig(cfg *Config) (*safety.SafetyConfig, error) {
 	if safetyConfig.BackupPath == "" {
 		safetyConfig.BackupPath = "./.mcp-backups"
 	}
+	safetyConfig.TokenKeyPath = cfg.GlobalSettings.ConfirmationKeyFile
+	if safetyConfig.TokenKeyPath == "" {
+		safetyConfig.TokenKeyPath = safety.DefaultTokenKeyPath
+	}
+	safetyConfig.RateLimitStatePath = cfg.GlobalSettings.RateLimitStateFile
+	if safetyConfig.RateLimitStatePath == "" {
+		safetyConfig.RateLimitStatePath = safety.DefaultRateLimitStatePath
+	}
+	safetyConfig.TokenReplayPath = cfg.GlobalSettings.ConfirmationReplayFile
+	if safetyConfig.TokenReplayPath == "" {
+		safetyConfig.TokenReplayPath = safety.DefaultTokenReplayPath
+	}
 
 	return safetyConfig, nil
 }
 
+// convertTokenExpirations parses the per-risk-level token lifetimes
+func convertTokenExpirations(settings map[string]string) (map[safety.RiskLevel]time.Duration, error) {
+	if len(settings) == 0 {
+		return nil, nil
+	}
+
+	expirations := make(map[safety.RiskLevel]time.Duration, len(settings))
+	for levelName, value := range settings {
+		level, ok := riskLevelFromString(levelName)
+		if !ok {
+			return nil, fmt.Errorf("tokenExpiration: invalid risk level: %s", levelName)
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("tokenExpiration: invalid duration for %s: %q", levelName, value)
+		}
+		expirations[level] = d
+	}
+	return expirations, nil
+}
+
+// convertRateLimits converts and validates the rate limits section
+func convertRateLimits(limitConfigs []RateLimitConfig) ([]safety.RateLimit, error) {
+	if len(limitConfigs) == 0 {
+		return nil, nil
+	}
+
+	limits := make([]safety.RateLimit, 0, len(limitConfigs))
+	for i, lc := range limitConfigs {
+		var level safety.RiskLevel
+		if lc.RiskLevel != "" {
+			parsed, ok := riskLevelFromString(lc.RiskLevel)
+			if !ok {
+				return nil, fmt.Errorf("rate limit %d: invalid riskLevel: %s", i, lc.RiskLevel)
+			}
+			level = parsed
+		}
+		window, err := time.ParseDuration(lc.Window)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit %d: invalid window %q: %w", i, lc.Window, err)
+		}
+		limits = append(limits, safety.RateLimit{
+			Name:       lc.Name,
+			Operations: lc.Operations,
+			Level:      level,
+			Max:        lc.Max,
+			Window:     window,
+		})
+	}
+
+	if err := safety.ValidateRateLimits(limits); err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// freezeTimeLayouts are the accepted start/end formats besides RFC 3339
+var freezeTimeLayouts = []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"}
+
+// convertChangeFreezes converts and validates the change freezes section
+func convertChangeFreezes(freezeConfigs []ChangeFreezeConfig) ([]safety.FreezeWindow, error) {
+	if len(freezeConfigs) == 0 {
+		return nil, nil
+	}
+
+	windows := make([]safety.FreezeWindow, 0, len(freezeConfigs))
+	for i, fc := range freezeConfigs {
+		loc := time.UTC
+		if fc.TimeZone != "" {
+			l, err := time.LoadLocation(fc.TimeZone)
+			if err != nil {
+				return nil, fmt.Errorf("change freeze %d: invalid timeZone %q: %w", i, fc.TimeZone, err)
+			}
+			loc = l
+		}
+
+		window := safety.FreezeWindow{
+			Name:       fc.Name,
+			Action:     safety.PolicyAction(fc.Action),
+			Operations: fc.Operations,
+			Repos:      fc.Repos,
+			Branches:   fc.Branches,
+			Location:   loc,
+		}
+		if fc.Action == "" {
+			window.Action = safety.PolicyDeny
+		}
+
+		var err error
+		if fc.Start != "" {
+			if window.Start, err = parseFreezeTime(fc.Start, loc); err != nil {
+				return nil, fmt.Errorf("change freeze %d: invalid start: %w", i, err)
+			}
+		}
+		if fc.End != "" {
+			if window.End, err = parseFreezeTime(fc.End, loc); err != nil {
+				return nil, fmt.Errorf("change freeze %d: invalid end: %w", i, err)
+			}
+		}
+		if fc.Cron != "" {
+			if window.Schedule, err = safety.ParseCron(fc.Cron); err != nil {
+				return nil, fmt.Errorf("change freeze %d: %w", i, err)
+			}
+		}
+		if fc.Duration != "" {
+			if window.Duration, err = time.ParseDuration(fc.Duration); err != nil {
+				return nil, fmt.Errorf("change freeze %d: invalid duration %q: %w", i, fc.Duration, err)
+			}
+		}
+		windows = append(windows, window)
+	}
+
+	if err := safety.ValidateFreezeWindows(windows); err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+// parseFreezeTime parses RFC 3339, or a local date and time in loc
+func parseFreezeTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range freezeTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q (use RFC 3339 or \"2006-01-02 15:04\")", value)
+}
+
+// convertApproval validates the approval section and applies it to the safety config
+func convertApproval(ac *ApprovalConfig, safetyConfig *safety.SafetyConfig) error {
+	if ac == nil || !ac.Enabled {
+		return nil
+	}
+
+	operations := ac.Operations
+	if len(operations) == 0 {
+		operations = safety.DefaultApprovalOperations
+	}
+	for _, op := range operations {
+		if _, ok := safety.ClassifyOperation(op); !ok {
+			return fmt.Errorf("approval: unknown operation: %s", op)
+		}
+	}
+
+	if ac.Expiration != "" {
+		d, err := time.ParseDuration(ac.Expiration)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("approval: invalid expiration %q", ac.Expiration)
+		}
+		safetyConfig.ApprovalExpiration = d
+	}
+	if ac.HTTPAddr != "" {
+		if err := safety.ValidateApprovalAddr(ac.HTTPAddr); err != nil {
+			return fmt.Errorf("approval: %w", err)
+		}
+	}
+
+	safetyConfig.ApprovalOperations = operations
+	safetyConfig.ApprovalHTTPAddr = ac.HTTPAddr
+	safetyConfig.ApprovalQueuePath = ac.QueueFile
+	if safetyConfig.ApprovalQueuePath == "" {
+		safetyConfig.ApprovalQueuePath = safety.DefaultApprovalQueuePath
+	}
+	return nil
+}
+
+// convertSandbox validates the allowed roots of the sandbox section
+func convertSandbox(sc *SandboxConfig) ([]string, error) {
+	if sc == nil {
+		return nil, nil
+	}
+	var roots []string
+	for _, root := range sc.AllowedRoots {
+		if strings.TrimSpace(root) == "" {
+			return nil, fmt.Errorf("sandbox: empty allowedRoots entry")
+		}
+		if !filepath.IsAbs(root) {
+			return nil, fmt.Errorf("sandbox: allowed root must be an absolute path: %s", root)
+		}
+		roots = append(roots, filepath.Clean(root))
+	}
+	return roots, nil
+}
+
+// convertWebhookAllowlist parses the internal webhook receivers
+func convertWebhookAllowlist(wc *WebhookAllowlistConfig) (safety.URLAllowlist, error) {
+	var allow safety.URLAllowlist
+	if wc == nil {
+		return allow, nil
+	}
+	for _, host := range wc.Hosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host == "" || strings.ContainsAny(host, "/:") {
+			return allow, fmt.Errorf("webhookAllowlist: invalid host %q (use a bare host name or glob)", host)
+		}
+		allow.Hosts = append(allow.Hosts, host)
+	}
+	for _, network := range wc.Networks {
+		prefix, err := netip.ParsePrefix(network)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(network)
+			if addrErr != nil {
+				return allow, fmt.Errorf("webhookAllowlist: invalid network %q (use a CIDR such as 10.20.0.0/16)", network)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		allow.Networks = append(allow.Networks, prefix.Masked())
+	}
+	return allow, nil
+}
+
+// convertSecretScanning compiles the secret scanning section
+func convertSecretScanning(sc *SecretScanningConfig) (safety.SecretScanConfig, error) {
+	var scan safety.SecretScanConfig
+	if sc == nil {
+		return scan, nil
+	}
+
+	switch sc.Action {
+	case "", safety.SecretScanBlock, safety.SecretScanWarn, safety.SecretScanOff:
+		scan.Action = sc.Action
+	default:
+		return scan, fmt.Errorf("secretScanning: invalid action: %s (allowed: block, warn, off)", sc.Action)
+	}
+	if sc.EntropyThreshold < 0 {
+		return scan, fmt.Errorf("secretScanning: entropyThreshold must not be negative")
+	}
+	scan.EntropyThreshold = sc.EntropyThreshold
+
+	for i, rc := range sc.Rules {
+		if rc.Name == "" {
+			return scan, fmt.Errorf("secretScanning: rule %d: name required", i)
+		}
+		re, err := regexp.Compile(rc.Pattern)
+		if err != nil || rc.Pattern == "" {
+			return scan, fmt.Errorf("secretScanning: rule %s: invalid pattern %q", rc.Name, rc.Pattern)
+		}
+		scan.Rules = append(scan.Rules, safety.SecretRule{Name: rc.Name, Pattern: re})
+	}
+	for _, pattern := range sc.Allowlist {
+		re, err := regexp.Compile(pattern)
+		if err != nil || pattern == "" {
+			return scan, fmt.Errorf("secretScanning: invalid allowlist pattern %q", pattern)
+		}
+		scan.Allowlist = append(scan.Allowlist, re)
+	}
+	for _, glob := range sc.AllowPaths {
+		if strings.TrimSpace(glob) == "" {
+			return scan, fmt.Errorf("secretScanning: empty allowPaths entry")
+		}
+	}
+	scan.AllowPaths = sc.AllowPaths
+	return scan, nil
+}
+
+// convertAuditSinks converts and validates the audit sinks section
+func convertAuditSinks(sinkConfigs []AuditSinkConfig) ([]safety.AuditSinkConfig, error) {
+	if len(sinkConfigs) == 0 {
+		return nil, nil
+	}
+
+	parseDuration := func(i int, field, value string) (time.Duration, error) {
+		if value == "" {
+			return 0, nil
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return 0, fmt.Errorf("audit sink %d: invalid %s %q (use a positive duration such as \"5s\")", i, field, value)
+		}
+		return d, nil
+	}
+
+	sinks := make([]safety.AuditSinkConfig, 0, len(sinkConfigs))
+	for i, sc := range sinkConfigs {
+		switch sc.Type {
+		case safety.AuditSinkSyslog:
+			if sc.Address == "" {
+				return nil, fmt.Errorf("audit sink %d: syslog requires address (host:port)", i)
+			}
+		case safety.AuditSinkHTTP, safety.AuditSinkOTLP:
+			if sc.URL == "" {
+				return nil, fmt.Errorf("audit sink %d: %s requires url", i, sc.Type)
+			}
+		default:
+			return nil, fmt.Errorf("audit sink %d: invalid type %q (allowed: syslog, http, otlp)", i, sc.Type)
+		}
+
+		flushInterval, err := parseDuration(i, "flushInterval", sc.FlushInterval)
+		if err != nil {
+			return nil, err
+		}
+		timeout, err := parseDuration(i, "timeout", sc.Timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		sinks = append(sinks, safety.AuditSinkConfig{
+			Type:          sc.Type,
+			Name:          sc.Name,
+			Network:       sc.Network,
+			Address:       sc.Address,
+			URL:           sc.URL,
+			Headers:       sc.Headers,
+			AppName:       sc.AppName,
+			BatchSize:     sc.BatchSize,
+			FlushInterval: flushInterval,
+			MaxRetries:    sc.MaxRetries,
+			Timeout:       timeout,
+			QueueSize:     sc.QueueSize,
+		})
+	}
+	return sinks, nil
+}
+
+// convertRules converts and validates the policy rules section
+func convertRules(ruleConfigs []RuleConfig) ([]safety.PolicyRule, error) {
+	if len(ruleConfigs) == 0 {
+		return nil, nil
+	}
+
+	rules := make([]safety.PolicyRule, 0, len(ruleConfigs))
+	for i, rc := range ruleConfigs {
+		var minRisk safety.RiskLevel
+		if rc.MinRisk != "" {
+			level, ok := riskLevelFromString(rc.MinRisk)
+			if !ok {
+				return nil, fmt.Errorf("rule %d: invalid minRisk: %s", i, rc.MinRisk)
+			}
+			minRisk = level
+		}
+		rules = append(rules, safety.PolicyRule{
+			Name:       rc.Name,
+			Action:     safety.PolicyAction(rc.Action),
+			Operations: rc.Operations,
+			Repos:      rc.Repos,
+			Branches:   rc.Branches,
+			MinRisk:    minRisk,
+		})
+	}
+
+	if err := safety.ValidatePolicyRules(rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// riskLevelFromString parses a risk level strictly (case-insensitive)
+func riskLevelFromString(level string) (safety.RiskLevel, bool) {
+	switch strings.ToLower(level) {
+	case "low":
+		return safety.RiskLow, true
+	case "medium":
+		return safety.RiskMedium, true
+	case "high":
+		return safety.RiskHigh, true
+	case "critical":
+		return safety.RiskCritical, true
+	default:
+		return 0, false
+	}
+}
+
 // riskLevelToString converts safety.RiskLevel to lowercase string
 func riskLevelToString(level safety.RiskLevel) string {
 	switch level {
@@ -167,9 +749,135 @@ func SaveConfig(configPath string, safetyConfig *safety.SafetyConfig) error {
 			RequireConfirmationAbove: riskLevelToString(safetyConfig.RequireConfirmationAbove),
 			EnableAutoBackup:         safetyConfig.EnableAutoBackup,
 			BackupPath:               safetyConfig.BackupPath,
+			ConfirmationKeyFile:      safetyConfig.TokenKeyPath,
+			ConfirmationReplayFile:   safetyConfig.TokenReplayPath,
+			AuditSigningKeyFile:      safetyConfig.AuditKeyPath,
+			RateLimitStateFile:       safetyConfig.RateLimitStatePath,
 		},
 	}
 
+	if len(safetyConfig.TokenExpirations) > 0 {
+		cfg.GlobalSettings.TokenExpiration = make(map[string]string, len(safetyConfig.TokenExpirations))
+		for level, d := range safetyConfig.TokenExpirations {
+			cfg.GlobalSettings.TokenExpiration[riskLevelToString(level)] = d.String()
+		}
+	}
+
+	for _, rule := range safetyConfig.Rules {
+		rc := RuleConfig{
+			Name:       rule.Name,
+			Action:     string(rule.Action),
+			Operations: rule.Operations,
+			Repos:      rule.Repos,
+			Branches:   rule.Branches,
+		}
+		if rule.MinRisk > 0 {
+			rc.MinRisk = riskLevelToString(rule.MinRisk)
+		}
+		cfg.Rules = append(cfg.Rules, rc)
+	}
+
+	for _, limit := range safetyConfig.RateLimits {
+		lc := RateLimitConfig{
+			Name:       limit.Name,
+			Operations: limit.Operations,
+			Max:        limit.Max,
+			Window:     limit.Window.String(),
+		}
+		if limit.Level > 0 {
+			lc.RiskLevel = riskLevelToString(limit.Level)
+		}
+		cfg.RateLimits = append(cfg.RateLimits, lc)
+	}
+
+	for _, sink := range safetyConfig.AuditSinks {
+		sc := AuditSinkConfig{
+			Type:       sink.Type,
+			Name:       sink.Name,
+			Network:    sink.Network,
+			Address:    sink.Address,
+			URL:        sink.URL,
+			Headers:    sink.Headers,
+			AppName:    sink.AppName,
+			BatchSize:  sink.BatchSize,
+			MaxRetries: sink.MaxRetries,
+			QueueSize:  sink.QueueSize,
+		}
+		if sink.FlushInterval > 0 {
+			sc.FlushInterval = sink.FlushInterval.String()
+		}
+		if sink.Timeout > 0 {
+			sc.Timeout = sink.Timeout.String()
+		}
+		cfg.AuditSinks = append(cfg.AuditSinks, sc)
+	}
+
+	if scan := safetyConfig.SecretScan; scan.Action != "" || len(scan.Rules) > 0 || len(scan.Allowlist) > 0 || len(scan.AllowPaths) > 0 || scan.EntropyThreshold > 0 {
+		cfg.SecretScanning = &SecretScanningConfig{
+			Action:           scan.Action,
+			AllowPaths:       scan.AllowPaths,
+			EntropyThreshold: scan.EntropyThreshold,
+		}
+		for _, rule := range scan.Rules {
+			cfg.SecretScanning.Rules = append(cfg.SecretScanning.Rules, SecretRuleConfig{Name: rule.Name, Pattern: rule.Pattern.String()})
+		}
+		for _, re := range scan.Allowlist {
+			cfg.SecretScanning.Allowlist = append(cfg.SecretScanning.Allowlist, re.String())
+		}
+	}
+
+	for _, window := range safetyConfig.Freezes {
+		fc := ChangeFreezeConfig{
+			Name:       window.Name,
+			Action:     string(window.Action),
+			Operations: window.Operations,
+			Repos:      window.Repos,
+			Branches:   window.Branches,
+		}
+		if window.Location != nil && window.Location != time.UTC {
+			fc.TimeZone = window.Location.String()
+		}
+		if window.Schedule != nil {
+			fc.Cron = window.Schedule.String()
+			fc.Duration = window.Duration.String()
+		} else {
+			fc.Start = window.Start.Format(time.RFC3339)
+			fc.End = window.End.Format(time.RFC3339)
+		}
+		cfg.ChangeFreezes = append(cfg.ChangeFreezes, fc)
+	}
+
+	if len(safetyConfig.AllowedRoots) > 0 {
+		cfg.Sandbox = &SandboxConfig{AllowedRoots: safetyConfig.AllowedRoots}
+	}
+
+	if allow := safetyConfig.WebhookAllowlist; len(allow.Hosts) > 0 || len(allow.Networks) > 0 {
+		cfg.WebhookAllowlist = &WebhookAllowlistConfig{Hosts: allow.Hosts}
+		for _, network := range allow.Networks {
+			cfg.WebhookAllowlist.Networks = append(cfg.WebhookAllowlist.Networks, network.String())
+		}
+	}
+
+	if len(safetyConfig.ApprovalOperations) > 0 {
+		cfg.Approval = &ApprovalConfig{
+			Enabled:    true,
+			Operations: safetyConfig.ApprovalOperations,
+			QueueFile:  safetyConfig.ApprovalQueuePath,
+			HTTPAddr:   safetyConfig.ApprovalHTTPAddr,
+		}
+		if safetyConfig.ApprovalExpiration > 0 {
+			cfg.Approval.Expiration = safetyConfig.ApprovalExpiration.String()
+		}
+	}
+
+	if len(safetyConfig.ProtectedBranches) > 0 || safetyConfig.MirrorRemoteProtection {
+		cfg.ProtectedBranches = &ProtectedBranchesConfig{
+			Patterns:     safetyConfig.ProtectedBranches,
+			Action:       string(safetyConfig.ProtectedBranchAction),
+			MirrorRemote: safetyConfig.MirrorRemoteProtection,
+		}
+	}
+
 	// Marshal to JSON with indentation
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
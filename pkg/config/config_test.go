@@ -1,9 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/scopweb/mcp-go-github/pkg/safety"
 )
@@ -85,6 +87,176 @@ func TestLoadConfig_ValidFile(t *testing.T) {
 	}
 }
 
+func TestLoadConfig_Rules(t *testing.T) {
+	tempDir := t.TempDir()
+
+	t.Run("Valid rules", func(t *testing.T) {
+		configPath := filepath.Join(tempDir, "rules.json")
+		configJSON := `{
+			"safetyMode": "moderate",
+			"globalSettings": {"requireConfirmationAbove": "high"},
+			"rules": [
+				{"name": "no-deletes-acme", "action": "deny", "operations": ["*:delete"], "repos": ["acme/*"]},
+				{"action": "require_confirmation", "operations": ["git_sync:push"], "branches": ["main", "release/*"], "minRisk": "MEDIUM"}
+			]
+		}`
+		if err := os.WriteFile(configPath, []byte(configJSON), 0644); err != nil {
+			t.Fatalf("Failed to create test config: %v", err)
+		}
+
+		config, err := LoadConfig(configPath)
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if len(config.Rules) != 2 {
+			t.Fatalf("Rules = %d, want 2", len(config.Rules))
+		}
+		if config.Rules[0].Action != safety.PolicyDeny || config.Rules[0].Repos[0] != "acme/*" {
+			t.Errorf("Rules[0] = %+v", config.Rules[0])
+		}
+		if config.Rules[1].MinRisk != safety.RiskMedium {
+			t.Errorf("Rules[1].MinRisk = %v, want MEDIUM", config.Rules[1].MinRisk)
+		}
+	})
+
+	t.Run("Invalid action rejected", func(t *testing.T) {
+		configPath := filepath.Join(tempDir, "bad-rules.json")
+		configJSON := `{"safetyMode": "moderate", "rules": [{"action": "block"}]}`
+		if err := os.WriteFile(configPath, []byte(configJSON), 0644); err != nil {
+			t.Fatalf("Failed to create test config: %v", err)
+		}
+		if _, err := LoadConfig(configPath); err == nil {
+			t.Error("LoadConfig() should reject an unknown rule action")
+		}
+	})
+
+	t.Run("Invalid minRisk rejected", func(t *testing.T) {
+		configPath := filepath.Join(tempDir, "bad-risk.json")
+		configJSON := `{"safetyMode": "moderate", "rules": [{"action": "deny", "minRisk": "extreme"}]}`
+		if err := os.WriteFile(configPath, []byte(configJSON), 0644); err != nil {
+			t.Fatalf("Failed to create test config: %v", err)
+		}
+		if _, err := LoadConfig(configPath); err == nil {
+			t.Error("LoadConfig() should reject an unknown minRisk")
+		}
+	})
+}
+
+func TestLoadConfig_ProtectedBranches(t *testing.T) {
+	tempDir := t.TempDir()
+
+	t.Run("Patterns and action", func(t *testing.T) {
+		configPath := filepath.Join(tempDir, "protected.json")
+		configJSON := `{
+			"safetyMode": "moderate",
+			"protectedBranches": {"patterns": ["main", "release/*"], "action": "require_confirmation", "mirrorRemote": true}
+		}`
+		if err := os.WriteFile(configPath, []byte(configJSON), 0644); err != nil {
+			t.Fatalf("Failed to create test config: %v", err)
+		}
+
+		config, err := LoadConfig(configPath)
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if len(config.ProtectedBranches) != 2 || config.ProtectedBranches[1] != "release/*" {
+			t.Errorf("ProtectedBranches = %v", config.ProtectedBranches)
+		}
+		if config.ProtectedBranchAction != safety.PolicyRequireConfirmation {
+			t.Errorf("ProtectedBranchAction = %q, want require_confirmation", config.ProtectedBranchAction)
+		}
+		if !config.MirrorRemoteProtection {
+			t.Error("MirrorRemoteProtection should be true")
+		}
+	})
+
+	t.Run("Action defaults to deny", func(t *testing.T) {
+		configPath := filepath.Join(tempDir, "protected-default.json")
+		configJSON := `{"safetyMode": "moderate", "protectedBranches": {"patterns": ["main"]}}`
+		if err := os.WriteFile(configPath, []byte(configJSON), 0644); err != nil {
+			t.Fatalf("Failed to create test config: %v", err)
+		}
+		config, err := LoadConfig(configPath)
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if config.ProtectedBranchAction != safety.PolicyDeny {
+			t.Errorf("ProtectedBranchAction = %q, want deny", config.ProtectedBranchAction)
+		}
+	})
+
+	t.Run("Invalid action rejected", func(t *testing.T) {
+		configPath := filepath.Join(tempDir, "protected-bad.json")
+		configJSON := `{"safetyMode": "moderate", "protectedBranches": {"patterns": ["main"], "action": "allow"}}`
+		if err := os.WriteFile(configPath, []byte(configJSON), 0644); err != nil {
+			t.Fatalf("Failed to create test config: %v", err)
+		}
+		if _, err := LoadConfig(configPath); err == nil {
+			t.Error("LoadConfig() should reject an unknown protectedBranches action")
+		}
+	})
+}
+
+func TestLoadConfig_ConfirmationTokens(t *testing.T) {
+	tempDir := t.TempDir()
+
+	t.Run("Expirations and default paths", func(t *testing.T) {
+		configPath := filepath.Join(tempDir, "tokens.json")
+		configJSON := `{
+			"safetyMode": "moderate",
+			"globalSettings": {"requireConfirmationAbove": "high", "tokenExpiration": {"critical": "2m", "HIGH": "10m"}}
+		}`
+		if err := os.WriteFile(configPath, []byte(configJSON), 0644); err != nil {
+			t.Fatalf("Failed to create test config: %v", err)
+		}
+
+		config, err := LoadConfig(configPath)
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if config.TokenExpirations[safety.RiskCritical] != 2*time.Minute || config.TokenExpirations[safety.RiskHigh] != 10*time.Minute {
+			t.Errorf("TokenExpirations = %v", config.TokenExpirations)
+		}
+		if config.TokenKeyPath != safety.DefaultTokenKeyPath || config.TokenReplayPath != safety.DefaultTokenReplayPath {
+			t.Errorf("token paths = %q, %q, want defaults", config.TokenKeyPath, config.TokenReplayPath)
+		}
+
+		savedPath := filepath.Join(tempDir, "tokens-saved.json")
+		if err := SaveConfig(savedPath, config); err != nil {
+			t.Fatalf("SaveConfig() error = %v", err)
+		}
+		reloaded, err := LoadConfig(savedPath)
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if reloaded.TokenExpirations[safety.RiskCritical] != 2*time.Minute {
+			t.Errorf("round-tripped TokenExpirations = %v", reloaded.TokenExpirations)
+		}
+	})
+
+	t.Run("Invalid duration rejected", func(t *testing.T) {
+		configPath := filepath.Join(tempDir, "tokens-bad.json")
+		configJSON := `{"safetyMode": "moderate", "globalSettings": {"tokenExpiration": {"high": "-1m"}}}`
+		if err := os.WriteFile(configPath, []byte(configJSON), 0644); err != nil {
+			t.Fatalf("Failed to create test config: %v", err)
+		}
+		if _, err := LoadConfig(configPath); err == nil {
+			t.Error("LoadConfig() should reject a non-positive token expiration")
+		}
+	})
+
+	t.Run("Invalid risk level rejected", func(t *testing.T) {
+		configPath := filepath.Join(tempDir, "tokens-bad-level.json")
+		configJSON := `{"safetyMode": "moderate", "globalSettings": {"tokenExpiration": {"severe": "1m"}}}`
+		if err := os.WriteFile(configPath, []byte(configJSON), 0644); err != nil {
+			t.Fatalf("Failed to create test config: %v", err)
+		}
+		if _, err := LoadConfig(configPath); err == nil {
+			t.Error("LoadConfig() should reject an unknown risk level")
+		}
+	})
+}
+
 func TestLoadConfig_InvalidJSON(t *testing.T) {
 	tempDir := t.TempDir()
 	configPath := filepath.Join(tempDir, "invalid.json")
@@ -432,6 +604,9 @@ func TestRoundTripConfig(t *testing.T) {
 		RequireConfirmationAbove: safety.RiskCritical,
 		EnableAutoBackup:         true,
 		BackupPath:               "./test-backups",
+		Rules: []safety.PolicyRule{
+			{Name: "confirm-main", Action: safety.PolicyRequireConfirmation, Branches: []string{"main"}, MinRisk: safety.RiskMedium},
+		},
 	}
 
 	// Save
@@ -465,6 +640,9 @@ func TestRoundTripConfig(t *testing.T) {
 	if loaded.BackupPath != original.BackupPath {
 		t.Errorf("BackupPath mismatch: %v != %v", loaded.BackupPath, original.BackupPath)
 	}
+	if len(loaded.Rules) != 1 || loaded.Rules[0].Name != "confirm-main" || loaded.Rules[0].MinRisk != safety.RiskMedium {
+		t.Errorf("Rules mismatch: %+v", loaded.Rules)
+	}
 }
 
 // Helper function to check if string contains substring
@@ -481,3 +659,433 @@ func contains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestLoadConfig_AuditSigningKey(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "audit.json")
+	configJSON := `{"safetyMode": "moderate", "globalSettings": {"enableAuditLog": true, "auditSigningKeyFile": "./audit.key"}}`
+	if err := os.WriteFile(configPath, []byte(configJSON), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if config.AuditKeyPath != "./audit.key" {
+		t.Errorf("AuditKeyPath = %q, want ./audit.key", config.AuditKeyPath)
+	}
+
+	savedPath := filepath.Join(tempDir, "audit-saved.json")
+	if err := SaveConfig(savedPath, config); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+	reloaded, err := LoadConfig(savedPath)
+	if err != nil || reloaded.AuditKeyPath != "./audit.key" {
+		t.Errorf("round-tripped AuditKeyPath = %q, %v", reloaded.AuditKeyPath, err)
+	}
+}
+
+func TestLoadConfig_AuditSinks(t *testing.T) {
+	tempDir := t.TempDir()
+
+	configPath := filepath.Join(tempDir, "sinks.json")
+	configJSON := `{
+		"safetyMode": "moderate",
+		"auditSinks": [
+			{"type": "syslog", "network": "tcp", "address": "siem.example.com:6514"},
+			{"type": "http", "url": "https://logs.example.com/ingest", "headers": {"Authorization": "Bearer ${AUDIT_TOKEN}"}, "flushInterval": "10s", "maxRetries": 5},
+			{"type": "otlp", "url": "http://collector:4318/v1/logs", "timeout": "2s"}
+		]
+	}`
+	if err := os.WriteFile(configPath, []byte(configJSON), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if len(config.AuditSinks) != 3 {
+		t.Fatalf("AuditSinks = %d, want 3", len(config.AuditSinks))
+	}
+	if sink := config.AuditSinks[1]; sink.FlushInterval != 10*time.Second || sink.MaxRetries != 5 || sink.Headers["Authorization"] != "Bearer ${AUDIT_TOKEN}" {
+		t.Errorf("http sink = %+v", sink)
+	}
+	if config.AuditSinks[2].Timeout != 2*time.Second {
+		t.Errorf("otlp timeout = %v", config.AuditSinks[2].Timeout)
+	}
+
+	savedPath := filepath.Join(tempDir, "sinks-saved.json")
+	if err := SaveConfig(savedPath, config); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+	reloaded, err := LoadConfig(savedPath)
+	if err != nil || len(reloaded.AuditSinks) != 3 || reloaded.AuditSinks[1].FlushInterval != 10*time.Second {
+		t.Errorf("round-tripped AuditSinks = %+v, %v", reloaded.AuditSinks, err)
+	}
+
+	invalid := []string{
+		`{"auditSinks": [{"type": "kafka"}]}`,
+		`{"auditSinks": [{"type": "syslog"}]}`,
+		`{"auditSinks": [{"type": "http"}]}`,
+		`{"auditSinks": [{"type": "otlp", "url": "http://c:4318/v1/logs", "timeout": "soon"}]}`,
+	}
+	for i, js := range invalid {
+		path := filepath.Join(tempDir, fmt.Sprintf("sinks-bad-%d.json", i))
+		if err := os.WriteFile(path, []byte(js), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("LoadConfig(%s) should fail", js)
+		}
+	}
+}
+
+func TestLoadConfig_RateLimits(t *testing.T) {
+	tempDir := t.TempDir()
+
+	configPath := filepath.Join(tempDir, "limits.json")
+	configJSON := `{
+		"safetyMode": "moderate",
+		"rateLimits": [
+			{"name": "critical-hourly", "riskLevel": "critical", "max": 3, "window": "1h"},
+			{"operations": ["github_collaborators:remove"], "max": 20, "window": "10m"}
+		]
+	}`
+	if err := os.WriteFile(configPath, []byte(configJSON), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if len(config.RateLimits) != 2 {
+		t.Fatalf("RateLimits = %d, want 2", len(config.RateLimits))
+	}
+	if limit := config.RateLimits[0]; limit.Level != safety.RiskCritical || limit.Max != 3 || limit.Window != time.Hour {
+		t.Errorf("first limit = %+v", limit)
+	}
+	if config.RateLimitStatePath != safety.DefaultRateLimitStatePath {
+		t.Errorf("RateLimitStatePath = %q, want default", config.RateLimitStatePath)
+	}
+
+	savedPath := filepath.Join(tempDir, "limits-saved.json")
+	if err := SaveConfig(savedPath, config); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+	reloaded, err := LoadConfig(savedPath)
+	if err != nil || len(reloaded.RateLimits) != 2 || reloaded.RateLimits[1].Window != 10*time.Minute || reloaded.RateLimits[0].Level != safety.RiskCritical {
+		t.Errorf("round-tripped RateLimits = %+v, %v", reloaded.RateLimits, err)
+	}
+
+	invalid := []string{
+		`{"rateLimits": [{"max": 0, "window": "1h"}]}`,
+		`{"rateLimits": [{"max": 1, "window": "hourly"}]}`,
+		`{"rateLimits": [{"max": 1, "window": "1h", "riskLevel": "severe"}]}`,
+	}
+	for i, js := range invalid {
+		path := filepath.Join(tempDir, fmt.Sprintf("limits-bad-%d.json", i))
+		if err := os.WriteFile(path, []byte(js), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("LoadConfig(%s) should fail", js)
+		}
+	}
+}
+
+func TestLoadConfig_Approval(t *testing.T) {
+	tempDir := t.TempDir()
+
+	configPath := filepath.Join(tempDir, "approval.json")
+	configJSON := `{
+		"safetyMode": "moderate",
+		"approval": {"enabled": true, "httpAddr": "127.0.0.1:8787", "expiration": "15m"}
+	}`
+	if err := os.WriteFile(configPath, []byte(configJSON), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if len(config.ApprovalOperations) != len(safety.DefaultApprovalOperations) {
+		t.Errorf("ApprovalOperations = %v, want defaults", config.ApprovalOperations)
+	}
+	if config.ApprovalQueuePath != safety.DefaultApprovalQueuePath || config.ApprovalExpiration != 15*time.Minute || config.ApprovalHTTPAddr != "127.0.0.1:8787" {
+		t.Errorf("approval settings = %q, %v, %q", config.ApprovalQueuePath, config.ApprovalExpiration, config.ApprovalHTTPAddr)
+	}
+
+	savedPath := filepath.Join(tempDir, "approval-saved.json")
+	if err := SaveConfig(savedPath, config); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+	reloaded, err := LoadConfig(savedPath)
+	if err != nil || len(reloaded.ApprovalOperations) != 3 || reloaded.ApprovalExpiration != 15*time.Minute {
+		t.Errorf("round-tripped approval = %v, %v, %v", reloaded.ApprovalOperations, reloaded.ApprovalExpiration, err)
+	}
+
+	disabledPath := filepath.Join(tempDir, "approval-off.json")
+	if err := os.WriteFile(disabledPath, []byte(`{"approval": {"enabled": false}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if disabled, err := LoadConfig(disabledPath); err != nil || len(disabled.ApprovalOperations) != 0 {
+		t.Errorf("disabled approval should gate nothing: %v, %v", disabled.ApprovalOperations, err)
+	}
+
+	invalid := []string{
+		`{"approval": {"enabled": true, "operations": ["github_admin_repo:nuke"]}}`,
+		`{"approval": {"enabled": true, "httpAddr": "0.0.0.0:8787"}}`,
+		`{"approval": {"enabled": true, "expiration": "soon"}}`,
+	}
+	for i, js := range invalid {
+		path := filepath.Join(tempDir, fmt.Sprintf("approval-bad-%d.json", i))
+		if err := os.WriteFile(path, []byte(js), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("LoadConfig(%s) should fail", js)
+		}
+	}
+}
+
+func TestLoadConfig_SecretScanning(t *testing.T) {
+	tempDir := t.TempDir()
+
+	configPath := filepath.Join(tempDir, "secrets.json")
+	configJSON := `{
+		"safetyMode": "moderate",
+		"secretScanning": {
+			"action": "warn",
+			"rules": [{"name": "internal_token", "pattern": "\\b(acme_[a-z0-9]{12})\\b"}],
+			"allowlist": ["EXAMPLE$"],
+			"allowPaths": ["testdata/**"],
+			"entropyThreshold": 4
+		}
+	}`
+	if err := os.WriteFile(configPath, []byte(configJSON), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	scan := config.SecretScan
+	if scan.Action != safety.SecretScanWarn || len(scan.Rules) != 1 || len(scan.Allowlist) != 1 || scan.AllowPaths[0] != "testdata/**" || scan.EntropyThreshold != 4 {
+		t.Errorf("SecretScan = %+v", scan)
+	}
+
+	savedPath := filepath.Join(tempDir, "secrets-saved.json")
+	if err := SaveConfig(savedPath, config); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+	reloaded, err := LoadConfig(savedPath)
+	if err != nil || len(reloaded.SecretScan.Rules) != 1 || reloaded.SecretScan.Rules[0].Pattern.String() != scan.Rules[0].Pattern.String() {
+		t.Errorf("round-tripped SecretScan = %+v, %v", reloaded.SecretScan, err)
+	}
+
+	invalid := []string{
+		`{"secretScanning": {"action": "ignore"}}`,
+		`{"secretScanning": {"rules": [{"name": "bad", "pattern": "("}]}}`,
+		`{"secretScanning": {"rules": [{"pattern": "x"}]}}`,
+		`{"secretScanning": {"allowlist": ["["]}}`,
+	}
+	for i, js := range invalid {
+		path := filepath.Join(tempDir, fmt.Sprintf("secrets-bad-%d.json", i))
+		if err := os.WriteFile(path, []byte(js), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("LoadConfig(%s) should fail", js)
+		}
+	}
+}
+
+func TestLoadConfig_ChangeFreezes(t *testing.T) {
+	tempDir := t.TempDir()
+
+	configPath := filepath.Join(tempDir, "freezes.json")
+	configJSON := `{
+		"safetyMode": "moderate",
+		"changeFreezes": [
+			{"name": "release-4.1", "start": "2026-11-02 09:00", "end": "2026-11-04 18:00", "timeZone": "Europe/Madrid", "repos": ["acme/*"]},
+			{"name": "weekend", "action": "require_confirmation", "cron": "0 18 * * 5", "duration": "63h", "branches": ["main"]}
+		]
+	}`
+	if err := os.WriteFile(configPath, []byte(configJSON), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if len(config.Freezes) != 2 {
+		t.Fatalf("Freezes = %+v", config.Freezes)
+	}
+	release, weekend := config.Freezes[0], config.Freezes[1]
+	if release.Action != safety.PolicyDeny || release.Start.UTC().Format(time.RFC3339) != "2026-11-02T08:00:00Z" || release.Repos[0] != "acme/*" {
+		t.Errorf("release freeze = %+v", release)
+	}
+	if weekend.Action != safety.PolicyRequireConfirmation || weekend.Schedule == nil || weekend.Duration != 63*time.Hour {
+		t.Errorf("weekend freeze = %+v", weekend)
+	}
+
+	savedPath := filepath.Join(tempDir, "freezes-saved.json")
+	if err := SaveConfig(savedPath, config); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+	reloaded, err := LoadConfig(savedPath)
+	if err != nil || len(reloaded.Freezes) != 2 || !reloaded.Freezes[0].End.Equal(release.End) ||
+		reloaded.Freezes[0].Location.String() != "Europe/Madrid" || reloaded.Freezes[1].Schedule.String() != "0 18 * * 5" {
+		t.Errorf("round-tripped Freezes = %+v, %v", reloaded.Freezes, err)
+	}
+
+	invalid := []string{
+		`{"changeFreezes": [{"start": "2026-11-04", "end": "2026-11-02"}]}`,
+		`{"changeFreezes": [{"start": "next monday", "end": "2026-11-02"}]}`,
+		`{"changeFreezes": [{"cron": "0 18 * *", "duration": "1h"}]}`,
+		`{"changeFreezes": [{"cron": "0 18 * * 5"}]}`,
+		`{"changeFreezes": [{"cron": "0 18 * * 5", "duration": "1h", "start": "2026-11-02"}]}`,
+		`{"changeFreezes": [{"start": "2026-11-02", "end": "2026-11-04", "timeZone": "Mars/Olympus"}]}`,
+		`{"changeFreezes": [{"start": "2026-11-02", "end": "2026-11-04", "action": "warn"}]}`,
+	}
+	for i, js := range invalid {
+		path := filepath.Join(tempDir, fmt.Sprintf("freezes-bad-%d.json", i))
+		if err := os.WriteFile(path, []byte(js), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("LoadConfig(%s) should fail", js)
+		}
+	}
+}
+
+func TestLoadConfig_Sandbox(t *testing.T) {
+	tempDir := t.TempDir()
+	root := filepath.Join(tempDir, "projects")
+
+	configPath := filepath.Join(tempDir, "sandbox.json")
+	configJSON := fmt.Sprintf(`{"safetyMode": "moderate", "sandbox": {"allowedRoots": [%q]}}`, root+string(filepath.Separator))
+	if err := os.WriteFile(configPath, []byte(configJSON), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if len(config.AllowedRoots) != 1 || config.AllowedRoots[0] != root {
+		t.Fatalf("AllowedRoots = %v, want [%s]", config.AllowedRoots, root)
+	}
+
+	savedPath := filepath.Join(tempDir, "sandbox-saved.json")
+	if err := SaveConfig(savedPath, config); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+	reloaded, err := LoadConfig(savedPath)
+	if err != nil || len(reloaded.AllowedRoots) != 1 || reloaded.AllowedRoots[0] != root {
+		t.Errorf("round-tripped AllowedRoots = %v, %v", reloaded.AllowedRoots, err)
+	}
+
+	invalid := []string{
+		`{"sandbox": {"allowedRoots": [""]}}`,
+		`{"sandbox": {"allowedRoots": ["relative/dir"]}}`,
+	}
+	for i, js := range invalid {
+		path := filepath.Join(tempDir, fmt.Sprintf("sandbox-bad-%d.json", i))
+		if err := os.WriteFile(path, []byte(js), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("LoadConfig(%s) should fail", js)
+		}
+	}
+}
+
+func TestLoadConfig_WebhookAllowlist(t *testing.T) {
+	tempDir := t.TempDir()
+
+	configPath := filepath.Join(tempDir, "webhooks.json")
+	configJSON := `{
+		"safetyMode": "moderate",
+		"webhookAllowlist": {"hosts": ["GHE.corp.example", "*.ci.corp.example"], "networks": ["10.20.0.0/16", "192.168.5.9"]}
+	}`
+	if err := os.WriteFile(configPath, []byte(configJSON), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	allow := config.WebhookAllowlist
+	if len(allow.Hosts) != 2 || allow.Hosts[0] != "ghe.corp.example" {
+		t.Errorf("Hosts = %v", allow.Hosts)
+	}
+	if len(allow.Networks) != 2 || allow.Networks[0].String() != "10.20.0.0/16" || allow.Networks[1].String() != "192.168.5.9/32" {
+		t.Errorf("Networks = %v", allow.Networks)
+	}
+
+	savedPath := filepath.Join(tempDir, "webhooks-saved.json")
+	if err := SaveConfig(savedPath, config); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+	reloaded, err := LoadConfig(savedPath)
+	if err != nil || len(reloaded.WebhookAllowlist.Networks) != 2 || reloaded.WebhookAllowlist.Hosts[1] != "*.ci.corp.example" {
+		t.Errorf("round-tripped WebhookAllowlist = %+v, %v", reloaded.WebhookAllowlist, err)
+	}
+
+	invalid := []string{
+		`{"webhookAllowlist": {"hosts": [""]}}`,
+		`{"webhookAllowlist": {"hosts": ["https://ghe.corp.example"]}}`,
+		`{"webhookAllowlist": {"networks": ["10.20.0.0/33"]}}`,
+		`{"webhookAllowlist": {"networks": ["intranet"]}}`,
+	}
+	for i, js := range invalid {
+		path := filepath.Join(tempDir, fmt.Sprintf("webhooks-bad-%d.json", i))
+		if err := os.WriteFile(path, []byte(js), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("LoadConfig(%s) should fail", js)
+		}
+	}
+}
+
+func TestLoadConfig_Example(t *testing.T) {
+	// The shipped example must use the real keys, so every section takes effect
+	config, err := LoadConfig(filepath.Join("..", "..", "safety.json.example"))
+	if err != nil {
+		t.Fatalf("LoadConfig(safety.json.example) error = %v", err)
+	}
+
+	if !config.EnableAuditLog || config.AuditKeyPath != "./.mcp-audit.key" || config.TokenExpirations[safety.RiskCritical] != 2*time.Minute {
+		t.Errorf("globalSettings not applied: %+v", config)
+	}
+	if len(config.AuditSinks) != 3 {
+		t.Errorf("AuditSinks = %d, want 3", len(config.AuditSinks))
+	}
+	if len(config.RateLimits) != 3 {
+		t.Errorf("RateLimits = %d, want 3", len(config.RateLimits))
+	}
+	if len(config.ApprovalOperations) != 3 || config.ApprovalHTTPAddr != "127.0.0.1:8787" {
+		t.Errorf("approval not applied: %v %q", config.ApprovalOperations, config.ApprovalHTTPAddr)
+	}
+	if config.SecretScan.Action != "block" || len(config.SecretScan.Rules) != 1 || len(config.SecretScan.AllowPaths) != 1 {
+		t.Errorf("secretScanning not applied: %+v", config.SecretScan)
+	}
+	if len(config.Freezes) != 2 {
+		t.Errorf("Freezes = %d, want 2", len(config.Freezes))
+	}
+	if len(config.WebhookAllowlist.Hosts) != 1 || len(config.WebhookAllowlist.Networks) != 1 {
+		t.Errorf("WebhookAllowlist = %+v", config.WebhookAllowlist)
+	}
+	if len(config.Rules) != 2 || len(config.ProtectedBranches) != 3 {
+		t.Errorf("rules/protectedBranches not applied: %d %v", len(config.Rules), config.ProtectedBranches)
+	}
+}
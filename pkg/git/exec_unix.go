@@ -0,0 +1,30 @@
+//go:build !windows
+
+package git
+
+import (
+	"errors"
+	"os"
+	exec_pkg "os/exec"
+	"syscall"
+)
+
+// setProcessGroup ejecuta el command en su propio grupo de procesos
+func setProcessGroup(cmd *exec_pkg.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killProcessGroup mata git y todos sus hijos (ssh, credential helpers)
+func killProcessGroup(cmd *exec_pkg.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	// Un pid negativo envía la señal a todo el grupo
+	if err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL); err != nil {
+		if errors.Is(err, syscall.ESRCH) {
+			return os.ErrProcessDone
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package git
+
+import (
+	exec_pkg "os/exec"
+	"strconv"
+	"syscall"
+)
+
+// setProcessGroup ejecuta el command en su propio grupo de procesos
+func setProcessGroup(cmd *exec_pkg.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// killProcessGroup mata git y todo su árbol de procesos (ssh, credential helpers)
+func killProcessGroup(cmd *exec_pkg.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	if err := exec_pkg.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run(); err != nil {
+		return cmd.Process.Kill()
+	}
+	return nil
+}
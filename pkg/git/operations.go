@@ -1,19 +1,35 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	exec_pkg "os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/scopweb/mcp-go-github/pkg/interfaces"
 	"github.com/scopweb/mcp-go-github/pkg/types"
 )
 
+// Tiempos máximos por defecto de los comandos git. Las operaciones de red
+// (fetch, pull, push...) pueden esperar a un remoto lento; las locales no.
+var (
+	localTimeout   = time.Minute
+	networkTimeout = 5 * time.Minute
+)
+
+// waitDelay es lo que se espera a que se cierren las tuberías tras matar el proceso
+const waitDelay = 5 * time.Second
+
+// maxTimeoutStderr limita la salida de error parcial incluida en un TimeoutError
+const maxTimeoutStderr = 2048
+
 // executor define la interfaz para ejecutar commands.
 type executor interface {
-	Command(name string, arg ...string) cmdWrapper
+	CommandContext(ctx context.Context, name string, arg ...string) cmdWrapper
 	LookPath(file string) (string, error)
 }
 
@@ -22,6 +38,7 @@ type cmdWrapper interface {
 	Output() ([]byte, error)
 	CombinedOutput() ([]byte, error)
 	SetDir(dir string)
+	SetEnv(env ...string)
 }
 
 // realCmd es la implementación real de la interfaz cmdWrapper.
@@ -34,14 +51,25 @@ func (c *realCmd) SetDir(dir string) {
 	c.Dir = dir
 }
 
+// SetEnv añade variables de entorno ("CLAVE=valor") al command; si ya
+// existían, prevalece el valor añadido.
+func (c *realCmd) SetEnv(env ...string) {
+	c.Env = append(c.Env, env...)
+}
+
 // realExecutor es la implementación real de la interfaz executor.
 type realExecutor struct{}
 
-// Command crea un nuevo command para ejecutar.
-func (e *realExecutor) Command(name string, arg ...string) cmdWrapper {
-	return &realCmd{
-		Cmd: exec_pkg.Command(name, arg...),
-	}
+// CommandContext crea un command que termina, junto con sus procesos hijos
+// (ssh, credential helpers), cuando se cancela ctx. Git nunca pide
+// credenciales por terminal: sin TTY la petición quedaría bloqueada.
+func (e *realExecutor) CommandContext(ctx context.Context, name string, arg ...string) cmdWrapper {
+	cmd := exec_pkg.CommandContext(ctx, name, arg...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GCM_INTERACTIVE=never")
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error { return killProcessGroup(cmd) }
+	cmd.WaitDelay = waitDelay
+	return &realCmd{Cmd: cmd}
 }
 
 // LookPath busca el ejecutable en el PATH del sistema.
@@ -49,10 +77,118 @@ func (e *realExecutor) LookPath(file string) (string, error) {
 	return exec_pkg.LookPath(file)
 }
 
+// TimeoutError indica que un comando git superó su tiempo máximo y fue terminado
+type TimeoutError struct {
+	Command string
+	Timeout time.Duration
+	Stderr  string // salida de error parcial antes de terminar el proceso
+}
+
+func (e *TimeoutError) Error() string {
+	msg := fmt.Sprintf("git %s: tiempo de espera agotado tras %s, proceso terminado", e.Command, e.Timeout)
+	if e.Stderr != "" {
+		msg += "; stderr parcial: " + e.Stderr
+	}
+	return msg
+}
+
+// timedCmd aplica el tiempo máximo de la operación a un command y libera su
+// contexto al terminar
+type timedCmd struct {
+	cmdWrapper
+	ctx     context.Context
+	cancel  context.CancelFunc
+	args    []string
+	timeout time.Duration
+}
+
+func (c *timedCmd) Output() ([]byte, error) {
+	defer c.cancel()
+	output, err := c.cmdWrapper.Output()
+	var stderr []byte
+	var exitErr *exec_pkg.ExitError
+	if errors.As(err, &exitErr) {
+		stderr = exitErr.Stderr
+	}
+	return output, c.timeoutErr(err, stderr)
+}
+
+func (c *timedCmd) CombinedOutput() ([]byte, error) {
+	defer c.cancel()
+	output, err := c.cmdWrapper.CombinedOutput()
+	return output, c.timeoutErr(err, output)
+}
+
+// timeoutErr sustituye el error por un TimeoutError si se agotó el tiempo, o
+// por uno que envuelve context.Canceled si se canceló la petición
+func (c *timedCmd) timeoutErr(err error, stderr []byte) error {
+	if err == nil || c.ctx.Err() == nil {
+		return err
+	}
+	if errors.Is(c.ctx.Err(), context.Canceled) {
+		return fmt.Errorf("git %s: petición cancelada, proceso terminado: %w", strings.Join(c.args, " "), context.Canceled)
+	}
+	partial := strings.TrimSpace(string(stderr))
+	if len(partial) > maxTimeoutStderr {
+		partial = "..." + partial[len(partial)-maxTimeoutStderr:]
+	}
+	return &TimeoutError{Command: strings.Join(c.args, " "), Timeout: c.timeout, Stderr: partial}
+}
+
+// gitError añade al error de un comando fallido su salida de error, que es
+// donde git explica el motivo (ref desconocida, ruta fuera del repositorio...)
+func gitError(err error) error {
+	var exitErr *exec_pkg.ExitError
+	if errors.As(err, &exitErr) {
+		if stderr := strings.TrimSpace(string(exitErr.Stderr)); stderr != "" {
+			return fmt.Errorf("%v: %s", err, stderr)
+		}
+	}
+	return err
+}
+
+// commandTimeout devuelve el tiempo máximo por defecto de un comando git
+func commandTimeout(args []string) time.Duration {
+	if len(args) == 0 {
+		return localTimeout
+	}
+	switch args[0] {
+	case "fetch", "pull", "push", "clone", "ls-remote", "submodule":
+		return networkTimeout
+	case "remote":
+		if len(args) > 1 && (args[1] == "update" || args[1] == "prune" || args[1] == "show") {
+			return networkTimeout
+		}
+	}
+	return localTimeout
+}
+
+// newGitCmd crea un comando git en dir con el tiempo máximo de su operación.
+// El proceso termina también si se cancela parent.
+func newGitCmd(parent context.Context, exec executor, dir string, args ...string) cmdWrapper {
+	if parent == nil {
+		parent = context.Background()
+	}
+	timeout := commandTimeout(args)
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.SetDir(dir)
+	return &timedCmd{cmdWrapper: cmd, ctx: ctx, cancel: cancel, args: args, timeout: timeout}
+}
+
 // Client es el cliente para interactuar con Git.
 type Client struct {
 	Config   *types.GitConfig
 	executor executor
+	ctx      context.Context // petición en curso; nil equivale a context.Background()
+}
+
+// WithContext devuelve una copia del cliente cuyos comandos git terminan al
+// cancelarse ctx. Comparte la configuración con el original.
+func (c *Client) WithContext(ctx context.Context) interfaces.GitOperations {
+	scoped := *c
+	scoped.ctx = ctx
+	return &scoped
 }
 
 // NewClientForTest crea un cliente con un ejecutor específico para pruebas.
@@ -76,33 +212,16 @@ func NewClient() (interfaces.GitOperations, error) {
 	}, nil
 }
 
-// enterWorkingDir cambia al directorio del repositorio Git y retorna una función
-// para restaurar el directorio original. Esto encapsula el patrón común de:
-//   originalDir, _ := os.Getwd()
-//   defer func() { _ = os.Chdir(originalDir) }()
-//   if err := os.Chdir(c.Config.RepoPath); err != nil { ... }
-func (c *Client) enterWorkingDir() (restore func(), err error) {
-	originalDir, _ := os.Getwd()
-
-	if err := os.Chdir(c.Config.RepoPath); err != nil {
-		return nil, fmt.Errorf("error cambiando al directorio del repositorio: %w", err)
-	}
-
-	// Retorna función que restaura el directorio original
-	return func() { _ = os.Chdir(originalDir) }, nil
+// gitCmd crea un comando git que se ejecuta en el repositorio. El directorio
+// se fija en el propio comando (SetDir) y nunca con os.Chdir, que afectaría a
+// todo el proceso y a las peticiones concurrentes.
+func (c *Client) gitCmd(args ...string) cmdWrapper {
+	return c.gitCmdIn(c.Config.RepoPath, args...)
 }
 
-// enterDir cambia a un directorio específico y retorna una función para restaurar
-// el directorio original. Útil para operaciones que necesitan cambiar a un directorio
-// arbitrario (no necesariamente c.Config.RepoPath).
-func enterDir(dir string) (restore func(), err error) {
-	originalDir, _ := os.Getwd()
-
-	if err := os.Chdir(dir); err != nil {
-		return nil, fmt.Errorf("error cambiando al directorio del repositorio: %w", err)
-	}
-
-	return func() { _ = os.Chdir(originalDir) }, nil
+// gitCmdIn crea un comando git que se ejecuta en dir
+func (c *Client) gitCmdIn(dir string, args ...string) cmdWrapper {
+	return newGitCmd(c.ctx, c.executor, dir, args...)
 }
 
 // detectGitEnvironment detecta y configura el entorno Git local.
@@ -130,17 +249,11 @@ func detectGitEnvironment(exec executor) types.GitConfig {
 	config.IsGitRepo = true
 	config.RepoPath = repoPath
 
-	originalDir, _ := os.Getwd()
-	if err := os.Chdir(repoPath); err != nil {
-		return config
-	}
-	defer func() { _ = os.Chdir(originalDir) }()
-
-	if output, err := exec.Command("git", "remote", "get-url", "origin").Output(); err == nil {
+	if output, err := newGitCmd(context.Background(), exec, repoPath, "remote", "get-url", "origin").Output(); err == nil {
 		config.RemoteURL = strings.TrimSpace(string(output))
 	}
 
-	if output, err := exec.Command("git", "branch", "--show-current").Output(); err == nil {
+	if output, err := newGitCmd(context.Background(), exec, repoPath, "branch", "--show-current").Output(); err == nil {
 		config.CurrentBranch = strings.TrimSpace(string(output))
 	}
 
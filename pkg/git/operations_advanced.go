@@ -13,29 +13,23 @@ func (c *Client) LogAnalysis(limit string) (string, error) {
 		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	if limit == "" {
 		limit = "20"
 	}
 
 	result := map[string]interface{}{}
 
-	cmd := c.executor.Command("git", "log", "--graph", "--oneline", "--decorate", "--all", "-"+limit)
+	cmd := c.gitCmd("log", "--graph", "--oneline", "--decorate", "--all", "-"+limit)
 	if output, err := cmd.Output(); err == nil {
 		result["graphLog"] = strings.TrimSpace(string(output))
 	}
 
-	cmd = c.executor.Command("git", "shortlog", "-sn", "--all")
+	cmd = c.gitCmd("shortlog", "-sn", "--all")
 	if output, err := cmd.Output(); err == nil {
 		result["authorStats"] = strings.TrimSpace(string(output))
 	}
 
-	cmd = c.executor.Command("git", "log", "--pretty=format:%h|%an|%ad|%s", "--date=short", "-"+limit)
+	cmd = c.gitCmd("log", "--pretty=format:%h|%an|%ad|%s", "--date=short", "-"+limit)
 	if output, err := cmd.Output(); err == nil {
 		result["recentCommits"] = strings.TrimSpace(string(output))
 	}
@@ -49,19 +43,13 @@ func (c *Client) DiffFiles(staged bool) (string, error) {
 		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	result := map[string]interface{}{}
 
 	var cmd cmdWrapper
 	if staged {
-		cmd = c.executor.Command("git", "diff", "--name-status", "--cached")
+		cmd = c.gitCmd("diff", "--name-status", "--cached")
 	} else {
-		cmd = c.executor.Command("git", "diff", "--name-status")
+		cmd = c.gitCmd("diff", "--name-status")
 	}
 
 	if output, err := cmd.Output(); err == nil {
@@ -69,9 +57,9 @@ func (c *Client) DiffFiles(staged bool) (string, error) {
 	}
 
 	if staged {
-		cmd = c.executor.Command("git", "diff", "--stat", "--cached")
+		cmd = c.gitCmd("diff", "--stat", "--cached")
 	} else {
-		cmd = c.executor.Command("git", "diff", "--stat")
+		cmd = c.gitCmd("diff", "--stat")
 	}
 
 	if output, err := cmd.Output(); err == nil {
@@ -79,7 +67,7 @@ func (c *Client) DiffFiles(staged bool) (string, error) {
 	}
 
 	if !staged {
-		cmd = c.executor.Command("git", "ls-files", "--others", "--exclude-standard")
+		cmd = c.gitCmd("ls-files", "--others", "--exclude-standard")
 		if output, err := cmd.Output(); err == nil {
 			untracked := strings.TrimSpace(string(output))
 			if untracked != "" {
@@ -97,18 +85,12 @@ func (c *Client) Stash(operation, name string) (string, error) {
 		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	var cmd cmdWrapper
 	var result string
 
 	switch operation {
 	case "list":
-		cmd = c.executor.Command("git", "stash", "list")
+		cmd = c.gitCmd("stash", "list")
 		if output, err := cmd.Output(); err == nil {
 			result = fmt.Sprintf("Stash list: %s", strings.TrimSpace(string(output)))
 		} else {
@@ -117,9 +99,9 @@ func (c *Client) Stash(operation, name string) (string, error) {
 
 	case "push":
 		if name != "" {
-			cmd = c.executor.Command("git", "stash", "push", "-m", name)
+			cmd = c.gitCmd("stash", "push", "-m", name)
 		} else {
-			cmd = c.executor.Command("git", "stash", "push")
+			cmd = c.gitCmd("stash", "push")
 		}
 		if output, err := cmd.CombinedOutput(); err == nil {
 			result = fmt.Sprintf("Stash creado: %s", strings.TrimSpace(string(output)))
@@ -129,9 +111,9 @@ func (c *Client) Stash(operation, name string) (string, error) {
 
 	case "pop":
 		if name != "" {
-			cmd = c.executor.Command("git", "stash", "pop", name)
+			cmd = c.gitCmd("stash", "pop", name)
 		} else {
-			cmd = c.executor.Command("git", "stash", "pop")
+			cmd = c.gitCmd("stash", "pop")
 		}
 		if output, err := cmd.CombinedOutput(); err == nil {
 			result = fmt.Sprintf("Stash aplicado y eliminado: %s", strings.TrimSpace(string(output)))
@@ -141,9 +123,9 @@ func (c *Client) Stash(operation, name string) (string, error) {
 
 	case "apply":
 		if name != "" {
-			cmd = c.executor.Command("git", "stash", "apply", name)
+			cmd = c.gitCmd("stash", "apply", name)
 		} else {
-			cmd = c.executor.Command("git", "stash", "apply")
+			cmd = c.gitCmd("stash", "apply")
 		}
 		if output, err := cmd.CombinedOutput(); err == nil {
 			result = fmt.Sprintf("Stash aplicado (mantenido): %s", strings.TrimSpace(string(output)))
@@ -153,9 +135,9 @@ func (c *Client) Stash(operation, name string) (string, error) {
 
 	case "drop":
 		if name != "" {
-			cmd = c.executor.Command("git", "stash", "drop", name)
+			cmd = c.gitCmd("stash", "drop", name)
 		} else {
-			cmd = c.executor.Command("git", "stash", "drop")
+			cmd = c.gitCmd("stash", "drop")
 		}
 		if output, err := cmd.CombinedOutput(); err == nil {
 			result = fmt.Sprintf("Stash eliminado: %s", strings.TrimSpace(string(output)))
@@ -164,7 +146,7 @@ func (c *Client) Stash(operation, name string) (string, error) {
 		}
 
 	case "clear":
-		cmd = c.executor.Command("git", "stash", "clear")
+		cmd = c.gitCmd("stash", "clear")
 		if output, err := cmd.CombinedOutput(); err == nil {
 			result = "Todos los stashes han sido eliminados"
 		} else {
@@ -183,18 +165,12 @@ func (c *Client) Remote(operation, name, url string) (string, error) {
 		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	var cmd cmdWrapper
 	var result string
 
 	switch operation {
 	case "list":
-		cmd = c.executor.Command("git", "remote", "-v")
+		cmd = c.gitCmd("remote", "-v")
 		if output, err := cmd.Output(); err == nil {
 			result = fmt.Sprintf("Remotos configurados: %s", strings.TrimSpace(string(output)))
 		} else {
@@ -205,7 +181,7 @@ func (c *Client) Remote(operation, name, url string) (string, error) {
 		if name == "" || url == "" {
 			return "", fmt.Errorf("nombre y URL requeridos para agregar remoto")
 		}
-		cmd = c.executor.Command("git", "remote", "add", name, url)
+		cmd = c.gitCmd("remote", "add", name, url)
 		if output, err := cmd.CombinedOutput(); err == nil {
 			result = fmt.Sprintf("Remoto '%s' agregado: %s", name, url)
 		} else {
@@ -216,7 +192,7 @@ func (c *Client) Remote(operation, name, url string) (string, error) {
 		if name == "" {
 			return "", fmt.Errorf("nombre del remoto requerido")
 		}
-		cmd = c.executor.Command("git", "remote", "remove", name)
+		cmd = c.gitCmd("remote", "remove", name)
 		if output, err := cmd.CombinedOutput(); err == nil {
 			result = fmt.Sprintf("Remoto '%s' eliminado", name)
 		} else {
@@ -227,7 +203,7 @@ func (c *Client) Remote(operation, name, url string) (string, error) {
 		if name == "" {
 			name = "origin"
 		}
-		cmd = c.executor.Command("git", "remote", "show", name)
+		cmd = c.gitCmd("remote", "show", name)
 		if output, err := cmd.Output(); err == nil {
 			result = fmt.Sprintf("Información del remoto '%s': %s", name, strings.TrimSpace(string(output)))
 		} else {
@@ -236,10 +212,10 @@ func (c *Client) Remote(operation, name, url string) (string, error) {
 
 	case "fetch":
 		if name == "" {
-			cmd = c.executor.Command("git", "fetch", "--all")
+			cmd = c.gitCmd("fetch", "--all")
 			result = "Fetching desde todos los remotos"
 		} else {
-			cmd = c.executor.Command("git", "fetch", name)
+			cmd = c.gitCmd("fetch", name)
 			result = fmt.Sprintf("Fetching desde '%s'", name)
 		}
 		if output, err := cmd.CombinedOutput(); err == nil {
@@ -260,18 +236,12 @@ func (c *Client) Tag(operation, tagName, message string) (string, error) {
 		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	var cmd cmdWrapper
 	var result string
 
 	switch operation {
 	case "list":
-		cmd = c.executor.Command("git", "tag", "-l", "--sort=-version:refname")
+		cmd = c.gitCmd("tag", "-l", "--sort=-version:refname")
 		if output, err := cmd.Output(); err == nil {
 			result = fmt.Sprintf("Tags disponibles: %s", strings.TrimSpace(string(output)))
 		} else {
@@ -283,9 +253,9 @@ func (c *Client) Tag(operation, tagName, message string) (string, error) {
 			return "", fmt.Errorf("nombre del tag requerido")
 		}
 		if message != "" {
-			cmd = c.executor.Command("git", "tag", "-a", tagName, "-m", message)
+			cmd = c.gitCmd("tag", "-a", tagName, "-m", message)
 		} else {
-			cmd = c.executor.Command("git", "tag", tagName)
+			cmd = c.gitCmd("tag", tagName)
 		}
 		if output, err := cmd.CombinedOutput(); err == nil {
 			result = fmt.Sprintf("Tag '%s' creado", tagName)
@@ -297,7 +267,7 @@ func (c *Client) Tag(operation, tagName, message string) (string, error) {
 		if tagName == "" {
 			return "", fmt.Errorf("nombre del tag requerido")
 		}
-		cmd = c.executor.Command("git", "tag", "-d", tagName)
+		cmd = c.gitCmd("tag", "-d", tagName)
 		if output, err := cmd.CombinedOutput(); err == nil {
 			result = fmt.Sprintf("Tag '%s' eliminado localmente", tagName)
 		} else {
@@ -306,10 +276,10 @@ func (c *Client) Tag(operation, tagName, message string) (string, error) {
 
 	case "push":
 		if tagName == "" {
-			cmd = c.executor.Command("git", "push", "origin", "--tags")
+			cmd = c.gitCmd("push", "origin", "--tags")
 			result = "Todos los tags enviados al remoto"
 		} else {
-			cmd = c.executor.Command("git", "push", "origin", tagName)
+			cmd = c.gitCmd("push", "origin", tagName)
 			result = fmt.Sprintf("Tag '%s' enviado al remoto", tagName)
 		}
 		if output, err := cmd.CombinedOutput(); err == nil {
@@ -322,7 +292,7 @@ func (c *Client) Tag(operation, tagName, message string) (string, error) {
 		if tagName == "" {
 			return "", fmt.Errorf("nombre del tag requerido")
 		}
-		cmd = c.executor.Command("git", "show", tagName)
+		cmd = c.gitCmd("show", tagName)
 		if output, err := cmd.Output(); err == nil {
 			result = fmt.Sprintf("Información del tag '%s': %s", tagName, strings.TrimSpace(string(output)))
 		} else {
@@ -341,49 +311,43 @@ func (c *Client) Clean(operation string, dryRun bool) (string, error) {
 		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	var cmd cmdWrapper
 	var result string
 
 	switch operation {
 	case "untracked":
 		if dryRun {
-			cmd = c.executor.Command("git", "clean", "-n")
+			cmd = c.gitCmd("clean", "-n")
 			result = "Vista previa - archivos que se eliminarían:"
 		} else {
-			cmd = c.executor.Command("git", "clean", "-f")
+			cmd = c.gitCmd("clean", "-f")
 			result = "Archivos sin seguimiento eliminados:"
 		}
 
 	case "untracked_dirs":
 		if dryRun {
-			cmd = c.executor.Command("git", "clean", "-n", "-d")
+			cmd = c.gitCmd("clean", "-n", "-d")
 			result = "Vista previa - archivos y directories que se eliminarían:"
 		} else {
-			cmd = c.executor.Command("git", "clean", "-f", "-d")
+			cmd = c.gitCmd("clean", "-f", "-d")
 			result = "Archivos y directories sin seguimiento eliminados:"
 		}
 
 	case "ignored":
 		if dryRun {
-			cmd = c.executor.Command("git", "clean", "-n", "-X")
+			cmd = c.gitCmd("clean", "-n", "-X")
 			result = "Vista previa - archivos ignorados que se eliminarían:"
 		} else {
-			cmd = c.executor.Command("git", "clean", "-f", "-X")
+			cmd = c.gitCmd("clean", "-f", "-X")
 			result = "Archivos ignorados eliminados:"
 		}
 
 	case "all":
 		if dryRun {
-			cmd = c.executor.Command("git", "clean", "-n", "-d", "-x")
+			cmd = c.gitCmd("clean", "-n", "-d", "-x")
 			result = "Vista previa - todos los archivos sin seguimiento que se eliminarían:"
 		} else {
-			cmd = c.executor.Command("git", "clean", "-f", "-d", "-x")
+			cmd = c.gitCmd("clean", "-f", "-d", "-x")
 			result = "Todos los archivos sin seguimiento eliminados:"
 		}
 
@@ -411,12 +375,6 @@ func (c *Client) ConflictStatus() (string, error) {
 		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	result := map[string]interface{}{}
 
 	// Check if in merge state
@@ -444,8 +402,13 @@ func (c *Client) ConflictStatus() (string, error) {
 		result["inRebaseState"] = false
 	}
 
+	// Check if a cherry-pick or revert stopped (also between commits of a range)
+	sequencer := c.sequencerInProgress()
+	result["inCherryPickState"] = sequencer == "cherry_pick"
+	result["inRevertState"] = sequencer == "revert"
+
 	// Get conflicted files
-	statusCmd := c.executor.Command("git", "status", "--porcelain")
+	statusCmd := c.gitCmd("status", "--porcelain")
 	statusOutput, err := statusCmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("error obteniendo status: %v", err)
@@ -472,12 +435,6 @@ func (c *Client) ResolveConflicts(strategy string) (string, error) {
 		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	// Paso 1: Obtener lista de archivos en conflicto
 	conflictedFiles, err := c.getConflictedFiles()
 	if err != nil {
@@ -494,13 +451,13 @@ func (c *Client) ResolveConflicts(strategy string) (string, error) {
 	switch strategy {
 	case "theirs":
 		// Aceptar versión remota para todos los conflicts
-		cmd = c.executor.Command("git", "checkout", "--theirs", ".")
+		cmd = c.gitCmd("checkout", "--theirs", ".")
 		if output, err := cmd.CombinedOutput(); err != nil {
 			return "", fmt.Errorf("error aceptando cambios remotos: %v, Output: %s", err, output)
 		}
 
 		// Agregar archivos resueltos
-		addCmd := c.executor.Command("git", "add", ".")
+		addCmd := c.gitCmd("add", ".")
 		if output, err := addCmd.CombinedOutput(); err != nil {
 			return "", fmt.Errorf("error agregando archivos resueltos: %v, Output: %s", err, output)
 		}
@@ -509,13 +466,13 @@ func (c *Client) ResolveConflicts(strategy string) (string, error) {
 
 	case "ours":
 		// Aceptar versión local para todos los conflicts
-		cmd = c.executor.Command("git", "checkout", "--ours", ".")
+		cmd = c.gitCmd("checkout", "--ours", ".")
 		if output, err := cmd.CombinedOutput(); err != nil {
 			return "", fmt.Errorf("error aceptando cambios locales: %v, Output: %s", err, output)
 		}
 
 		// Agregar archivos resueltos
-		addCmd := c.executor.Command("git", "add", ".")
+		addCmd := c.gitCmd("add", ".")
 		if output, err := addCmd.CombinedOutput(); err != nil {
 			return "", fmt.Errorf("error agregando archivos resueltos: %v, Output: %s", err, output)
 		}
@@ -523,10 +480,10 @@ func (c *Client) ResolveConflicts(strategy string) (string, error) {
 		result = fmt.Sprintf("Conflicts resueltos en %d archivo(s) aceptando versión local (ours)", len(conflictedFiles))
 
 	case "abort":
-		// Abortar merge o rebase
+		// Abortar merge, rebase, cherry-pick o revert
 		mergeHeadPath := filepath.Join(c.Config.RepoPath, ".git", "MERGE_HEAD")
 		if _, err := os.Stat(mergeHeadPath); err == nil {
-			cmd = c.executor.Command("git", "merge", "--abort")
+			cmd = c.gitCmd("merge", "--abort")
 		} else {
 			// Verificar si hay rebase activo
 			rebaseDirPath := filepath.Join(c.Config.RepoPath, ".git", "rebase-merge")
@@ -534,9 +491,11 @@ func (c *Client) ResolveConflicts(strategy string) (string, error) {
 			_, rebaseDirErr := os.Stat(rebaseDirPath)
 			_, rebaseApplyErr := os.Stat(rebaseApplyPath)
 			if rebaseDirErr == nil || rebaseApplyErr == nil {
-				cmd = c.executor.Command("git", "rebase", "--abort")
+				cmd = c.gitCmd("rebase", "--abort")
+			} else if sequencer := c.sequencerInProgress(); sequencer != "" {
+				cmd = c.gitCmd(sequencerOps[sequencer].command, "--abort")
 			} else {
-				return "", fmt.Errorf("no hay operación de merge o rebase activa para abortar")
+				return "", fmt.Errorf("no hay operación de merge, rebase, cherry-pick o revert activa para abortar")
 			}
 		}
 
@@ -567,7 +526,7 @@ func (c *Client) ResolveConflicts(strategy string) (string, error) {
 
 // getConflictedFiles obtiene la lista de archivos en conflicto
 func (c *Client) getConflictedFiles() ([]string, error) {
-	statusCmd := c.executor.Command("git", "status", "--porcelain")
+	statusCmd := c.gitCmd("status", "--porcelain")
 	statusOutput, err := statusCmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo status: %v", err)
@@ -595,20 +554,11 @@ func (c *Client) ValidateCleanState() (bool, error) {
 		return false, fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
+	status, err := c.StatusInfo()
 	if err != nil {
 		return false, err
 	}
-	defer restore()
-
-	cmd := c.executor.Command("git", "status", "--porcelain")
-	output, err := cmd.Output()
-	if err != nil {
-		return false, fmt.Errorf("error obteniendo status: %v", err)
-	}
-
-	// Empty output means clean state
-	return strings.TrimSpace(string(output)) == "", nil
+	return status.Clean, nil
 }
 
 func (c *Client) DetectPotentialConflicts(sourceBranch string, targetBranch string) (string, error) {
@@ -616,14 +566,8 @@ func (c *Client) DetectPotentialConflicts(sourceBranch string, targetBranch stri
 		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	// Get merge base
-	mergeBaseCmd := c.executor.Command("git", "merge-base", sourceBranch, targetBranch)
+	mergeBaseCmd := c.gitCmd("merge-base", sourceBranch, targetBranch)
 	mergeBase, err := mergeBaseCmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("error encontrando merge base: %v", err)
@@ -632,13 +576,13 @@ func (c *Client) DetectPotentialConflicts(sourceBranch string, targetBranch stri
 	mergeBaseHash := strings.TrimSpace(string(mergeBase))
 
 	// Get files changed in both branches since merge base
-	sourceFilesCmd := c.executor.Command("git", "diff", "--name-only", mergeBaseHash, sourceBranch)
+	sourceFilesCmd := c.gitCmd("diff", "--name-only", mergeBaseHash, sourceBranch)
 	sourceFiles, err := sourceFilesCmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("error obteniendo archivos de rama origen: %v", err)
 	}
 
-	targetFilesCmd := c.executor.Command("git", "diff", "--name-only", mergeBaseHash, targetBranch)
+	targetFilesCmd := c.gitCmd("diff", "--name-only", mergeBaseHash, targetBranch)
 	targetFiles, err := targetFilesCmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("error obteniendo archivos de rama destino: %v", err)
@@ -670,24 +614,18 @@ func (c *Client) CreateBackup(name string) (string, error) {
 		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	// Create tag as backup
 	tagName := fmt.Sprintf("backup/%s", name)
 
 	// Get current commit
-	commitCmd := c.executor.Command("git", "rev-parse", "HEAD")
+	commitCmd := c.gitCmd("rev-parse", "HEAD")
 	commitHash, err := commitCmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("error obteniendo commit actual: %v", err)
 	}
 
 	// Create backup tag
-	tagCmd := c.executor.Command("git", "tag", tagName, strings.TrimSpace(string(commitHash)))
+	tagCmd := c.gitCmd("tag", tagName, strings.TrimSpace(string(commitHash)))
 	output, err := tagCmd.CombinedOutput()
 	if err != nil {
 		return "", fmt.Errorf("error creando backup tag: %v, Output: %s", err, output)
@@ -737,14 +675,8 @@ func (c *Client) Reset(mode string, target string, files []string) (string, erro
 		}
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	// Validar que el target existe
-	validateCmd := c.executor.Command("git", "rev-parse", target)
+	validateCmd := c.gitCmd("rev-parse", target)
 	if _, err := validateCmd.Output(); err != nil {
 		return "", fmt.Errorf("target inválido '%s': %v", target, err)
 	}
@@ -759,10 +691,10 @@ func (c *Client) Reset(mode string, target string, files []string) (string, erro
 		}
 		args = append(args, target)
 		args = append(args, files...)
-		cmd = c.executor.Command("git", args...)
+		cmd = c.gitCmd(args...)
 	} else {
 		// Reset completo
-		cmd = c.executor.Command("git", "reset", "--"+mode, target)
+		cmd = c.gitCmd("reset", "--"+mode, target)
 	}
 
 	output, err := cmd.CombinedOutput()
@@ -784,14 +716,11 @@ func (c *Client) ShowConflict(filePath string) (string, error) {
 		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
+	// Leer contenido del archivo
+	fullPath, err := repoFile(c.Config.RepoPath, filePath)
 	if err != nil {
 		return "", err
 	}
-	defer restore()
-
-	// Leer contenido del archivo
-	fullPath := filepath.Join(c.Config.RepoPath, filePath)
 	content, err := os.ReadFile(fullPath)
 	if err != nil {
 		return "", fmt.Errorf("error leyendo archivo '%s': %v", filePath, err)
@@ -910,14 +839,11 @@ func (c *Client) ResolveFile(filePath string, strategy string, customContent *st
 		return "", fmt.Errorf("estrategia inválida: %s. Usa: ours, theirs, manual", strategy)
 	}
 
-	restore, err := c.enterWorkingDir()
+	// Validar que el archivo existe
+	fullPath, err := repoFile(c.Config.RepoPath, filePath)
 	if err != nil {
 		return "", err
 	}
-	defer restore()
-
-	// Validar que el archivo existe
-	fullPath := filepath.Join(c.Config.RepoPath, filePath)
 	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
 		return "", fmt.Errorf("archivo no encontrado: %s", filePath)
 	}
@@ -928,12 +854,12 @@ func (c *Client) ResolveFile(filePath string, strategy string, customContent *st
 	switch strategy {
 	case "ours":
 		// Aceptar nuestra versión
-		cmd = c.executor.Command("git", "checkout", "--ours", filePath)
+		cmd = c.gitCmd("checkout", "--ours", filePath)
 		result = "Archivo resuelto aceptando nuestra versión (ours)"
 
 	case "theirs":
 		// Aceptar su versión
-		cmd = c.executor.Command("git", "checkout", "--theirs", filePath)
+		cmd = c.gitCmd("checkout", "--theirs", filePath)
 		result = "Archivo resuelto aceptando su versión (theirs)"
 
 	case "manual":
@@ -958,7 +884,7 @@ func (c *Client) ResolveFile(filePath string, strategy string, customContent *st
 	}
 
 	// Agregar archivo resuelto al staging
-	addCmd := c.executor.Command("git", "add", filePath)
+	addCmd := c.gitCmd("add", filePath)
 	if output, err := addCmd.CombinedOutput(); err != nil {
 		return "", fmt.Errorf("error agregando archivo: %v, Output: %s", err, output)
 	}
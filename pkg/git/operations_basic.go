@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -25,26 +26,27 @@ func (c *Client) Status() (string, error) {
 		return string(output), nil
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		result["message"] = err.Error()
+	if _, err := os.Stat(c.Config.RepoPath); err != nil {
+		result["message"] = fmt.Sprintf("error accediendo al directorio del repositorio: %v", err)
 		output, _ := json.MarshalIndent(result, "", "  ")
 		return string(output), nil
 	}
-	defer restore()
 
 	// Re-read current remote URL from .git/config (not cached value)
-	if output, err := c.executor.Command("git", "remote", "get-url", "origin").Output(); err == nil {
+	if output, err := c.gitCmd("remote", "get-url", "origin").Output(); err == nil {
 		c.Config.RemoteURL = strings.TrimSpace(string(output))
 	} else {
 		c.Config.RemoteURL = ""
 	}
 
-	if output, err := c.executor.Command("git", "status", "--porcelain").Output(); err == nil {
-		result["status"] = strings.TrimSpace(string(output))
+	if status, err := c.StatusInfo(); err == nil {
+		result["status"] = status
+		if status.Branch != "" {
+			c.Config.CurrentBranch = status.Branch
+		}
 	}
 
-	if output, err := c.executor.Command("git", "log", "--oneline", "-5").Output(); err == nil {
+	if output, err := c.gitCmd("log", "--oneline", "-5").Output(); err == nil {
 		result["recentCommits"] = strings.TrimSpace(string(output))
 	}
 
@@ -65,8 +67,7 @@ func (c *Client) Add(files string) (string, error) {
 
 	// Build args: ["add", file1, file2, ...]
 	args := append([]string{"add"}, parts...)
-	cmd := c.executor.Command("git", args...)
-	cmd.SetDir(c.Config.RepoPath)
+	cmd := c.gitCmd(args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return "", fmt.Errorf("error ejecutando git add: %s", string(output))
@@ -76,13 +77,16 @@ func (c *Client) Add(files string) (string, error) {
 
 // Commit realiza un commit con el mensaje proporcionado.
 func (c *Client) Commit(message string) (string, error) {
-	cmd := c.executor.Command("git", "commit", "-m", message)
-	cmd.SetDir(c.Config.RepoPath)
+	warning, err := c.scanStaged()
+	if err != nil {
+		return "", err
+	}
+	cmd := c.gitCmd("commit", "-m", message)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return "", fmt.Errorf("error ejecutando git commit: %s", string(output))
 	}
-	return fmt.Sprintf("Commit realizado: %s. Salida: %s", message, string(output)), nil
+	return fmt.Sprintf("Commit realizado: %s. Salida: %s%s", message, string(output), warning), nil
 }
 
 // Push realiza un push a la rama especificada.
@@ -91,8 +95,7 @@ func (c *Client) Push(branch string) (string, error) {
 		branch = c.Config.CurrentBranch
 	}
 	// Obtener el nombre del control remoto
-	cmdRemote := c.executor.Command("git", "remote")
-	cmdRemote.SetDir(c.Config.RepoPath)
+	cmdRemote := c.gitCmd("remote")
 	remoteOutput, err := cmdRemote.Output()
 	if err != nil {
 		return "", fmt.Errorf("error al obtener remotos de Git: %w", err)
@@ -103,15 +106,19 @@ func (c *Client) Push(branch string) (string, error) {
 	}
 	remote := remotes[0] // Usar el primer remoto encontrado
 
+	warning, err := c.scanPush(remote, branch)
+	if err != nil {
+		return "", err
+	}
+
 	// Ejecutar git push
-	cmdPush := c.executor.Command("git", "push", remote, branch)
-	cmdPush.SetDir(c.Config.RepoPath)
+	cmdPush := c.gitCmd("push", remote, branch)
 	output, err := cmdPush.CombinedOutput()
 	if err != nil {
 		return "", fmt.Errorf("error ejecutando git push: %s", string(output))
 	}
 
-	return fmt.Sprintf("Push a '%s' en la rama '%s' realizado con éxito. Salida: %s", remote, branch, string(output)), nil
+	return fmt.Sprintf("Push a '%s' en la rama '%s' realizado con éxito. Salida: %s%s", remote, branch, string(output), warning), nil
 }
 
 func (c *Client) Pull(branch string) (string, error) {
@@ -119,19 +126,13 @@ func (c *Client) Pull(branch string) (string, error) {
 		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	effectiveBranch := branch
 	if effectiveBranch == "" {
 		effectiveBranch = c.Config.CurrentBranch
 	}
 
 	// Paso 1: Fetch para tener info actualizada del remoto
-	fetchCmd := c.executor.Command("git", "fetch", "origin", effectiveBranch)
+	fetchCmd := c.gitCmd("fetch", "origin", effectiveBranch)
 	if output, err := fetchCmd.CombinedOutput(); err != nil {
 		return "", fmt.Errorf("error en fetch inicial: %v, Output: %s", err, output)
 	}
@@ -144,7 +145,7 @@ func (c *Client) Pull(branch string) (string, error) {
 
 	// Paso 3: Si no hay divergencias, hacer fast-forward
 	if divergenceInfo["canFastForward"].(bool) {
-		cmd := c.executor.Command("git", "pull", "--ff-only", "origin", effectiveBranch)
+		cmd := c.gitCmd("pull", "--ff-only", "origin", effectiveBranch)
 		output, err := cmd.CombinedOutput()
 		if err != nil {
 			return "", fmt.Errorf("error en pull (ff-only): %v, Output: %s", err, output)
@@ -155,7 +156,7 @@ func (c *Client) Pull(branch string) (string, error) {
 	// Paso 4: Si hay divergencias, intentar merge regular
 	if divergenceInfo["aheadCount"].(int) > 0 && divergenceInfo["behindCount"].(int) > 0 {
 		// Divergencia: usar merge strategy
-		cmd := c.executor.Command("git", "pull", "--no-rebase", "origin", effectiveBranch)
+		cmd := c.gitCmd("pull", "--no-rebase", "origin", effectiveBranch)
 		output, err := cmd.CombinedOutput()
 		if err != nil {
 			if strings.Contains(string(output), "CONFLICT") {
@@ -167,7 +168,7 @@ func (c *Client) Pull(branch string) (string, error) {
 	}
 
 	// Solo cambios remotos
-	cmd := c.executor.Command("git", "pull", "origin", effectiveBranch)
+	cmd := c.gitCmd("pull", "origin", effectiveBranch)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return "", fmt.Errorf("error ejecutando git pull: %v, Output: %s", err, output)
@@ -186,7 +187,7 @@ func (c *Client) checkDivergence(branch string) (map[string]interface{}, error)
 	}
 
 	// Contar commits adelante y atrás
-	countCmd := c.executor.Command("git", "rev-list", "--left-right", "--count", fmt.Sprintf("HEAD...origin/%s", branch))
+	countCmd := c.gitCmd("rev-list", "--left-right", "--count", fmt.Sprintf("HEAD...origin/%s", branch))
 	output, err := countCmd.Output()
 	if err != nil {
 		return result, fmt.Errorf("error contando divergencias: %v", err)
@@ -241,17 +242,19 @@ func (c *Client) Init(path string, initialBranch string) (string, error) {
 		return "", fmt.Errorf("git no está disponible en el sistema")
 	}
 
+	if err := checkDir(path); err != nil {
+		return "", err
+	}
+
 	// Validar que el directorio existe
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return "", fmt.Errorf("el directorio no existe: %s", path)
 	}
 
-	// Cambiar al directorio
-	restore, err := enterDir(path)
-	if err != nil {
-		return "", err
+	// Guardar la ruta absoluta: los comandos y archivos no dependen del cwd
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
 	}
-	defer restore()
 
 	// Determinar rama inicial
 	branch := initialBranch
@@ -260,7 +263,7 @@ func (c *Client) Init(path string, initialBranch string) (string, error) {
 	}
 
 	// Ejecutar git init con rama inicial
-	cmd := c.executor.Command("git", "init", "-b", branch)
+	cmd := c.gitCmdIn(path, "init", "-b", branch)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return "", fmt.Errorf("git init falló en %s: %s", path, string(output))
@@ -0,0 +1,183 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+// maxBlameLines limita las líneas devueltas; para archivos mayores hay que
+// acotar con StartLine/EndLine
+const maxBlameLines = 2000
+
+// BlameLine es una línea del archivo con el commit que la introdujo
+type BlameLine struct {
+	Line        int    `json:"line"`
+	Commit      string `json:"commit"`
+	OrigLine    int    `json:"origLine"`
+	OrigPath    string `json:"origPath,omitempty"` // solo si la línea viene de otro archivo (-M/-C o renombrado)
+	Author      string `json:"author"`
+	AuthorEmail string `json:"authorEmail"`
+	AuthorDate  string `json:"authorDate"` // ISO 8601
+	Summary     string `json:"summary"`
+	Boundary    bool   `json:"boundary,omitempty"` // commit en el límite del historial analizado
+	Content     string `json:"content"`
+}
+
+// BlameResult es el blame de un archivo o de un rango de líneas
+type BlameResult struct {
+	Path      string      `json:"path"`
+	Ref       string      `json:"ref,omitempty"`
+	Lines     []BlameLine `json:"lines"`
+	Truncated bool        `json:"truncated,omitempty"`
+}
+
+// Blame indica, línea a línea, el commit, autor y fecha del último cambio
+func (c *Client) Blame(opts types.BlameOptions) (string, error) {
+	if !c.Config.HasGit || !c.Config.IsGitRepo {
+		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
+	}
+
+	args, err := blameArgs(opts)
+	if err != nil {
+		return "", err
+	}
+	output, err := c.gitCmd(args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("error obteniendo blame de %s: %v", opts.Path, gitError(err))
+	}
+
+	// git informa las rutas relativas a la raíz y con '/'
+	repoPath := path.Clean(filepath.ToSlash(opts.Path))
+	result := &BlameResult{Path: opts.Path, Ref: opts.Ref, Lines: parseBlame(string(output), repoPath)}
+	if len(result.Lines) > maxBlameLines {
+		result.Lines = result.Lines[:maxBlameLines]
+		result.Truncated = true
+	}
+
+	jsonOutput, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error serializando blame: %v", err)
+	}
+	return string(jsonOutput), nil
+}
+
+// blameArgs construye los argumentos de git blame --line-porcelain
+func blameArgs(opts types.BlameOptions) ([]string, error) {
+	if opts.Path == "" {
+		return nil, fmt.Errorf("path requerido para blame")
+	}
+	if opts.StartLine < 0 || opts.EndLine < 0 {
+		return nil, fmt.Errorf("start_line y end_line deben ser positivos")
+	}
+	if opts.EndLine > 0 && opts.EndLine < opts.StartLine {
+		return nil, fmt.Errorf("end_line (%d) es menor que start_line (%d)", opts.EndLine, opts.StartLine)
+	}
+
+	args := []string{"-c", "core.quotePath=false", "blame", "--line-porcelain"}
+	if opts.StartLine > 0 || opts.EndLine > 0 {
+		start := opts.StartLine
+		if start == 0 {
+			start = 1
+		}
+		lines := strconv.Itoa(start) + ","
+		if opts.EndLine > 0 {
+			lines += strconv.Itoa(opts.EndLine)
+		}
+		args = append(args, "-L", lines)
+	}
+	if opts.IgnoreWhitespace {
+		args = append(args, "-w")
+	}
+	if opts.DetectMoves {
+		args = append(args, "-M")
+	}
+	if opts.DetectCopies {
+		args = append(args, "-C")
+	}
+	if opts.Ref != "" {
+		if err := checkRef(opts.Ref); err != nil {
+			return nil, err
+		}
+		args = append(args, opts.Ref)
+	}
+	return append(args, "--", opts.Path), nil
+}
+
+// parseBlame interpreta la salida de --line-porcelain: por cada línea, una
+// cabecera "<sha> <línea original> <línea final> [n]", los datos del commit
+// como "clave valor" y el contenido precedido por un tabulador
+func parseBlame(output, path string) []BlameLine {
+	lines := []BlameLine{}
+	var current BlameLine
+	var authorTime int64
+	var authorTZ string
+	header := true
+
+	for _, raw := range strings.Split(output, "\n") {
+		if header {
+			fields := strings.Fields(raw)
+			if len(fields) < 3 {
+				continue
+			}
+			current = BlameLine{Commit: fields[0]}
+			current.OrigLine, _ = strconv.Atoi(fields[1])
+			current.Line, _ = strconv.Atoi(fields[2])
+			authorTime, authorTZ = 0, ""
+			header = false
+			continue
+		}
+
+		if strings.HasPrefix(raw, "\t") {
+			current.Content = raw[1:]
+			current.AuthorDate = blameDate(authorTime, authorTZ)
+			lines = append(lines, current)
+			header = true
+			continue
+		}
+
+		key, value, _ := strings.Cut(raw, " ")
+		switch key {
+		case "author":
+			current.Author = value
+		case "author-mail":
+			current.AuthorEmail = strings.TrimSuffix(strings.TrimPrefix(value, "<"), ">")
+		case "author-time":
+			authorTime, _ = strconv.ParseInt(value, 10, 64)
+		case "author-tz":
+			authorTZ = value
+		case "summary":
+			current.Summary = value
+		case "boundary":
+			current.Boundary = true
+		case "filename":
+			if value != path {
+				current.OrigPath = value
+			}
+		}
+	}
+	return lines
+}
+
+// blameDate convierte author-time y author-tz ("+0200") a ISO 8601
+func blameDate(unix int64, tz string) string {
+	loc := time.UTC
+	if len(tz) == 5 {
+		hours, errH := strconv.Atoi(tz[1:3])
+		minutes, errM := strconv.Atoi(tz[3:5])
+		if errH == nil && errM == nil {
+			offset := hours*3600 + minutes*60
+			if tz[0] == '-' {
+				offset = -offset
+			}
+			loc = time.FixedZone(tz, offset)
+		}
+	}
+	return time.Unix(unix, 0).In(loc).Format(time.RFC3339)
+}
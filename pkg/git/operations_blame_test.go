@@ -0,0 +1,172 @@
+package git
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+const movedBlock = "func movedHelper(value int) int {\n\treturn value * 1000 + 42 // long enough to be detected\n}\n"
+
+// createBlameRepo builds a file written by Alice, then Bob moves a block
+// inside it, reindents a line and moves another block to a second file
+func createBlameRepo(t *testing.T) (string, *Client) {
+	t.Helper()
+	dir := createTestRepo(t)
+	alice := []string{"-c", "user.name=Alice", "-c", "user.email=alice@example.com"}
+	bob := []string{"-c", "user.name=Bob", "-c", "user.email=bob@example.com"}
+
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	header := "package main\n\nvar indented = 1\n\n"
+	footer := "\nfunc main() {\n\tprintln(\"main body\")\n}\n"
+	copied := "func copiedHelper(text string) string {\n\treturn text + \" copied between files\"\n}\n"
+
+	write("main go.txt", header+movedBlock+"\n"+copied+footer)
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, append(alice, "commit", "-m", "alice writes main")...)
+	runGit(t, dir, "tag", "v1")
+
+	write("main go.txt", strings.Replace(header, "var indented", "    var indented", 1)+footer+"\n"+movedBlock)
+	write("helpers.txt", copied)
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, append(bob, "commit", "-m", "bob moves things")...)
+
+	client := &Client{
+		Config:   &types.GitConfig{HasGit: true, IsGitRepo: true, RepoPath: dir},
+		executor: &realExecutor{},
+	}
+	return dir, client
+}
+
+func decodeBlame(t *testing.T) func(string, error) BlameResult {
+	return func(output string, err error) BlameResult {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var result BlameResult
+		if err := json.Unmarshal([]byte(output), &result); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, output)
+		}
+		return result
+	}
+}
+
+// blameAuthor returns the author of the first line containing text
+func blameAuthor(t *testing.T, result BlameResult, text string) BlameLine {
+	t.Helper()
+	for _, line := range result.Lines {
+		if strings.Contains(line.Content, text) {
+			return line
+		}
+	}
+	t.Fatalf("no line contains %q", text)
+	return BlameLine{}
+}
+
+func TestBlame(t *testing.T) {
+	_, client := createBlameRepo(t)
+
+	result := decodeBlame(t)(client.Blame(types.BlameOptions{Path: "main go.txt"}))
+	line := blameAuthor(t, result, "package main")
+	if line.Line != 1 || line.Author != "Alice" || line.AuthorEmail != "alice@example.com" || line.Summary != "alice writes main" {
+		t.Errorf("line 1 = %+v", line)
+	}
+	if len(line.Commit) != 40 || line.AuthorDate == "" || line.OrigPath != "" {
+		t.Errorf("line 1 metadata = %+v", line)
+	}
+	if got := blameAuthor(t, result, "movedHelper").Author; got != "Bob" {
+		t.Errorf("moved block without -M attributed to %s, want Bob", got)
+	}
+	if got := blameAuthor(t, result, "var indented").Author; got != "Bob" {
+		t.Errorf("reindented line without -w attributed to %s, want Bob", got)
+	}
+
+	t.Run("Move and whitespace detection", func(t *testing.T) {
+		result := decodeBlame(t)(client.Blame(types.BlameOptions{Path: "main go.txt", DetectMoves: true, IgnoreWhitespace: true}))
+		if got := blameAuthor(t, result, "movedHelper").Author; got != "Alice" {
+			t.Errorf("moved block with -M attributed to %s, want Alice", got)
+		}
+		if got := blameAuthor(t, result, "var indented").Author; got != "Alice" {
+			t.Errorf("reindented line with -w attributed to %s, want Alice", got)
+		}
+	})
+
+	t.Run("Copy detection", func(t *testing.T) {
+		result := decodeBlame(t)(client.Blame(types.BlameOptions{Path: "helpers.txt"}))
+		if got := blameAuthor(t, result, "copiedHelper").Author; got != "Bob" {
+			t.Errorf("copied block without -C attributed to %s, want Bob", got)
+		}
+		result = decodeBlame(t)(client.Blame(types.BlameOptions{Path: "helpers.txt", DetectCopies: true}))
+		line := blameAuthor(t, result, "copiedHelper")
+		if line.Author != "Alice" || line.OrigPath != "main go.txt" {
+			t.Errorf("copied block with -C = %+v, want Alice from main go.txt", line)
+		}
+	})
+
+	t.Run("Line range and ref", func(t *testing.T) {
+		result := decodeBlame(t)(client.Blame(types.BlameOptions{Path: "main go.txt", Ref: "v1", StartLine: 3, EndLine: 5}))
+		if len(result.Lines) != 3 || result.Lines[0].Line != 3 || result.Lines[2].Line != 5 {
+			t.Fatalf("lines = %+v, want 3..5", result.Lines)
+		}
+		for _, line := range result.Lines {
+			if line.Author != "Alice" {
+				t.Errorf("at v1 every line is Alice's: %+v", line)
+			}
+		}
+		if result.Lines[0].Content != "var indented = 1" {
+			t.Errorf("line 3 at v1 = %q", result.Lines[0].Content)
+		}
+	})
+}
+
+func TestBlame_Errors(t *testing.T) {
+	_, client := createBlameRepo(t)
+
+	tests := []struct {
+		name   string
+		opts   types.BlameOptions
+		errMsg string
+	}{
+		{"Missing path", types.BlameOptions{}, "path requerido"},
+		{"Reversed range", types.BlameOptions{Path: "helpers.txt", StartLine: 5, EndLine: 2}, "menor que start_line"},
+		{"Negative line", types.BlameOptions{Path: "helpers.txt", StartLine: -1}, "positivos"},
+		{"Option injection", types.BlameOptions{Path: "helpers.txt", Ref: "--output=/tmp/x"}, "no puede empezar por '-'"},
+		{"Unknown file", types.BlameOptions{Path: "nope.txt"}, "nope.txt"},
+		{"Range past the end", types.BlameOptions{Path: "helpers.txt", StartLine: 100}, "helpers.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Blame(tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("error = %v, should contain %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestBlameDate(t *testing.T) {
+	tests := []struct {
+		unix int64
+		tz   string
+		want string
+	}{
+		{0, "+0000", "1970-01-01T00:00:00Z"},
+		{3600, "+0200", "1970-01-01T03:00:00+02:00"},
+		{3600, "-0530", "1969-12-31T19:30:00-05:30"},
+		{0, "bogus", "1970-01-01T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		if got := blameDate(tt.unix, tt.tz); got != tt.want {
+			t.Errorf("blameDate(%d, %q) = %s, want %s", tt.unix, tt.tz, got, tt.want)
+		}
+	}
+}
@@ -17,18 +17,12 @@ func (c *Client) Checkout(branch string, create bool) (string, error) {
 		return "", fmt.Errorf("nombre de rama requerido")
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	// Paso 1: Validar que la rama existe (si no es creación de rama nueva)
 	if !create {
-		checkCmd := c.executor.Command("git", "show-ref", "--verify", "--quiet", "refs/heads/"+branch)
+		checkCmd := c.gitCmd("show-ref", "--verify", "--quiet", "refs/heads/"+branch)
 		if _, err := checkCmd.CombinedOutput(); err != nil {
 			// Intentar desde remoto
-			checkRemoteCmd := c.executor.Command("git", "show-ref", "--verify", "--quiet", "refs/remotes/origin/"+branch)
+			checkRemoteCmd := c.gitCmd("show-ref", "--verify", "--quiet", "refs/remotes/origin/"+branch)
 			if _, err := checkRemoteCmd.CombinedOutput(); err != nil {
 				return "", fmt.Errorf("rama '%s' no existe (ni local ni remota). Crea con 'create: true' o usa 'CheckoutRemote'", branch)
 			}
@@ -55,9 +49,9 @@ func (c *Client) Checkout(branch string, create bool) (string, error) {
 	// Paso 4: Ejecutar checkout
 	var cmd cmdWrapper
 	if create {
-		cmd = c.executor.Command("git", "checkout", "-b", branch)
+		cmd = c.gitCmd("checkout", "-b", branch)
 	} else {
-		cmd = c.executor.Command("git", "checkout", branch)
+		cmd = c.gitCmd("checkout", branch)
 	}
 
 	output, err := cmd.CombinedOutput()
@@ -90,14 +84,8 @@ func (c *Client) BranchList(remote bool) ([]types.BranchInfo, error) {
 	}
 
 	workingDir := c.getEffectiveWorkingDir()
-	restore, err := enterDir(workingDir)
-	if err != nil {
-		return nil, err
-	}
-	defer restore()
-
 	// Get current branch
-	cmdCurrent := c.executor.Command("git", "branch", "--show-current")
+	cmdCurrent := c.gitCmdIn(workingDir, "branch", "--show-current")
 	currentBranchBytes, err := cmdCurrent.Output()
 	if err != nil {
 		// This can fail if in detached HEAD state, not a fatal error
@@ -110,7 +98,7 @@ func (c *Client) BranchList(remote bool) ([]types.BranchInfo, error) {
 	if remote {
 		args = append(args, "refs/remotes")
 	}
-	cmdList := c.executor.Command("git", args...)
+	cmdList := c.gitCmdIn(workingDir, args...)
 	output, err := cmdList.Output()
 	if err != nil {
 		return nil, fmt.Errorf("error listing branches: %w", err)
@@ -150,14 +138,8 @@ func (c *Client) CheckoutRemote(remoteBranch string, localBranch string) (string
 		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	// Ensure we have the latest remote info
-	fetchCmd := c.executor.Command("git", "fetch", "origin")
+	fetchCmd := c.gitCmd("fetch", "origin")
 	if output, err := fetchCmd.CombinedOutput(); err != nil {
 		return "", fmt.Errorf("error en fetch: %v, Output: %s", err, output)
 	}
@@ -173,21 +155,21 @@ func (c *Client) CheckoutRemote(remoteBranch string, localBranch string) (string
 	}
 
 	// Check if local branch already exists
-	checkCmd := c.executor.Command("git", "show-ref", "--verify", "--quiet", "refs/heads/"+localBranch)
+	checkCmd := c.gitCmd("show-ref", "--verify", "--quiet", "refs/heads/"+localBranch)
 	if _, err := checkCmd.CombinedOutput(); err == nil {
 		// Local branch exists, just checkout and pull
-		checkoutCmd := c.executor.Command("git", "checkout", localBranch)
+		checkoutCmd := c.gitCmd("checkout", localBranch)
 		if output, err := checkoutCmd.CombinedOutput(); err != nil {
 			return "", fmt.Errorf("error en checkout: %v, Output: %s", err, output)
 		}
 
-		pullCmd := c.executor.Command("git", "pull", "origin", remoteBranch)
+		pullCmd := c.gitCmd("pull", "origin", remoteBranch)
 		if output, err := pullCmd.CombinedOutput(); err != nil {
 			return "", fmt.Errorf("error en pull: %v, Output: %s", err, output)
 		}
 	} else {
 		// Create new local branch tracking remote
-		cmd := c.executor.Command("git", "checkout", "-b", localBranch, "origin/"+remoteBranch)
+		cmd := c.gitCmd("checkout", "-b", localBranch, "origin/"+remoteBranch)
 		if output, err := cmd.CombinedOutput(); err != nil {
 			return "", fmt.Errorf("error en checkout remoto: %v, Output: %s", err, output)
 		}
@@ -202,12 +184,6 @@ func (c *Client) Merge(sourceBranch string, targetBranch string) (string, error)
 		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	// Validate clean state
 	if clean, err := c.ValidateCleanState(); err != nil {
 		return "", fmt.Errorf("error validando estado: %v", err)
@@ -220,7 +196,7 @@ func (c *Client) Merge(sourceBranch string, targetBranch string) (string, error)
 		targetBranch = c.Config.CurrentBranch
 	} else if targetBranch != c.Config.CurrentBranch {
 		// Checkout to target branch
-		checkoutCmd := c.executor.Command("git", "checkout", targetBranch)
+		checkoutCmd := c.gitCmd("checkout", targetBranch)
 		if output, err := checkoutCmd.CombinedOutput(); err != nil {
 			return "", fmt.Errorf("error cambiando a rama %s: %v, Output: %s", targetBranch, err, output)
 		}
@@ -228,11 +204,11 @@ func (c *Client) Merge(sourceBranch string, targetBranch string) (string, error)
 	}
 
 	// Perform merge
-	mergeCmd := c.executor.Command("git", "merge", sourceBranch)
+	mergeCmd := c.gitCmd("merge", sourceBranch)
 	output, err := mergeCmd.CombinedOutput()
 	if err != nil {
 		// Check if it's a conflict
-		statusCmd := c.executor.Command("git", "status", "--porcelain")
+		statusCmd := c.gitCmd("status", "--porcelain")
 		statusOut, _ := statusCmd.Output()
 		if strings.Contains(string(statusOut), "UU") || strings.Contains(string(output), "CONFLICT") {
 			return "", fmt.Errorf("conflicts de merge detectados. Usa 'ConflictStatus' para ver detalles y 'ResolveConflicts' para resolverlos: %s", output)
@@ -248,12 +224,6 @@ func (c *Client) Rebase(branch string) (string, error) {
 		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	// Validate clean state
 	if clean, err := c.ValidateCleanState(); err != nil {
 		return "", fmt.Errorf("error validando estado: %v", err)
@@ -262,7 +232,7 @@ func (c *Client) Rebase(branch string) (string, error) {
 	}
 
 	// Perform rebase
-	rebaseCmd := c.executor.Command("git", "rebase", branch)
+	rebaseCmd := c.gitCmd("rebase", branch)
 	output, err := rebaseCmd.CombinedOutput()
 	if err != nil {
 		// Check if it's a conflict
@@ -282,12 +252,6 @@ func (c *Client) PullWithStrategy(branch string, strategy string) (string, error
 		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	if branch == "" {
 		branch = c.Config.CurrentBranch
 	}
@@ -295,11 +259,11 @@ func (c *Client) PullWithStrategy(branch string, strategy string) (string, error
 	var cmd cmdWrapper
 	switch strategy {
 	case "merge":
-		cmd = c.executor.Command("git", "pull", "--no-rebase", "origin", branch)
+		cmd = c.gitCmd("pull", "--no-rebase", "origin", branch)
 	case "rebase":
-		cmd = c.executor.Command("git", "pull", "--rebase", "origin", branch)
+		cmd = c.gitCmd("pull", "--rebase", "origin", branch)
 	case "ff-only":
-		cmd = c.executor.Command("git", "pull", "--ff-only", "origin", branch)
+		cmd = c.gitCmd("pull", "--ff-only", "origin", branch)
 	default:
 		return "", fmt.Errorf("estrategia no válida: %s. Usa: merge, rebase, ff-only", strategy)
 	}
@@ -320,18 +284,12 @@ func (c *Client) ForcePush(branch string, force bool) (string, error) {
 		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	if branch == "" {
 		branch = c.Config.CurrentBranch
 	}
 
 	// Get remote name
-	remoteCmd := c.executor.Command("git", "remote")
+	remoteCmd := c.gitCmd("remote")
 	remoteOutput, err := remoteCmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("error obteniendo remotos: %v", err)
@@ -342,6 +300,11 @@ func (c *Client) ForcePush(branch string, force bool) (string, error) {
 	}
 	remote := remotes[0]
 
+	warning, err := c.scanPush(remote, branch)
+	if err != nil {
+		return "", err
+	}
+
 	var cmd cmdWrapper
 	if force {
 		// Create backup before force push
@@ -350,9 +313,9 @@ func (c *Client) ForcePush(branch string, force bool) (string, error) {
 			return "", fmt.Errorf("error creando backup antes de force push: %v", err)
 		}
 
-		cmd = c.executor.Command("git", "push", "--force-with-lease", remote, branch)
+		cmd = c.gitCmd("push", "--force-with-lease", remote, branch)
 	} else {
-		cmd = c.executor.Command("git", "push", remote, branch)
+		cmd = c.gitCmd("push", remote, branch)
 	}
 
 	output, err := cmd.CombinedOutput()
@@ -361,9 +324,9 @@ func (c *Client) ForcePush(branch string, force bool) (string, error) {
 	}
 
 	if force {
-		return fmt.Sprintf("Force push exitoso (con backup): %s a %s", branch, remote), nil
+		return fmt.Sprintf("Force push exitoso (con backup): %s a %s%s", branch, remote, warning), nil
 	}
-	return fmt.Sprintf("Push exitoso: %s a %s", branch, remote), nil
+	return fmt.Sprintf("Push exitoso: %s a %s%s", branch, remote, warning), nil
 }
 
 func (c *Client) PushUpstream(branch string) (string, error) {
@@ -371,18 +334,12 @@ func (c *Client) PushUpstream(branch string) (string, error) {
 		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	if branch == "" {
 		branch = c.Config.CurrentBranch
 	}
 
 	// Get remote name
-	remoteCmd := c.executor.Command("git", "remote")
+	remoteCmd := c.gitCmd("remote")
 	remoteOutput, err := remoteCmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("error obteniendo remotos: %v", err)
@@ -393,13 +350,18 @@ func (c *Client) PushUpstream(branch string) (string, error) {
 	}
 	remote := remotes[0]
 
-	cmd := c.executor.Command("git", "push", "-u", remote, branch)
+	warning, err := c.scanPush(remote, branch)
+	if err != nil {
+		return "", err
+	}
+
+	cmd := c.gitCmd("push", "-u", remote, branch)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return "", fmt.Errorf("error en push upstream: %v, Output: %s", err, output)
 	}
 
-	return fmt.Sprintf("Push upstream exitoso: %s configurado para trackear %s/%s", branch, remote, branch), nil
+	return fmt.Sprintf("Push upstream exitoso: %s configurado para trackear %s/%s%s", branch, remote, branch, warning), nil
 }
 
 // Batch operations
@@ -409,16 +371,10 @@ func (c *Client) SyncWithRemote(remoteBranch string) (string, error) {
 		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	results := []string{}
 
 	// 1. Fetch from remote
-	fetchCmd := c.executor.Command("git", "fetch", "origin")
+	fetchCmd := c.gitCmd("fetch", "origin")
 	if output, err := fetchCmd.CombinedOutput(); err != nil {
 		return "", fmt.Errorf("error en fetch: %v, Output: %s", err, output)
 	}
@@ -431,21 +387,21 @@ func (c *Client) SyncWithRemote(remoteBranch string) (string, error) {
 	}
 
 	// Check if remote branch exists
-	checkCmd := c.executor.Command("git", "show-ref", "--verify", "--quiet", "refs/remotes/origin/"+remoteBranch)
+	checkCmd := c.gitCmd("show-ref", "--verify", "--quiet", "refs/remotes/origin/"+remoteBranch)
 	if _, err := checkCmd.CombinedOutput(); err != nil {
 		return "", fmt.Errorf("rama remota no encontrada: origin/%s", remoteBranch)
 	}
 
 	// 3. Check if fast-forward is possible
-	mergeBaseCmd := c.executor.Command("git", "merge-base", currentBranch, "origin/"+remoteBranch)
+	mergeBaseCmd := c.gitCmd("merge-base", currentBranch, "origin/"+remoteBranch)
 	mergeBase, _ := mergeBaseCmd.Output()
 
-	currentCommitCmd := c.executor.Command("git", "rev-parse", currentBranch)
+	currentCommitCmd := c.gitCmd("rev-parse", currentBranch)
 	currentCommit, _ := currentCommitCmd.Output()
 
 	if strings.TrimSpace(string(mergeBase)) == strings.TrimSpace(string(currentCommit)) {
 		// Fast-forward possible
-		mergeCmd := c.executor.Command("git", "merge", "--ff-only", "origin/"+remoteBranch)
+		mergeCmd := c.gitCmd("merge", "--ff-only", "origin/"+remoteBranch)
 		if output, err := mergeCmd.CombinedOutput(); err != nil {
 			return "", fmt.Errorf("error en fast-forward: %v, Output: %s", err, output)
 		}
@@ -458,7 +414,7 @@ func (c *Client) SyncWithRemote(remoteBranch string) (string, error) {
 			return "", fmt.Errorf("el directorio debe estar limpio para sincronizar")
 		}
 
-		mergeCmd := c.executor.Command("git", "merge", "origin/"+remoteBranch)
+		mergeCmd := c.gitCmd("merge", "origin/"+remoteBranch)
 		if output, err := mergeCmd.CombinedOutput(); err != nil {
 			if strings.Contains(string(output), "CONFLICT") {
 				return "", fmt.Errorf("conflicts detectados durante sincronización: %s", output)
@@ -476,12 +432,6 @@ func (c *Client) SafeMerge(source string, target string) (string, error) {
 		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
 	}
 
-	restore, err := c.enterWorkingDir()
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	// 1. Create backup
 	backupName := fmt.Sprintf("safe-merge-backup-%s", target)
 	if _, err := c.CreateBackup(backupName); err != nil {
@@ -507,7 +457,7 @@ func (c *Client) SafeMerge(source string, target string) (string, error) {
 
 	// Switch to target branch if needed
 	if target != "" && target != originalBranch {
-		checkoutCmd := c.executor.Command("git", "checkout", target)
+		checkoutCmd := c.gitCmd("checkout", target)
 		if output, err := checkoutCmd.CombinedOutput(); err != nil {
 			return "", fmt.Errorf("error cambiando a rama %s: %v, Output: %s", target, err, output)
 		}
@@ -515,11 +465,11 @@ func (c *Client) SafeMerge(source string, target string) (string, error) {
 	}
 
 	// Perform merge
-	mergeCmd := c.executor.Command("git", "merge", "--no-ff", source)
+	mergeCmd := c.gitCmd("merge", "--no-ff", source)
 	output, err := mergeCmd.CombinedOutput()
 	if err != nil {
 		// Rollback on error
-		resetCmd := c.executor.Command("git", "reset", "--hard", "HEAD~1")
+		resetCmd := c.gitCmd("reset", "--hard", "HEAD~1")
 		if _, resetErr := resetCmd.CombinedOutput(); resetErr != nil {
 			// Log error but continue with original error
 			_ = resetErr
@@ -0,0 +1,400 @@
+package git
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+const (
+	// defaultDiffPageSize es el tamaño de página por defecto de DiffPatch
+	defaultDiffPageSize = 32 * 1024
+	// minDiffPageSize y maxDiffPageSize acotan el tamaño pedido
+	minDiffPageSize = 1024
+	maxDiffPageSize = 256 * 1024
+	// maxDiffContext limita las líneas de contexto pedidas
+	maxDiffContext = 1000
+)
+
+// FileDiff es el parche de un archivo dentro de una página. Un archivo con
+// muchos hunks puede repartirse entre varias páginas; FirstHunk indica el
+// primero incluido y la cabecera se repite en cada página.
+type FileDiff struct {
+	Path       string   `json:"path"`
+	OldPath    string   `json:"oldPath,omitempty"`
+	Status     string   `json:"status"` // added, deleted, modified, renamed, copied
+	Binary     bool     `json:"binary,omitempty"`
+	Header     string   `json:"header"`
+	Hunks      []string `json:"hunks,omitempty"`
+	FirstHunk  int      `json:"firstHunk,omitempty"`
+	TotalHunks int      `json:"totalHunks"`
+	Truncated  bool     `json:"truncated,omitempty"` // un hunk mayor que la página se recortó
+}
+
+// DiffPage es una página de un diff con parches completos
+type DiffPage struct {
+	Range      string     `json:"range,omitempty"`
+	Staged     bool       `json:"staged,omitempty"`
+	Paths      []string   `json:"paths,omitempty"`
+	TotalFiles int        `json:"totalFiles"`
+	Files      []FileDiff `json:"files"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+}
+
+// patchFile es un archivo del parche completo antes de paginar
+type patchFile struct {
+	path, oldPath, status string
+	binary                bool
+	header                string
+	hunks                 []string
+}
+
+// diffCursor es la posición de la siguiente página más una huella del parche,
+// para detectar que el diff cambió entre llamadas
+type diffCursor struct {
+	file, hunk int
+	digest     string
+}
+
+// DiffPatch devuelve el diff con parches unificados, paginado por archivo y
+// hunk. Acepta rangos entre refs, filtros de ruta, contexto configurable y
+// diff por palabras; los archivos binarios se marcan sin contenido.
+func (c *Client) DiffPatch(opts types.DiffOptions) (string, error) {
+	if !c.Config.HasGit || !c.Config.IsGitRepo {
+		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
+	}
+
+	args, err := diffPatchArgs(opts)
+	if err != nil {
+		return "", err
+	}
+	if err := checkPageSize(opts.PageSize); err != nil {
+		return "", err
+	}
+
+	output, err := c.gitCmd(args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("error obteniendo diff: %v", gitError(err))
+	}
+
+	page, err := pagePatch(output, opts.Cursor, opts.PageSize)
+	if err != nil {
+		return "", err
+	}
+	page.Range = opts.Range
+	page.Staged = opts.Staged
+	page.Paths = opts.Paths
+
+	jsonOutput, err := json.MarshalIndent(page, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error serializando diff: %v", err)
+	}
+	return string(jsonOutput), nil
+}
+
+// checkPageSize valida el tamaño de página pedido (0 usa el valor por defecto)
+func checkPageSize(pageSize int) error {
+	if pageSize != 0 && (pageSize < minDiffPageSize || pageSize > maxDiffPageSize) {
+		return fmt.Errorf("page_size debe estar entre %d y %d bytes", minDiffPageSize, maxDiffPageSize)
+	}
+	return nil
+}
+
+// pagePatch parte la salida de un parche en la página que empieza en cursor.
+// El cursor lleva una huella del parche y se rechaza si el parche cambió.
+func pagePatch(output []byte, cursor string, pageSize int) (*DiffPage, error) {
+	if pageSize == 0 {
+		pageSize = defaultDiffPageSize
+	}
+
+	digest := patchDigest(output)
+	start := diffCursor{digest: digest}
+	if cursor != "" {
+		var err error
+		if start, err = decodeDiffCursor(cursor); err != nil {
+			return nil, err
+		}
+		if start.digest != digest {
+			return nil, fmt.Errorf("el diff cambió desde que se emitió el cursor; vuelve a pedir la primera página")
+		}
+	}
+
+	files := parsePatch(string(output))
+	if start.file > len(files) || (start.file < len(files) && start.hunk > len(files[start.file].hunks)) {
+		return nil, fmt.Errorf("cursor fuera de rango")
+	}
+	return paginatePatch(files, start, pageSize), nil
+}
+
+// patchFormatArgs traduce el contexto y el diff por palabras a opciones de git
+func patchFormatArgs(context int, wordDiff bool) ([]string, error) {
+	var args []string
+	if context > maxDiffContext {
+		return nil, fmt.Errorf("context no puede superar %d líneas", maxDiffContext)
+	}
+	if context >= 0 {
+		args = append(args, "-U"+strconv.Itoa(context))
+	}
+	if wordDiff {
+		args = append(args, "--word-diff=plain")
+	}
+	return args, nil
+}
+
+// diffPatchArgs construye los argumentos de git diff
+func diffPatchArgs(opts types.DiffOptions) ([]string, error) {
+	args := []string{"-c", "core.quotePath=false", "diff", "--no-color", "--no-ext-diff", "--no-textconv"}
+
+	format, err := patchFormatArgs(opts.Context, opts.WordDiff)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, format...)
+
+	if opts.Range != "" {
+		refs, err := splitRange(opts.Range)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Staged && len(refs) > 1 {
+			return nil, fmt.Errorf("staged solo se puede combinar con una ref, no con un rango")
+		}
+	}
+	if opts.Staged {
+		args = append(args, "--cached")
+	}
+	if opts.Range != "" {
+		args = append(args, opts.Range)
+	}
+
+	args = append(args, "--")
+	for _, path := range opts.Paths {
+		if path == "" {
+			continue
+		}
+		args = append(args, path)
+	}
+	return args, nil
+}
+
+// splitRange valida "ref", "a..b" o "a...b" y devuelve sus refs
+func splitRange(rangeSpec string) ([]string, error) {
+	var refs []string
+	switch {
+	case strings.Contains(rangeSpec, "..."):
+		refs = strings.SplitN(rangeSpec, "...", 2)
+	case strings.Contains(rangeSpec, ".."):
+		refs = strings.SplitN(rangeSpec, "..", 2)
+	default:
+		refs = []string{rangeSpec}
+	}
+	for _, ref := range refs {
+		// En a..b un extremo vacío significa HEAD
+		if ref == "" && len(refs) == 2 {
+			continue
+		}
+		if err := checkRef(ref); err != nil {
+			return nil, err
+		}
+	}
+	return refs, nil
+}
+
+// checkRef rechaza refs vacías, con espacios o que git interpretaría como opciones
+func checkRef(ref string) error {
+	if ref == "" {
+		return fmt.Errorf("ref vacía")
+	}
+	if strings.HasPrefix(ref, "-") {
+		return fmt.Errorf("ref inválida (no puede empezar por '-'): %s", ref)
+	}
+	if strings.ContainsAny(ref, " \t\n\r\x00") {
+		return fmt.Errorf("ref inválida (contiene espacios): %q", ref)
+	}
+	return nil
+}
+
+// parsePatch separa la salida de git diff en archivos con su cabecera y hunks
+func parsePatch(patch string) []patchFile {
+	var files []patchFile
+	var block strings.Builder
+	inHunk := false
+
+	flush := func() {
+		if len(files) == 0 {
+			return
+		}
+		current := &files[len(files)-1]
+		if inHunk {
+			current.hunks = append(current.hunks, block.String())
+		} else {
+			current.header = block.String()
+		}
+		block.Reset()
+	}
+
+	for _, line := range strings.SplitAfter(patch, "\n") {
+		if line == "" {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+			files = append(files, patchFile{status: "modified", path: diffGitPath(strings.TrimSuffix(line, "\n"))})
+			inHunk = false
+		case len(files) == 0:
+			continue
+		case strings.HasPrefix(line, "@@"):
+			flush()
+			inHunk = true
+		case !inHunk:
+			parseHeaderLine(&files[len(files)-1], strings.TrimSuffix(line, "\n"))
+		}
+		block.WriteString(line)
+	}
+	flush()
+	return files
+}
+
+// parseHeaderLine extrae estado, rutas y binario de una línea de cabecera
+func parseHeaderLine(file *patchFile, line string) {
+	switch {
+	case strings.HasPrefix(line, "new file mode"):
+		file.status = "added"
+	case strings.HasPrefix(line, "deleted file mode"):
+		file.status = "deleted"
+	case strings.HasPrefix(line, "rename from "):
+		file.status = "renamed"
+		file.oldPath = strings.TrimPrefix(line, "rename from ")
+	case strings.HasPrefix(line, "rename to "):
+		file.path = strings.TrimPrefix(line, "rename to ")
+	case strings.HasPrefix(line, "copy from "):
+		file.status = "copied"
+		file.oldPath = strings.TrimPrefix(line, "copy from ")
+	case strings.HasPrefix(line, "copy to "):
+		file.path = strings.TrimPrefix(line, "copy to ")
+	case strings.HasPrefix(line, "Binary files "), strings.HasPrefix(line, "GIT binary patch"):
+		file.binary = true
+	case strings.HasPrefix(line, "+++ b/"):
+		file.path = strings.TrimPrefix(line, "+++ b/")
+	case strings.HasPrefix(line, "--- a/") && file.status == "deleted":
+		file.path = strings.TrimPrefix(line, "--- a/")
+	}
+}
+
+// diffGitPath obtiene la ruta de "diff --git a/<p> b/<p>" cuando ambas
+// coinciden; los renombrados la corrigen con las líneas "rename to"
+func diffGitPath(line string) string {
+	rest := strings.TrimPrefix(line, "diff --git ")
+	if n := len(rest) - len("a/ b/"); n > 0 && n%2 == 0 {
+		if path := rest[2 : 2+n/2]; rest == "a/"+path+" b/"+path {
+			return path
+		}
+	}
+	if i := strings.Index(rest, " b/"); strings.HasPrefix(rest, "a/") && i > 0 {
+		return rest[i+len(" b/"):]
+	}
+	return rest
+}
+
+// paginatePatch llena una página desde start hasta pageSize bytes. Cada
+// página avanza al menos un hunk aunque supere el tamaño (recortado).
+func paginatePatch(files []patchFile, start diffCursor, pageSize int) *DiffPage {
+	page := &DiffPage{TotalFiles: len(files), Files: []FileDiff{}}
+	size := 0
+
+	for fi := start.file; fi < len(files); fi++ {
+		file := files[fi]
+		first := 0
+		if fi == start.file {
+			first = start.hunk
+		}
+		entry := FileDiff{
+			Path:       file.path,
+			OldPath:    file.oldPath,
+			Status:     file.status,
+			Binary:     file.binary,
+			Header:     file.header,
+			FirstHunk:  first,
+			TotalHunks: len(file.hunks),
+		}
+
+		cost := len(file.header)
+		if size > 0 && size+cost > pageSize {
+			page.NextCursor = encodeDiffCursor(diffCursor{file: fi, hunk: first, digest: start.digest})
+			return page
+		}
+		for hi := first; hi < len(file.hunks); hi++ {
+			hunk := file.hunks[hi]
+			if size+cost+len(hunk) > pageSize {
+				if size > 0 || len(entry.Hunks) > 0 {
+					if len(entry.Hunks) > 0 {
+						page.Files = append(page.Files, entry)
+					}
+					page.NextCursor = encodeDiffCursor(diffCursor{file: fi, hunk: hi, digest: start.digest})
+					return page
+				}
+				// Un único hunk mayor que la página: se recorta para avanzar
+				hunk = truncateUTF8(hunk, pageSize-cost)
+				entry.Truncated = true
+			}
+			entry.Hunks = append(entry.Hunks, hunk)
+			cost += len(hunk)
+		}
+		page.Files = append(page.Files, entry)
+		size += cost
+	}
+	return page
+}
+
+// truncateUTF8 recorta s a como mucho n bytes sin partir un carácter
+func truncateUTF8(s string, n int) string {
+	if n < 0 {
+		n = 0
+	}
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+func patchDigest(patch []byte) string {
+	sum := sha256.Sum256(patch)
+	return hex.EncodeToString(sum[:6])
+}
+
+func encodeDiffCursor(cursor diffCursor) string {
+	raw := fmt.Sprintf("%d:%d:%s", cursor.file, cursor.hunk, cursor.digest)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeDiffCursor(value string) (diffCursor, error) {
+	invalid := fmt.Errorf("cursor inválido: %s", value)
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return diffCursor{}, invalid
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 {
+		return diffCursor{}, invalid
+	}
+	file, err := strconv.Atoi(parts[0])
+	if err != nil || file < 0 {
+		return diffCursor{}, invalid
+	}
+	hunk, err := strconv.Atoi(parts[1])
+	if err != nil || hunk < 0 {
+		return diffCursor{}, invalid
+	}
+	return diffCursor{file: file, hunk: hunk, digest: parts[2]}, nil
+}
@@ -0,0 +1,200 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+// createDiffRepo builds a repository with a commit touching several files
+// (text, binary, rename) on top of the initial one
+func createDiffRepo(t *testing.T) (string, *Client) {
+	t.Helper()
+	dir := createTestRepo(t)
+	var lines []string
+	for i := 0; i < 200; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	commitFile(t, dir, "big.txt", strings.Join(lines, "\n")+"\n", "add big")
+	commitFile(t, dir, "old name.txt", "rename me\n", "add rename source")
+	runGit(t, dir, "tag", "base")
+
+	for _, i := range []int{10, 60, 110, 160} {
+		lines[i] = fmt.Sprintf("changed %d", i)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "big.txt"), []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "image.bin"), []byte{0, 1, 2, 0, 255}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "mv", "old name.txt", "new name.txt")
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-m", "change things")
+
+	client := &Client{
+		Config:   &types.GitConfig{HasGit: true, IsGitRepo: true, RepoPath: dir},
+		executor: &realExecutor{},
+	}
+	return dir, client
+}
+
+func decodeDiffPage(t *testing.T) func(string, error) DiffPage {
+	return func(output string, err error) DiffPage {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var page DiffPage
+		if err := json.Unmarshal([]byte(output), &page); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, output)
+		}
+		return page
+	}
+}
+
+func TestDiffPatch_Range(t *testing.T) {
+	_, client := createDiffRepo(t)
+
+	page := decodeDiffPage(t)(client.DiffPatch(types.DiffOptions{Range: "base..HEAD", Context: -1}))
+	if page.TotalFiles != 3 || page.NextCursor != "" {
+		t.Fatalf("page = %+v, want 3 files in one page", page)
+	}
+	files := map[string]FileDiff{}
+	for _, f := range page.Files {
+		files[f.Path] = f
+	}
+	if f := files["big.txt"]; f.Status != "modified" || f.TotalHunks != 4 || len(f.Hunks) != 4 {
+		t.Errorf("big.txt = %+v", f)
+	}
+	if f := files["image.bin"]; !f.Binary || f.Status != "added" || len(f.Hunks) != 0 {
+		t.Errorf("image.bin = %+v", f)
+	}
+	if f := files["new name.txt"]; f.Status != "renamed" || f.OldPath != "old name.txt" {
+		t.Errorf("rename = %+v", f)
+	}
+
+	page = decodeDiffPage(t)(client.DiffPatch(types.DiffOptions{Range: "base...HEAD", Paths: []string{"big.txt"}, Context: 0}))
+	if page.TotalFiles != 1 || !strings.Contains(page.Files[0].Hunks[0], "+changed 10") {
+		t.Errorf("filtered page = %+v", page)
+	}
+	if strings.Contains(page.Files[0].Hunks[0], "\n line 9\n") {
+		t.Error("context 0 should not include surrounding lines")
+	}
+
+	page = decodeDiffPage(t)(client.DiffPatch(types.DiffOptions{Range: "HEAD~1", Paths: []string{"big.txt"}, WordDiff: true, Context: -1}))
+	if !strings.Contains(page.Files[0].Hunks[0], "[-line-]{+changed+} 10") {
+		t.Errorf("word diff hunk = %q", page.Files[0].Hunks[0])
+	}
+}
+
+func TestDiffPatch_Pagination(t *testing.T) {
+	_, client := createDiffRepo(t)
+	opts := types.DiffOptions{Range: "base..HEAD", Paths: []string{"big.txt"}, Context: 20, PageSize: minDiffPageSize}
+
+	var hunks []string
+	pages := 0
+	for {
+		page := decodeDiffPage(t)(client.DiffPatch(opts))
+		pages++
+		for _, f := range page.Files {
+			if f.Header == "" || f.Path != "big.txt" {
+				t.Errorf("every page should repeat the file header: %+v", f)
+			}
+			if f.FirstHunk != len(hunks) {
+				t.Errorf("firstHunk = %d, want %d", f.FirstHunk, len(hunks))
+			}
+			hunks = append(hunks, f.Hunks...)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		if pages > 10 {
+			t.Fatal("pagination does not terminate")
+		}
+		opts.Cursor = page.NextCursor
+	}
+	if pages < 2 || len(hunks) != 4 {
+		t.Errorf("got %d hunks over %d pages, want 4 hunks over several pages", len(hunks), pages)
+	}
+}
+
+func TestDiffPatch_Errors(t *testing.T) {
+	dir, client := createDiffRepo(t)
+
+	tests := []struct {
+		name   string
+		opts   types.DiffOptions
+		errMsg string
+	}{
+		{"Option injection", types.DiffOptions{Range: "--output=/tmp/x", Context: -1}, "no puede empezar por '-'"},
+		{"Injection in range end", types.DiffOptions{Range: "main..--exec", Context: -1}, "no puede empezar por '-'"},
+		{"Staged with range", types.DiffOptions{Range: "a..b", Staged: true, Context: -1}, "staged"},
+		{"Page too small", types.DiffOptions{PageSize: 10, Context: -1}, "page_size"},
+		{"Bad cursor", types.DiffOptions{Cursor: "not-a-cursor", Context: -1}, "cursor inválido"},
+		{"Unknown ref", types.DiffOptions{Range: "nope..HEAD", Context: -1}, "nope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.DiffPatch(tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("error = %v, should contain %q", err, tt.errMsg)
+			}
+		})
+	}
+
+	t.Run("Cursor from a different diff", func(t *testing.T) {
+		opts := types.DiffOptions{Paths: []string{"big.txt"}, Context: 20, PageSize: minDiffPageSize}
+		writeBig := func(marker string) {
+			var lines []string
+			for i := 0; i < 200; i++ {
+				if i%50 == 10 {
+					lines = append(lines, marker)
+				} else {
+					lines = append(lines, fmt.Sprintf("line %d", i))
+				}
+			}
+			if err := os.WriteFile(filepath.Join(dir, "big.txt"), []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		writeBig("first edit")
+		page := decodeDiffPage(t)(client.DiffPatch(opts))
+		if page.NextCursor == "" {
+			t.Fatal("expected more than one page")
+		}
+		writeBig("second edit")
+		opts.Cursor = page.NextCursor
+		if _, err := client.DiffPatch(opts); err == nil || !strings.Contains(err.Error(), "cambió") {
+			t.Errorf("stale cursor error = %v", err)
+		}
+	})
+}
+
+func TestPaginatePatch_OversizedHunk(t *testing.T) {
+	files := []patchFile{{path: "a", status: "modified", header: "diff --git a/a b/a\n", hunks: []string{
+		"@@ -1 +1 @@\n" + strings.Repeat("+ñ", 2000) + "\n",
+		"@@ -9 +9 @@\n+x\n",
+	}}}
+
+	page := paginatePatch(files, diffCursor{digest: "d"}, minDiffPageSize)
+	if len(page.Files) != 1 || !page.Files[0].Truncated || len(page.Files[0].Hunks) != 1 {
+		t.Fatalf("page = %+v, want the first hunk truncated", page)
+	}
+	if len(page.Files[0].Header)+len(page.Files[0].Hunks[0]) > minDiffPageSize {
+		t.Error("truncated hunk should fit in the page")
+	}
+	if !utf8.ValidString(page.Files[0].Hunks[0]) {
+		t.Error("truncation should not split a UTF-8 character")
+	}
+	next, err := decodeDiffCursor(page.NextCursor)
+	if err != nil || next.file != 0 || next.hunk != 1 {
+		t.Errorf("next cursor = %+v, %v", next, err)
+	}
+}
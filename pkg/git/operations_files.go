@@ -5,8 +5,39 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/scopweb/mcp-go-github/pkg/safety"
 )
 
+// sandbox limita los directorios accesibles; el servidor lo sustituye por el
+// configurado en safety.json
+var sandbox = safety.NewSandbox(nil)
+
+// SetSandbox instala el sandbox que valida workspace, init y archivos
+func SetSandbox(s *safety.Sandbox) {
+	if s != nil {
+		sandbox = s
+	}
+}
+
+// checkDir valida que un directorio recibido como argumento esté dentro del sandbox
+func checkDir(path string) error {
+	if _, err := sandbox.Resolve(path); err != nil {
+		return fmt.Errorf("ruta no permitida: %v", err)
+	}
+	return nil
+}
+
+// repoFile resuelve una ruta relativa dentro de dir, rechazando rutas que
+// salgan del repositorio (.., enlaces simbólicos) o del sandbox
+func repoFile(dir, path string) (string, error) {
+	fullPath, err := sandbox.ResolveIn(dir, path)
+	if err != nil {
+		return "", fmt.Errorf("ruta no permitida: %v", err)
+	}
+	return fullPath, nil
+}
+
 // normalizeWindowsPath convierte rutas WSL (/mnt/c/...) a rutas Windows (C:\...)
 // y normaliza separadores de ruta
 func normalizeWindowsPath(path string) string {
@@ -27,13 +58,10 @@ func normalizeWindowsPath(path string) string {
 }
 
 func (c *Client) CreateFile(path, content string) (string, error) {
-	restore, err := c.enterWorkingDir()
+	fullPath, err := repoFile(c.Config.RepoPath, path)
 	if err != nil {
 		return "", err
 	}
-	defer restore()
-
-	fullPath := filepath.Join(c.Config.RepoPath, path)
 	err = os.MkdirAll(filepath.Dir(fullPath), 0755)
 	if err != nil {
 		return "", fmt.Errorf("error creando directorio: %v", err)
@@ -50,13 +78,10 @@ func (c *Client) CreateFile(path, content string) (string, error) {
 func (c *Client) UpdateFile(path, content, _ string) (string, error) {
 	workingDir := c.getEffectiveWorkingDir()
 
-	restore, err := enterDir(workingDir)
+	fullPath, err := repoFile(workingDir, path)
 	if err != nil {
 		return "", err
 	}
-	defer restore()
-
-	fullPath := filepath.Join(workingDir, path)
 	err = os.WriteFile(fullPath, []byte(content), 0600)
 	if err != nil {
 		return "", fmt.Errorf("error actualizando archivo: %v", err)
@@ -68,6 +93,13 @@ func (c *Client) UpdateFile(path, content, _ string) (string, error) {
 func (c *Client) SetWorkspace(workspacePath string) (string, error) {
 	// Normalizar ruta (convierte WSL a Windows, limpia separadores)
 	workspacePath = normalizeWindowsPath(workspacePath)
+	if err := checkDir(workspacePath); err != nil {
+		return "", err
+	}
+	// Guardar la ruta absoluta: los comandos y archivos no dependen del cwd
+	if abs, err := filepath.Abs(workspacePath); err == nil {
+		workspacePath = abs
+	}
 
 	// Verificar que el directorio existe (capturar todos los errores, no solo IsNotExist)
 	if _, err := os.Stat(workspacePath); err != nil {
@@ -97,18 +129,12 @@ func (c *Client) SetWorkspace(workspacePath string) (string, error) {
 	c.Config.RepoPath = workspacePath
 	c.Config.HasGit = true
 
-	// Cambiar al directorio y obtener info del repo
-	restore, err := enterDir(workspacePath)
-	if err != nil {
-		return "", fmt.Errorf("error cambiando a directorio: %v", err)
-	}
-	defer restore()
-
-	if output, err := c.executor.Command("git", "remote", "get-url", "origin").Output(); err == nil {
+	// Obtener info del repo
+	if output, err := c.gitCmdIn(workspacePath, "remote", "get-url", "origin").Output(); err == nil {
 		c.Config.RemoteURL = strings.TrimSpace(string(output))
 	}
 
-	if output, err := c.executor.Command("git", "branch", "--show-current").Output(); err == nil {
+	if output, err := c.gitCmdIn(workspacePath, "branch", "--show-current").Output(); err == nil {
 		c.Config.CurrentBranch = strings.TrimSpace(string(output))
 	}
 
@@ -122,13 +148,7 @@ func (c *Client) GetFileSHA(filePath string) (string, error) {
 	}
 
 	workingDir := c.getEffectiveWorkingDir()
-	restore, err := enterDir(workingDir)
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
-	cmd := c.executor.Command("git", "rev-parse", fmt.Sprintf("HEAD:%s", filePath))
+	cmd := c.gitCmdIn(workingDir, "rev-parse", fmt.Sprintf("HEAD:%s", filePath))
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("error obteniendo SHA del archivo %s: %v", filePath, err)
@@ -144,13 +164,7 @@ func (c *Client) GetLastCommit() (string, error) {
 	}
 
 	workingDir := c.getEffectiveWorkingDir()
-	restore, err := enterDir(workingDir)
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
-	cmd := c.executor.Command("git", "rev-parse", "HEAD")
+	cmd := c.gitCmdIn(workingDir, "rev-parse", "HEAD")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("error obteniendo SHA del commit: %v", err)
@@ -166,17 +180,11 @@ func (c *Client) GetFileContent(filePath, ref string) (string, error) {
 	}
 
 	workingDir := c.getEffectiveWorkingDir()
-	restore, err := enterDir(workingDir)
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	if ref == "" {
 		ref = "HEAD"
 	}
 
-	cmd := c.executor.Command("git", "show", fmt.Sprintf("%s:%s", ref, filePath))
+	cmd := c.gitCmdIn(workingDir, "show", fmt.Sprintf("%s:%s", ref, filePath))
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("error obteniendo contenido del archivo %s en %s: %v", filePath, ref, err)
@@ -191,17 +199,11 @@ func (c *Client) GetChangedFiles(staged bool) (string, error) {
 	}
 
 	workingDir := c.getEffectiveWorkingDir()
-	restore, err := enterDir(workingDir)
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	var cmd cmdWrapper
 	if staged {
-		cmd = c.executor.Command("git", "diff", "--cached", "--name-only")
+		cmd = c.gitCmdIn(workingDir, "diff", "--cached", "--name-only")
 	} else {
-		cmd = c.executor.Command("git", "diff", "--name-only")
+		cmd = c.gitCmdIn(workingDir, "diff", "--name-only")
 	}
 
 	output, err := cmd.Output()
@@ -220,6 +222,9 @@ func (c *Client) GetChangedFiles(staged bool) (string, error) {
 func (c *Client) ValidateRepo(path string) (string, error) {
 	// Normalizar ruta (convierte WSL a Windows, limpia separadores)
 	path = normalizeWindowsPath(path)
+	if err := checkDir(path); err != nil {
+		return "", err
+	}
 
 	// Verificar que el directorio existe
 	if _, err := os.Stat(path); err != nil {
@@ -243,17 +248,11 @@ func (c *Client) ValidateRepo(path string) (string, error) {
 		return "", fmt.Errorf("git no está disponible en el sistema: %v", err)
 	}
 
-	// Cambiar al directorio y obtener info del repo
-	restore, err := enterDir(path)
-	if err != nil {
-		return "", fmt.Errorf("error cambiando a directorio: %v", err)
-	}
-	defer restore()
-
-	cmd := c.executor.Command("git", "remote", "get-url", "origin")
+	// Obtener info del repo
+	cmd := c.gitCmdIn(path, "remote", "get-url", "origin")
 	remoteOutput, _ := cmd.Output()
 
-	cmd = c.executor.Command("git", "branch", "--show-current")
+	cmd = c.gitCmdIn(path, "branch", "--show-current")
 	branchOutput, _ := cmd.Output()
 
 	return fmt.Sprintf("Repositorio Git válido: %s, Rama: %s, Remote: %s",
@@ -268,17 +267,11 @@ func (c *Client) ListFiles(ref string) (string, error) {
 	}
 
 	workingDir := c.getEffectiveWorkingDir()
-	restore, err := enterDir(workingDir)
-	if err != nil {
-		return "", err
-	}
-	defer restore()
-
 	if ref == "" {
 		ref = "HEAD"
 	}
 
-	cmd := c.executor.Command("git", "ls-tree", "--name-only", "-r", ref)
+	cmd := c.gitCmdIn(workingDir, "ls-tree", "--name-only", "-r", ref)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("error listando archivos en %s: %v", ref, err)
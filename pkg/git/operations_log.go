@@ -0,0 +1,318 @@
+package git
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+const (
+	// defaultLogLimit y maxLogLimit acotan los commits por página de LogQuery
+	defaultLogLimit = 20
+	maxLogLimit     = 200
+
+	// Separadores del formato de git log: inicio de commit y campo
+	logRecordSep = "\x1e"
+	logFieldSep  = "\x1f"
+)
+
+// logFormat pide a git log los campos de CommitInfo, cada uno terminado en
+// logFieldSep; lo que sigue al último separador es el numstat del commit
+var logFormat = strings.Join([]string{
+	"%H", "%P", "%an", "%ae", "%aI", "%cn", "%ce", "%cI", "%s", "%b", "%(trailers:only,unfold)", "",
+}, "%x1f")
+
+// Signature es el autor o el committer de un commit
+type Signature struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Date  string `json:"date"` // ISO 8601
+}
+
+// Trailer es una línea "Clave: valor" al final del mensaje (Signed-off-by, Co-authored-by...)
+type Trailer struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// CommitFile es un archivo cambiado en un commit con sus líneas añadidas y borradas
+type CommitFile struct {
+	Path    string `json:"path"`
+	OldPath string `json:"oldPath,omitempty"`
+	Added   int    `json:"added"`
+	Deleted int    `json:"deleted"`
+	Binary  bool   `json:"binary,omitempty"`
+}
+
+// CommitInfo es un commit del historial
+type CommitInfo struct {
+	SHA       string       `json:"sha"`
+	Parents   []string     `json:"parents"`
+	Author    Signature    `json:"author"`
+	Committer Signature    `json:"committer"`
+	Subject   string       `json:"subject"`
+	Body      string       `json:"body,omitempty"`
+	Trailers  []Trailer    `json:"trailers,omitempty"`
+	Files     []CommitFile `json:"files"`
+}
+
+// LogPage es una página del historial
+type LogPage struct {
+	Range      string       `json:"range"` // rango con las refs resueltas a SHA
+	Commits    []CommitInfo `json:"commits"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+}
+
+// logCursor fija el rango ya resuelto y el desplazamiento, para que las
+// páginas siguientes no cambien aunque las ramas avancen entre llamadas, y
+// una huella de los filtros: el desplazamiento solo vale con los mismos
+type logCursor struct {
+	Range   string `json:"r"`
+	Skip    int    `json:"s"`
+	Filters string `json:"f"`
+}
+
+// LogQuery devuelve commits estructurados filtrados por rango, rutas, autor,
+// fechas, mensaje y pickaxe, paginados con un cursor
+func (c *Client) LogQuery(opts types.LogOptions) (string, error) {
+	if !c.Config.HasGit || !c.Config.IsGitRepo {
+		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
+	}
+
+	limit := opts.Limit
+	if limit == 0 {
+		limit = defaultLogLimit
+	}
+	if limit < 1 || limit > maxLogLimit {
+		return "", fmt.Errorf("limit debe estar entre 1 y %d", maxLogLimit)
+	}
+
+	filters := logFilterDigest(opts)
+	cursor := logCursor{Filters: filters}
+	if opts.Cursor != "" {
+		var err error
+		if cursor, err = decodeLogCursor(opts.Cursor); err != nil {
+			return "", err
+		}
+		if cursor.Filters != filters {
+			return "", fmt.Errorf("los filtros cambiaron desde que se emitió el cursor; vuelve a pedir la primera página")
+		}
+	} else {
+		resolved, err := c.resolveRange(opts.Range)
+		if err != nil {
+			return "", err
+		}
+		cursor.Range = resolved
+	}
+
+	// Se pide un commit de más para saber si hay otra página
+	args := logQueryArgs(opts, cursor, limit+1)
+	output, err := c.gitCmd(args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("error obteniendo historial: %v", gitError(err))
+	}
+
+	commits, err := parseLog(string(output))
+	if err != nil {
+		return "", err
+	}
+
+	page := &LogPage{Range: cursor.Range, Commits: commits}
+	if len(commits) > limit {
+		page.Commits = commits[:limit]
+		page.NextCursor = encodeLogCursor(logCursor{Range: cursor.Range, Skip: cursor.Skip + limit, Filters: filters})
+	}
+
+	jsonOutput, err := json.MarshalIndent(page, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error serializando historial: %v", err)
+	}
+	return string(jsonOutput), nil
+}
+
+// logQueryArgs construye los argumentos de git log. Los valores van unidos a
+// su opción (--author=...), así que nunca se interpretan como opciones.
+func logQueryArgs(opts types.LogOptions, cursor logCursor, count int) []string {
+	args := []string{"-c", "core.quotePath=false", "log", "-z", "--no-color", "--numstat",
+		"--format=" + logRecordSep + logFormat, "--max-count=" + strconv.Itoa(count)}
+	if cursor.Skip > 0 {
+		args = append(args, "--skip="+strconv.Itoa(cursor.Skip))
+	}
+	if opts.Author != "" {
+		args = append(args, "--author="+opts.Author)
+	}
+	if opts.Since != "" {
+		args = append(args, "--since="+opts.Since)
+	}
+	if opts.Until != "" {
+		args = append(args, "--until="+opts.Until)
+	}
+	if opts.Grep != "" {
+		args = append(args, "--grep="+opts.Grep)
+	}
+	if opts.PickaxeString != "" {
+		args = append(args, "-S"+opts.PickaxeString)
+	}
+	if opts.PickaxeRegex != "" {
+		args = append(args, "-G"+opts.PickaxeRegex)
+	}
+	if opts.FirstParent {
+		args = append(args, "--first-parent")
+	}
+
+	args = append(args, cursor.Range, "--")
+	for _, path := range opts.Paths {
+		if path != "" {
+			args = append(args, path)
+		}
+	}
+	return args
+}
+
+// resolveRange sustituye las refs de "ref", "a..b" o "a...b" por sus SHA
+func (c *Client) resolveRange(rangeSpec string) (string, error) {
+	if rangeSpec == "" {
+		rangeSpec = "HEAD"
+	}
+	refs, err := splitRange(rangeSpec)
+	if err != nil {
+		return "", err
+	}
+
+	sep := ""
+	if len(refs) == 2 {
+		sep = ".."
+		if strings.Contains(rangeSpec, "...") {
+			sep = "..."
+		}
+	}
+
+	resolved := make([]string, len(refs))
+	for i, ref := range refs {
+		if ref == "" {
+			ref = "HEAD"
+		}
+		output, err := c.gitCmd("rev-parse", "--verify", "--quiet", ref+"^{commit}").Output()
+		if err != nil {
+			return "", fmt.Errorf("ref desconocida: %s", ref)
+		}
+		resolved[i] = strings.TrimSpace(string(output))
+	}
+	return strings.Join(resolved, sep), nil
+}
+
+// parseLog interpreta la salida de git log -z --numstat con logFormat. Cada
+// commit empieza por logRecordSep; tras sus campos vienen las entradas de
+// numstat terminadas en NUL ("añadidas\tborradas\truta", o con ruta vacía
+// seguida de origen y destino en los renombrados).
+func parseLog(output string) ([]CommitInfo, error) {
+	commits := []CommitInfo{}
+	for _, record := range strings.Split(output, logRecordSep) {
+		if strings.Trim(record, "\x00\n") == "" {
+			continue
+		}
+		fields := strings.SplitN(record, logFieldSep, 12)
+		if len(fields) != 12 {
+			return nil, fmt.Errorf("registro de log inválido: %q", record)
+		}
+
+		commit := CommitInfo{
+			SHA:       fields[0],
+			Parents:   strings.Fields(fields[1]),
+			Author:    Signature{Name: fields[2], Email: fields[3], Date: fields[4]},
+			Committer: Signature{Name: fields[5], Email: fields[6], Date: fields[7]},
+			Subject:   fields[8],
+			Body:      strings.TrimSpace(fields[9]),
+			Files:     []CommitFile{},
+		}
+		if commit.Parents == nil {
+			commit.Parents = []string{}
+		}
+
+		commit.Trailers = parseTrailers(fields[10])
+		commit.Files = parseNumstat(fields[11])
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// parseTrailers interpreta las líneas "Clave: valor" de %(trailers:only,unfold)
+func parseTrailers(block string) []Trailer {
+	var trailers []Trailer
+	for _, line := range strings.Split(block, "\n") {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok || key == "" {
+			continue
+		}
+		trailers = append(trailers, Trailer{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value)})
+	}
+	return trailers
+}
+
+// parseNumstat interpreta las entradas de --numstat -z
+func parseNumstat(block string) []CommitFile {
+	files := []CommitFile{}
+	tokens := strings.Split(block, "\x00")
+	for i := 0; i < len(tokens); i++ {
+		token := strings.TrimLeft(tokens[i], "\n")
+		parts := strings.SplitN(token, "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		file := CommitFile{Path: parts[2]}
+		if parts[0] == "-" && parts[1] == "-" {
+			file.Binary = true
+		} else {
+			file.Added, _ = strconv.Atoi(parts[0])
+			file.Deleted, _ = strconv.Atoi(parts[1])
+		}
+		// Renombrado: ruta vacía y luego origen y destino
+		if file.Path == "" && i+2 < len(tokens) {
+			file.OldPath, file.Path = tokens[i+1], tokens[i+2]
+			i += 2
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+// logFilterDigest resume los filtros que deciden qué commits entran en el
+// historial; el límite por página puede cambiar entre llamadas
+func logFilterDigest(opts types.LogOptions) string {
+	var paths []string
+	for _, path := range opts.Paths {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	data, _ := json.Marshal([]interface{}{
+		paths, opts.Author, opts.Since, opts.Until, opts.Grep,
+		opts.PickaxeString, opts.PickaxeRegex, opts.FirstParent,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+func encodeLogCursor(cursor logCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeLogCursor(value string) (logCursor, error) {
+	var cursor logCursor
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || json.Unmarshal(data, &cursor) != nil || cursor.Skip < 0 || cursor.Range == "" {
+		return logCursor{}, fmt.Errorf("cursor inválido: %s", value)
+	}
+	// El rango del cursor vuelve a la línea de comandos: mismas reglas que una ref
+	if _, err := splitRange(cursor.Range); err != nil {
+		return logCursor{}, fmt.Errorf("cursor inválido: %s", value)
+	}
+	return cursor, nil
+}
@@ -0,0 +1,209 @@
+package git
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+// createLogRepo builds a history with two authors, trailers, a rename, a
+// binary file and a merged side branch on top of the initial commit
+func createLogRepo(t *testing.T) (string, *Client) {
+	t.Helper()
+	dir := createTestRepo(t)
+	runGit(t, dir, "tag", "start")
+
+	commitFile(t, dir, "old name.txt", "token alpha\n", "add rename source")
+	runGit(t, dir, "mv", "old name.txt", "new name.txt")
+	if err := os.WriteFile(filepath.Join(dir, "image.bin"), []byte{0, 1, 2, 0, 255}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "-c", "user.name=Alice", "-c", "user.email=alice@example.com", "commit",
+		"-m", "rename and add image\n\nLonger explanation.\n\nSigned-off-by: Alice <alice@example.com>\nCo-authored-by: Bob <bob@example.com>")
+
+	runGit(t, dir, "checkout", "-q", "-b", "side")
+	commitFile(t, dir, "side.txt", "side work\n", "side commit")
+	runGit(t, dir, "checkout", "-q", "-")
+	commitFile(t, dir, "main.txt", "regex 42\n", "fix: main commit")
+	runGit(t, dir, "merge", "--no-ff", "-q", "-m", "merge side", "side")
+
+	client := &Client{
+		Config:   &types.GitConfig{HasGit: true, IsGitRepo: true, RepoPath: dir},
+		executor: &realExecutor{},
+	}
+	return dir, client
+}
+
+func decodeLogPage(t *testing.T) func(string, error) LogPage {
+	return func(output string, err error) LogPage {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var page LogPage
+		if err := json.Unmarshal([]byte(output), &page); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, output)
+		}
+		return page
+	}
+}
+
+func subjects(page LogPage) []string {
+	var result []string
+	for _, c := range page.Commits {
+		result = append(result, c.Subject)
+	}
+	return result
+}
+
+func TestLogQuery_StructuredCommits(t *testing.T) {
+	_, client := createLogRepo(t)
+
+	page := decodeLogPage(t)(client.LogQuery(types.LogOptions{Author: "alice"}))
+	if len(page.Commits) != 1 {
+		t.Fatalf("commits = %v, want only Alice's", subjects(page))
+	}
+	c := page.Commits[0]
+	if len(c.SHA) != 40 || len(c.Parents) != 1 {
+		t.Errorf("sha = %q, parents = %v", c.SHA, c.Parents)
+	}
+	if c.Author.Name != "Alice" || c.Author.Email != "alice@example.com" || c.Author.Date == "" {
+		t.Errorf("author = %+v", c.Author)
+	}
+	if c.Committer.Name != "Alice" {
+		t.Errorf("committer = %+v", c.Committer)
+	}
+	if c.Subject != "rename and add image" || !strings.HasPrefix(c.Body, "Longer explanation.") {
+		t.Errorf("subject = %q, body = %q", c.Subject, c.Body)
+	}
+	want := []Trailer{{"Signed-off-by", "Alice <alice@example.com>"}, {"Co-authored-by", "Bob <bob@example.com>"}}
+	if len(c.Trailers) != 2 || c.Trailers[0] != want[0] || c.Trailers[1] != want[1] {
+		t.Errorf("trailers = %+v", c.Trailers)
+	}
+
+	files := map[string]CommitFile{}
+	for _, f := range c.Files {
+		files[f.Path] = f
+	}
+	if f := files["new name.txt"]; f.OldPath != "old name.txt" || f.Added != 0 || f.Deleted != 0 {
+		t.Errorf("rename = %+v (files %+v)", f, c.Files)
+	}
+	if f := files["image.bin"]; !f.Binary {
+		t.Errorf("binary = %+v", f)
+	}
+
+	merge := decodeLogPage(t)(client.LogQuery(types.LogOptions{Limit: 1})).Commits[0]
+	if merge.Subject != "merge side" || len(merge.Parents) != 2 {
+		t.Errorf("merge commit = %+v", merge)
+	}
+}
+
+func TestLogQuery_Filters(t *testing.T) {
+	_, client := createLogRepo(t)
+
+	tests := []struct {
+		name string
+		opts types.LogOptions
+		want []string
+	}{
+		{"Grep", types.LogOptions{Grep: "^fix:"}, []string{"fix: main commit"}},
+		{"Pickaxe string", types.LogOptions{PickaxeString: "token alpha"}, []string{"add rename source"}},
+		{"Pickaxe regex", types.LogOptions{PickaxeRegex: "regex [0-9]+"}, []string{"fix: main commit"}},
+		{"Path with spaces", types.LogOptions{Paths: []string{"old name.txt"}}, []string{"rename and add image", "add rename source"}},
+		{"Several paths", types.LogOptions{Paths: []string{"side.txt", "image.bin"}}, []string{"side commit", "rename and add image"}},
+		{"First parent", types.LogOptions{Range: "start..HEAD", FirstParent: true}, []string{"merge side", "fix: main commit", "rename and add image", "add rename source"}},
+		{"Range", types.LogOptions{Range: "HEAD^1..side"}, []string{"side commit"}},
+		{"Symmetric range", types.LogOptions{Range: "side...HEAD^1", Grep: "side"}, []string{"side commit"}},
+		{"Future since", types.LogOptions{Since: "2099-01-01"}, nil},
+		{"Past until", types.LogOptions{Until: "2000-01-01"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := subjects(decodeLogPage(t)(client.LogQuery(tt.opts)))
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("subjects = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogQuery_Pagination(t *testing.T) {
+	dir, client := createLogRepo(t)
+	all := subjects(decodeLogPage(t)(client.LogQuery(types.LogOptions{})))
+
+	opts := types.LogOptions{Limit: 2}
+	first := decodeLogPage(t)(client.LogQuery(opts))
+	if len(first.Commits) != 2 || first.NextCursor == "" {
+		t.Fatalf("first page = %v, want 2 commits and a cursor", subjects(first))
+	}
+
+	// A commit made between calls must not shift the following pages
+	commitFile(t, dir, "late.txt", "late\n", "late commit")
+
+	got := subjects(first)
+	opts.Cursor = first.NextCursor
+	for pages := 1; opts.Cursor != ""; pages++ {
+		if pages > 10 {
+			t.Fatal("pagination does not terminate")
+		}
+		page := decodeLogPage(t)(client.LogQuery(opts))
+		if page.Range != first.Range {
+			t.Errorf("range = %q, want %q", page.Range, first.Range)
+		}
+		got = append(got, subjects(page)...)
+		opts.Cursor = page.NextCursor
+	}
+	if strings.Join(got, "|") != strings.Join(all, "|") {
+		t.Errorf("paged subjects = %q, want %q", got, all)
+	}
+
+	t.Run("Cursor reused with other filters", func(t *testing.T) {
+		first := decodeLogPage(t)(client.LogQuery(types.LogOptions{Limit: 1, Paths: []string{"side.txt", "image.bin"}}))
+		for name, opts := range map[string]types.LogOptions{
+			"author":  {Author: "nobody", Paths: []string{"side.txt", "image.bin"}},
+			"paths":   {Paths: []string{"side.txt"}},
+			"grep":    {Grep: "side", Paths: []string{"side.txt", "image.bin"}},
+			"pickaxe": {PickaxeString: "token alpha", Paths: []string{"side.txt", "image.bin"}},
+		} {
+			opts.Cursor = first.NextCursor
+			if _, err := client.LogQuery(opts); err == nil || !strings.Contains(err.Error(), "filtros cambiaron") {
+				t.Errorf("%s: error = %v", name, err)
+			}
+		}
+		next := decodeLogPage(t)(client.LogQuery(types.LogOptions{Limit: 5, Paths: []string{"side.txt", "", "image.bin"}, Cursor: first.NextCursor}))
+		if got := subjects(next); strings.Join(got, "|") != "rename and add image" {
+			t.Errorf("same filters with another limit = %q", got)
+		}
+	})
+}
+
+func TestLogQuery_Errors(t *testing.T) {
+	_, client := createLogRepo(t)
+
+	tests := []struct {
+		name   string
+		opts   types.LogOptions
+		errMsg string
+	}{
+		{"Option injection", types.LogOptions{Range: "--output=/tmp/x"}, "no puede empezar por '-'"},
+		{"Injection in range end", types.LogOptions{Range: "main..--exec"}, "no puede empezar por '-'"},
+		{"Unknown ref", types.LogOptions{Range: "nope..HEAD"}, "ref desconocida: nope"},
+		{"Limit too large", types.LogOptions{Limit: maxLogLimit + 1}, "limit"},
+		{"Negative limit", types.LogOptions{Limit: -1}, "limit"},
+		{"Bad cursor", types.LogOptions{Cursor: "not-a-cursor"}, "cursor inválido"},
+		{"Cursor with injected range", types.LogOptions{Cursor: encodeLogCursor(logCursor{Range: "--output=/tmp/x"})}, "cursor inválido"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.LogQuery(tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("error = %v, should contain %q", err, tt.errMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package git
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	exec_pkg "os/exec"
+	"strconv"
+	"strings"
+)
+
+// FileChange es un archivo que cambiaría con la fusión (status A, M, D, ...)
+type FileChange struct {
+	Status string `json:"status"`
+	Path   string `json:"path"`
+}
+
+// MergeConflict es un archivo que quedaría en conflicto, con sus hunks
+type MergeConflict struct {
+	Path    string           `json:"path"`
+	Type    string           `json:"type"`
+	Markers []ConflictMarker `json:"markers,omitempty"`
+}
+
+// MergePreview es el resultado de un merge o rebase calculado con
+// git merge-tree, sin tocar el working tree, el índice ni las ramas
+type MergePreview struct {
+	Operation    string          `json:"operation"`
+	Source       string          `json:"source"`
+	Target       string          `json:"target"`
+	MergeBase    string          `json:"mergeBase,omitempty"`
+	FastForward  bool            `json:"fastForward"`
+	UpToDate     bool            `json:"upToDate"`
+	Clean        bool            `json:"clean"`
+	ResultTree   string          `json:"resultTree,omitempty"`
+	FilesChanged []FileChange    `json:"filesChanged"`
+	Conflicts    []MergeConflict `json:"conflicts"`
+	Messages     []string        `json:"messages,omitempty"`
+	Commits      []string        `json:"commits,omitempty"`
+	Note         string          `json:"note,omitempty"`
+}
+
+// PreviewMerge calcula el resultado de fusionar source en target (por defecto
+// la rama actual) sin modificar el repositorio
+func (c *Client) PreviewMerge(source string, target string) (string, error) {
+	if !c.Config.HasGit || !c.Config.IsGitRepo {
+		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
+	}
+	if source == "" {
+		return "", fmt.Errorf("se requiere la rama origen")
+	}
+
+	if target == "" {
+		target = c.currentBranchName()
+	}
+
+	preview := &MergePreview{Operation: "merge", Source: source, Target: target}
+	if err := c.previewMergeTree(preview, target, source); err != nil {
+		return "", err
+	}
+
+	output, _ := json.MarshalIndent(preview, "", "  ")
+	return string(output), nil
+}
+
+// PreviewRebase calcula el resultado de hacer rebase de la rama actual sobre
+// upstream sin modificar el repositorio. El árbol final de un rebase coincide
+// con el de la fusión de ambas ramas; los conflictos se calculan sobre ese
+// resultado, no commit a commit.
+func (c *Client) PreviewRebase(upstream string) (string, error) {
+	if !c.Config.HasGit || !c.Config.IsGitRepo {
+		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
+	}
+	if upstream == "" {
+		return "", fmt.Errorf("se requiere la rama sobre la que hacer rebase")
+	}
+
+	preview := &MergePreview{Operation: "rebase", Source: c.currentBranchName(), Target: upstream}
+	if err := c.previewMergeTree(preview, upstream, "HEAD"); err != nil {
+		return "", err
+	}
+
+	if !preview.UpToDate && !preview.FastForward {
+		logCmd := c.gitCmd("log", "--reverse", "--format=%h %s", upstream+"..HEAD")
+		if output, err := logCmd.Output(); err == nil {
+			for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+				if line != "" {
+					preview.Commits = append(preview.Commits, line)
+				}
+			}
+		}
+		if !preview.Clean {
+			preview.Note = "Los conflictos se calculan sobre el resultado final; durante el rebase pueden aparecer en commits distintos o repetirse"
+		}
+	}
+
+	output, _ := json.MarshalIndent(preview, "", "  ")
+	return string(output), nil
+}
+
+// previewMergeTree rellena el preview fusionando theirs sobre ours con
+// git merge-tree --write-tree (git >= 2.38)
+func (c *Client) previewMergeTree(preview *MergePreview, ours, theirs string) error {
+	oursHash, err := c.resolveCommit(ours)
+	if err != nil {
+		return err
+	}
+	theirsHash, err := c.resolveCommit(theirs)
+	if err != nil {
+		return err
+	}
+
+	baseCmd := c.gitCmd("merge-base", oursHash, theirsHash)
+	base, err := baseCmd.Output()
+	if err != nil {
+		return fmt.Errorf("no hay historia común entre '%s' y '%s'", ours, theirs)
+	}
+	preview.MergeBase = strings.TrimSpace(string(base))
+	preview.FilesChanged = []FileChange{}
+	preview.Conflicts = []MergeConflict{}
+
+	if preview.MergeBase == theirsHash {
+		preview.UpToDate = true
+		preview.Clean = true
+		return nil
+	}
+	preview.FastForward = preview.MergeBase == oursHash
+
+	mergeCmd := c.gitCmd("merge-tree", "--write-tree", "-z", oursHash, theirsHash)
+	output, err := mergeCmd.Output()
+	conflicted := false
+	if err != nil {
+		var exitErr *exec_pkg.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+			return fmt.Errorf("error ejecutando git merge-tree (requiere git >= 2.38): %v", err)
+		}
+		conflicted = true
+	}
+
+	tokens := strings.Split(string(output), "\x00")
+	preview.ResultTree = strings.TrimSpace(tokens[0])
+	preview.Clean = !conflicted
+	if conflicted {
+		preview.Conflicts, preview.Messages = parseMergeTreeConflicts(tokens[1:])
+		for i := range preview.Conflicts {
+			conflict := &preview.Conflicts[i]
+			catCmd := c.gitCmd("cat-file", "-p", preview.ResultTree+":"+conflict.Path)
+			if content, err := catCmd.Output(); err == nil {
+				conflict.Markers = c.parseConflictMarkers(conflict.Path, string(content)).Markers
+			}
+		}
+	}
+
+	diffCmd := c.gitCmd("diff-tree", "-r", "-z", "--name-status", oursHash, preview.ResultTree)
+	diff, err := diffCmd.Output()
+	if err != nil {
+		return fmt.Errorf("error obteniendo archivos modificados: %v", err)
+	}
+	fields := strings.Split(string(diff), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		preview.FilesChanged = append(preview.FilesChanged, FileChange{Status: fields[i], Path: fields[i+1]})
+	}
+
+	return nil
+}
+
+// parseMergeTreeConflicts interpreta la salida -z de merge-tree tras el OID del
+// árbol: entradas "<mode> <oid> <stage>\t<path>" hasta un token vacío, y luego
+// mensajes "<n>, n paths, <tipo>, <mensaje>"
+func parseMergeTreeConflicts(tokens []string) ([]MergeConflict, []string) {
+	conflicts := []MergeConflict{}
+	index := make(map[string]int)
+
+	i := 0
+	for ; i < len(tokens) && tokens[i] != ""; i++ {
+		tab := strings.IndexByte(tokens[i], '\t')
+		if tab < 0 {
+			continue
+		}
+		path := tokens[i][tab+1:]
+		if _, seen := index[path]; !seen {
+			index[path] = len(conflicts)
+			conflicts = append(conflicts, MergeConflict{Path: path, Type: "CONFLICT"})
+		}
+	}
+
+	var messages []string
+	typed := make(map[string]bool)
+	for i++; i < len(tokens); {
+		n, err := strconv.Atoi(tokens[i])
+		if err != nil || i+n+2 >= len(tokens) {
+			break
+		}
+		paths := tokens[i+1 : i+1+n]
+		msgType := tokens[i+n+1]
+		messages = append(messages, strings.TrimSpace(tokens[i+n+2]))
+		if strings.HasPrefix(msgType, "CONFLICT") {
+			for _, path := range paths {
+				if idx, ok := index[path]; ok && !typed[path] {
+					conflicts[idx].Type = msgType
+					typed[path] = true
+				}
+			}
+		}
+		i += n + 3
+	}
+
+	return conflicts, messages
+}
+
+// resolveCommit devuelve el hash del commit al que apunta ref
+func (c *Client) resolveCommit(ref string) (string, error) {
+	cmd := c.gitCmd("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("rama o commit '%s' no encontrado", ref)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// currentBranchName devuelve la rama actual, o HEAD si está desacoplado
+func (c *Client) currentBranchName() string {
+	cmd := c.gitCmd("rev-parse", "--abbrev-ref", "HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return "HEAD"
+	}
+	return strings.TrimSpace(string(output))
+}
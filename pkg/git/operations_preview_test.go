@@ -0,0 +1,179 @@
+package git
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+// runGit runs a git command in dir and returns its trimmed output
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func commitFile(t *testing.T, dir, name, content, message string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	runGit(t, dir, "add", name)
+	runGit(t, dir, "commit", "-m", message)
+}
+
+// createDivergedRepo creates a repo whose "main" and "feature" branches both
+// changed config.txt, and "feature" also added feature.txt. HEAD is on main.
+func createDivergedRepo(t *testing.T) (string, *Client) {
+	t.Helper()
+	dir := createTestRepo(t)
+	runGit(t, dir, "branch", "-M", "main")
+	commitFile(t, dir, "config.txt", "a\nb\nc\n", "add config")
+
+	runGit(t, dir, "checkout", "-b", "feature")
+	commitFile(t, dir, "config.txt", "a\nfeature\nc\n", "feature config")
+	commitFile(t, dir, "feature.txt", "new\n", "add feature file")
+
+	runGit(t, dir, "checkout", "main")
+	commitFile(t, dir, "config.txt", "a\nmain\nc\n", "main config")
+
+	client := &Client{
+		Config:   &types.GitConfig{HasGit: true, IsGitRepo: true, RepoPath: dir},
+		executor: &realExecutor{},
+	}
+	return dir, client
+}
+
+func decodePreview(t *testing.T, output string) MergePreview {
+	t.Helper()
+	var preview MergePreview
+	if err := json.Unmarshal([]byte(output), &preview); err != nil {
+		t.Fatalf("invalid preview JSON: %v\n%s", err, output)
+	}
+	return preview
+}
+
+func TestPreviewMerge_Conflict(t *testing.T) {
+	dir, client := createDivergedRepo(t)
+	head := runGit(t, dir, "rev-parse", "HEAD")
+
+	output, err := client.PreviewMerge("feature", "")
+	if err != nil {
+		t.Fatalf("PreviewMerge() error = %v", err)
+	}
+	preview := decodePreview(t, output)
+
+	if preview.Target != "main" || preview.Clean || preview.FastForward || preview.UpToDate {
+		t.Errorf("unexpected preview flags: %+v", preview)
+	}
+	if len(preview.Conflicts) != 1 || preview.Conflicts[0].Path != "config.txt" {
+		t.Fatalf("conflicts = %+v, want config.txt", preview.Conflicts)
+	}
+	conflict := preview.Conflicts[0]
+	if !strings.HasPrefix(conflict.Type, "CONFLICT") {
+		t.Errorf("conflict type = %q", conflict.Type)
+	}
+	if len(conflict.Markers) != 1 || conflict.Markers[0].Ours != "main" || conflict.Markers[0].Theirs != "feature" {
+		t.Errorf("hunks = %+v, want ours=main theirs=feature", conflict.Markers)
+	}
+
+	changed := make(map[string]string)
+	for _, f := range preview.FilesChanged {
+		changed[f.Path] = f.Status
+	}
+	if changed["feature.txt"] != "A" || changed["config.txt"] != "M" {
+		t.Errorf("filesChanged = %+v", preview.FilesChanged)
+	}
+
+	// Nothing was touched
+	if got := runGit(t, dir, "rev-parse", "HEAD"); got != head {
+		t.Errorf("HEAD moved from %s to %s", head, got)
+	}
+	if status := runGit(t, dir, "status", "--porcelain"); status != "" {
+		t.Errorf("worktree changed:\n%s", status)
+	}
+	if content, _ := os.ReadFile(filepath.Join(dir, "config.txt")); string(content) != "a\nmain\nc\n" {
+		t.Errorf("config.txt changed: %q", content)
+	}
+}
+
+func TestPreviewMerge_FastForwardAndUpToDate(t *testing.T) {
+	dir, client := createDivergedRepo(t)
+	runGit(t, dir, "checkout", "-b", "hotfix")
+	commitFile(t, dir, "fix.txt", "fix\n", "hotfix")
+
+	preview := decodePreview(t, mustPreview(t)(client.PreviewMerge("hotfix", "main")))
+	if !preview.FastForward || !preview.Clean || len(preview.Conflicts) != 0 {
+		t.Errorf("hotfix into main should fast-forward cleanly: %+v", preview)
+	}
+	if len(preview.FilesChanged) != 1 || preview.FilesChanged[0] != (FileChange{Status: "A", Path: "fix.txt"}) {
+		t.Errorf("filesChanged = %+v", preview.FilesChanged)
+	}
+
+	preview = decodePreview(t, mustPreview(t)(client.PreviewMerge("main", "hotfix")))
+	if !preview.UpToDate || len(preview.FilesChanged) != 0 {
+		t.Errorf("main is already in hotfix: %+v", preview)
+	}
+
+	if _, err := client.PreviewMerge("missing", "main"); err == nil {
+		t.Error("unknown branch should fail")
+	}
+}
+
+func TestPreviewRebase(t *testing.T) {
+	dir, client := createDivergedRepo(t)
+	runGit(t, dir, "checkout", "feature")
+	head := runGit(t, dir, "rev-parse", "HEAD")
+
+	preview := decodePreview(t, mustPreview(t)(client.PreviewRebase("main")))
+	if preview.Operation != "rebase" || preview.Source != "feature" || preview.Target != "main" {
+		t.Errorf("unexpected preview header: %+v", preview)
+	}
+	if preview.Clean || len(preview.Conflicts) != 1 || preview.Note == "" {
+		t.Errorf("rebase onto main should report the config.txt conflict: %+v", preview)
+	}
+	if len(preview.Commits) != 2 || !strings.HasSuffix(preview.Commits[0], "feature config") {
+		t.Errorf("commits to replay = %v", preview.Commits)
+	}
+	if got := runGit(t, dir, "rev-parse", "HEAD"); got != head {
+		t.Errorf("HEAD moved from %s to %s", head, got)
+	}
+}
+
+func TestParseMergeTreeConflicts(t *testing.T) {
+	tokens := strings.Split("100644 aaa 1\tf\x00100644 bbb 2\tf\x00100644 ccc 3\tf\x00100644 ddd 2\tg\x00\x00"+
+		"1\x00f\x00Auto-merging\x00Auto-merging f\n\x00"+
+		"1\x00f\x00CONFLICT (contents)\x00CONFLICT (content): Merge conflict in f\n\x00"+
+		"1\x00g\x00CONFLICT (modify/delete)\x00CONFLICT (modify/delete): g deleted in main\n\x00", "\x00")
+
+	conflicts, messages := parseMergeTreeConflicts(tokens)
+	if len(conflicts) != 2 {
+		t.Fatalf("conflicts = %+v, want f and g", conflicts)
+	}
+	if conflicts[0].Path != "f" || conflicts[0].Type != "CONFLICT (contents)" || conflicts[1].Type != "CONFLICT (modify/delete)" {
+		t.Errorf("conflicts = %+v", conflicts)
+	}
+	if len(messages) != 3 || messages[0] != "Auto-merging f" {
+		t.Errorf("messages = %q", messages)
+	}
+}
+
+func mustPreview(t *testing.T) func(string, error) string {
+	return func(output string, err error) string {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("preview error = %v", err)
+		}
+		return output
+	}
+}
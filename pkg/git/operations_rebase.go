@@ -0,0 +1,399 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+// rebaseStateDir guarda, dentro del directorio de git, la lista de tareas y
+// los mensajes nuevos; tienen que sobrevivir a las paradas por conflicto
+const rebaseStateDir = "mcp-rebase"
+
+// rebaseEditor es el editor de mensajes durante el rebase: acepta el mensaje
+// preparado por git, así que git nunca espera a un editor interactivo
+const rebaseEditor = "GIT_EDITOR=true"
+
+// amendMessageExec es el paso de la lista de tareas que aplica el mensaje
+// nuevo de un reword o un squash al commit que lo precede
+const amendMessageExec = "exec git commit --amend --quiet --allow-empty -F "
+
+var rebaseActions = map[string]bool{
+	"pick": true, "reword": true, "edit": true, "squash": true, "fixup": true, "drop": true,
+}
+
+// RebaseResult es el estado de un rebase tras ejecutarlo o continuarlo
+type RebaseResult struct {
+	Status          string   `json:"status"` // completed, conflict, stopped, aborted
+	Head            string   `json:"head"`
+	Onto            string   `json:"onto,omitempty"`
+	StoppedAt       string   `json:"stoppedAt,omitempty"`
+	Done            int      `json:"done,omitempty"`
+	Remaining       int      `json:"remaining,omitempty"`
+	ConflictedFiles []string `json:"conflictedFiles,omitempty"`
+	Output          string   `json:"output,omitempty"`
+	Hint            string   `json:"hint,omitempty"`
+}
+
+// RebaseInteractive reescribe los commits de onto..HEAD según steps sin
+// intervención humana: un GIT_SEQUENCE_EDITOR generado sustituye la lista de
+// tareas de git rebase -i, y los mensajes nuevos se aplican con líneas exec
+// (git commit --amend -F). Cada commit del rango debe aparecer exactamente
+// una vez; para quitarlo se usa drop. Ante un conflicto o un edit el rebase
+// se detiene y sigue con RebaseControl.
+func (c *Client) RebaseInteractive(onto string, steps []types.RebaseStep) (string, error) {
+	if !c.Config.HasGit || !c.Config.IsGitRepo {
+		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
+	}
+	if c.rebaseInProgress() {
+		return "", fmt.Errorf("ya hay un rebase en curso; usa rebase_continue, rebase_skip o rebase_abort")
+	}
+	if clean, err := c.ValidateCleanState(); err != nil {
+		return "", fmt.Errorf("error validando estado: %v", err)
+	} else if !clean {
+		return "", fmt.Errorf("el directorio de trabajo debe estar limpio para hacer rebase")
+	}
+
+	if err := checkRef(onto); err != nil {
+		return "", err
+	}
+	ontoSHA, err := c.resolveCommit(onto)
+	if err != nil {
+		return "", err
+	}
+	output, err := c.gitCmd("rev-list", "--reverse", ontoSHA+"..HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("error listando commits: %v", gitError(err))
+	}
+	commits := strings.Fields(string(output))
+	if output, err := c.gitCmd("rev-list", "--min-parents=2", ontoSHA+"..HEAD").Output(); err != nil {
+		return "", fmt.Errorf("error listando commits: %v", gitError(err))
+	} else if len(strings.TrimSpace(string(output))) > 0 {
+		return "", fmt.Errorf("el rango %s..HEAD contiene merges; el rebase interactivo solo admite historia lineal", onto)
+	}
+
+	resolved := make([]types.RebaseStep, len(steps))
+	for i, step := range steps {
+		if err := checkRef(step.Commit); err != nil {
+			return "", fmt.Errorf("paso %d: %v", i+1, err)
+		}
+		sha, err := c.resolveCommit(step.Commit)
+		if err != nil {
+			return "", fmt.Errorf("paso %d: %v", i+1, err)
+		}
+		resolved[i] = types.RebaseStep{Action: step.Action, Commit: sha, Message: step.Message}
+	}
+	if err := validateRebaseSteps(resolved, commits); err != nil {
+		return "", err
+	}
+
+	stateDir, err := c.gitPath(rebaseStateDir)
+	if err != nil {
+		return "", err
+	}
+	todoPath, err := writeRebaseTodo(stateDir, resolved)
+	if err != nil {
+		return "", err
+	}
+
+	cmd := c.gitCmd("rebase", "-i", ontoSHA)
+	cmd.SetEnv("GIT_SEQUENCE_EDITOR=cp "+shellQuote(todoPath), rebaseEditor)
+	output, runErr := cmd.CombinedOutput()
+	return c.rebaseResult(output, runErr)
+}
+
+// RebaseControl continúa, salta el commit actual o aborta un rebase detenido
+func (c *Client) RebaseControl(action string) (string, error) {
+	if !c.Config.HasGit || !c.Config.IsGitRepo {
+		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
+	}
+	if action != "continue" && action != "skip" && action != "abort" {
+		return "", fmt.Errorf("acción de rebase no válida: %s. Usa: continue, skip, abort", action)
+	}
+	if !c.rebaseInProgress() {
+		return "", fmt.Errorf("no hay ningún rebase en curso")
+	}
+
+	if action == "continue" {
+		status, err := c.StatusInfo()
+		if err != nil {
+			return "", err
+		}
+		if conflicted := entryPaths(status.Conflicted); len(conflicted) > 0 {
+			return "", fmt.Errorf("quedan archivos en conflicto (%s); resuélvelos con git_conflict resolve antes de continuar",
+				strings.Join(conflicted, ", "))
+		}
+	}
+
+	if action == "skip" {
+		if err := c.dropSkippedMessage(); err != nil {
+			return "", err
+		}
+	}
+
+	cmd := c.gitCmd("rebase", "--"+action)
+	cmd.SetEnv(rebaseEditor)
+	output, runErr := cmd.CombinedOutput()
+
+	if action == "abort" {
+		if runErr != nil {
+			return "", fmt.Errorf("error abortando rebase: %v, Output: %s", runErr, output)
+		}
+		c.removeRebaseState()
+		head, _ := c.resolveCommit("HEAD")
+		return marshalRebaseResult(&RebaseResult{Status: "aborted", Head: head})
+	}
+	return c.rebaseResult(output, runErr)
+}
+
+// validateRebaseSteps comprueba acciones, mensajes y que los pasos cubran
+// exactamente los commits del rango
+func validateRebaseSteps(steps []types.RebaseStep, commits []string) error {
+	if len(steps) == 0 {
+		return fmt.Errorf("la lista de tareas del rebase está vacía")
+	}
+	pending := make(map[string]bool, len(commits))
+	for _, sha := range commits {
+		pending[sha] = true
+	}
+
+	first := true
+	for i, step := range steps {
+		if !rebaseActions[step.Action] {
+			return fmt.Errorf("paso %d: acción no válida '%s'. Usa: pick, reword, edit, squash, fixup, drop", i+1, step.Action)
+		}
+		if !pending[step.Commit] {
+			return fmt.Errorf("paso %d: el commit %s no está en el rango o está repetido", i+1, shortSHA(step.Commit))
+		}
+		delete(pending, step.Commit)
+
+		switch step.Action {
+		case "reword":
+			if strings.TrimSpace(step.Message) == "" {
+				return fmt.Errorf("paso %d: reword necesita un mensaje", i+1)
+			}
+		case "squash":
+		default:
+			if step.Message != "" {
+				return fmt.Errorf("paso %d: message solo se admite con reword o squash", i+1)
+			}
+		}
+		if step.Action == "drop" {
+			continue
+		}
+		if first && (step.Action == "squash" || step.Action == "fixup") {
+			return fmt.Errorf("paso %d: %s necesita un commit anterior con el que combinarse", i+1, step.Action)
+		}
+		first = false
+	}
+
+	if len(pending) > 0 {
+		var missing []string
+		for _, sha := range commits {
+			if pending[sha] {
+				missing = append(missing, shortSHA(sha))
+			}
+		}
+		return fmt.Errorf("faltan commits del rango en la lista de tareas (usa drop para quitarlos): %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// writeRebaseTodo escribe la lista de tareas y los mensajes nuevos en
+// stateDir y devuelve la ruta de la lista. reword se convierte en pick y
+// squash con mensaje en fixup, seguidos de un exec que aplica el mensaje.
+func writeRebaseTodo(stateDir string, steps []types.RebaseStep) (string, error) {
+	if err := os.RemoveAll(stateDir); err != nil {
+		return "", fmt.Errorf("no se puede preparar el rebase: %v", err)
+	}
+	if err := os.MkdirAll(stateDir, 0700); err != nil {
+		return "", fmt.Errorf("no se puede preparar el rebase: %v", err)
+	}
+
+	var todo strings.Builder
+	for i, step := range steps {
+		action := step.Action
+		switch {
+		case action == "reword":
+			action = "pick"
+		case action == "squash" && step.Message != "":
+			action = "fixup"
+		}
+		fmt.Fprintf(&todo, "%s %s\n", action, step.Commit)
+		if step.Message == "" {
+			continue
+		}
+		msgPath := filepath.Join(stateDir, "message-"+strconv.Itoa(i+1))
+		if err := os.WriteFile(msgPath, []byte(step.Message), 0600); err != nil {
+			return "", fmt.Errorf("no se puede preparar el rebase: %v", err)
+		}
+		fmt.Fprintf(&todo, "%s%s\n", amendMessageExec, shellQuote(msgPath))
+	}
+
+	todoPath := filepath.Join(stateDir, "todo")
+	if err := os.WriteFile(todoPath, []byte(todo.String()), 0600); err != nil {
+		return "", fmt.Errorf("no se puede preparar el rebase: %v", err)
+	}
+	return todoPath, nil
+}
+
+// dropSkippedMessage quita de la lista pendiente el exec que aplica el
+// mensaje nuevo del commit que se va a saltar; si no, git lo ejecutaría
+// igualmente y cambiaría el mensaje del commit anterior
+func (c *Client) dropSkippedMessage() error {
+	dir, err := c.gitPath("rebase-merge")
+	if err != nil {
+		return err
+	}
+	todoPath := filepath.Join(dir, "git-rebase-todo")
+	content, err := os.ReadFile(todoPath)
+	if err != nil {
+		return nil // sin lista pendiente no hay nada que quitar
+	}
+
+	lines := strings.SplitAfter(string(content), "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if !strings.HasPrefix(trimmed, amendMessageExec) {
+			return nil
+		}
+		todo := strings.Join(append(lines[:i:i], lines[i+1:]...), "")
+		if err := os.WriteFile(todoPath, []byte(todo), 0600); err != nil {
+			return fmt.Errorf("error actualizando la lista de tareas del rebase: %v", err)
+		}
+		return nil
+	}
+	return nil
+}
+
+// rebaseResult describe el estado tras ejecutar git rebase. Si el rebase ya
+// no está en curso, un error es un fallo real; si sigue en curso, git se ha
+// detenido en un conflicto, en un edit o en un exec fallido.
+func (c *Client) rebaseResult(output []byte, runErr error) (string, error) {
+	if !c.rebaseInProgress() {
+		c.removeRebaseState()
+		if runErr != nil {
+			return "", fmt.Errorf("error en rebase: %v, Output: %s", runErr, output)
+		}
+		head, _ := c.resolveCommit("HEAD")
+		return marshalRebaseResult(&RebaseResult{Status: "completed", Head: head})
+	}
+
+	result := &RebaseResult{Status: "stopped", Output: strings.TrimSpace(string(output))}
+	result.Head, _ = c.resolveCommit("HEAD")
+	if dir, err := c.gitPath("rebase-merge"); err == nil {
+		result.Onto = readStateFile(filepath.Join(dir, "onto"))
+		result.StoppedAt = readStateFile(filepath.Join(dir, "stopped-sha"))
+		result.Done = countTodoLines(filepath.Join(dir, "done"))
+		result.Remaining = countTodoLines(filepath.Join(dir, "git-rebase-todo"))
+	}
+
+	status, err := c.StatusInfo()
+	if err != nil {
+		return "", err
+	}
+	if conflicted := entryPaths(status.Conflicted); len(conflicted) > 0 {
+		result.Status = "conflict"
+		result.ConflictedFiles = conflicted
+		result.Hint = "resuelve los conflictos con git_conflict resolve y sigue con rebase_continue, o usa rebase_skip / rebase_abort"
+	} else {
+		result.Hint = "haz los cambios necesarios y sigue con rebase_continue, o usa rebase_abort"
+	}
+	return marshalRebaseResult(result)
+}
+
+// rebaseInProgress indica si hay un rebase (interactivo o no) detenido
+func (c *Client) rebaseInProgress() bool {
+	for _, name := range []string{"rebase-merge", "rebase-apply"} {
+		if dir, err := c.gitPath(name); err == nil {
+			if _, err := os.Stat(dir); err == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *Client) removeRebaseState() {
+	if dir, err := c.gitPath(rebaseStateDir); err == nil {
+		os.RemoveAll(dir)
+	}
+}
+
+// gitPath devuelve la ruta absoluta de name dentro del directorio de git
+// (también en worktrees, donde .git es un archivo)
+func (c *Client) gitPath(name string) (string, error) {
+	output, err := c.gitCmd("rev-parse", "--git-path", name).Output()
+	if err != nil {
+		return "", fmt.Errorf("error localizando el directorio de git: %v", gitError(err))
+	}
+	path := strings.TrimSpace(string(output))
+	if path == "" {
+		return "", fmt.Errorf("error localizando el directorio de git: ruta vacía para %s", name)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(c.Config.RepoPath, path)
+	}
+	return path, nil
+}
+
+// entryPaths devuelve las rutas de unas entradas de status
+func entryPaths(entries []types.StatusEntry) []string {
+	var paths []string
+	for _, entry := range entries {
+		paths = append(paths, entry.Path)
+	}
+	return paths
+}
+
+func readStateFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// countTodoLines cuenta las tareas de un archivo de la secuencia de rebase
+func countTodoLines(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			count++
+		}
+	}
+	return count
+}
+
+func marshalRebaseResult(result *RebaseResult) (string, error) {
+	jsonOutput, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error serializando rebase: %v", err)
+	}
+	return string(jsonOutput), nil
+}
+
+// shellQuote protege una ruta para la shell con la que git ejecuta editores
+// y líneas exec; en Windows esa shell es la de Git for Windows, que acepta '/'
+func shellQuote(path string) string {
+	return "'" + strings.ReplaceAll(filepath.ToSlash(path), "'", `'\''`) + "'"
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
@@ -0,0 +1,271 @@
+package git
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+// createRebaseRepo builds four commits on top of the "base" tag: two that
+// edit value.txt in sequence and two that add independent files. It returns
+// their SHAs in order.
+func createRebaseRepo(t *testing.T) (string, *Client, []string) {
+	t.Helper()
+	dir := createTestRepo(t)
+	commitFile(t, dir, "value.txt", "1\n", "value 1")
+	runGit(t, dir, "tag", "base")
+
+	var shas []string
+	for _, c := range []struct{ name, content, msg string }{
+		{"value.txt", "2\n", "value 2"},
+		{"value.txt", "3\n", "value 3"},
+		{"a.txt", "a\n", "add a"},
+		{"b.txt", "b\n", "add b"},
+	} {
+		commitFile(t, dir, c.name, c.content, c.msg)
+		shas = append(shas, runGit(t, dir, "rev-parse", "HEAD"))
+	}
+
+	client := &Client{
+		Config:   &types.GitConfig{HasGit: true, IsGitRepo: true, RepoPath: dir},
+		executor: &realExecutor{},
+	}
+	return dir, client, shas
+}
+
+func decodeRebase(t *testing.T) func(string, error) RebaseResult {
+	return func(output string, err error) RebaseResult {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var result RebaseResult
+		if err := json.Unmarshal([]byte(output), &result); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, output)
+		}
+		return result
+	}
+}
+
+// history returns the full messages of base..HEAD, oldest first
+func history(t *testing.T, dir string) []string {
+	t.Helper()
+	out := runGit(t, dir, "log", "--reverse", "--format=%B%x00", "base..HEAD")
+	var messages []string
+	for _, msg := range strings.Split(out, "\x00") {
+		if msg = strings.TrimSpace(msg); msg != "" {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+func assertNoRebaseState(t *testing.T, dir string) {
+	t.Helper()
+	for _, name := range []string{"rebase-merge", rebaseStateDir} {
+		if _, err := os.Stat(filepath.Join(dir, ".git", name)); !os.IsNotExist(err) {
+			t.Errorf(".git/%s should be gone: %v", name, err)
+		}
+	}
+}
+
+func TestRebaseInteractive_Script(t *testing.T) {
+	dir, client, shas := createRebaseRepo(t)
+
+	result := decodeRebase(t)(client.RebaseInteractive("base", []types.RebaseStep{
+		{Action: "reword", Commit: shas[0][:8], Message: "value: set to 2\n\nExplained 'with quotes'."},
+		{Action: "squash", Commit: shas[1], Message: "value: set to 3"},
+		{Action: "pick", Commit: shas[3]},
+		{Action: "drop", Commit: shas[2]},
+	}))
+	if result.Status != "completed" || result.Head != runGit(t, dir, "rev-parse", "HEAD") {
+		t.Fatalf("result = %+v", result)
+	}
+
+	want := []string{"value: set to 3", "add b"}
+	if got := history(t, dir); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("history = %q, want %q", got, want)
+	}
+	if content, _ := os.ReadFile(filepath.Join(dir, "value.txt")); string(content) != "3\n" {
+		t.Errorf("value.txt = %q", content)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.txt")); !os.IsNotExist(err) {
+		t.Error("dropped commit should not leave a.txt")
+	}
+	assertNoRebaseState(t, dir)
+}
+
+func TestRebaseInteractive_RewordAndPlainSquash(t *testing.T) {
+	dir, client, shas := createRebaseRepo(t)
+
+	decodeRebase(t)(client.RebaseInteractive("base", []types.RebaseStep{
+		{Action: "pick", Commit: shas[0]},
+		{Action: "squash", Commit: shas[1]},
+		{Action: "reword", Commit: shas[2], Message: "docs: add a\n\nWith a body."},
+		{Action: "fixup", Commit: shas[3]},
+	}))
+	got := history(t, dir)
+	if len(got) != 2 || got[0] != "value 2\n\nvalue 3" || got[1] != "docs: add a\n\nWith a body." {
+		t.Errorf("history = %q", got)
+	}
+}
+
+func TestRebaseInteractive_ConflictContinueSkip(t *testing.T) {
+	dir, client, shas := createRebaseRepo(t)
+
+	// value 3 applied before value 2 conflicts, and so does value 2 after it
+	result := decodeRebase(t)(client.RebaseInteractive("base", []types.RebaseStep{
+		{Action: "reword", Commit: shas[1], Message: "value: jump to 3"},
+		{Action: "pick", Commit: shas[0]},
+		{Action: "pick", Commit: shas[2]},
+		{Action: "pick", Commit: shas[3]},
+	}))
+	if result.Status != "conflict" || len(result.ConflictedFiles) != 1 || result.ConflictedFiles[0] != "value.txt" {
+		t.Fatalf("result = %+v", result)
+	}
+	if result.Remaining != 4 || result.Hint == "" {
+		t.Errorf("progress = %+v", result)
+	}
+
+	if _, err := client.RebaseControl("continue"); err == nil || !strings.Contains(err.Error(), "value.txt") {
+		t.Errorf("continue with conflicts should fail: %v", err)
+	}
+	if _, err := client.RebaseInteractive("base", nil); err == nil || !strings.Contains(err.Error(), "en curso") {
+		t.Errorf("a second rebase should be rejected: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "value.txt"), []byte("3\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", "value.txt")
+	result = decodeRebase(t)(client.RebaseControl("continue"))
+	if result.Status != "conflict" {
+		t.Fatalf("picking value 2 on top of 3 should conflict: %+v", result)
+	}
+
+	result = decodeRebase(t)(client.RebaseControl("skip"))
+	if result.Status != "completed" {
+		t.Fatalf("result = %+v", result)
+	}
+	want := []string{"value: jump to 3", "add a", "add b"}
+	if got := history(t, dir); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("history = %q, want %q", got, want)
+	}
+	assertNoRebaseState(t, dir)
+
+	t.Run("Skipped reword keeps the previous message", func(t *testing.T) {
+		runGit(t, dir, "reset", "-q", "--hard", shas[3])
+		result := decodeRebase(t)(client.RebaseInteractive("base", []types.RebaseStep{
+			{Action: "pick", Commit: shas[2]},
+			{Action: "reword", Commit: shas[1], Message: "value: skipped"},
+			{Action: "pick", Commit: shas[0]},
+			{Action: "pick", Commit: shas[3]},
+		}))
+		if result.Status != "conflict" {
+			t.Fatalf("result = %+v", result)
+		}
+		result = decodeRebase(t)(client.RebaseControl("skip"))
+		if result.Status != "completed" {
+			t.Fatalf("skip = %+v", result)
+		}
+		want := []string{"add a", "value 2", "add b"}
+		if got := history(t, dir); strings.Join(got, "|") != strings.Join(want, "|") {
+			t.Errorf("history = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestRebaseInteractive_EditAndAbort(t *testing.T) {
+	dir, client, shas := createRebaseRepo(t)
+	original := runGit(t, dir, "rev-parse", "HEAD")
+
+	steps := []types.RebaseStep{
+		{Action: "pick", Commit: shas[0]},
+		{Action: "pick", Commit: shas[1]},
+		{Action: "edit", Commit: shas[2]},
+		{Action: "pick", Commit: shas[3]},
+	}
+	result := decodeRebase(t)(client.RebaseInteractive("base", steps))
+	if result.Status != "stopped" || result.StoppedAt != shas[2] || result.Remaining != 1 {
+		t.Fatalf("result = %+v", result)
+	}
+
+	result = decodeRebase(t)(client.RebaseControl("abort"))
+	if result.Status != "aborted" || result.Head != original {
+		t.Errorf("abort = %+v, want HEAD back at %s", result, original)
+	}
+	assertNoRebaseState(t, dir)
+
+	decodeRebase(t)(client.RebaseInteractive("base", steps))
+	commitFile(t, dir, "a.txt", "a edited\n", "unused")
+	runGit(t, dir, "reset", "--soft", "HEAD~1")
+	runGit(t, dir, "commit", "--amend", "--no-edit")
+	result = decodeRebase(t)(client.RebaseControl("continue"))
+	if result.Status != "completed" {
+		t.Fatalf("continue after edit = %+v", result)
+	}
+	if content, _ := os.ReadFile(filepath.Join(dir, "a.txt")); string(content) != "a edited\n" {
+		t.Errorf("a.txt = %q", content)
+	}
+}
+
+func TestRebaseInteractive_Errors(t *testing.T) {
+	dir, client, shas := createRebaseRepo(t)
+	all := func(action string) []types.RebaseStep {
+		var steps []types.RebaseStep
+		for _, sha := range shas {
+			steps = append(steps, types.RebaseStep{Action: action, Commit: sha})
+		}
+		return steps
+	}
+
+	tests := []struct {
+		name   string
+		onto   string
+		steps  []types.RebaseStep
+		errMsg string
+	}{
+		{"Empty todo", "base", nil, "vacía"},
+		{"Missing commit", "base", all("pick")[:3], "faltan commits"},
+		{"Duplicate commit", "base", append(all("pick"), types.RebaseStep{Action: "pick", Commit: shas[0]}), "repetido"},
+		{"Commit outside the range", "base", append(all("pick"), types.RebaseStep{Action: "pick", Commit: "base"}), "no está en el rango"},
+		{"Unknown action", "base", append([]types.RebaseStep{{Action: "exec", Commit: shas[0]}}, all("pick")[1:]...), "acción no válida"},
+		{"Squash first", "base", append([]types.RebaseStep{{Action: "drop", Commit: shas[0]}, {Action: "squash", Commit: shas[1]}}, all("pick")[2:]...), "commit anterior"},
+		{"Reword without message", "base", append([]types.RebaseStep{{Action: "reword", Commit: shas[0]}}, all("pick")[1:]...), "necesita un mensaje"},
+		{"Message on pick", "base", append([]types.RebaseStep{{Action: "pick", Commit: shas[0], Message: "x"}}, all("pick")[1:]...), "solo se admite"},
+		{"Option injection in onto", "--exec=sh", all("pick"), "no puede empezar por '-'"},
+		{"Option injection in commit", "base", []types.RebaseStep{{Action: "pick", Commit: "--root"}}, "no puede empezar por '-'"},
+		{"Unknown onto", "nope", all("pick"), "nope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.RebaseInteractive(tt.onto, tt.steps)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("error = %v, should contain %q", err, tt.errMsg)
+			}
+		})
+	}
+
+	t.Run("Merges in range", func(t *testing.T) {
+		runGit(t, dir, "checkout", "-q", "-b", "side", "base")
+		commitFile(t, dir, "side.txt", "side\n", "side")
+		runGit(t, dir, "checkout", "-q", "-")
+		runGit(t, dir, "merge", "-q", "--no-ff", "-m", "merge side", "side")
+		if _, err := client.RebaseInteractive("base", all("pick")); err == nil || !strings.Contains(err.Error(), "merges") {
+			t.Errorf("error = %v, should mention merges", err)
+		}
+	})
+
+	t.Run("Control without a rebase", func(t *testing.T) {
+		if _, err := client.RebaseControl("continue"); err == nil || !strings.Contains(err.Error(), "ningún rebase") {
+			t.Errorf("error = %v", err)
+		}
+		if _, err := client.RebaseControl("restart"); err == nil || !strings.Contains(err.Error(), "no válida") {
+			t.Errorf("error = %v", err)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scopweb/mcp-go-github/pkg/safety"
+)
+
+// secretScanner revisa lo que se va a confirmar o a enviar al remoto; el
+// servidor lo sustituye por el configurado en safety.json
+var secretScanner = safety.NewSecretScanner(safety.SecretScanConfig{})
+
+// SetSecretScanner instala el escáner usado antes de commit y push
+func SetSecretScanner(scanner *safety.SecretScanner) {
+	if scanner != nil {
+		secretScanner = scanner
+	}
+}
+
+// scanStaged revisa el contenido del índice, que es lo que confirmará el
+// commit, no el del directorio de trabajo
+func (c *Client) scanStaged() (string, error) {
+	if secretScanner.Action() == safety.SecretScanOff {
+		return "", nil
+	}
+	output, err := c.gitCmd("diff", "--cached", "--name-only", "-z", "--diff-filter=d").Output()
+	if err != nil {
+		return "", fmt.Errorf("error listando archivos preparados: %v", gitError(err))
+	}
+
+	var findings []safety.SecretFinding
+	for _, path := range strings.Split(string(output), "\x00") {
+		if path == "" {
+			continue
+		}
+		// Los submódulos no tienen blob; no hay contenido que revisar
+		content, err := c.gitCmd("cat-file", "blob", ":"+path).Output()
+		if err != nil {
+			continue
+		}
+		findings = append(findings, secretScanner.Scan(path, content)...)
+	}
+	return secretReport(findings)
+}
+
+// scanPush revisa los archivos que cambian los commits de branch que aún no
+// están en remote. Un secreto que sigue en varios commits se informa una vez,
+// en el primero que lo contiene.
+func (c *Client) scanPush(remote, branch string) (string, error) {
+	if secretScanner.Action() == safety.SecretScanOff {
+		return "", nil
+	}
+	if branch == "" {
+		branch = "HEAD"
+	}
+	if err := checkRef(branch); err != nil {
+		return "", err
+	}
+	output, err := c.gitCmd("rev-list", "--reverse", branch, "--not", "--remotes="+remote).Output()
+	if err != nil {
+		return "", fmt.Errorf("error listando commits a enviar: %v", gitError(err))
+	}
+
+	var findings []safety.SecretFinding
+	seen := make(map[string]bool)
+	for _, sha := range strings.Fields(string(output)) {
+		files, err := c.gitCmd("diff-tree", "-r", "-z", "--root", "--no-commit-id", "--name-only", "--diff-filter=d", sha).Output()
+		if err != nil {
+			return "", fmt.Errorf("error listando archivos del commit %s: %v", shortSHA(sha), gitError(err))
+		}
+		for _, path := range strings.Split(string(files), "\x00") {
+			if path == "" {
+				continue
+			}
+			content, err := c.gitCmd("cat-file", "blob", sha+":"+path).Output()
+			if err != nil {
+				continue
+			}
+			for _, f := range secretScanner.Scan(path, content) {
+				key := f.File + "\x00" + f.Rule + "\x00" + f.Secret
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				f.File = fmt.Sprintf("%s@%s", path, shortSHA(sha))
+				findings = append(findings, f)
+			}
+		}
+	}
+	return secretReport(findings)
+}
+
+// secretReport devuelve un *safety.SecretsFoundError en modo block y, en modo
+// warn, un informe para añadir al resultado
+func secretReport(findings []safety.SecretFinding) (string, error) {
+	if len(findings) == 0 {
+		return "", nil
+	}
+	if secretScanner.Action() == safety.SecretScanBlock {
+		return "", &safety.SecretsFoundError{Findings: findings}
+	}
+	return "\n\n" + safety.FormatSecretReport(findings, false), nil
+}
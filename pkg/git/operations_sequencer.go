@@ -0,0 +1,234 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+// Operaciones del secuenciador de git (cherry-pick y revert) con el nombre
+// de la operación de git que las ejecuta y del archivo que marca la parada
+var sequencerOps = map[string]struct{ command, head string }{
+	"cherry_pick": {"cherry-pick", "CHERRY_PICK_HEAD"},
+	"revert":      {"revert", "REVERT_HEAD"},
+}
+
+// SequencerResult es el estado de un cherry-pick o revert tras ejecutarlo o continuarlo
+type SequencerResult struct {
+	Operation       string   `json:"operation"` // cherry_pick, revert
+	Status          string   `json:"status"`    // completed, conflict, stopped, aborted
+	Head            string   `json:"head"`
+	Commits         []string `json:"commits,omitempty"` // commits creados en esta llamada
+	StoppedAt       string   `json:"stoppedAt,omitempty"`
+	Remaining       int      `json:"remaining,omitempty"`
+	ConflictedFiles []string `json:"conflictedFiles,omitempty"`
+	Output          string   `json:"output,omitempty"`
+	Hint            string   `json:"hint,omitempty"`
+}
+
+// CherryPick aplica un commit o un rango "a..b" sobre la rama actual. Ante
+// un conflicto se detiene; se resuelve con ConflictStatus/ResolveFile y se
+// sigue con CherryPickControl.
+func (c *Client) CherryPick(opts types.CherryPickOptions) (string, error) {
+	var args []string
+	if opts.RecordOrigin {
+		args = append(args, "-x")
+	}
+	return c.runSequencer("cherry_pick", opts.Commits, opts.Mainline, args)
+}
+
+// Revert crea commits que deshacen un commit o un rango "a..b"
+func (c *Client) Revert(opts types.RevertOptions) (string, error) {
+	return c.runSequencer("revert", opts.Commits, opts.Mainline, []string{"--no-edit"})
+}
+
+// CherryPickControl continúa, salta el commit actual o aborta un cherry-pick detenido
+func (c *Client) CherryPickControl(action string) (string, error) {
+	return c.controlSequencer("cherry_pick", action)
+}
+
+// RevertControl continúa, salta el commit actual o aborta un revert detenido
+func (c *Client) RevertControl(action string) (string, error) {
+	return c.controlSequencer("revert", action)
+}
+
+func (c *Client) runSequencer(operation, commits string, mainline int, extra []string) (string, error) {
+	if !c.Config.HasGit || !c.Config.IsGitRepo {
+		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
+	}
+	op := sequencerOps[operation]
+
+	if commits == "" {
+		return "", fmt.Errorf("commits requerido: un SHA o un rango 'a..b'")
+	}
+	if strings.Contains(commits, "...") {
+		return "", fmt.Errorf("rango no válido para %s: usa 'a..b', no 'a...b'", op.command)
+	}
+	refs, err := splitRange(commits)
+	if err != nil {
+		return "", err
+	}
+	for _, ref := range refs {
+		if ref == "" {
+			ref = "HEAD"
+		}
+		if _, err := c.resolveCommit(ref); err != nil {
+			return "", err
+		}
+	}
+	if mainline < 0 {
+		return "", fmt.Errorf("mainline debe ser el número (1, 2...) del padre de referencia del merge")
+	}
+
+	if busy := c.operationInProgress(); busy != "" {
+		return "", fmt.Errorf("hay un %s en curso; termínalo o abórtalo antes de empezar un %s", busy, op.command)
+	}
+	if clean, err := c.ValidateCleanState(); err != nil {
+		return "", fmt.Errorf("error validando estado: %v", err)
+	} else if !clean {
+		return "", fmt.Errorf("el directorio de trabajo debe estar limpio para hacer %s", op.command)
+	}
+
+	args := append([]string{op.command}, extra...)
+	if mainline > 0 {
+		args = append(args, "-m", strconv.Itoa(mainline))
+	}
+	args = append(args, commits)
+
+	before, _ := c.resolveCommit("HEAD")
+	cmd := c.gitCmd(args...)
+	cmd.SetEnv(rebaseEditor)
+	output, runErr := cmd.CombinedOutput()
+	return c.sequencerResult(operation, before, output, runErr)
+}
+
+func (c *Client) controlSequencer(operation, action string) (string, error) {
+	if !c.Config.HasGit || !c.Config.IsGitRepo {
+		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
+	}
+	op := sequencerOps[operation]
+	if action != "continue" && action != "skip" && action != "abort" {
+		return "", fmt.Errorf("acción de %s no válida: %s. Usa: continue, skip, abort", op.command, action)
+	}
+	if c.sequencerInProgress() != operation {
+		return "", fmt.Errorf("no hay ningún %s en curso", op.command)
+	}
+
+	if action == "continue" {
+		status, err := c.StatusInfo()
+		if err != nil {
+			return "", err
+		}
+		if conflicted := entryPaths(status.Conflicted); len(conflicted) > 0 {
+			return "", fmt.Errorf("quedan archivos en conflicto (%s); resuélvelos con git_conflict resolve antes de continuar",
+				strings.Join(conflicted, ", "))
+		}
+	}
+
+	before, _ := c.resolveCommit("HEAD")
+	cmd := c.gitCmd(op.command, "--"+action)
+	cmd.SetEnv(rebaseEditor)
+	output, runErr := cmd.CombinedOutput()
+
+	if action == "abort" {
+		if runErr != nil {
+			return "", fmt.Errorf("error abortando %s: %v, Output: %s", op.command, runErr, output)
+		}
+		head, _ := c.resolveCommit("HEAD")
+		return marshalSequencerResult(&SequencerResult{Operation: operation, Status: "aborted", Head: head})
+	}
+	return c.sequencerResult(operation, before, output, runErr)
+}
+
+// sequencerResult describe el estado tras ejecutar git cherry-pick o revert.
+// Si la operación ya no está en curso, un error es un fallo real; si sigue
+// en curso, git se ha detenido en un conflicto o en un commit que quedó vacío.
+func (c *Client) sequencerResult(operation, before string, output []byte, runErr error) (string, error) {
+	op := sequencerOps[operation]
+	result := &SequencerResult{Operation: operation, Status: "completed"}
+	result.Head, _ = c.resolveCommit("HEAD")
+	if before != "" && result.Head != before {
+		if out, err := c.gitCmd("rev-list", "--reverse", before+".."+result.Head).Output(); err == nil {
+			result.Commits = strings.Fields(string(out))
+		}
+	}
+
+	if c.sequencerInProgress() != operation {
+		if runErr != nil {
+			return "", fmt.Errorf("error en %s: %v, Output: %s", op.command, runErr, output)
+		}
+		return marshalSequencerResult(result)
+	}
+
+	result.Status = "stopped"
+	result.Output = strings.TrimSpace(string(output))
+	if path, err := c.gitPath(op.head); err == nil {
+		result.StoppedAt = readStateFile(path)
+	}
+	if path, err := c.gitPath("sequencer/todo"); err == nil {
+		result.Remaining = countTodoLines(path)
+	}
+
+	status, err := c.StatusInfo()
+	if err != nil {
+		return "", err
+	}
+	if conflicted := entryPaths(status.Conflicted); len(conflicted) > 0 {
+		result.Status = "conflict"
+		result.ConflictedFiles = conflicted
+		result.Hint = fmt.Sprintf("resuelve los conflictos con git_conflict resolve y sigue con %s_continue, o usa %s_skip / %s_abort",
+			operation, operation, operation)
+	} else {
+		result.Hint = fmt.Sprintf("el %s no dejó cambios; usa %s_skip para saltarlo o %s_abort", op.command, operation, operation)
+	}
+	return marshalSequencerResult(result)
+}
+
+// sequencerInProgress devuelve "cherry_pick" o "revert" si hay uno detenido
+func (c *Client) sequencerInProgress() string {
+	for _, operation := range []string{"cherry_pick", "revert"} {
+		if path, err := c.gitPath(sequencerOps[operation].head); err == nil {
+			if _, err := os.Stat(path); err == nil {
+				return operation
+			}
+		}
+	}
+	// En un rango, tras resolver y confirmar a mano, solo queda la lista de tareas
+	if path, err := c.gitPath("sequencer/todo"); err == nil {
+		switch fields := strings.Fields(readStateFile(path)); {
+		case len(fields) > 0 && fields[0] == "pick":
+			return "cherry_pick"
+		case len(fields) > 0 && fields[0] == "revert":
+			return "revert"
+		}
+	}
+	return ""
+}
+
+// operationInProgress nombra la operación que tiene el repositorio a medias
+func (c *Client) operationInProgress() string {
+	if c.rebaseInProgress() {
+		return "rebase"
+	}
+	if path, err := c.gitPath("MERGE_HEAD"); err == nil {
+		if _, err := os.Stat(path); err == nil {
+			return "merge"
+		}
+	}
+	if operation := c.sequencerInProgress(); operation != "" {
+		return sequencerOps[operation].command
+	}
+	return ""
+}
+
+func marshalSequencerResult(result *SequencerResult) (string, error) {
+	jsonOutput, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error serializando %s: %v", result.Operation, err)
+	}
+	return string(jsonOutput), nil
+}
@@ -0,0 +1,245 @@
+package git
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+// createBackportRepo builds three commits on master (one editing value.txt,
+// two adding files) and a "release" branch from before them, which also
+// edits value.txt. HEAD is left on release.
+func createBackportRepo(t *testing.T) (string, *Client, []string) {
+	t.Helper()
+	dir := createTestRepo(t)
+	commitFile(t, dir, "value.txt", "1\n", "value 1")
+	runGit(t, dir, "branch", "release")
+
+	var shas []string
+	for _, c := range []struct{ name, content, msg string }{
+		{"value.txt", "2\n", "value 2"},
+		{"fix.txt", "fix\n", "add fix"},
+		{"other.txt", "other\n", "add other"},
+	} {
+		commitFile(t, dir, c.name, c.content, c.msg)
+		shas = append(shas, runGit(t, dir, "rev-parse", "HEAD"))
+	}
+
+	runGit(t, dir, "checkout", "-q", "release")
+	commitFile(t, dir, "value.txt", "9\n", "release value")
+
+	client := &Client{
+		Config:   &types.GitConfig{HasGit: true, IsGitRepo: true, RepoPath: dir},
+		executor: &realExecutor{},
+	}
+	return dir, client, shas
+}
+
+func decodeSequencer(t *testing.T) func(string, error) SequencerResult {
+	return func(output string, err error) SequencerResult {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var result SequencerResult
+		if err := json.Unmarshal([]byte(output), &result); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, output)
+		}
+		return result
+	}
+}
+
+func decodeConflictStatus(t *testing.T, client *Client) map[string]interface{} {
+	t.Helper()
+	output, err := client.ConflictStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var status map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &status); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, output)
+	}
+	return status
+}
+
+func TestCherryPick(t *testing.T) {
+	dir, client, shas := createBackportRepo(t)
+
+	result := decodeSequencer(t)(client.CherryPick(types.CherryPickOptions{Commits: shas[1][:10], RecordOrigin: true}))
+	if result.Status != "completed" || len(result.Commits) != 1 || result.Commits[0] != result.Head {
+		t.Fatalf("result = %+v", result)
+	}
+	if msg := runGit(t, dir, "log", "-1", "--format=%B"); !strings.Contains(msg, "(cherry picked from commit "+shas[1]+")") {
+		t.Errorf("-x trailer missing: %q", msg)
+	}
+
+	result = decodeSequencer(t)(client.CherryPick(types.CherryPickOptions{Commits: shas[1] + ".." + shas[2]}))
+	if result.Status != "completed" || len(result.Commits) != 1 {
+		t.Fatalf("range result = %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "other.txt")); err != nil {
+		t.Errorf("range should apply add other: %v", err)
+	}
+
+	t.Run("Already applied commit stops empty", func(t *testing.T) {
+		result := decodeSequencer(t)(client.CherryPick(types.CherryPickOptions{Commits: shas[1]}))
+		if result.Status != "stopped" || result.StoppedAt != shas[1] || len(result.Commits) != 0 {
+			t.Fatalf("result = %+v", result)
+		}
+		result = decodeSequencer(t)(client.CherryPickControl("skip"))
+		if result.Status != "completed" {
+			t.Errorf("skip = %+v", result)
+		}
+	})
+}
+
+func TestCherryPick_ConflictFlow(t *testing.T) {
+	dir, client, shas := createBackportRepo(t)
+	start := runGit(t, dir, "rev-parse", "HEAD")
+
+	result := decodeSequencer(t)(client.CherryPick(types.CherryPickOptions{Commits: "master~3..master"}))
+	if result.Status != "conflict" || result.StoppedAt != shas[0] || result.Remaining != 3 {
+		t.Fatalf("result = %+v", result)
+	}
+	if len(result.ConflictedFiles) != 1 || result.ConflictedFiles[0] != "value.txt" {
+		t.Errorf("conflicted = %v", result.ConflictedFiles)
+	}
+
+	status := decodeConflictStatus(t, client)
+	if status["inCherryPickState"] != true || status["inRevertState"] != false || status["hasConflicts"] != true {
+		t.Errorf("ConflictStatus = %v", status)
+	}
+
+	if _, err := client.CherryPickControl("continue"); err == nil || !strings.Contains(err.Error(), "value.txt") {
+		t.Errorf("continue with conflicts should fail: %v", err)
+	}
+	if _, err := client.RevertControl("continue"); err == nil || !strings.Contains(err.Error(), "ningún revert") {
+		t.Errorf("revert control during a cherry-pick should fail: %v", err)
+	}
+	if _, err := client.Revert(types.RevertOptions{Commits: shas[2]}); err == nil || !strings.Contains(err.Error(), "cherry-pick en curso") {
+		t.Errorf("starting a revert during a cherry-pick should fail: %v", err)
+	}
+
+	if _, err := client.ResolveFile("value.txt", "theirs", nil); err != nil {
+		t.Fatal(err)
+	}
+	result = decodeSequencer(t)(client.CherryPickControl("continue"))
+	if result.Status != "completed" || len(result.Commits) != 3 {
+		t.Fatalf("continue = %+v", result)
+	}
+	if content, _ := os.ReadFile(filepath.Join(dir, "value.txt")); string(content) != "2\n" {
+		t.Errorf("value.txt = %q", content)
+	}
+	if subjects := runGit(t, dir, "log", "--format=%s", start+"..HEAD"); subjects != "add other\nadd fix\nvalue 2" {
+		t.Errorf("backported commits = %q", subjects)
+	}
+	if status := decodeConflictStatus(t, client); status["inCherryPickState"] != false {
+		t.Errorf("cherry-pick should be finished: %v", status)
+	}
+
+	t.Run("Abort restores HEAD", func(t *testing.T) {
+		runGit(t, dir, "reset", "-q", "--hard", start)
+		decodeSequencer(t)(client.CherryPick(types.CherryPickOptions{Commits: shas[0]}))
+		result := decodeSequencer(t)(client.CherryPickControl("abort"))
+		if result.Status != "aborted" || result.Head != start {
+			t.Errorf("abort = %+v, want HEAD at %s", result, start)
+		}
+	})
+}
+
+func TestRevert(t *testing.T) {
+	dir, client, shas := createBackportRepo(t)
+	runGit(t, dir, "checkout", "-q", "master")
+
+	result := decodeSequencer(t)(client.Revert(types.RevertOptions{Commits: shas[2]}))
+	if result.Status != "completed" || len(result.Commits) != 1 {
+		t.Fatalf("result = %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "other.txt")); !os.IsNotExist(err) {
+		t.Error("revert should remove other.txt")
+	}
+	if subject := runGit(t, dir, "log", "-1", "--format=%s"); subject != `Revert "add other"` {
+		t.Errorf("subject = %q", subject)
+	}
+
+	t.Run("Merge needs a mainline", func(t *testing.T) {
+		runGit(t, dir, "checkout", "-q", "-b", "feature")
+		commitFile(t, dir, "feature.txt", "feature\n", "add feature")
+		runGit(t, dir, "checkout", "-q", "master")
+		runGit(t, dir, "merge", "-q", "--no-ff", "-m", "merge feature", "feature")
+		if _, err := client.Revert(types.RevertOptions{Commits: "HEAD"}); err == nil || !strings.Contains(err.Error(), "-m") {
+			t.Errorf("revert of a merge without mainline should fail: %v", err)
+		}
+		result := decodeSequencer(t)(client.Revert(types.RevertOptions{Commits: "HEAD", Mainline: 1}))
+		if result.Status != "completed" {
+			t.Errorf("result = %+v", result)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "feature.txt")); !os.IsNotExist(err) {
+			t.Error("reverting the merge should remove feature.txt")
+		}
+	})
+
+	t.Run("Conflict aborted through git_conflict", func(t *testing.T) {
+		commitFile(t, dir, "value.txt", "3\n", "value 3")
+		head := runGit(t, dir, "rev-parse", "HEAD")
+		result := decodeSequencer(t)(client.Revert(types.RevertOptions{Commits: shas[0]}))
+		if result.Status != "conflict" {
+			t.Fatalf("result = %+v", result)
+		}
+		if status := decodeConflictStatus(t, client); status["inRevertState"] != true {
+			t.Errorf("ConflictStatus = %v", status)
+		}
+		if _, err := client.ResolveConflicts("abort"); err != nil {
+			t.Fatal(err)
+		}
+		if client.sequencerInProgress() != "" || runGit(t, dir, "rev-parse", "HEAD") != head {
+			t.Error("revert should be aborted")
+		}
+	})
+}
+
+func TestCherryPick_Errors(t *testing.T) {
+	dir, client, shas := createBackportRepo(t)
+
+	tests := []struct {
+		name   string
+		opts   types.CherryPickOptions
+		errMsg string
+	}{
+		{"Missing commits", types.CherryPickOptions{}, "commits requerido"},
+		{"Symmetric range", types.CherryPickOptions{Commits: "master...release"}, "a...b"},
+		{"Option injection", types.CherryPickOptions{Commits: "--exec=sh"}, "no puede empezar por '-'"},
+		{"Unknown ref", types.CherryPickOptions{Commits: "nope..master"}, "nope"},
+		{"Negative mainline", types.CherryPickOptions{Commits: shas[1], Mainline: -1}, "mainline"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.CherryPick(tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("error = %v, should contain %q", err, tt.errMsg)
+			}
+		})
+	}
+
+	t.Run("Dirty tree", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(dir, "value.txt"), []byte("dirty\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.CherryPick(types.CherryPickOptions{Commits: shas[1]}); err == nil || !strings.Contains(err.Error(), "limpio") {
+			t.Errorf("error = %v", err)
+		}
+	})
+
+	t.Run("Control without an operation", func(t *testing.T) {
+		if _, err := client.CherryPickControl("abort"); err == nil || !strings.Contains(err.Error(), "ningún cherry-pick") {
+			t.Errorf("error = %v", err)
+		}
+		if _, err := client.RevertControl("redo"); err == nil || !strings.Contains(err.Error(), "no válida") {
+			t.Errorf("error = %v", err)
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package git
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+// maxShowFileSize limita el contenido devuelto al leer un archivo en una revisión
+const maxShowFileSize = 256 * 1024
+
+// CommitShow es un commit con su parche paginado
+type CommitShow struct {
+	Commit CommitInfo `json:"commit"`
+	Patch  *DiffPage  `json:"patch"`
+}
+
+// FileAtRevision es el contenido de un archivo en una revisión
+type FileAtRevision struct {
+	Ref       string `json:"ref"`
+	Path      string `json:"path"`
+	Size      int    `json:"size"`
+	Binary    bool   `json:"binary,omitempty"`
+	Content   string `json:"content,omitempty"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// Show devuelve los metadatos y el parche de un commit. Con "ref:ruta"
+// devuelve el contenido de ese archivo en la revisión indicada.
+func (c *Client) Show(opts types.ShowOptions) (string, error) {
+	if !c.Config.HasGit || !c.Config.IsGitRepo {
+		return "", fmt.Errorf("git no disponible o no es un repositorio Git")
+	}
+
+	var result interface{}
+	var err error
+	if ref, path, ok := strings.Cut(opts.Ref, ":"); ok {
+		result, err = c.showFile(ref, path)
+	} else {
+		result, err = c.showCommit(opts)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	jsonOutput, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error serializando show: %v", err)
+	}
+	return string(jsonOutput), nil
+}
+
+// showCommit obtiene los metadatos como en LogQuery y el parche respecto al
+// primer padre (los merges no se muestran como diff combinado)
+func (c *Client) showCommit(opts types.ShowOptions) (*CommitShow, error) {
+	ref := opts.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if err := checkRef(ref); err != nil {
+		return nil, err
+	}
+	format, err := patchFormatArgs(opts.Context, opts.WordDiff)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPageSize(opts.PageSize); err != nil {
+		return nil, err
+	}
+
+	output, err := c.gitCmd("rev-parse", "--verify", "--quiet", ref+"^{commit}").Output()
+	if err != nil {
+		return nil, fmt.Errorf("ref desconocida: %s", ref)
+	}
+	sha := strings.TrimSpace(string(output))
+
+	output, err = c.gitCmd("-c", "core.quotePath=false", "show", "-z", "--no-color", "--numstat",
+		"--diff-merges=first-parent", "--format="+logRecordSep+logFormat, sha, "--").Output()
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo commit %s: %v", ref, gitError(err))
+	}
+	commits, err := parseLog(string(output))
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) != 1 {
+		return nil, fmt.Errorf("commit no encontrado: %s", ref)
+	}
+
+	args := []string{"-c", "core.quotePath=false", "show", "--format=", "--patch", "--no-color",
+		"--no-ext-diff", "--no-textconv", "--diff-merges=first-parent"}
+	args = append(args, format...)
+	args = append(args, sha, "--")
+	for _, path := range opts.Paths {
+		if path != "" {
+			args = append(args, path)
+		}
+	}
+	patch, err := c.gitCmd(args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo parche de %s: %v", ref, gitError(err))
+	}
+
+	page, err := pagePatch(patch, opts.Cursor, opts.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	page.Range = sha
+	page.Paths = opts.Paths
+	return &CommitShow{Commit: commits[0], Patch: page}, nil
+}
+
+// showFile lee un archivo en una revisión. Con ref vacía (":ruta") git lo
+// lee del índice. Los binarios solo informan del tamaño.
+func (c *Client) showFile(ref, path string) (*FileAtRevision, error) {
+	if ref != "" {
+		if err := checkRef(ref); err != nil {
+			return nil, err
+		}
+	}
+	if path == "" {
+		return nil, fmt.Errorf("ruta vacía en '%s:'", ref)
+	}
+	spec := ref + ":" + path
+
+	output, err := c.gitCmd("cat-file", "-t", spec).Output()
+	if err != nil {
+		return nil, fmt.Errorf("no existe %s en %s", path, refLabel(ref))
+	}
+	if objectType := strings.TrimSpace(string(output)); objectType != "blob" {
+		return nil, fmt.Errorf("%s no es un archivo en %s (%s)", path, refLabel(ref), objectType)
+	}
+
+	data, err := c.gitCmd("cat-file", "blob", spec).Output()
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo %s: %v", spec, gitError(err))
+	}
+
+	file := &FileAtRevision{Ref: ref, Path: path, Size: len(data)}
+	probe := data
+	if len(probe) > 8000 {
+		probe = probe[:8000]
+	}
+	// Misma heurística que git: un NUL al principio indica binario
+	if bytes.IndexByte(probe, 0) >= 0 {
+		file.Binary = true
+		return file, nil
+	}
+	file.Content = string(data)
+	if len(data) > maxShowFileSize {
+		file.Content = truncateUTF8(file.Content, maxShowFileSize)
+		file.Truncated = true
+	}
+	return file, nil
+}
+
+// refLabel nombra la revisión en los mensajes; la ref vacía es el índice
+func refLabel(ref string) string {
+	if ref == "" {
+		return "el índice"
+	}
+	return ref
+}
@@ -0,0 +1,169 @@
+package git
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+func decodeShow(t *testing.T) func(string, error) CommitShow {
+	return func(output string, err error) CommitShow {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var show CommitShow
+		if err := json.Unmarshal([]byte(output), &show); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, output)
+		}
+		return show
+	}
+}
+
+func decodeFileAtRevision(t *testing.T) func(string, error) FileAtRevision {
+	return func(output string, err error) FileAtRevision {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var file FileAtRevision
+		if err := json.Unmarshal([]byte(output), &file); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, output)
+		}
+		return file
+	}
+}
+
+func TestShow_Commit(t *testing.T) {
+	_, client := createLogRepo(t)
+
+	show := decodeShow(t)(client.Show(types.ShowOptions{Ref: "HEAD~1", Context: -1}))
+	if show.Commit.Subject != "fix: main commit" || len(show.Commit.Files) != 1 || show.Commit.Files[0].Path != "main.txt" {
+		t.Errorf("commit = %+v", show.Commit)
+	}
+	if show.Patch == nil || len(show.Patch.Files) != 1 || show.Patch.Range != show.Commit.SHA {
+		t.Fatalf("patch = %+v", show.Patch)
+	}
+	if f := show.Patch.Files[0]; f.Status != "added" || !strings.Contains(f.Hunks[0], "+regex 42") {
+		t.Errorf("patch file = %+v", f)
+	}
+
+	show = decodeShow(t)(client.Show(types.ShowOptions{Ref: "HEAD^2~1", Context: -1, Paths: []string{"image.bin"}}))
+	if show.Commit.Author.Name != "Alice" || len(show.Commit.Trailers) != 2 {
+		t.Errorf("commit = %+v", show.Commit)
+	}
+	if len(show.Patch.Files) != 1 || !show.Patch.Files[0].Binary {
+		t.Errorf("path-limited patch = %+v", show.Patch)
+	}
+
+	t.Run("Merge shows the diff against the first parent", func(t *testing.T) {
+		show := decodeShow(t)(client.Show(types.ShowOptions{Context: -1}))
+		if len(show.Commit.Parents) != 2 {
+			t.Fatalf("HEAD should be the merge: %+v", show.Commit)
+		}
+		if len(show.Patch.Files) != 1 || show.Patch.Files[0].Path != "side.txt" {
+			t.Errorf("merge patch = %+v", show.Patch)
+		}
+		if len(show.Commit.Files) != 1 || show.Commit.Files[0].Path != "side.txt" {
+			t.Errorf("merge files = %+v", show.Commit.Files)
+		}
+	})
+
+	t.Run("Root commit", func(t *testing.T) {
+		show := decodeShow(t)(client.Show(types.ShowOptions{Ref: "start", Context: -1}))
+		if len(show.Commit.Parents) != 0 || len(show.Patch.Files) != 1 || show.Patch.Files[0].Status != "added" {
+			t.Errorf("root commit = %+v, patch = %+v", show.Commit, show.Patch)
+		}
+	})
+}
+
+func TestShow_Pagination(t *testing.T) {
+	_, client := createDiffRepo(t)
+	opts := types.ShowOptions{Paths: []string{"big.txt"}, Context: 20, PageSize: minDiffPageSize}
+
+	var hunks []string
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatal("pagination does not terminate")
+		}
+		show := decodeShow(t)(client.Show(opts))
+		if show.Commit.Subject != "change things" {
+			t.Errorf("every page should carry the commit: %+v", show.Commit)
+		}
+		for _, f := range show.Patch.Files {
+			hunks = append(hunks, f.Hunks...)
+		}
+		if show.Patch.NextCursor == "" {
+			break
+		}
+		opts.Cursor = show.Patch.NextCursor
+	}
+	if len(hunks) != 4 {
+		t.Errorf("got %d hunks, want 4", len(hunks))
+	}
+}
+
+func TestShow_FileAtRevision(t *testing.T) {
+	dir, client := createLogRepo(t)
+
+	file := decodeFileAtRevision(t)(client.Show(types.ShowOptions{Ref: "HEAD~1:main.txt"}))
+	if file.Ref != "HEAD~1" || file.Path != "main.txt" || file.Content != "regex 42\n" || file.Size != 9 {
+		t.Errorf("file = %+v", file)
+	}
+
+	file = decodeFileAtRevision(t)(client.Show(types.ShowOptions{Ref: "HEAD:new name.txt"}))
+	if file.Content != "token alpha\n" {
+		t.Errorf("path with spaces = %+v", file)
+	}
+
+	file = decodeFileAtRevision(t)(client.Show(types.ShowOptions{Ref: "HEAD:image.bin"}))
+	if !file.Binary || file.Content != "" || file.Size != 5 {
+		t.Errorf("binary = %+v", file)
+	}
+
+	commitFile(t, dir, "large.txt", strings.Repeat("ñ", maxShowFileSize), "large file")
+	file = decodeFileAtRevision(t)(client.Show(types.ShowOptions{Ref: "HEAD:large.txt"}))
+	if !file.Truncated || len(file.Content) > maxShowFileSize || file.Size != 2*maxShowFileSize {
+		t.Errorf("large file: truncated=%v len=%d size=%d", file.Truncated, len(file.Content), file.Size)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "main.txt"), []byte("staged\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", "main.txt")
+	file = decodeFileAtRevision(t)(client.Show(types.ShowOptions{Ref: ":main.txt"}))
+	if file.Content != "staged\n" {
+		t.Errorf("index version = %+v", file)
+	}
+}
+
+func TestShow_Errors(t *testing.T) {
+	_, client := createLogRepo(t)
+
+	tests := []struct {
+		name   string
+		opts   types.ShowOptions
+		errMsg string
+	}{
+		{"Option injection", types.ShowOptions{Ref: "--output=/tmp/x", Context: -1}, "no puede empezar por '-'"},
+		{"Injection before path", types.ShowOptions{Ref: "--output=/tmp/x:main.txt"}, "no puede empezar por '-'"},
+		{"Unknown commit", types.ShowOptions{Ref: "nope", Context: -1}, "ref desconocida: nope"},
+		{"Missing file", types.ShowOptions{Ref: "HEAD:nope.txt"}, "no existe nope.txt"},
+		{"Directory", types.ShowOptions{Ref: "HEAD:./"}, "no es un archivo"},
+		{"Empty path", types.ShowOptions{Ref: "HEAD:"}, "ruta vacía"},
+		{"Bad cursor", types.ShowOptions{Cursor: "not-a-cursor", Context: -1}, "cursor inválido"},
+		{"Page too small", types.ShowOptions{PageSize: 10, Context: -1}, "page_size"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Show(tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("error = %v, should contain %q", err, tt.errMsg)
+			}
+		})
+	}
+}
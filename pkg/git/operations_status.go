@@ -0,0 +1,149 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+// StatusInfo devuelve el estado estructurado del repositorio a partir de
+// git status --porcelain=v2 --branch -z
+func (c *Client) StatusInfo() (*types.RepoStatus, error) {
+	if !c.Config.HasGit || !c.Config.IsGitRepo {
+		return nil, fmt.Errorf("git no disponible o no es un repositorio Git")
+	}
+
+	output, err := c.gitCmd("status", "--porcelain=v2", "--branch", "-z").Output()
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo status: %v", err)
+	}
+	return parseStatusV2(output)
+}
+
+// parseStatusV2 interpreta la salida de git status --porcelain=v2 --branch -z.
+// Con -z cada registro termina en NUL y las rutas no van entre comillas; el
+// origen de un renombrado (registro "2") es el registro siguiente.
+func parseStatusV2(output []byte) (*types.RepoStatus, error) {
+	status := &types.RepoStatus{
+		Staged:     []types.StatusEntry{},
+		Unstaged:   []types.StatusEntry{},
+		Untracked:  []string{},
+		Conflicted: []types.StatusEntry{},
+	}
+
+	records := bytes.Split(output, []byte{0})
+	for i := 0; i < len(records); i++ {
+		record := string(records[i])
+		if record == "" {
+			continue
+		}
+
+		switch record[0] {
+		case '#':
+			if err := parseBranchHeader(status, record); err != nil {
+				return nil, err
+			}
+		case '1':
+			// 1 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <path>
+			fields := strings.SplitN(record, " ", 9)
+			if len(fields) != 9 {
+				return nil, fmt.Errorf("registro de status inválido: %q", record)
+			}
+			addChange(status, newStatusEntry(fields[1], fields[2], fields[8]))
+		case '2':
+			// 2 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <X><score> <path>, origen en el siguiente registro
+			fields := strings.SplitN(record, " ", 10)
+			if len(fields) != 10 || i+1 >= len(records) {
+				return nil, fmt.Errorf("registro de status inválido: %q", record)
+			}
+			entry := newStatusEntry(fields[1], fields[2], fields[9])
+			entry.Score = fields[8]
+			i++
+			entry.OrigPath = string(records[i])
+			addChange(status, entry)
+		case 'u':
+			// u <XY> <sub> <m1> <m2> <m3> <mW> <h1> <h2> <h3> <path>
+			fields := strings.SplitN(record, " ", 11)
+			if len(fields) != 11 {
+				return nil, fmt.Errorf("registro de status inválido: %q", record)
+			}
+			status.Conflicted = append(status.Conflicted, newStatusEntry(fields[1], fields[2], fields[10]))
+		case '?':
+			status.Untracked = append(status.Untracked, strings.TrimPrefix(record, "? "))
+		case '!':
+			// Ignorados: solo aparecen con --ignored
+		default:
+			return nil, fmt.Errorf("registro de status desconocido: %q", record)
+		}
+	}
+
+	status.Clean = len(status.Staged) == 0 && len(status.Unstaged) == 0 &&
+		len(status.Untracked) == 0 && len(status.Conflicted) == 0
+	return status, nil
+}
+
+// parseBranchHeader interpreta las cabeceras "# branch.*"
+func parseBranchHeader(status *types.RepoStatus, record string) error {
+	fields := strings.Fields(record)
+	if len(fields) < 3 {
+		return nil
+	}
+	switch fields[1] {
+	case "branch.oid":
+		if fields[2] != "(initial)" {
+			status.Commit = fields[2]
+		}
+	case "branch.head":
+		if fields[2] == "(detached)" {
+			status.Detached = true
+		} else {
+			status.Branch = fields[2]
+		}
+	case "branch.upstream":
+		status.Upstream = fields[2]
+	case "branch.ab":
+		if len(fields) != 4 {
+			return fmt.Errorf("cabecera de status inválida: %q", record)
+		}
+		ahead, err := strconv.Atoi(strings.TrimPrefix(fields[2], "+"))
+		if err != nil {
+			return fmt.Errorf("cabecera de status inválida: %q", record)
+		}
+		behind, err := strconv.Atoi(strings.TrimPrefix(fields[3], "-"))
+		if err != nil {
+			return fmt.Errorf("cabecera de status inválida: %q", record)
+		}
+		status.Ahead, status.Behind = ahead, behind
+	}
+	return nil
+}
+
+// newStatusEntry crea una entrada a partir de los campos XY y <sub>
+func newStatusEntry(xy, sub, path string) types.StatusEntry {
+	entry := types.StatusEntry{Path: path}
+	if len(xy) == 2 {
+		entry.Index, entry.WorkTree = xy[:1], xy[1:]
+	}
+	// <sub> es "N..." para archivos normales o "S<c><m><u>" para submódulos
+	if len(sub) == 4 && sub[0] == 'S' {
+		entry.Submodule = &types.SubmoduleStatus{
+			CommitChanged:    sub[1] == 'C',
+			TrackedChanges:   sub[2] == 'M',
+			UntrackedChanges: sub[3] == 'U',
+		}
+	}
+	return entry
+}
+
+// addChange clasifica una entrada como staged, unstaged o ambas
+func addChange(status *types.RepoStatus, entry types.StatusEntry) {
+	if entry.Index != "." {
+		status.Staged = append(status.Staged, entry)
+	}
+	if entry.WorkTree != "." {
+		status.Unstaged = append(status.Unstaged, entry)
+	}
+}
@@ -0,0 +1,130 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+func TestParseStatusV2(t *testing.T) {
+	output := "# branch.oid 1234567890abcdef\x00" +
+		"# branch.head main\x00" +
+		"# branch.upstream origin/main\x00" +
+		"# branch.ab +3 -2\x00" +
+		"1 M. N... 100644 100644 100644 aaa bbb staged file.go\x00" +
+		"1 MM N... 100644 100644 100644 aaa bbb both.go\x00" +
+		"1 .D N... 100644 100644 000000 aaa aaa deleted.go\x00" +
+		"2 R. N... 100644 100644 100644 aaa aaa R100 new name.go\x00old name.go\x00" +
+		"1 .M SC.U 160000 160000 160000 aaa aaa vendor/lib\x00" +
+		"u UU N... 100644 100644 100644 100644 aaa bbb ccc conflict.go\x00" +
+		"? notes with spaces.txt\x00"
+
+	status, err := parseStatusV2([]byte(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status.Branch != "main" || status.Upstream != "origin/main" || status.Ahead != 3 || status.Behind != 2 {
+		t.Errorf("branch info = %q %q +%d -%d", status.Branch, status.Upstream, status.Ahead, status.Behind)
+	}
+	if status.Commit != "1234567890abcdef" || status.Detached || status.Clean {
+		t.Errorf("commit = %q, detached = %v, clean = %v", status.Commit, status.Detached, status.Clean)
+	}
+
+	staged := map[string]string{}
+	for _, e := range status.Staged {
+		staged[e.Path] = e.Index
+	}
+	if len(staged) != 3 || staged["staged file.go"] != "M" || staged["both.go"] != "M" || staged["new name.go"] != "R" {
+		t.Errorf("staged = %+v", status.Staged)
+	}
+	unstaged := map[string]string{}
+	for _, e := range status.Unstaged {
+		unstaged[e.Path] = e.WorkTree
+	}
+	if len(unstaged) != 3 || unstaged["both.go"] != "M" || unstaged["deleted.go"] != "D" || unstaged["vendor/lib"] != "M" {
+		t.Errorf("unstaged = %+v", status.Unstaged)
+	}
+
+	for _, e := range status.Staged {
+		if e.Path == "new name.go" && (e.OrigPath != "old name.go" || e.Score != "R100") {
+			t.Errorf("rename = %+v, want origPath 'old name.go' and score R100", e)
+		}
+	}
+	for _, e := range status.Unstaged {
+		if e.Path != "vendor/lib" {
+			continue
+		}
+		if e.Submodule == nil || !e.Submodule.CommitChanged || e.Submodule.TrackedChanges || !e.Submodule.UntrackedChanges {
+			t.Errorf("submodule = %+v", e.Submodule)
+		}
+	}
+
+	if len(status.Conflicted) != 1 || status.Conflicted[0].Path != "conflict.go" || status.Conflicted[0].Index != "U" {
+		t.Errorf("conflicted = %+v", status.Conflicted)
+	}
+	if len(status.Untracked) != 1 || status.Untracked[0] != "notes with spaces.txt" {
+		t.Errorf("untracked = %+v", status.Untracked)
+	}
+}
+
+func TestParseStatusV2_EdgeCases(t *testing.T) {
+	status, err := parseStatusV2([]byte("# branch.oid (initial)\x00# branch.head (detached)\x00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Detached || status.Branch != "" || status.Commit != "" || !status.Clean {
+		t.Errorf("status = %+v", status)
+	}
+
+	for _, bad := range []string{
+		"1 M. N... truncated\x00",
+		"2 R. N... 100644 100644 100644 aaa aaa R100 missing-orig",
+		"# branch.ab +x -1\x00",
+		"X unknown\x00",
+	} {
+		if _, err := parseStatusV2([]byte(bad)); err == nil {
+			t.Errorf("parseStatusV2(%q) should fail", bad)
+		}
+	}
+}
+
+func TestStatusInfo_RealRepo(t *testing.T) {
+	dir := createTestRepo(t)
+	client := &Client{
+		Config:   &types.GitConfig{HasGit: true, IsGitRepo: true, RepoPath: dir},
+		executor: &realExecutor{},
+	}
+
+	status, err := client.StatusInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Clean || status.Commit == "" {
+		t.Errorf("fresh repo should be clean with a commit: %+v", status)
+	}
+	if clean, err := client.ValidateCleanState(); err != nil || !clean {
+		t.Errorf("ValidateCleanState() = %v, %v", clean, err)
+	}
+
+	runGit(t, dir, "mv", "README.md", "RENAMED.md")
+	if err := os.WriteFile(filepath.Join(dir, "untracked.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err = client.StatusInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Staged) != 1 || status.Staged[0].Path != "RENAMED.md" || status.Staged[0].OrigPath != "README.md" {
+		t.Errorf("staged = %+v, want README.md renamed to RENAMED.md", status.Staged)
+	}
+	if len(status.Untracked) != 1 || status.Untracked[0] != "untracked.txt" {
+		t.Errorf("untracked = %+v", status.Untracked)
+	}
+	if clean, err := client.ValidateCleanState(); err != nil || clean {
+		t.Errorf("ValidateCleanState() = %v, %v, want dirty", clean, err)
+	}
+}
@@ -1,14 +1,19 @@
 package git
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/scopweb/mcp-go-github/pkg/safety"
 	"github.com/scopweb/mcp-go-github/pkg/types"
 )
 
@@ -30,6 +35,10 @@ func (c *mockCmd) SetDir(_ string) {
 	// No-op for mock. This method is required to satisfy the cmdWrapper interface.
 }
 
+func (c *mockCmd) SetEnv(_ ...string) {
+	// No-op for mock. This method is required to satisfy the cmdWrapper interface.
+}
+
 // mockExecutor is a mock for the executor interface
 type mockExecutor struct {
 	t *testing.T
@@ -39,7 +48,7 @@ type mockExecutor struct {
 	mockErrors  map[string]error
 }
 
-func (e *mockExecutor) Command(name string, arg ...string) cmdWrapper {
+func (e *mockExecutor) CommandContext(_ context.Context, name string, arg ...string) cmdWrapper {
 	cmdStr := strings.TrimSpace(name + " " + strings.Join(arg, " "))
 
 	mockOutput, okOutput := e.mockOutputs[cmdStr]
@@ -127,11 +136,11 @@ func createTestRepo(t *testing.T) string {
 // the mockExecutor. It prints the desired output to stdout/stderr and exits.
 func TestHelperProcess(_ *testing.T) {
 	for cmdStr := range map[string]string{
-		"git status --porcelain":         " M modified_file.go",
-		"git log --oneline -5":           "abcde123 Fix stuff",
-		"git remote":                     "origin\nother-remote",
-		"git push origin feature-branch": "Everything up-to-date",
-		"git push origin main":           "Everything up-to-date",
+		"git status --porcelain=v2 --branch -z": "1 .M N... 100644 100644 100644 abc abc modified_file.go\x00",
+		"git log --oneline -5":                  "abcde123 Fix stuff",
+		"git remote":                            "origin\nother-remote",
+		"git push origin feature-branch":        "Everything up-to-date",
+		"git push origin main":                  "Everything up-to-date",
 	} {
 		cmd := exec.Command("sh", "-c", "echo -n 'mock output'")
 		cmd.Env = append(os.Environ(), "GIT_TEST_CMD="+cmdStr)
@@ -151,8 +160,9 @@ func TestStatus(t *testing.T) {
 		}
 
 		mockOutputs := map[string]string{
-			"git status --porcelain": " M modified_file.go",
-			"git log --oneline -5":   "abcde123 Fix stuff",
+			"git status --porcelain=v2 --branch -z": "# branch.oid abc\x00# branch.head main\x00" +
+				"1 .M N... 100644 100644 100644 abc abc modified_file.go\x00",
+			"git log --oneline -5": "abcde123 Fix stuff",
 		}
 
 		client := newTestClient(t, config, mockOutputs, nil)
@@ -162,7 +172,7 @@ func TestStatus(t *testing.T) {
 			t.Fatalf("Expected no error, but got: %v", err)
 		}
 
-		if !strings.Contains(status, `"status": "M modified_file.go"`) {
+		if !strings.Contains(status, `"workTree": "M"`) || !strings.Contains(status, `"path": "modified_file.go"`) {
 			t.Errorf("Expected status to contain modified file, but it didn't. Got:\n%s", status)
 		}
 		if !strings.Contains(status, `"recentCommits": "abcde123 Fix stuff"`) {
@@ -443,7 +453,7 @@ func TestCheckout(t *testing.T) {
 	t.Run("Successful checkout to a new branch", func(t *testing.T) {
 		branch := "new-feature-branch"
 		mockOutputs := map[string]string{
-			"git status --porcelain":                  "",
+			"git status --porcelain=v2 --branch -z":   "",
 			fmt.Sprintf("git checkout -b %s", branch): fmt.Sprintf("Switched to a new branch '%s'", branch),
 		}
 		client := newTestClient(t, config, mockOutputs, nil)
@@ -472,8 +482,8 @@ func TestCheckout(t *testing.T) {
 
 		mockOutputs := map[string]string{
 			fmt.Sprintf("git show-ref --verify --quiet refs/heads/%s", existingBranch): "",
-			"git status --porcelain":                       "",
-			fmt.Sprintf("git checkout %s", existingBranch): fmt.Sprintf("Switched to branch '%s'", existingBranch),
+			"git status --porcelain=v2 --branch -z":                                    "",
+			fmt.Sprintf("git checkout %s", existingBranch):                             fmt.Sprintf("Switched to branch '%s'", existingBranch),
 		}
 		client := newTestClient(t, config, mockOutputs, nil)
 
@@ -565,6 +575,72 @@ func TestCreateFile(t *testing.T) {
 			t.Errorf("Expected file content '%s', but got '%s'", content, string(fileContent))
 		}
 	})
+	t.Run("Path escaping the repository", func(t *testing.T) {
+		outside := t.TempDir()
+		if err := os.Symlink(outside, filepath.Join(repoPath, "link")); err != nil {
+			t.Skipf("symlinks unavailable: %v", err)
+		}
+
+		for _, filePath := range []string{"../escape.txt", "link/escape.txt", "/tmp/escape.txt"} {
+			if _, err := client.CreateFile(filePath, "x"); err == nil {
+				t.Errorf("CreateFile(%q) should fail", filePath)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(outside, "escape.txt")); !os.IsNotExist(err) {
+			t.Errorf("file written through the symlink: %v", err)
+		}
+	})
+}
+
+func TestResolveFile_PathEscape(t *testing.T) {
+	repoPath := createTestRepo(t)
+	client := &Client{
+		Config:   &types.GitConfig{HasGit: true, IsGitRepo: true, RepoPath: repoPath},
+		executor: &realExecutor{},
+	}
+
+	outside := t.TempDir()
+	target := filepath.Join(outside, "target.txt")
+	if err := os.WriteFile(target, []byte("original"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(repoPath, "link")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	if err := os.Symlink(target, filepath.Join(repoPath, "leak.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	content := "x"
+	for _, filePath := range []string{"../target.txt", "link/target.txt", "leak.txt", target} {
+		if _, err := client.ResolveFile(filePath, "manual", &content); err == nil || !strings.Contains(err.Error(), "ruta no permitida") {
+			t.Errorf("ResolveFile(%q) error = %v, should be rejected", filePath, err)
+		}
+		if _, err := client.ShowConflict(filePath); err == nil || !strings.Contains(err.Error(), "ruta no permitida") {
+			t.Errorf("ShowConflict(%q) error = %v, should be rejected", filePath, err)
+		}
+	}
+	if data, _ := os.ReadFile(target); string(data) != "original" {
+		t.Errorf("file outside the repository overwritten: %q", data)
+	}
+}
+
+func TestSandbox_Workspace(t *testing.T) {
+	root := t.TempDir()
+	allowed := createTestRepo(t)
+	SetSandbox(safety.NewSandbox([]string{root}))
+	defer SetSandbox(safety.NewSandbox(nil))
+
+	client := newTestClient(t, &types.GitConfig{HasGit: true}, nil, nil)
+	if _, err := client.SetWorkspace(allowed); err == nil || !strings.Contains(err.Error(), "ruta no permitida") {
+		t.Errorf("SetWorkspace outside the roots should fail: %v", err)
+	}
+	if _, err := client.ValidateRepo(allowed); err == nil || !strings.Contains(err.Error(), "ruta no permitida") {
+		t.Errorf("ValidateRepo outside the roots should fail: %v", err)
+	}
+	if _, err := client.Init(allowed, ""); err == nil || !strings.Contains(err.Error(), "ruta no permitida") {
+		t.Errorf("Init outside the roots should fail: %v", err)
+	}
 }
 
 func TestUpdateFile(t *testing.T) {
@@ -608,3 +684,170 @@ func TestUpdateFile(t *testing.T) {
 		}
 	})
 }
+
+// TestConcurrentWorkspaces operates on two repositories at the same time; with
+// a process-wide chdir, files and commits would land in the wrong one
+func TestConcurrentWorkspaces(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repos := []string{createTestRepo(t), createTestRepo(t)}
+	var wg sync.WaitGroup
+	errs := make(chan error, len(repos))
+	for i, repo := range repos {
+		client := &Client{
+			Config:   &types.GitConfig{HasGit: true, IsGitRepo: true, RepoPath: repo},
+			executor: &realExecutor{},
+		}
+		wg.Add(1)
+		go func(i int, client *Client) {
+			defer wg.Done()
+			for n := 0; n < 10; n++ {
+				name := fmt.Sprintf("repo%d-%d.txt", i, n)
+				if _, err := client.CreateFile(name, name); err != nil {
+					errs <- err
+					return
+				}
+				if _, err := client.Add(name); err != nil {
+					errs <- err
+					return
+				}
+				if _, err := client.Commit("add " + name); err != nil {
+					errs <- err
+					return
+				}
+				if _, err := client.Status(); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(i, client)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent operation failed: %v", err)
+	}
+
+	for i, repo := range repos {
+		cmd := exec.Command("git", "ls-files")
+		cmd.Dir = repo
+		output, err := cmd.Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files := strings.Fields(string(output))
+		if len(files) != 11 {
+			t.Errorf("repo %d has %d tracked files, want README.md + 10: %v", i, len(files), files)
+		}
+		for _, f := range files {
+			if f != "README.md" && !strings.HasPrefix(f, fmt.Sprintf("repo%d-", i)) {
+				t.Errorf("repo %d tracks %s from the other workspace", i, f)
+			}
+		}
+	}
+
+	if after, _ := os.Getwd(); after != cwd {
+		t.Errorf("working directory changed from %s to %s", cwd, after)
+	}
+}
+
+func TestCommandTimeout(t *testing.T) {
+	tests := []struct {
+		args []string
+		want time.Duration
+	}{
+		{[]string{"status", "--porcelain"}, localTimeout},
+		{[]string{"commit", "-m", "x"}, localTimeout},
+		{[]string{"fetch", "origin"}, networkTimeout},
+		{[]string{"push", "origin", "main"}, networkTimeout},
+		{[]string{"pull"}, networkTimeout},
+		{[]string{"remote", "update"}, networkTimeout},
+		{[]string{"remote", "get-url", "origin"}, localTimeout},
+	}
+	for _, tt := range tests {
+		if got := commandTimeout(tt.args); got != tt.want {
+			t.Errorf("commandTimeout(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestGitCmd_TimeoutKillsProcessGroup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell alias")
+	}
+	original := localTimeout
+	localTimeout = 300 * time.Millisecond
+	defer func() { localTimeout = original }()
+
+	client := &Client{
+		Config:   &types.GitConfig{HasGit: true, IsGitRepo: true, RepoPath: t.TempDir()},
+		executor: &realExecutor{},
+	}
+
+	// The alias runs through sh, so sleep is a grandchild of git that keeps
+	// stderr open: only killing the whole group returns before waitDelay
+	start := time.Now()
+	_, err := client.gitCmd("-c", "alias.hang=!echo waiting for credentials >&2; sleep 30", "hang").Output()
+	elapsed := time.Since(start)
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("error = %v, want a TimeoutError", err)
+	}
+	if !strings.Contains(timeoutErr.Stderr, "waiting for credentials") {
+		t.Errorf("timeout error should include the partial stderr, got %q", timeoutErr.Stderr)
+	}
+	if !strings.Contains(err.Error(), "tiempo de espera agotado") {
+		t.Errorf("error message = %q", err.Error())
+	}
+	if elapsed > waitDelay {
+		t.Errorf("command took %v; the process group was not killed", elapsed)
+	}
+}
+
+func TestGitCmd_CancelKillsProcessGroup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell alias")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	client := (&Client{
+		Config:   &types.GitConfig{HasGit: true, IsGitRepo: true, RepoPath: t.TempDir()},
+		executor: &realExecutor{},
+	}).WithContext(ctx).(*Client)
+
+	time.AfterFunc(300*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := client.gitCmd("-c", "alias.hang=!sleep 30", "hang").Output()
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		t.Error("a cancelled command is not a timeout")
+	}
+	if elapsed > waitDelay {
+		t.Errorf("command took %v; the process group was not killed", elapsed)
+	}
+}
+
+func TestGitCmd_NoTerminalPrompt(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell alias")
+	}
+	client := &Client{
+		Config:   &types.GitConfig{HasGit: true, IsGitRepo: true, RepoPath: t.TempDir()},
+		executor: &realExecutor{},
+	}
+	output, err := client.gitCmd("-c", "alias.env=!echo $GIT_TERMINAL_PROMPT", "env").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(output)) != "0" {
+		t.Errorf("GIT_TERMINAL_PROMPT = %q, want 0", strings.TrimSpace(string(output)))
+	}
+}
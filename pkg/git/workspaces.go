@@ -0,0 +1,222 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/scopweb/mcp-go-github/pkg/interfaces"
+	"github.com/scopweb/mcp-go-github/pkg/types"
+)
+
+// DefaultWorkspaceStatePath es el archivo donde se guarda el registro de workspaces
+const DefaultWorkspaceStatePath = "./.mcp-workspaces.json"
+
+// workspaceNamePattern limita los nombres a identificadores cortos y legibles
+var workspaceNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// Workspace es un repositorio registrado con nombre
+type Workspace struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Default bool   `json:"default,omitempty"`
+}
+
+// workspaceState es el contenido persistido del registro
+type workspaceState struct {
+	Default    string            `json:"default,omitempty"`
+	Workspaces map[string]string `json:"workspaces"`
+}
+
+// WorkspaceRegistry mantiene los workspaces con nombre y un cliente Git por
+// cada uno, para que una llamada pueda operar sobre otro repositorio sin
+// cambiar el workspace actual. Con path vacío el registro solo vive en memoria.
+type WorkspaceRegistry struct {
+	mu       sync.Mutex
+	path     string
+	executor executor
+	state    workspaceState
+	clients  map[string]*Client
+}
+
+// NewWorkspaceRegistry carga el registro desde path (si existe)
+func NewWorkspaceRegistry(path string) (*WorkspaceRegistry, error) {
+	return newWorkspaceRegistry(path, &realExecutor{})
+}
+
+func newWorkspaceRegistry(path string, exec executor) (*WorkspaceRegistry, error) {
+	r := &WorkspaceRegistry{
+		path:     path,
+		executor: exec,
+		state:    workspaceState{Workspaces: make(map[string]string)},
+		clients:  make(map[string]*Client),
+	}
+	if path == "" {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) || (err == nil && len(data) == 0) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("no se puede leer el registro de workspaces: %w", err)
+	}
+	if err := json.Unmarshal(data, &r.state); err != nil {
+		return nil, fmt.Errorf("registro de workspaces inválido %s: %w", path, err)
+	}
+	if r.state.Workspaces == nil {
+		r.state.Workspaces = make(map[string]string)
+	}
+	if _, ok := r.state.Workspaces[r.state.Default]; !ok {
+		r.state.Default = ""
+	}
+	return r, nil
+}
+
+// Add registra un repositorio con nombre. La ruta se valida igual que en
+// SetWorkspace (sandbox, carpeta .git) y se guarda absoluta.
+func (r *WorkspaceRegistry) Add(name, path string) (Workspace, error) {
+	if !workspaceNamePattern.MatchString(name) {
+		return Workspace{}, fmt.Errorf("nombre de workspace inválido '%s': usa letras, números, '.', '_' o '-' (máx. 64)", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.state.Workspaces[name]; ok {
+		return Workspace{}, fmt.Errorf("workspace '%s' ya registrado en %s", name, existing)
+	}
+
+	client := &Client{Config: &types.GitConfig{}, executor: r.executor}
+	if _, err := client.SetWorkspace(path); err != nil {
+		return Workspace{}, err
+	}
+
+	r.state.Workspaces[name] = client.Config.RepoPath
+	if err := r.save(); err != nil {
+		delete(r.state.Workspaces, name)
+		return Workspace{}, err
+	}
+	r.clients[name] = client
+	return Workspace{Name: name, Path: client.Config.RepoPath, Default: r.state.Default == name}, nil
+}
+
+// Remove elimina un workspace; si era el predeterminado, deja de haberlo
+func (r *WorkspaceRegistry) Remove(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	path, ok := r.state.Workspaces[name]
+	if !ok {
+		return fmt.Errorf("workspace no registrado: %s", name)
+	}
+	previousDefault := r.state.Default
+	delete(r.state.Workspaces, name)
+	if r.state.Default == name {
+		r.state.Default = ""
+	}
+	if err := r.save(); err != nil {
+		r.state.Workspaces[name] = path
+		r.state.Default = previousDefault
+		return err
+	}
+	delete(r.clients, name)
+	return nil
+}
+
+// SetDefault marca el workspace usado cuando una llamada no indica ninguno
+func (r *WorkspaceRegistry) SetDefault(name string) (Workspace, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	path, ok := r.state.Workspaces[name]
+	if !ok {
+		return Workspace{}, fmt.Errorf("workspace no registrado: %s", name)
+	}
+	previous := r.state.Default
+	r.state.Default = name
+	if err := r.save(); err != nil {
+		r.state.Default = previous
+		return Workspace{}, err
+	}
+	return Workspace{Name: name, Path: path, Default: true}, nil
+}
+
+// Default devuelve el workspace predeterminado, si hay uno
+func (r *WorkspaceRegistry) Default() (Workspace, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.state.Default == "" {
+		return Workspace{}, false
+	}
+	return Workspace{Name: r.state.Default, Path: r.state.Workspaces[r.state.Default], Default: true}, true
+}
+
+// List devuelve los workspaces ordenados por nombre
+func (r *WorkspaceRegistry) List() []Workspace {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	workspaces := make([]Workspace, 0, len(r.state.Workspaces))
+	for name, path := range r.state.Workspaces {
+		workspaces = append(workspaces, Workspace{Name: name, Path: path, Default: name == r.state.Default})
+	}
+	sort.Slice(workspaces, func(i, j int) bool { return workspaces[i].Name < workspaces[j].Name })
+	return workspaces
+}
+
+// Client devuelve el cliente Git del workspace, creándolo la primera vez. La
+// ruta se vuelve a validar al crearlo, por si el registro viene de otra sesión.
+func (r *WorkspaceRegistry) Client(name string) (interfaces.GitOperations, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	path, ok := r.state.Workspaces[name]
+	if !ok {
+		return nil, fmt.Errorf("workspace no registrado: %s", name)
+	}
+	if client, ok := r.clients[name]; ok {
+		return client, nil
+	}
+
+	client := &Client{Config: &types.GitConfig{}, executor: r.executor}
+	if _, err := client.SetWorkspace(path); err != nil {
+		return nil, fmt.Errorf("workspace '%s' no disponible: %w", name, err)
+	}
+	r.clients[name] = client
+	return client, nil
+}
+
+// save escribe el registro de forma atómica (archivo temporal + rename)
+func (r *WorkspaceRegistry) save() error {
+	if r.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(r.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("no se puede codificar el registro de workspaces: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), ".workspaces-*")
+	if err != nil {
+		return fmt.Errorf("no se puede escribir el registro de workspaces: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("no se puede escribir el registro de workspaces: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("no se puede escribir el registro de workspaces: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		return fmt.Errorf("no se puede escribir el registro de workspaces: %w", err)
+	}
+	return nil
+}
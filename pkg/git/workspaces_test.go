@@ -0,0 +1,111 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorkspaceRegistry(t *testing.T) {
+	service := createTestRepo(t)
+	infra := createTestRepo(t)
+	statePath := filepath.Join(t.TempDir(), "workspaces.json")
+
+	registry, err := NewWorkspaceRegistry(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := registry.Add("service", service); err != nil {
+		t.Fatalf("Add(service) error = %v", err)
+	}
+	if _, err := registry.Add("infra", infra); err != nil {
+		t.Fatalf("Add(infra) error = %v", err)
+	}
+	if _, err := registry.SetDefault("infra"); err != nil {
+		t.Fatalf("SetDefault error = %v", err)
+	}
+
+	t.Run("invalid entries are rejected", func(t *testing.T) {
+		if _, err := registry.Add("service", infra); err == nil || !strings.Contains(err.Error(), "ya registrado") {
+			t.Errorf("duplicate name should fail: %v", err)
+		}
+		if _, err := registry.Add("../x", service); err == nil || !strings.Contains(err.Error(), "inválido") {
+			t.Errorf("invalid name should fail: %v", err)
+		}
+		if _, err := registry.Add("plain", t.TempDir()); err == nil || !strings.Contains(err.Error(), ".git") {
+			t.Errorf("directory without .git should fail: %v", err)
+		}
+		if _, err := registry.SetDefault("missing"); err == nil {
+			t.Error("SetDefault of an unknown workspace should fail")
+		}
+	})
+
+	t.Run("each workspace gets its own client", func(t *testing.T) {
+		serviceClient, err := registry.Client("service")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := serviceClient.CreateFile("only-service.txt", "x"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(service, "only-service.txt")); err != nil {
+			t.Errorf("file should be created in the service repo: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(infra, "only-service.txt")); !os.IsNotExist(err) {
+			t.Error("file should not be created in the infra repo")
+		}
+		if _, err := registry.Client("missing"); err == nil || !strings.Contains(err.Error(), "no registrado") {
+			t.Errorf("unknown workspace should fail: %v", err)
+		}
+	})
+
+	t.Run("state survives a reload", func(t *testing.T) {
+		reloaded, err := NewWorkspaceRegistry(statePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		list := reloaded.List()
+		if len(list) != 2 || list[0].Name != "infra" || list[1].Name != "service" {
+			t.Fatalf("List() = %+v, want infra and service", list)
+		}
+		if !list[0].Default || list[1].Default {
+			t.Errorf("infra should be the only default: %+v", list)
+		}
+		if def, ok := reloaded.Default(); !ok || def.Name != "infra" || !filepath.IsAbs(def.Path) {
+			t.Errorf("Default() = %+v, %v", def, ok)
+		}
+		if client, err := reloaded.Client("service"); err != nil || client.GetRepoPath() == "" {
+			t.Errorf("Client(service) after reload = %v", err)
+		}
+	})
+
+	t.Run("removing the default clears it", func(t *testing.T) {
+		if err := registry.Remove("infra"); err != nil {
+			t.Fatal(err)
+		}
+		if err := registry.Remove("infra"); err == nil {
+			t.Error("removing twice should fail")
+		}
+		reloaded, err := NewWorkspaceRegistry(statePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := reloaded.Default(); ok {
+			t.Error("default should be cleared")
+		}
+		if list := reloaded.List(); len(list) != 1 || list[0].Name != "service" {
+			t.Errorf("List() = %+v, want only service", list)
+		}
+	})
+}
+
+func TestWorkspaceRegistry_CorruptState(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "workspaces.json")
+	if err := os.WriteFile(statePath, []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewWorkspaceRegistry(statePath); err == nil {
+		t.Error("corrupt state file should fail to load")
+	}
+}
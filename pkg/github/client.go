@@ -18,6 +18,7 @@ type RepositoriesService interface {
 
 // PullRequestsService define la interfaz para interactuar con la API de pull requests de GitHub.
 type PullRequestsService interface {
+	Get(ctx context.Context, owner, repo string, number int) (*github.PullRequest, *github.Response, error)
 	List(ctx context.Context, owner, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
 	Create(ctx context.Context, owner, repo string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error)
 	Merge(ctx context.Context, owner, repo string, number int, commitMessage string, opts *github.PullRequestOptions) (*github.PullRequestMergeResult, *github.Response, error)
@@ -26,39 +27,44 @@ type PullRequestsService interface {
 
 // IssuesService define la interfaz para interactuar con la API de issues de GitHub.
 type IssuesService interface {
+	Get(ctx context.Context, owner, repo string, number int) (*github.Issue, *github.Response, error)
 	CreateComment(ctx context.Context, owner, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
 	Edit(ctx context.Context, owner, repo string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
 }
 
 // ActionsService define la interfaz para interactuar con GitHub Actions.
 type ActionsService interface {
+	GetWorkflowRunByID(ctx context.Context, owner, repo string, runID int64) (*github.WorkflowRun, *github.Response, error)
 	RerunWorkflowByID(ctx context.Context, owner, repo string, runID int64) (*github.Response, error)
 	RerunFailedJobsByID(ctx context.Context, owner, repo string, runID int64) (*github.Response, error)
 }
 
 // DependabotService define la interfaz para alertas de Dependabot.
 type DependabotService interface {
+	GetRepoAlert(ctx context.Context, owner, repo string, number int) (*github.DependabotAlert, *github.Response, error)
 	UpdateAlert(ctx context.Context, owner, repo string, number int, stateInfo *github.DependabotAlertState) (*github.DependabotAlert, *github.Response, error)
 }
 
 // CodeScanningService define la interfaz para alertas de Code Scanning.
 type CodeScanningService interface {
+	GetAlert(ctx context.Context, owner, repo string, id int64) (*github.Alert, *github.Response, error)
 	UpdateAlert(ctx context.Context, owner, repo string, id int64, stateInfo *github.CodeScanningAlertState) (*github.Alert, *github.Response, error)
 }
 
 // SecretScanningService define la interfaz para alertas de Secret Scanning.
 type SecretScanningService interface {
+	GetAlert(ctx context.Context, owner, repo string, number int64) (*github.SecretScanningAlert, *github.Response, error)
 	UpdateAlert(ctx context.Context, owner, repo string, number int64, opts *github.SecretScanningAlertUpdateOptions) (*github.SecretScanningAlert, *github.Response, error)
 }
 
 // Client implementa la interfaz GitHubOperations.
 type Client struct {
-	Repositories RepositoriesService
-	PullRequests PullRequestsService
-	Issues       IssuesService
-	Actions      ActionsService
-	Dependabot   DependabotService
-	CodeScanning CodeScanningService
+	Repositories   RepositoriesService
+	PullRequests   PullRequestsService
+	Issues         IssuesService
+	Actions        ActionsService
+	Dependabot     DependabotService
+	CodeScanning   CodeScanningService
 	SecretScanning SecretScanningService
 }
 
@@ -66,12 +72,12 @@ type Client struct {
 // Acepta un cliente de go-github y extrae los servicios necesarios.
 func NewClient(ghClient *github.Client) interfaces.GitHubOperations {
 	return &Client{
-		Repositories:  ghClient.Repositories,
-		PullRequests:  ghClient.PullRequests,
-		Issues:        ghClient.Issues,
-		Actions:       ghClient.Actions,
-		Dependabot:    ghClient.Dependabot,
-		CodeScanning:  ghClient.CodeScanning,
+		Repositories:   ghClient.Repositories,
+		PullRequests:   ghClient.PullRequests,
+		Issues:         ghClient.Issues,
+		Actions:        ghClient.Actions,
+		Dependabot:     ghClient.Dependabot,
+		CodeScanning:   ghClient.CodeScanning,
 		SecretScanning: ghClient.SecretScanning,
 	}
 }
@@ -159,6 +165,12 @@ func (c *Client) CreateIssueComment(ctx context.Context, owner, repo string, num
 	return result, err
 }
 
+// GetIssue obtiene un issue.
+func (c *Client) GetIssue(ctx context.Context, owner, repo string, number int) (*github.Issue, error) {
+	result, _, err := c.Issues.Get(ctx, owner, repo, number)
+	return result, err
+}
+
 // CloseIssue cierra un issue.
 func (c *Client) CloseIssue(ctx context.Context, owner, repo string, number int, comment string) (*github.Issue, error) {
 	req := &github.IssueRequest{
@@ -173,6 +185,12 @@ func (c *Client) CloseIssue(ctx context.Context, owner, repo string, number int,
 
 // === PULL REQUEST OPERATIONS ===
 
+// GetPullRequest obtiene un pull request.
+func (c *Client) GetPullRequest(ctx context.Context, owner, repo string, number int) (*github.PullRequest, error) {
+	result, _, err := c.PullRequests.Get(ctx, owner, repo, number)
+	return result, err
+}
+
 // CreatePRComment crea un comentario en un pull request.
 func (c *Client) CreatePRComment(ctx context.Context, owner, repo string, number int, body string) (*github.IssueComment, error) {
 	return c.CreateIssueComment(ctx, owner, repo, number, body)
@@ -199,6 +217,12 @@ func (c *Client) MergePullRequest(ctx context.Context, owner, repo string, numbe
 
 // === WORKFLOW OPERATIONS ===
 
+// GetWorkflowRun obtiene una ejecución de workflow.
+func (c *Client) GetWorkflowRun(ctx context.Context, owner, repo string, runID int64) (*github.WorkflowRun, error) {
+	result, _, err := c.Actions.GetWorkflowRunByID(ctx, owner, repo, runID)
+	return result, err
+}
+
 // RerunWorkflow re-ejecuta un workflow.
 func (c *Client) RerunWorkflow(ctx context.Context, owner, repo string, runID int64) error {
 	_, err := c.Actions.RerunWorkflowByID(ctx, owner, repo, runID)
@@ -213,6 +237,24 @@ func (c *Client) RerunFailedJobs(ctx context.Context, owner, repo string, runID
 
 // === SECURITY ALERT OPERATIONS ===
 
+// GetDependabotAlert obtiene una alerta de Dependabot.
+func (c *Client) GetDependabotAlert(ctx context.Context, owner, repo string, number int) (*github.DependabotAlert, error) {
+	result, _, err := c.Dependabot.GetRepoAlert(ctx, owner, repo, number)
+	return result, err
+}
+
+// GetCodeScanningAlert obtiene una alerta de code scanning.
+func (c *Client) GetCodeScanningAlert(ctx context.Context, owner, repo string, number int64) (*github.Alert, error) {
+	result, _, err := c.CodeScanning.GetAlert(ctx, owner, repo, number)
+	return result, err
+}
+
+// GetSecretScanningAlert obtiene una alerta de secret scanning.
+func (c *Client) GetSecretScanningAlert(ctx context.Context, owner, repo string, number int64) (*github.SecretScanningAlert, error) {
+	result, _, err := c.SecretScanning.GetAlert(ctx, owner, repo, number)
+	return result, err
+}
+
 // DismissDependabotAlert dismissa una alerta de Dependabot.
 func (c *Client) DismissDependabotAlert(ctx context.Context, owner, repo string, number int, reason, comment string) (*github.DependabotAlert, error) {
 	state := &github.DependabotAlertState{
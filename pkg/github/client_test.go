@@ -49,12 +49,20 @@ func (m *mockRepositoriesService) UpdateFile(ctx context.Context, owner, repo, p
 
 // mockPullRequestsService es una implementación simulada de PullRequestsService para pruebas.
 type mockPullRequestsService struct {
-	ListFunc        func(ctx context.Context, owner, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
-	CreateFunc      func(ctx context.Context, owner, repo string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error)
-	MergeFunc       func(ctx context.Context, owner, repo string, number int, commitMessage string, opts *github.PullRequestOptions) (*github.PullRequestMergeResult, *github.Response, error)
+	GetFunc          func(ctx context.Context, owner, repo string, number int) (*github.PullRequest, *github.Response, error)
+	ListFunc         func(ctx context.Context, owner, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
+	CreateFunc       func(ctx context.Context, owner, repo string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error)
+	MergeFunc        func(ctx context.Context, owner, repo string, number int, commitMessage string, opts *github.PullRequestOptions) (*github.PullRequestMergeResult, *github.Response, error)
 	CreateReviewFunc func(ctx context.Context, owner, repo string, number int, review *github.PullRequestReviewRequest) (*github.PullRequestReview, *github.Response, error)
 }
 
+func (m *mockPullRequestsService) Get(ctx context.Context, owner, repo string, number int) (*github.PullRequest, *github.Response, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, owner, repo, number)
+	}
+	return nil, nil, nil
+}
+
 func (m *mockPullRequestsService) List(ctx context.Context, owner, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error) {
 	if m.ListFunc != nil {
 		return m.ListFunc(ctx, owner, repo, opts)
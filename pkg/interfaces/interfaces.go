@@ -15,6 +15,7 @@ type GitOperations interface {
 	GetCurrentBranch() string
 	GetRemoteURL() string
 	Status() (string, error)
+	StatusInfo() (*types.RepoStatus, error)
 	Add(path string) (string, error)
 	Commit(message string) (string, error)
 	Push(branch string) (string, error)
@@ -24,6 +25,7 @@ type GitOperations interface {
 	UpdateFile(path, content, sha string) (string, error)
 	BranchList(remote bool) ([]types.BranchInfo, error)
 	SetWorkspace(path string) (string, error)
+	WithContext(ctx context.Context) GitOperations
 	GetFileSHA(path string) (string, error)
 	GetLastCommit() (string, error)
 	GetFileContent(path, ref string) (string, error)
@@ -31,7 +33,11 @@ type GitOperations interface {
 	ValidateRepo(path string) (string, error)
 	ListFiles(ref string) (string, error)
 	LogAnalysis(limit string) (string, error)
+	LogQuery(opts types.LogOptions) (string, error)
 	DiffFiles(staged bool) (string, error)
+	DiffPatch(opts types.DiffOptions) (string, error)
+	Blame(opts types.BlameOptions) (string, error)
+	Show(opts types.ShowOptions) (string, error)
 	Stash(operation, name string) (string, error)
 	Remote(operation, name, url string) (string, error)
 	Tag(operation, tagName, message string) (string, error)
@@ -41,6 +47,12 @@ type GitOperations interface {
 	CheckoutRemote(remoteBranch string, localBranch string) (string, error)
 	Merge(sourceBranch string, targetBranch string) (string, error)
 	Rebase(branch string) (string, error)
+	RebaseInteractive(onto string, steps []types.RebaseStep) (string, error)
+	RebaseControl(action string) (string, error)
+	CherryPick(opts types.CherryPickOptions) (string, error)
+	CherryPickControl(action string) (string, error)
+	Revert(opts types.RevertOptions) (string, error)
+	RevertControl(action string) (string, error)
 
 	// Enhanced pull/push operations
 	PullWithStrategy(branch string, strategy string) (string, error)
@@ -60,6 +72,10 @@ type GitOperations interface {
 	DetectPotentialConflicts(sourceBranch string, targetBranch string) (string, error)
 	CreateBackup(name string) (string, error)
 
+	// Dry-run previews (git merge-tree, no worktree changes)
+	PreviewMerge(source string, target string) (string, error)
+	PreviewRebase(upstream string) (string, error)
+
 	// Phase 1: Essential commands (Fase 1)
 	Reset(mode string, target string, files []string) (string, error)
 
@@ -81,19 +97,25 @@ type GitHubOperations interface {
 	UpdateFile(ctx context.Context, owner, repo, path, content, message, sha, branch string) (*github.RepositoryContentResponse, error)
 
 	// Issue operations
+	GetIssue(ctx context.Context, owner, repo string, number int) (*github.Issue, error)
 	CreateIssueComment(ctx context.Context, owner, repo string, number int, body string) (*github.IssueComment, error)
 	CloseIssue(ctx context.Context, owner, repo string, number int, comment string) (*github.Issue, error)
 
 	// Pull Request operations
+	GetPullRequest(ctx context.Context, owner, repo string, number int) (*github.PullRequest, error)
 	CreatePRComment(ctx context.Context, owner, repo string, number int, body string) (*github.IssueComment, error)
 	CreatePRReview(ctx context.Context, owner, repo string, number int, event, body string) (*github.PullRequestReview, error)
 	MergePullRequest(ctx context.Context, owner, repo string, number int, commitMessage, mergeMethod string) (*github.PullRequestMergeResult, error)
 
 	// Workflow operations
+	GetWorkflowRun(ctx context.Context, owner, repo string, runID int64) (*github.WorkflowRun, error)
 	RerunWorkflow(ctx context.Context, owner, repo string, runID int64) error
 	RerunFailedJobs(ctx context.Context, owner, repo string, runID int64) error
 
 	// Security alert operations
+	GetDependabotAlert(ctx context.Context, owner, repo string, number int) (*github.DependabotAlert, error)
+	GetCodeScanningAlert(ctx context.Context, owner, repo string, number int64) (*github.Alert, error)
+	GetSecretScanningAlert(ctx context.Context, owner, repo string, number int64) (*github.SecretScanningAlert, error)
 	DismissDependabotAlert(ctx context.Context, owner, repo string, number int, reason, comment string) (*github.DependabotAlert, error)
 	DismissCodeScanningAlert(ctx context.Context, owner, repo string, number int64, reason, comment string) (*github.Alert, error)
 	DismissSecretScanningAlert(ctx context.Context, owner, repo string, number int64, resolution string) (*github.SecretScanningAlert, error)
@@ -105,6 +127,7 @@ type AdminOperations interface {
 	GetRepositorySettings(ctx context.Context, owner, repo string) (*github.Repository, error)
 	UpdateRepositorySettings(ctx context.Context, owner, repo string, settings map[string]interface{}) (*github.Repository, error)
 	ArchiveRepository(ctx context.Context, owner, repo string) (*github.Repository, error)
+	UnarchiveRepository(ctx context.Context, owner, repo string) (*github.Repository, error)
 	DeleteRepository(ctx context.Context, owner, repo string) error
 
 	// Branch Protection
@@ -114,6 +137,7 @@ type AdminOperations interface {
 
 	// Webhooks
 	ListWebhooks(ctx context.Context, owner, repo string) ([]*github.Hook, error)
+	GetWebhook(ctx context.Context, owner, repo string, hookID int64) (*github.Hook, error)
 	CreateWebhook(ctx context.Context, owner, repo string, config map[string]interface{}) (*github.Hook, error)
 	UpdateWebhook(ctx context.Context, owner, repo string, hookID int64, config map[string]interface{}) (*github.Hook, error)
 	DeleteWebhook(ctx context.Context, owner, repo string, hookID int64) error
@@ -124,6 +148,7 @@ type AdminOperations interface {
 	AddCollaborator(ctx context.Context, owner, repo, username, permission string) (*github.CollaboratorInvitation, error)
 	UpdateCollaboratorPermission(ctx context.Context, owner, repo, username, permission string) (*github.CollaboratorInvitation, error)
 	RemoveCollaborator(ctx context.Context, owner, repo, username string) error
+	GetCollaboratorPermission(ctx context.Context, owner, repo, username string) (string, error)
 	CheckCollaborator(ctx context.Context, owner, repo, username string) (bool, error)
 
 	// Repository Invitations
@@ -0,0 +1,429 @@
+package safety
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Two-person approval: for the configured operations the confirmation token is
+// not returned to the caller. It is parked in an approval queue and only
+// becomes valid once a human approves the request out-of-band (CLI or local
+// HTTP endpoint); the caller then retries with the approval ID.
+
+const (
+	// DefaultApprovalQueuePath is where approval requests are kept when safety.json exists
+	DefaultApprovalQueuePath = "./.mcp-approvals.json"
+
+	// DefaultApprovalExpiration is how long a request waits for a decision
+	DefaultApprovalExpiration = 30 * time.Minute
+
+	// ApprovalRuleLabelPrefix prefixes the policy rule recorded in the audit
+	// log for operations decided by a human approval
+	ApprovalRuleLabelPrefix = "approval:"
+
+	// ApprovalTokenEnv is the bearer token the approval HTTP endpoint
+	// requires; the endpoint does not start without it
+	ApprovalTokenEnv = "MCP_APPROVAL_TOKEN"
+
+	// minApprovalTokenLength is the minimum bearer token size
+	minApprovalTokenLength = 32
+
+	// approvalRetention keeps decided and expired requests listed for the record
+	approvalRetention = 24 * time.Hour
+)
+
+// DefaultApprovalOperations are gated when approval is enabled without an operation list
+var DefaultApprovalOperations = []string{
+	"github_admin_repo:delete",
+	"github_admin_repo:archive",
+	"github_branch_protection:delete",
+}
+
+// ApprovalStatus is the state of an approval request
+type ApprovalStatus string
+
+const (
+	ApprovalPending  ApprovalStatus = "pending"
+	ApprovalApproved ApprovalStatus = "approved"
+	ApprovalDenied   ApprovalStatus = "denied"
+	ApprovalUsed     ApprovalStatus = "used"
+)
+
+var (
+	// ErrApprovalNotFound is returned for unknown approval IDs
+	ErrApprovalNotFound = errors.New("approval request not found")
+
+	// ErrApprovalRequired is returned when a token for a gated operation has not been approved
+	ErrApprovalRequired = errors.New("operation requires human approval")
+)
+
+// ApprovalRequest is one entry of the approval queue
+type ApprovalRequest struct {
+	ID         string                 `json:"id"`
+	Operation  string                 `json:"operation"`
+	Parameters map[string]interface{} `json:"parameters"` // sanitized, for the approver
+	RiskLevel  string                 `json:"risk_level"`
+	Status     ApprovalStatus         `json:"status"`
+	CreatedAt  time.Time              `json:"created_at"`
+	ExpiresAt  time.Time              `json:"expires_at"`
+	DecidedAt  *time.Time             `json:"decided_at,omitempty"`
+	DecidedBy  string                 `json:"decided_by,omitempty"`
+	Nonce      string                 `json:"nonce,omitempty"`
+	Claims     string                 `json:"claims,omitempty"`     // unsigned token payload, signed again once approved
+	TokenHash  string                 `json:"token_hash,omitempty"` // SHA-256 of the issued token, to check the re-signed one
+}
+
+// Expired reports whether the request can no longer be decided or used
+func (r *ApprovalRequest) Expired(now time.Time) bool {
+	return now.After(r.ExpiresAt)
+}
+
+// ApprovalQueue stores approval requests, in memory or in a JSON file that is
+// re-read on every use so the CLI and the server see the same queue
+type ApprovalQueue struct {
+	mu         sync.Mutex
+	path       string
+	operations map[string]bool
+	expiration time.Duration
+	memory     map[string]*ApprovalRequest
+}
+
+// NewApprovalQueue creates a queue gating operations. An empty path keeps
+// requests in memory; expiration <= 0 uses DefaultApprovalExpiration.
+func NewApprovalQueue(path string, operations []string, expiration time.Duration) *ApprovalQueue {
+	if expiration <= 0 {
+		expiration = DefaultApprovalExpiration
+	}
+	ops := make(map[string]bool, len(operations))
+	for _, op := range operations {
+		ops[op] = true
+	}
+	return &ApprovalQueue{
+		path:       path,
+		operations: ops,
+		expiration: expiration,
+		memory:     make(map[string]*ApprovalRequest),
+	}
+}
+
+// Requires reports whether an operation is gated by human approval
+func (q *ApprovalQueue) Requires(operation string) bool {
+	return q != nil && q.operations[operation]
+}
+
+// Expiration is how long a new request stays valid
+func (q *ApprovalQueue) Expiration() time.Duration {
+	return q.expiration
+}
+
+// Submit queues a pending request for the token. The queue keeps the token's
+// claims and a hash of it, never the signed token itself.
+func (q *ApprovalQueue) Submit(token *ConfirmationToken) (*ApprovalRequest, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	requests, err := q.load()
+	if err != nil {
+		return nil, err
+	}
+
+	req := &ApprovalRequest{
+		ID:         hex.EncodeToString(mustRandomBytes(4)),
+		Operation:  token.Operation,
+		Parameters: token.Parameters,
+		RiskLevel:  token.RiskLevel.String(),
+		Status:     ApprovalPending,
+		CreatedAt:  token.CreatedAt,
+		ExpiresAt:  token.ExpiresAt,
+		Nonce:      token.Nonce,
+		Claims:     tokenClaims(token.Token),
+		TokenHash:  hashToken(token.Token),
+	}
+	requests[req.ID] = req
+	return req, q.save(requests)
+}
+
+// Get returns a request by ID
+func (q *ApprovalQueue) Get(id string) (*ApprovalRequest, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	requests, err := q.load()
+	if err != nil {
+		return nil, err
+	}
+	req, ok := requests[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrApprovalNotFound, id)
+	}
+	return req, nil
+}
+
+// List returns the requests, newest first; pendingOnly hides decided and expired ones
+func (q *ApprovalQueue) List(pendingOnly bool) ([]*ApprovalRequest, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	requests, err := q.load()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var list []*ApprovalRequest
+	for _, req := range requests {
+		if pendingOnly && (req.Status != ApprovalPending || req.Expired(now)) {
+			continue
+		}
+		list = append(list, req)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.After(list[j].CreatedAt) })
+	return list, nil
+}
+
+// Decide approves or denies a pending request on behalf of decidedBy
+func (q *ApprovalQueue) Decide(id string, approve bool, decidedBy string) (*ApprovalRequest, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	requests, err := q.load()
+	if err != nil {
+		return nil, err
+	}
+	req, ok := requests[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrApprovalNotFound, id)
+	}
+
+	now := time.Now()
+	if req.Status != ApprovalPending {
+		return nil, fmt.Errorf("approval request %s is already %s", id, req.Status)
+	}
+	if req.Expired(now) {
+		return nil, fmt.Errorf("approval request %s expired at %s", id, req.ExpiresAt.Format(time.RFC3339))
+	}
+
+	req.Status = ApprovalDenied
+	if approve {
+		req.Status = ApprovalApproved
+	}
+	req.DecidedAt = &now
+	req.DecidedBy = decidedBy
+	return req, q.save(requests)
+}
+
+// IsApproved reports whether the token nonce belongs to an approved request
+func (q *ApprovalQueue) IsApproved(nonce string) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	requests, err := q.load()
+	if err != nil {
+		return false, err
+	}
+	for _, req := range requests {
+		if req.Nonce == nonce {
+			return req.Status == ApprovalApproved, nil
+		}
+	}
+	return false, nil
+}
+
+// MarkUsed records that an approved request has been executed
+func (q *ApprovalQueue) MarkUsed(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	requests, err := q.load()
+	if err != nil {
+		return err
+	}
+	if req, ok := requests[id]; ok {
+		req.Status = ApprovalUsed
+	}
+	return q.save(requests)
+}
+
+func (q *ApprovalQueue) load() (map[string]*ApprovalRequest, error) {
+	if q.path == "" {
+		return q.memory, nil
+	}
+
+	requests := make(map[string]*ApprovalRequest)
+	data, err := os.ReadFile(q.path)
+	if os.IsNotExist(err) || (err == nil && len(data) == 0) {
+		return requests, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read approval queue: %w", err)
+	}
+	if err := json.Unmarshal(data, &requests); err != nil {
+		return nil, fmt.Errorf("failed to parse approval queue %s: %w", q.path, err)
+	}
+	return requests, nil
+}
+
+func (q *ApprovalQueue) save(requests map[string]*ApprovalRequest) error {
+	cutoff := time.Now().Add(-approvalRetention)
+	for id, req := range requests {
+		if req.ExpiresAt.Before(cutoff) {
+			delete(requests, id)
+		}
+	}
+
+	if q.path == "" {
+		q.memory = requests
+		return nil
+	}
+
+	data, err := json.MarshalIndent(requests, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode approval queue: %w", err)
+	}
+	if err := writeFileAtomic(q.path, data, ".approvals-*"); err != nil {
+		return fmt.Errorf("failed to write approval queue: %w", err)
+	}
+	return nil
+}
+
+// tokenClaims returns the unsigned payload of a signed token
+func tokenClaims(tokenStr string) string {
+	body := strings.TrimPrefix(tokenStr, TokenPrefix)
+	claims, _, _ := strings.Cut(body, ".")
+	return claims
+}
+
+func hashToken(tokenStr string) string {
+	sum := sha256.Sum256([]byte(tokenStr))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetApprovalMessage tells the caller that a human has to approve the
+// operation out-of-band. It deliberately contains no confirmation token.
+func GetApprovalMessage(req *ApprovalRequest, additionalInfo, httpAddr string) string {
+	message := fmt.Sprintf(`👥 %s RISK OPERATION NEEDS HUMAN APPROVAL: %s
+
+%s
+
+Approval request %s is pending. Ask a human to approve it:
+  github-mcp-server approve %s
+`, req.RiskLevel, req.Operation, additionalInfo, req.ID, req.ID)
+
+	if httpAddr != "" {
+		message += fmt.Sprintf("  or: curl -X POST -H \"Authorization: Bearer $%s\" http://%s/approvals/%s/approve\n", ApprovalTokenEnv, httpAddr, req.ID)
+	}
+
+	message += fmt.Sprintf(`
+Once approved, call again with:
+  approval_id=%s
+
+Request expires in %s
+`, req.ID, formatTokenLifetime(time.Until(req.ExpiresAt)))
+	return message
+}
+
+// ValidateApprovalAddr accepts only loopback listen addresses, so the
+// approval endpoint is never exposed to the network
+func ValidateApprovalAddr(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("approval address must be host:port: %w", err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("approval address must be a loopback address, got %q", host)
+	}
+	return nil
+}
+
+// NewApprovalHandler serves the approval queue for humans:
+//
+//	GET  /approvals              pending requests
+//	POST /approvals/{id}/approve approve a request
+//	POST /approvals/{id}/deny    deny a request
+//
+// Every request must present secret as a bearer token: the endpoint is on
+// loopback, but so is the agent, which must not approve its own requests.
+// Requests carrying an Origin header are refused so a web page cannot drive
+// the endpoint from the approver's browser.
+func NewApprovalHandler(queue *ApprovalQueue, secret string) (http.Handler, error) {
+	if len(secret) < minApprovalTokenLength {
+		return nil, fmt.Errorf("%s must be set to at least %d characters to serve approvals over HTTP", ApprovalTokenEnv, minApprovalTokenLength)
+	}
+
+	decide := func(approve bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			by := r.URL.Query().Get("by")
+			if by == "" {
+				by = "http:" + r.RemoteAddr
+			}
+			req, err := queue.Decide(r.PathValue("id"), approve, by)
+			if errors.Is(err, ErrApprovalNotFound) {
+				writeApprovalJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+				return
+			}
+			if err != nil {
+				writeApprovalJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+				return
+			}
+			writeApprovalJSON(w, http.StatusOK, redactApproval(req))
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /approvals", func(w http.ResponseWriter, r *http.Request) {
+		list, err := queue.List(true)
+		if err != nil {
+			writeApprovalJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		redacted := make([]*ApprovalRequest, 0, len(list))
+		for _, req := range list {
+			redacted = append(redacted, redactApproval(req))
+		}
+		writeApprovalJSON(w, http.StatusOK, redacted)
+	})
+	mux.HandleFunc("POST /approvals/{id}/approve", decide(true))
+	mux.HandleFunc("POST /approvals/{id}/deny", decide(false))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Origin") != "" {
+			writeApprovalJSON(w, http.StatusForbidden, map[string]string{"error": "cross-origin requests are not allowed"})
+			return
+		}
+		presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(presented), []byte(secret)) != 1 {
+			writeApprovalJSON(w, http.StatusUnauthorized, map[string]string{"error": "missing or invalid bearer token"})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}), nil
+}
+
+// redactApproval drops the token material from a request shown to clients
+func redactApproval(req *ApprovalRequest) *ApprovalRequest {
+	shown := *req
+	shown.Claims = ""
+	shown.TokenHash = ""
+	shown.Nonce = ""
+	return &shown
+}
+
+func writeApprovalJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
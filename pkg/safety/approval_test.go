@@ -0,0 +1,272 @@
+package safety
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func approvalEngine(t *testing.T) *Engine {
+	t.Helper()
+	return NewEngine(&SafetyConfig{
+		Mode:                     SafetyModeModerate,
+		RequireConfirmationAbove: RiskHigh,
+		ApprovalOperations:       DefaultApprovalOperations,
+		ApprovalQueuePath:        filepath.Join(t.TempDir(), "approvals.json"),
+		ApprovalHTTPAddr:         "127.0.0.1:8787",
+	})
+}
+
+func pendingApproval(t *testing.T, queue *ApprovalQueue) *ApprovalRequest {
+	t.Helper()
+	pending, err := queue.List(true)
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("expected one pending request, got %d (%v)", len(pending), err)
+	}
+	return pending[0]
+}
+
+func mustGenerate(t *testing.T, engine *Engine, operation string, params map[string]interface{}) string {
+	t.Helper()
+	token, err := engine.tokens.Generate(operation, params, RiskCritical)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	return token.Token
+}
+
+func TestEngine_CheckOperation_Approval(t *testing.T) {
+	ctx := context.Background()
+	engine := approvalEngine(t)
+	params := map[string]interface{}{"owner": "acme", "repo": "api", "dry_run": false}
+
+	check, err := engine.CheckOperation(ctx, "github_admin_repo:delete", params)
+	if err != nil || check.CanProceed {
+		t.Fatalf("delete should wait for approval: %+v, %v", check, err)
+	}
+	if strings.Contains(check.Message, TokenPrefix) {
+		t.Fatalf("approval message must not reveal the confirmation token: %s", check.Message)
+	}
+
+	req := pendingApproval(t, engine.GetApprovals())
+	for _, want := range []string{"github-mcp-server approve " + req.ID, "127.0.0.1:8787/approvals/" + req.ID + "/approve", "approval_id=" + req.ID} {
+		if !strings.Contains(check.Message, want) {
+			t.Errorf("message should contain %q:\n%s", want, check.Message)
+		}
+	}
+
+	withApproval := func(id string, repo string) map[string]interface{} {
+		return map[string]interface{}{"owner": "acme", "repo": repo, "dry_run": false, "approval_id": id}
+	}
+
+	check, _ = engine.CheckOperation(ctx, "github_admin_repo:delete", withApproval(req.ID, "api"))
+	if check.CanProceed || !strings.Contains(check.Message, "still pending") {
+		t.Errorf("pending request should not authorize: %+v", check)
+	}
+
+	// The queue does not hold a usable token, and the token itself is useless
+	// until a human approves it
+	if data, _ := os.ReadFile(engine.GetConfig().ApprovalQueuePath); strings.Contains(string(data), TokenPrefix) {
+		t.Errorf("queue file must not store the confirmation token:\n%s", data)
+	}
+	token, err := engine.tokens.approvedToken(req)
+	if err != nil {
+		t.Fatalf("approvedToken() error = %v", err)
+	}
+	tampered := *req
+	tampered.Claims = tokenClaims(mustGenerate(t, engine, "github_admin_repo:delete", map[string]interface{}{"owner": "acme", "repo": "web"}))
+	if _, err := engine.tokens.approvedToken(&tampered); err == nil {
+		t.Error("claims edited in the queue file should be rejected")
+	}
+	tokenParams := map[string]interface{}{"owner": "acme", "repo": "api", "dry_run": false, "confirmation_token": token}
+	if _, err := engine.CheckOperation(ctx, "github_admin_repo:delete", tokenParams); !errors.Is(err, ErrApprovalRequired) {
+		t.Errorf("unapproved token should be refused with ErrApprovalRequired, got %v", err)
+	}
+
+	if _, err := engine.GetApprovals().Decide(req.ID, true, "alice"); err != nil {
+		t.Fatalf("Decide() error = %v", err)
+	}
+
+	// Approval is bound to the parameters it was requested for
+	if _, err := engine.CheckOperation(ctx, "github_admin_repo:delete", withApproval(req.ID, "web")); err == nil {
+		t.Error("approval for acme/api should not authorize acme/web")
+	}
+	if _, err := engine.CheckOperation(ctx, "github_admin_repo:archive", withApproval(req.ID, "api")); err != nil {
+		t.Errorf("CheckOperation() error = %v", err)
+	}
+
+	check, err = engine.CheckOperation(ctx, "github_admin_repo:delete", withApproval(req.ID, "api"))
+	if err != nil || !check.CanProceed {
+		t.Fatalf("approved request should authorize: %+v, %v", check, err)
+	}
+	if check.PolicyRule != ApprovalRuleLabelPrefix+req.ID || !strings.Contains(check.Message, "alice") {
+		t.Errorf("check should record the approval: %+v", check)
+	}
+
+	check, _ = engine.CheckOperation(ctx, "github_admin_repo:delete", withApproval(req.ID, "api"))
+	if check.CanProceed || !strings.Contains(check.Message, "already been used") {
+		t.Errorf("approval should be single use: %+v", check)
+	}
+}
+
+func TestEngine_CheckOperation_ApprovalDenied(t *testing.T) {
+	ctx := context.Background()
+	engine := approvalEngine(t)
+	params := map[string]interface{}{"owner": "acme", "repo": "api", "branch": "main", "dry_run": false}
+
+	if _, err := engine.CheckOperation(ctx, "github_branch_protection:delete", params); err != nil {
+		t.Fatalf("CheckOperation() error = %v", err)
+	}
+	req := pendingApproval(t, engine.GetApprovals())
+	if _, err := engine.GetApprovals().Decide(req.ID, false, "bob"); err != nil {
+		t.Fatalf("Decide() error = %v", err)
+	}
+
+	params["approval_id"] = req.ID
+	check, err := engine.CheckOperation(ctx, "github_branch_protection:delete", params)
+	if err != nil {
+		t.Fatalf("CheckOperation() error = %v", err)
+	}
+	if check.CanProceed || check.PolicyAction != PolicyDeny || !strings.Contains(check.Message, "bob") {
+		t.Errorf("denied request should be refused and audited as denied: %+v", check)
+	}
+}
+
+func TestEngine_CheckOperation_ApprovalNotRequired(t *testing.T) {
+	engine := approvalEngine(t)
+	params := map[string]interface{}{"owner": "acme", "repo": "api", "hook_id": 1, "dry_run": false}
+
+	check, err := engine.CheckOperation(context.Background(), "github_webhooks:delete", params)
+	if err != nil {
+		t.Fatalf("CheckOperation() error = %v", err)
+	}
+	if !strings.Contains(check.Message, TokenPrefix) {
+		t.Errorf("operations outside the approval list keep the normal token flow: %s", check.Message)
+	}
+	if pending, _ := engine.GetApprovals().List(true); len(pending) != 0 {
+		t.Errorf("no approval request expected, got %d", len(pending))
+	}
+}
+
+func TestApprovalQueue_SharedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "approvals.json")
+	server := NewApprovalQueue(path, DefaultApprovalOperations, 0)
+	cli := NewApprovalQueue(path, DefaultApprovalOperations, 0)
+
+	token, err := NewTokenSigner(processTokenKey, NewMemoryReplayStore(), nil).Generate("github_admin_repo:archive", map[string]interface{}{"owner": "acme", "repo": "api"}, RiskCritical)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	req, err := server.Submit(token)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	if _, err := cli.Decide(req.ID, true, "alice"); err != nil {
+		t.Fatalf("Decide() from another instance error = %v", err)
+	}
+	if approved, err := server.IsApproved(token.Nonce); err != nil || !approved {
+		t.Errorf("decision should be visible to the server: %v, %v", approved, err)
+	}
+	if _, err := cli.Decide(req.ID, false, "bob"); err == nil {
+		t.Error("a decided request should not be decided again")
+	}
+	if _, err := cli.Decide("missing", true, "alice"); !errors.Is(err, ErrApprovalNotFound) {
+		t.Errorf("unknown ID should return ErrApprovalNotFound, got %v", err)
+	}
+}
+
+func TestApprovalHandler(t *testing.T) {
+	engine := approvalEngine(t)
+	params := map[string]interface{}{"owner": "acme", "repo": "api", "dry_run": false}
+	if _, err := engine.CheckOperation(context.Background(), "github_admin_repo:delete", params); err != nil {
+		t.Fatalf("CheckOperation() error = %v", err)
+	}
+	req := pendingApproval(t, engine.GetApprovals())
+
+	if _, err := NewApprovalHandler(engine.GetApprovals(), ""); err == nil {
+		t.Fatal("the endpoint must not start without a bearer token")
+	}
+	if _, err := NewApprovalHandler(engine.GetApprovals(), "short"); err == nil {
+		t.Fatal("the endpoint must not start with a short bearer token")
+	}
+	secret := strings.Repeat("s3cret", 6)
+	handler, err := NewApprovalHandler(engine.GetApprovals(), secret)
+	if err != nil {
+		t.Fatalf("NewApprovalHandler() error = %v", err)
+	}
+
+	do := func(method, path string, header map[string]string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, nil)
+		for k, v := range header {
+			r.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+	auth := map[string]string{"Authorization": "Bearer " + secret}
+
+	if w := do(http.MethodGet, "/approvals", nil); w.Code != http.StatusUnauthorized {
+		t.Errorf("missing bearer token: status = %d, want 401", w.Code)
+	}
+	if w := do(http.MethodPost, "/approvals/"+req.ID+"/approve", map[string]string{"Authorization": "Bearer wrong"}); w.Code != http.StatusUnauthorized {
+		t.Errorf("wrong bearer token: status = %d, want 401", w.Code)
+	}
+	if w := do(http.MethodPost, "/approvals/"+req.ID+"/approve", map[string]string{"Authorization": "Bearer " + secret, "Origin": "https://evil.example"}); w.Code != http.StatusForbidden {
+		t.Errorf("cross-origin request: status = %d, want 403", w.Code)
+	}
+
+	w := do(http.MethodGet, "/approvals", auth)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /approvals status = %d", w.Code)
+	}
+	if strings.Contains(w.Body.String(), TokenPrefix) {
+		t.Error("listing must not expose confirmation tokens")
+	}
+	var listed []ApprovalRequest
+	if err := json.Unmarshal(w.Body.Bytes(), &listed); err != nil || len(listed) != 1 || listed[0].ID != req.ID {
+		t.Fatalf("listing = %s (%v)", w.Body.String(), err)
+	}
+
+	if w := do(http.MethodPost, "/approvals/nope/approve", auth); w.Code != http.StatusNotFound {
+		t.Errorf("unknown ID: status = %d, want 404", w.Code)
+	}
+	if w := do(http.MethodPost, "/approvals/"+req.ID+"/approve?by=carol", auth); w.Code != http.StatusOK {
+		t.Fatalf("approve status = %d: %s", w.Code, w.Body.String())
+	}
+	if w := do(http.MethodPost, "/approvals/"+req.ID+"/deny", auth); w.Code != http.StatusConflict {
+		t.Errorf("second decision: status = %d, want 409", w.Code)
+	}
+
+	decided, _ := engine.GetApprovals().Get(req.ID)
+	if decided.Status != ApprovalApproved || decided.DecidedBy != "carol" {
+		t.Errorf("request = %+v, want approved by carol", decided)
+	}
+}
+
+func TestValidateApprovalAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		wantErr bool
+	}{
+		{"127.0.0.1:8787", false},
+		{"localhost:8787", false},
+		{"[::1]:8787", false},
+		{"0.0.0.0:8787", true},
+		{"192.168.1.10:8787", true},
+		{":8787", true},
+		{"127.0.0.1", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateApprovalAddr(tt.addr); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateApprovalAddr(%q) error = %v, wantErr %v", tt.addr, err, tt.wantErr)
+		}
+	}
+}
@@ -11,16 +11,23 @@ import (
 
 // AuditEntry represents a single audit log entry
 type AuditEntry struct {
-	Timestamp       time.Time              `json:"timestamp"`
-	Operation       string                 `json:"operation"`
-	RiskLevel       string                 `json:"risk_level"`
-	Arguments       map[string]interface{} `json:"arguments"`
-	Result          string                 `json:"result"` // success, failed, partial
-	Changes         []string               `json:"changes,omitempty"`
-	RollbackCommand string                 `json:"rollback_cmd,omitempty"`
-	ConfirmationToken string               `json:"confirmation_token,omitempty"`
-	ExecutionTimeMs int64                  `json:"execution_time_ms"`
-	ErrorMessage    string                 `json:"error_message,omitempty"`
+	Timestamp         time.Time              `json:"timestamp"`
+	Operation         string                 `json:"operation"`
+	RiskLevel         string                 `json:"risk_level"`
+	Arguments         map[string]interface{} `json:"arguments"`
+	Result            string                 `json:"result"` // success, failed, partial
+	Changes           []string               `json:"changes,omitempty"`
+	RollbackCommand   string                 `json:"rollback_cmd,omitempty"`
+	ConfirmationToken string                 `json:"confirmation_token,omitempty"`
+	ExecutionTimeMs   int64                  `json:"execution_time_ms"`
+	ErrorMessage      string                 `json:"error_message,omitempty"`
+	PolicyRule        string                 `json:"policy_rule,omitempty"` // rule that decided, if any
+
+	// Hash chain (see audit_chain.go). Hash and Signature must stay the last
+	// fields: they are appended to the serialized entry they cover.
+	PrevHash  string `json:"prev_hash,omitempty"`
+	Hash      string `json:"hash,omitempty"`
+	Signature string `json:"signature,omitempty"` // HMAC-SHA256 of Hash, when a key is configured
 }
 
 // AuditLogger manages audit trail logging
@@ -30,6 +37,12 @@ type AuditLogger struct {
 	enabled      bool
 	maxSizeBytes int64
 	maxBackups   int
+
+	key         []byte // optional HMAC key for entry signatures
+	lastHash    string // hash of the last written entry
+	chainLoaded bool   // lastHash has been read from disk
+
+	sinks []AuditSink // external destinations, fed after the local write
 }
 
 const (
@@ -97,6 +110,17 @@ func (l *AuditLogger) LogOperation(entry *AuditEntry) error {
 		return fmt.Errorf("log rotation failed: %w", err)
 	}
 
+	// Continue the chain from the last entry on disk, which may now be in the
+	// rotated file
+	if !l.chainLoaded {
+		lastHash, err := lastChainHash(l.logPath)
+		if err != nil {
+			return err
+		}
+		l.lastHash = lastHash
+		l.chainLoaded = true
+	}
+
 	// Open log file for appending
 	f, err := os.OpenFile(l.logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
@@ -104,20 +128,70 @@ func (l *AuditLogger) LogOperation(entry *AuditEntry) error {
 	}
 	defer f.Close()
 
-	// Marshal entry to JSON
+	// Marshal entry to JSON, linked to the previous entry
+	entry.PrevHash = l.lastHash
+	entry.Hash = ""
+	entry.Signature = ""
 	jsonData, err := json.Marshal(entry)
 	if err != nil {
 		return fmt.Errorf("failed to marshal audit entry: %w", err)
 	}
+	line, hash, signature := sealEntry(jsonData, l.key)
 
 	// Write JSON line
-	if _, err := f.Write(append(jsonData, '\n')); err != nil {
+	if _, err := f.Write(append(line, '\n')); err != nil {
 		return fmt.Errorf("failed to write audit entry: %w", err)
 	}
 
+	entry.Hash = hash
+	entry.Signature = signature
+	l.lastHash = hash
+
+	// Sinks queue without blocking; delivery happens in the background
+	for _, sink := range l.sinks {
+		forwarded := *entry
+		sink.Send(&forwarded)
+	}
 	return nil
 }
 
+// SetSinks replaces the external audit sinks, closing the previous ones
+func (l *AuditLogger) SetSinks(sinks []AuditSink) {
+	l.mu.Lock()
+	previous := l.sinks
+	l.sinks = sinks
+	l.mu.Unlock()
+
+	closeSinks(previous)
+}
+
+// Close flushes and closes the external audit sinks
+func (l *AuditLogger) Close() error {
+	l.mu.Lock()
+	sinks := l.sinks
+	l.sinks = nil
+	l.mu.Unlock()
+
+	return closeSinks(sinks)
+}
+
+func closeSinks(sinks []AuditSink) error {
+	var firstErr error
+	for _, sink := range sinks {
+		if err := sink.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// SetSigningKey enables HMAC signatures on new entries; nil disables them
+func (l *AuditLogger) SetSigningKey(key []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.key = key
+}
+
 // rotateIfNeeded rotates the log file if it exceeds max size
 func (l *AuditLogger) rotateIfNeeded() error {
 	info, err := os.Stat(l.logPath)
@@ -256,6 +330,11 @@ func GetStatistics(logPath string) (map[string]interface{}, error) {
 		return nil, err
 	}
 
+	return computeStatistics(entries), nil
+}
+
+// computeStatistics counts entries by risk level, result and operation
+func computeStatistics(entries []*AuditEntry) map[string]interface{} {
 	stats := map[string]interface{}{
 		"total_entries": len(entries),
 		"by_risk_level": make(map[string]int),
@@ -273,7 +352,7 @@ func GetStatistics(logPath string) (map[string]interface{}, error) {
 		byOp[entry.Operation]++
 	}
 
-	return stats, nil
+	return stats
 }
 
 // CleanupOldLogs removes audit log files older than specified days
@@ -0,0 +1,254 @@
+package safety
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Each audit entry carries the hash of the previous one, so editing, removing
+// or reordering a line breaks every link after it. The hash covers the entry
+// as serialized without its hash and signature, which are appended as the last
+// fields of the line; verification works on the raw bytes and never depends on
+// re-encoding the JSON. With a signing key each hash is also HMAC-signed, so
+// the chain cannot be recomputed by someone who can only edit the file.
+
+// AuditKeyEnv overrides the audit signing key file
+const AuditKeyEnv = "MCP_AUDIT_KEY"
+
+// sealPattern matches the hash and optional signature at the end of a sealed line
+var sealPattern = regexp.MustCompile(`,"hash":"([0-9a-f]{64})"(?:,"signature":"([0-9a-f]{64})")?}$`)
+
+// LoadAuditKey returns the audit signing key from AuditKeyEnv or the hex key
+// file at path. The file is created when create is true and it does not exist.
+func LoadAuditKey(path string, create bool) ([]byte, error) {
+	return loadKey(AuditKeyEnv, path, "audit signing key", create)
+}
+
+// sealEntry appends the hash (and signature) to a serialized entry
+func sealEntry(data []byte, key []byte) (line []byte, hash, signature string) {
+	sum := sha256.Sum256(data)
+	hash = hex.EncodeToString(sum[:])
+
+	line = append([]byte{}, data[:len(data)-1]...)
+	line = append(line, `,"hash":"`+hash+`"`...)
+	if len(key) > 0 {
+		signature = signHash(key, hash)
+		line = append(line, `,"signature":"`+signature+`"`...)
+	}
+	line = append(line, '}')
+	return line, hash, signature
+}
+
+// unsealLine splits a sealed line into the covered bytes, hash and signature.
+// ok is false for lines written before the chain existed.
+func unsealLine(line []byte) (data []byte, hash, signature string, ok bool) {
+	loc := sealPattern.FindSubmatchIndex(line)
+	if loc == nil {
+		return nil, "", "", false
+	}
+	data = append(append([]byte{}, line[:loc[0]]...), '}')
+	hash = string(line[loc[2]:loc[3]])
+	if loc[4] >= 0 {
+		signature = string(line[loc[4]:loc[5]])
+	}
+	return data, hash, signature, true
+}
+
+func signHash(key []byte, hash string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(hash))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// lineHash is the value the next entry links to: the sealed hash, or the
+// digest of the raw line for entries written before the chain existed
+func lineHash(line []byte) string {
+	if _, hash, _, ok := unsealLine(line); ok {
+		return hash
+	}
+	sum := sha256.Sum256(line)
+	return hex.EncodeToString(sum[:])
+}
+
+// lastChainHash returns the hash of the last entry in the log, looking in the
+// most recent rotated file when the current one is missing or empty
+func lastChainHash(logPath string) (string, error) {
+	for _, path := range []string{logPath, logPath + ".1"} {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read audit log: %w", err)
+		}
+		lines := bytes.Split(bytes.TrimRight(data, "\n"), []byte("\n"))
+		if last := lines[len(lines)-1]; len(last) > 0 {
+			return lineHash(last), nil
+		}
+	}
+	return "", nil
+}
+
+// AuditLogFiles returns the log and its rotated files, oldest first
+func AuditLogFiles(logPath string) ([]string, error) {
+	matches, err := filepath.Glob(logPath + ".*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rotated audit logs: %w", err)
+	}
+
+	type rotated struct {
+		path string
+		n    int
+	}
+	var files []rotated
+	for _, path := range matches {
+		n, err := strconv.Atoi(path[len(logPath)+1:])
+		if err != nil || n < 1 {
+			continue
+		}
+		files = append(files, rotated{path, n})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].n > files[j].n })
+
+	paths := make([]string, 0, len(files)+1)
+	for _, f := range files {
+		paths = append(paths, f.path)
+	}
+	if _, err := os.Stat(logPath); err == nil {
+		paths = append(paths, logPath)
+	}
+	return paths, nil
+}
+
+// ChainBreak locates the first entry that does not verify
+type ChainBreak struct {
+	File   string
+	Line   int
+	Reason string
+}
+
+// ChainReport is the result of VerifyAuditChain
+type ChainReport struct {
+	Files          []string
+	Entries        int    // chained entries verified
+	Legacy         int    // entries written before the chain existed
+	Signed         int    // entries with a verified signature
+	UncheckedSigs  int    // signatures present but no key given
+	LastHash       string // hash of the last entry; record it elsewhere to detect truncation
+	Broken         *ChainBreak
+	AnchoredAtHead bool // the first chained entry links to a file no longer present
+}
+
+// VerifyAuditChain walks the log and all rotated files, oldest first, and
+// reports the first broken link. Signatures are checked when key is set; once
+// a signed entry was seen, later entries must be signed too.
+func VerifyAuditChain(logPath string, key []byte) (*ChainReport, error) {
+	files, err := AuditLogFiles(logPath)
+	if err != nil {
+		return nil, err
+	}
+	report := &ChainReport{Files: files}
+
+	prev := ""
+	seenLine := false
+	started := false
+	signedSeen := false
+
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log: %w", err)
+		}
+
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+		lineNo := 0
+		for scanner.Scan() {
+			lineNo++
+			line := scanner.Bytes()
+			if len(line) == 0 {
+				continue
+			}
+			fail := func(reason string) {
+				report.Broken = &ChainBreak{File: path, Line: lineNo, Reason: reason}
+			}
+
+			data, hash, signature, sealed := unsealLine(line)
+			if !sealed {
+				if started {
+					fail("entry has no hash (inserted or stripped)")
+					break
+				}
+				report.Legacy++
+				prev = lineHash(line)
+				seenLine = true
+				continue
+			}
+
+			sum := sha256.Sum256(data)
+			if hex.EncodeToString(sum[:]) != hash {
+				fail("entry content does not match its hash (modified)")
+				break
+			}
+
+			var entry AuditEntry
+			if err := json.Unmarshal(data, &entry); err != nil {
+				fail(fmt.Sprintf("entry is not valid JSON: %v", err))
+				break
+			}
+			if seenLine && entry.PrevHash != prev {
+				fail("prev_hash does not match the previous entry (entry removed, inserted or reordered)")
+				break
+			}
+			if !seenLine && entry.PrevHash != "" {
+				report.AnchoredAtHead = true
+			}
+
+			switch {
+			case signature != "" && len(key) > 0:
+				if !hmac.Equal([]byte(signature), []byte(signHash(key, hash))) {
+					fail("invalid signature")
+					break
+				}
+				report.Signed++
+				signedSeen = true
+			case signature != "":
+				report.UncheckedSigs++
+			case signedSeen:
+				fail("entry is not signed but earlier entries are")
+			}
+			if report.Broken != nil {
+				break
+			}
+
+			report.Entries++
+			prev = hash
+			seenLine = true
+			started = true
+		}
+		scanErr := scanner.Err()
+		f.Close()
+
+		if report.Broken != nil {
+			return report, nil
+		}
+		if scanErr != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, scanErr)
+		}
+	}
+
+	if started {
+		report.LastHash = prev
+	}
+	return report, nil
+}
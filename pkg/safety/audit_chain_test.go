@@ -0,0 +1,243 @@
+package safety
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeChainEntries(t *testing.T, logger *AuditLogger, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		entry := &AuditEntry{
+			Timestamp:       time.Now(),
+			Operation:       "github_webhooks:delete",
+			RiskLevel:       "HIGH",
+			Arguments:       map[string]interface{}{"owner": "test", "hook_id": i, "hash": "not-the-chain"},
+			Result:          "success",
+			ExecutionTimeMs: 10,
+		}
+		if err := logger.LogOperation(entry); err != nil {
+			t.Fatalf("LogOperation() error = %v", err)
+		}
+	}
+}
+
+func TestAuditChain_LinksEntries(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	logger := NewAuditLogger(logPath, true)
+	writeChainEntries(t, logger, 3)
+
+	entries, err := ReadAuditLog(logPath)
+	if err != nil {
+		t.Fatalf("ReadAuditLog() error = %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("entries = %d, want 3", len(entries))
+	}
+	if entries[0].PrevHash != "" {
+		t.Errorf("first entry prev_hash = %q, want empty", entries[0].PrevHash)
+	}
+	for i := 1; i < len(entries); i++ {
+		if entries[i].PrevHash != entries[i-1].Hash {
+			t.Errorf("entry %d prev_hash does not link to entry %d", i, i-1)
+		}
+	}
+
+	report, err := VerifyAuditChain(logPath, nil)
+	if err != nil {
+		t.Fatalf("VerifyAuditChain() error = %v", err)
+	}
+	if report.Broken != nil || report.Entries != 3 || report.LastHash != entries[2].Hash {
+		t.Errorf("report = %+v, broken = %+v", report, report.Broken)
+	}
+
+	// A new logger on the same file continues the chain
+	writeChainEntries(t, NewAuditLogger(logPath, true), 1)
+	report, _ = VerifyAuditChain(logPath, nil)
+	if report.Broken != nil || report.Entries != 4 {
+		t.Errorf("chain should continue across loggers: %+v", report.Broken)
+	}
+}
+
+func TestAuditChain_DetectsTampering(t *testing.T) {
+	tests := []struct {
+		name       string
+		tamper     func(lines [][]byte) [][]byte
+		wantLine   int
+		wantReason string
+	}{
+		{
+			name: "Modified entry",
+			tamper: func(lines [][]byte) [][]byte {
+				lines[1] = bytes.Replace(lines[1], []byte(`"success"`), []byte(`"failed"`), 1)
+				return lines
+			},
+			wantLine:   2,
+			wantReason: "modified",
+		},
+		{
+			name: "Removed entry",
+			tamper: func(lines [][]byte) [][]byte {
+				return append(lines[:1], lines[2:]...)
+			},
+			wantLine:   2,
+			wantReason: "prev_hash",
+		},
+		{
+			name: "Reordered entries",
+			tamper: func(lines [][]byte) [][]byte {
+				lines[1], lines[2] = lines[2], lines[1]
+				return lines
+			},
+			wantLine:   2,
+			wantReason: "prev_hash",
+		},
+		{
+			name: "Inserted unchained entry",
+			tamper: func(lines [][]byte) [][]byte {
+				forged := []byte(`{"operation":"forged","result":"success"}`)
+				return append(lines[:2], append([][]byte{forged}, lines[2:]...)...)
+			},
+			wantLine:   3,
+			wantReason: "no hash",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := filepath.Join(t.TempDir(), "audit.log")
+			writeChainEntries(t, NewAuditLogger(logPath, true), 4)
+
+			data, err := os.ReadFile(logPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := tt.tamper(bytes.Split(bytes.TrimRight(data, "\n"), []byte("\n")))
+			if err := os.WriteFile(logPath, append(bytes.Join(lines, []byte("\n")), '\n'), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			report, err := VerifyAuditChain(logPath, nil)
+			if err != nil {
+				t.Fatalf("VerifyAuditChain() error = %v", err)
+			}
+			if report.Broken == nil {
+				t.Fatal("tampering was not detected")
+			}
+			if report.Broken.Line != tt.wantLine || !strings.Contains(report.Broken.Reason, tt.wantReason) {
+				t.Errorf("break = line %d %q, want line %d containing %q", report.Broken.Line, report.Broken.Reason, tt.wantLine, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestAuditChain_AcrossRotation(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	logger := NewAuditLogger(logPath, true)
+	logger.maxSizeBytes = 300
+	writeChainEntries(t, logger, 12)
+
+	files, err := AuditLogFiles(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) < 3 || files[len(files)-1] != logPath {
+		t.Fatalf("expected several rotated files ending with the live log, got %v", files)
+	}
+
+	report, err := VerifyAuditChain(logPath, nil)
+	if err != nil {
+		t.Fatalf("VerifyAuditChain() error = %v", err)
+	}
+	if report.Broken != nil {
+		t.Fatalf("chain broken across rotation: %+v", report.Broken)
+	}
+
+	// A restarted logger picks up the last hash from the rotated file
+	if err := os.Remove(logPath); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := VerifyAuditChain(logPath, nil)
+	writeChainEntries(t, NewAuditLogger(logPath, true), 1)
+	after, _ := VerifyAuditChain(logPath, nil)
+	if after.Broken != nil || after.Entries != before.Entries+1 {
+		t.Errorf("restart after rotation should continue from %s.1: %+v", logPath, after.Broken)
+	}
+}
+
+func TestAuditChain_LegacyEntries(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	legacy := `{"timestamp":"2025-01-01T00:00:00Z","operation":"old","result":"success"}` + "\n"
+	if err := os.WriteFile(logPath, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeChainEntries(t, NewAuditLogger(logPath, true), 2)
+
+	report, err := VerifyAuditChain(logPath, nil)
+	if err != nil {
+		t.Fatalf("VerifyAuditChain() error = %v", err)
+	}
+	if report.Broken != nil || report.Legacy != 1 || report.Entries != 2 {
+		t.Errorf("report = %+v, broken = %+v", report, report.Broken)
+	}
+}
+
+func TestAuditChain_Signatures(t *testing.T) {
+	key := bytes.Repeat([]byte("k"), 32)
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	logger := NewAuditLogger(logPath, true)
+	logger.SetSigningKey(key)
+	writeChainEntries(t, logger, 3)
+
+	report, err := VerifyAuditChain(logPath, key)
+	if err != nil {
+		t.Fatalf("VerifyAuditChain() error = %v", err)
+	}
+	if report.Broken != nil || report.Signed != 3 {
+		t.Errorf("report = %+v, broken = %+v", report, report.Broken)
+	}
+
+	report, _ = VerifyAuditChain(logPath, nil)
+	if report.Broken != nil || report.UncheckedSigs != 3 {
+		t.Errorf("without a key signatures should be reported unchecked: %+v", report)
+	}
+
+	report, _ = VerifyAuditChain(logPath, bytes.Repeat([]byte("x"), 32))
+	if report.Broken == nil || report.Broken.Line != 1 || !strings.Contains(report.Broken.Reason, "signature") {
+		t.Errorf("wrong key should fail on the first entry: %+v", report.Broken)
+	}
+
+	// Appending an unsigned entry after signed ones is a break
+	logger.SetSigningKey(nil)
+	writeChainEntries(t, logger, 1)
+	report, _ = VerifyAuditChain(logPath, key)
+	if report.Broken == nil || report.Broken.Line != 4 {
+		t.Errorf("unsigned entry after signed ones should break the chain: %+v", report.Broken)
+	}
+}
+
+func TestLoadAuditKey(t *testing.T) {
+	t.Setenv(AuditKeyEnv, "")
+	keyPath := filepath.Join(t.TempDir(), "audit.key")
+
+	key, err := LoadAuditKey(keyPath, false)
+	if err != nil || key != nil {
+		t.Errorf("LoadAuditKey(create=false) on a missing file = %v, %v; want nil, nil", key, err)
+	}
+	if _, err := os.Stat(keyPath); !os.IsNotExist(err) {
+		t.Error("LoadAuditKey(create=false) should not create the key file")
+	}
+
+	key, err = LoadAuditKey(keyPath, true)
+	if err != nil || len(key) != minTokenKeyLength {
+		t.Fatalf("LoadAuditKey(create=true) = %d bytes, %v", len(key), err)
+	}
+	again, err := LoadAuditKey(keyPath, false)
+	if err != nil || !bytes.Equal(key, again) {
+		t.Errorf("key should be read back from the file: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package safety
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AuditQuery filters audit entries. Zero values match everything.
+type AuditQuery struct {
+	Since     time.Time
+	Until     time.Time // exclusive
+	Operation string    // exact "tool:operation", or a tool name matching all its operations
+	Repo      string    // "owner/repo" or just "repo"
+	Result    string    // success, failed, denied
+	RiskLevel string    // LOW, MEDIUM, HIGH, CRITICAL (case-insensitive)
+	Limit     int       // maximum entries returned, newest first; 0 = no limit
+}
+
+// ReadAuditLogs reads the log and all rotated files, oldest entry first.
+// A missing log yields no entries.
+func ReadAuditLogs(logPath string) ([]*AuditEntry, error) {
+	files, err := AuditLogFiles(logPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*AuditEntry
+	for _, path := range files {
+		fileEntries, err := ReadAuditLog(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue // rotated away while reading
+			}
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+	return entries, nil
+}
+
+// SearchAuditLog returns the entries across all log files matching q, newest first
+func SearchAuditLog(logPath string, q AuditQuery) ([]*AuditEntry, error) {
+	entries, err := ReadAuditLogs(logPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*AuditEntry
+	for i := len(entries) - 1; i >= 0; i-- {
+		if !q.Matches(entries[i]) {
+			continue
+		}
+		matched = append(matched, entries[i])
+		if q.Limit > 0 && len(matched) == q.Limit {
+			break
+		}
+	}
+	// Rotated files are in order already; sort in case clocks went backwards
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].Timestamp.After(matched[j].Timestamp) })
+	return matched, nil
+}
+
+// Matches reports whether an entry satisfies every filter of the query
+func (q AuditQuery) Matches(entry *AuditEntry) bool {
+	if !q.Since.IsZero() && entry.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !entry.Timestamp.Before(q.Until) {
+		return false
+	}
+	if q.Operation != "" && entry.Operation != q.Operation && !strings.HasPrefix(entry.Operation, q.Operation+":") {
+		return false
+	}
+	if q.Result != "" && entry.Result != q.Result {
+		return false
+	}
+	if q.RiskLevel != "" && !strings.EqualFold(entry.RiskLevel, q.RiskLevel) {
+		return false
+	}
+	if q.Repo != "" {
+		if strings.Contains(q.Repo, "/") {
+			if !strings.EqualFold(EntryRepo(entry), q.Repo) {
+				return false
+			}
+		} else if repo, _ := entry.Arguments["repo"].(string); !strings.EqualFold(repo, q.Repo) {
+			return false
+		}
+	}
+	return true
+}
+
+// EntryRepo returns "owner/repo" from an entry's arguments, or "" when absent
+func EntryRepo(entry *AuditEntry) string {
+	owner, _ := entry.Arguments["owner"].(string)
+	repo, _ := entry.Arguments["repo"].(string)
+	if owner == "" || repo == "" {
+		return repo
+	}
+	return owner + "/" + repo
+}
+
+// AuditStatistics summarizes entries the same way as GetStatistics, adding
+// per-repository counts and the time span covered
+func AuditStatistics(entries []*AuditEntry) map[string]interface{} {
+	stats := computeStatistics(entries)
+
+	byRepo := make(map[string]int)
+	for _, entry := range entries {
+		if repo := EntryRepo(entry); repo != "" {
+			byRepo[repo]++
+		}
+	}
+	stats["by_repo"] = byRepo
+
+	if len(entries) > 0 {
+		first, last := entries[0].Timestamp, entries[0].Timestamp
+		for _, entry := range entries[1:] {
+			if entry.Timestamp.Before(first) {
+				first = entry.Timestamp
+			}
+			if entry.Timestamp.After(last) {
+				last = entry.Timestamp
+			}
+		}
+		stats["first_entry"] = first
+		stats["last_entry"] = last
+	}
+	return stats
+}
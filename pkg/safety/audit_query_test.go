@@ -0,0 +1,123 @@
+package safety
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeQueryEntries(t *testing.T, logPath string, maxSize int64) time.Time {
+	t.Helper()
+	logger := NewAuditLogger(logPath, true)
+	if maxSize > 0 {
+		logger.maxSizeBytes = maxSize
+	}
+
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	entries := []*AuditEntry{
+		{Timestamp: base, Operation: "github_webhooks:delete", RiskLevel: "HIGH", Result: "success",
+			Arguments: map[string]interface{}{"owner": "acme", "repo": "api"}},
+		{Timestamp: base.Add(time.Hour), Operation: "github_webhooks:create", RiskLevel: "MEDIUM", Result: "failed",
+			Arguments: map[string]interface{}{"owner": "acme", "repo": "web"}},
+		{Timestamp: base.Add(24 * time.Hour), Operation: "git_sync:push", RiskLevel: "MEDIUM", Result: "denied",
+			Arguments: map[string]interface{}{"branch": "main"}},
+		{Timestamp: base.Add(48 * time.Hour), Operation: "github_admin_repo:delete", RiskLevel: "CRITICAL", Result: "success",
+			Arguments: map[string]interface{}{"owner": "other", "repo": "api"}},
+	}
+	for _, entry := range entries {
+		if err := logger.LogOperation(entry); err != nil {
+			t.Fatalf("LogOperation() error = %v", err)
+		}
+	}
+	return base
+}
+
+func TestSearchAuditLog(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	base := writeQueryEntries(t, logPath, 0)
+
+	tests := []struct {
+		name  string
+		query AuditQuery
+		want  []string
+	}{
+		{"All, newest first", AuditQuery{}, []string{"github_admin_repo:delete", "git_sync:push", "github_webhooks:create", "github_webhooks:delete"}},
+		{"Limit", AuditQuery{Limit: 2}, []string{"github_admin_repo:delete", "git_sync:push"}},
+		{"Exact operation", AuditQuery{Operation: "github_webhooks:delete"}, []string{"github_webhooks:delete"}},
+		{"Tool prefix", AuditQuery{Operation: "github_webhooks"}, []string{"github_webhooks:create", "github_webhooks:delete"}},
+		{"Tool prefix needs separator", AuditQuery{Operation: "github_web"}, nil},
+		{"Owner and repo", AuditQuery{Repo: "acme/api"}, []string{"github_webhooks:delete"}},
+		{"Repo name only", AuditQuery{Repo: "API"}, []string{"github_admin_repo:delete", "github_webhooks:delete"}},
+		{"Result", AuditQuery{Result: "denied"}, []string{"git_sync:push"}},
+		{"Risk level case-insensitive", AuditQuery{RiskLevel: "medium"}, []string{"git_sync:push", "github_webhooks:create"}},
+		{"Time range, until exclusive", AuditQuery{Since: base.Add(time.Hour), Until: base.Add(48 * time.Hour)}, []string{"git_sync:push", "github_webhooks:create"}},
+		{"Combined filters", AuditQuery{Repo: "acme/web", Result: "success"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := SearchAuditLog(logPath, tt.query)
+			if err != nil {
+				t.Fatalf("SearchAuditLog() error = %v", err)
+			}
+			if len(entries) != len(tt.want) {
+				t.Fatalf("got %d entries, want %d", len(entries), len(tt.want))
+			}
+			for i, entry := range entries {
+				if entry.Operation != tt.want[i] {
+					t.Errorf("entry %d = %s, want %s", i, entry.Operation, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSearchAuditLog_AcrossRotatedFiles(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	writeQueryEntries(t, logPath, 200)
+
+	files, err := AuditLogFiles(logPath)
+	if err != nil || len(files) < 2 {
+		t.Fatalf("expected rotated files, got %v (%v)", files, err)
+	}
+
+	entries, err := SearchAuditLog(logPath, AuditQuery{})
+	if err != nil {
+		t.Fatalf("SearchAuditLog() error = %v", err)
+	}
+	if len(entries) != 4 {
+		t.Errorf("got %d entries across %d files, want 4", len(entries), len(files))
+	}
+}
+
+func TestSearchAuditLog_MissingLog(t *testing.T) {
+	entries, err := SearchAuditLog(filepath.Join(t.TempDir(), "none.log"), AuditQuery{})
+	if err != nil || len(entries) != 0 {
+		t.Errorf("SearchAuditLog() on a missing log = %v, %v; want no entries", entries, err)
+	}
+}
+
+func TestAuditStatistics(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	base := writeQueryEntries(t, logPath, 0)
+
+	entries, err := ReadAuditLogs(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := AuditStatistics(entries)
+
+	if stats["total_entries"] != 4 {
+		t.Errorf("total_entries = %v, want 4", stats["total_entries"])
+	}
+	byRepo := stats["by_repo"].(map[string]int)
+	if byRepo["acme/api"] != 1 || byRepo["other/api"] != 1 || len(byRepo) != 3 {
+		t.Errorf("by_repo = %v", byRepo)
+	}
+	if stats["by_result"].(map[string]int)["success"] != 2 {
+		t.Errorf("by_result = %v", stats["by_result"])
+	}
+	if !stats["first_entry"].(time.Time).Equal(base) || !stats["last_entry"].(time.Time).Equal(base.Add(48*time.Hour)) {
+		t.Errorf("time span = %v - %v", stats["first_entry"], stats["last_entry"])
+	}
+}
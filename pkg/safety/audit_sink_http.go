@@ -0,0 +1,163 @@
+package safety
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// newHTTPDelivery POSTs batches as a JSON array of entries (http) or as an
+// OTLP/HTTP JSON logs request (otlp). Header values may reference environment
+// variables ("Bearer ${AUDIT_TOKEN}") so secrets stay out of safety.json.
+func newHTTPDelivery(cfg AuditSinkConfig) (func(ctx context.Context, entries []*AuditEntry) error, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("audit sink %s: url must be an absolute http(s) URL, got %q", cfg.Name, cfg.URL)
+	}
+
+	headers := make(map[string]string, len(cfg.Headers))
+	for k, v := range cfg.Headers {
+		headers[k] = os.ExpandEnv(v)
+	}
+
+	encode := func(entries []*AuditEntry) ([]byte, error) {
+		return json.Marshal(entries)
+	}
+	if cfg.Type == AuditSinkOTLP {
+		encode = func(entries []*AuditEntry) ([]byte, error) {
+			return json.Marshal(otlpLogsRequest(entries, cfg.AppName))
+		}
+	}
+
+	client := &http.Client{}
+	return func(ctx context.Context, entries []*AuditEntry) error {
+		body, err := encode(entries)
+		if err != nil {
+			return permanentError{fmt.Errorf("failed to encode audit batch: %w", err)}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+		if err != nil {
+			return permanentError{err}
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		statusErr := fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+		// Client errors other than throttling will fail the same way again
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusRequestTimeout {
+			return permanentError{statusErr}
+		}
+		return statusErr
+	}, nil
+}
+
+// OTLP/HTTP JSON encoding of the logs export request
+// (opentelemetry-proto, collector/logs/v1/logs_service.proto)
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano   string         `json:"timeUnixNano"`
+	SeverityNumber int            `json:"severityNumber"`
+	SeverityText   string         `json:"severityText"`
+	Body           otlpAnyValue   `json:"body"`
+	Attributes     []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      map[string]string `json:"scope"`
+	LogRecords []otlpLogRecord   `json:"logRecords"`
+}
+
+type otlpResourceLogs struct {
+	Resource  map[string][]otlpKeyValue `json:"resource"`
+	ScopeLogs []otlpScopeLogs           `json:"scopeLogs"`
+}
+
+type otlpExportLogsRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+func otlpLogsRequest(entries []*AuditEntry, serviceName string) otlpExportLogsRequest {
+	records := make([]otlpLogRecord, 0, len(entries))
+	for _, entry := range entries {
+		body, _ := json.Marshal(entry)
+		number, text := otlpSeverity(entry.RiskLevel)
+
+		attrs := []otlpKeyValue{
+			{Key: "audit.operation", Value: otlpAnyValue{entry.Operation}},
+			{Key: "audit.result", Value: otlpAnyValue{entry.Result}},
+			{Key: "audit.risk_level", Value: otlpAnyValue{entry.RiskLevel}},
+		}
+		if repo := EntryRepo(entry); repo != "" {
+			attrs = append(attrs, otlpKeyValue{Key: "audit.repo", Value: otlpAnyValue{repo}})
+		}
+		if entry.PolicyRule != "" {
+			attrs = append(attrs, otlpKeyValue{Key: "audit.policy_rule", Value: otlpAnyValue{entry.PolicyRule}})
+		}
+		if entry.Hash != "" {
+			attrs = append(attrs, otlpKeyValue{Key: "audit.hash", Value: otlpAnyValue{entry.Hash}})
+		}
+
+		records = append(records, otlpLogRecord{
+			TimeUnixNano:   strconv.FormatInt(entry.Timestamp.UnixNano(), 10),
+			SeverityNumber: number,
+			SeverityText:   text,
+			Body:           otlpAnyValue{string(body)},
+			Attributes:     attrs,
+		})
+	}
+
+	return otlpExportLogsRequest{
+		ResourceLogs: []otlpResourceLogs{{
+			Resource: map[string][]otlpKeyValue{
+				"attributes": {{Key: "service.name", Value: otlpAnyValue{serviceName}}},
+			},
+			ScopeLogs: []otlpScopeLogs{{
+				Scope:      map[string]string{"name": "github-mcp-server/audit"},
+				LogRecords: records,
+			}},
+		}},
+	}
+}
+
+// otlpSeverity maps risk levels to OTLP severity numbers
+func otlpSeverity(riskLevel string) (int, string) {
+	switch strings.ToUpper(riskLevel) {
+	case "CRITICAL":
+		return 17, "ERROR"
+	case "HIGH":
+		return 13, "WARN"
+	case "MEDIUM":
+		return 10, "INFO2"
+	default:
+		return 9, "INFO"
+	}
+}
@@ -0,0 +1,142 @@
+package safety
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// syslogFacilityLogAudit is facility 13 ("log audit") from RFC 5424
+	syslogFacilityLogAudit = 13
+
+	// syslogSDID is the structured data ID; 32473 is the enterprise number
+	// reserved for documentation (RFC 5612)
+	syslogSDID = "mcp@32473"
+)
+
+// newSyslogDelivery sends each entry as an RFC 5424 message. UDP sends one
+// datagram per message; TCP uses octet-counting framing (RFC 6587).
+func newSyslogDelivery(cfg AuditSinkConfig) (func(ctx context.Context, entries []*AuditEntry) error, error) {
+	network := cfg.Network
+	if network == "" {
+		network = "udp"
+	}
+	if network != "udp" && network != "tcp" {
+		return nil, fmt.Errorf("audit sink %s: syslog network must be udp or tcp, got %q", cfg.Name, network)
+	}
+	if _, _, err := net.SplitHostPort(cfg.Address); err != nil {
+		return nil, fmt.Errorf("audit sink %s: syslog address must be host:port: %w", cfg.Name, err)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	procID := strconv.Itoa(os.Getpid())
+
+	return func(ctx context.Context, entries []*AuditEntry) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, network, cfg.Address)
+		if err != nil {
+			return fmt.Errorf("syslog dial: %w", err)
+		}
+		defer conn.Close()
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+
+		for _, entry := range entries {
+			msg, err := formatSyslogMessage(entry, hostname, cfg.AppName, procID)
+			if err != nil {
+				return permanentError{err}
+			}
+			if network == "tcp" {
+				msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+			}
+			if _, err := conn.Write(msg); err != nil {
+				return fmt.Errorf("syslog write: %w", err)
+			}
+		}
+		return nil
+	}, nil
+}
+
+// formatSyslogMessage renders an entry as an RFC 5424 message:
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD] MSG
+// MSG is the JSON entry, so the receiver gets the same record as the local log.
+func formatSyslogMessage(entry *AuditEntry, hostname, appName, procID string) ([]byte, error) {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	pri := syslogFacilityLogAudit*8 + syslogSeverity(entry.RiskLevel)
+	timestamp := entry.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	sd := fmt.Sprintf(`[%s result="%s" risk="%s"`, syslogSDID, escapeSDParam(entry.Result), escapeSDParam(entry.RiskLevel))
+	if repo := EntryRepo(entry); repo != "" {
+		sd += fmt.Sprintf(` repo="%s"`, escapeSDParam(repo))
+	}
+	if entry.Hash != "" {
+		sd += fmt.Sprintf(` hash="%s"`, entry.Hash)
+	}
+	sd += "]"
+
+	header := fmt.Sprintf("<%d>1 %s %s %s %s %s %s ",
+		pri,
+		timestamp.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogHeaderField(hostname, 255),
+		syslogHeaderField(appName, 48),
+		syslogHeaderField(procID, 128),
+		syslogHeaderField(entry.Operation, 32),
+		sd,
+	)
+	return append([]byte(header), body...), nil
+}
+
+// syslogSeverity maps risk levels to RFC 5424 severities
+func syslogSeverity(riskLevel string) int {
+	switch strings.ToUpper(riskLevel) {
+	case "CRITICAL":
+		return 2 // critical
+	case "HIGH":
+		return 4 // warning
+	case "MEDIUM":
+		return 5 // notice
+	default:
+		return 6 // informational
+	}
+}
+
+// syslogHeaderField returns a header field as printable ASCII without spaces,
+// truncated to max, or the NILVALUE "-" when empty
+func syslogHeaderField(value string, max int) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r > 32 && r < 127 {
+			b.WriteRune(r)
+		}
+	}
+	field := b.String()
+	if field == "" {
+		return "-"
+	}
+	if len(field) > max {
+		field = field[:max]
+	}
+	return field
+}
+
+// escapeSDParam escapes '"', '\' and ']' in a structured data value
+func escapeSDParam(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+}
@@ -0,0 +1,236 @@
+package safety
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Audit sink types
+const (
+	AuditSinkSyslog = "syslog" // RFC 5424 over UDP or TCP
+	AuditSinkHTTP   = "http"   // JSON array of entries POSTed to a URL
+	AuditSinkOTLP   = "otlp"   // OTLP/HTTP JSON log export
+)
+
+const (
+	defaultSinkQueueSize     = 1000
+	defaultSinkBatchSize     = 50
+	defaultSinkFlushInterval = 2 * time.Second
+	defaultSinkMaxRetries    = 3
+	defaultSinkRetryBackoff  = 500 * time.Millisecond
+	defaultSinkTimeout       = 5 * time.Second
+	sinkCloseTimeout         = 5 * time.Second
+)
+
+// AuditSink forwards audit entries to an external system. Send must not block:
+// the local log write never waits on a sink.
+type AuditSink interface {
+	Name() string
+	Send(entry *AuditEntry)
+	Close() error
+}
+
+// AuditSinkConfig configures one audit sink
+type AuditSinkConfig struct {
+	Type          string            // AuditSinkSyslog, AuditSinkHTTP or AuditSinkOTLP
+	Name          string            // label for warnings; defaults to the type
+	Network       string            // syslog: udp (default) or tcp
+	Address       string            // syslog: host:port
+	URL           string            // http, otlp: endpoint (otlp: .../v1/logs)
+	Headers       map[string]string // http, otlp: extra request headers
+	AppName       string            // syslog APP-NAME and OTLP service.name
+	BatchSize     int               // entries per delivery
+	FlushInterval time.Duration     // maximum time an entry waits for its batch
+	MaxRetries    int               // delivery attempts after the first failure
+	Timeout       time.Duration     // per delivery attempt
+	QueueSize     int               // entries buffered before new ones are dropped
+}
+
+// permanentError marks a delivery failure that retrying cannot fix
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// NewAuditSink creates a sink from its configuration
+func NewAuditSink(cfg AuditSinkConfig) (AuditSink, error) {
+	if cfg.Name == "" {
+		cfg.Name = cfg.Type
+	}
+	if cfg.AppName == "" {
+		cfg.AppName = "github-mcp-server"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultSinkTimeout
+	}
+
+	var deliver func(ctx context.Context, entries []*AuditEntry) error
+	switch cfg.Type {
+	case AuditSinkSyslog:
+		d, err := newSyslogDelivery(cfg)
+		if err != nil {
+			return nil, err
+		}
+		deliver = d
+	case AuditSinkHTTP, AuditSinkOTLP:
+		d, err := newHTTPDelivery(cfg)
+		if err != nil {
+			return nil, err
+		}
+		deliver = d
+	default:
+		return nil, fmt.Errorf("unknown audit sink type: %s (allowed: syslog, http, otlp)", cfg.Type)
+	}
+
+	return newAsyncSink(cfg, deliver), nil
+}
+
+// asyncSink queues entries and delivers them in batches from a background
+// goroutine, retrying with exponential backoff. When the queue is full new
+// entries are dropped rather than blocking the caller.
+type asyncSink struct {
+	name          string
+	deliver       func(ctx context.Context, entries []*AuditEntry) error
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+	retryBackoff  time.Duration
+	timeout       time.Duration
+
+	mu      sync.RWMutex
+	closed  bool
+	queue   chan *AuditEntry
+	done    chan struct{}
+	dropped atomic.Int64
+}
+
+func newAsyncSink(cfg AuditSinkConfig, deliver func(ctx context.Context, entries []*AuditEntry) error) *asyncSink {
+	s := &asyncSink{
+		name:          cfg.Name,
+		deliver:       deliver,
+		batchSize:     cfg.BatchSize,
+		flushInterval: cfg.FlushInterval,
+		maxRetries:    cfg.MaxRetries,
+		retryBackoff:  defaultSinkRetryBackoff,
+		timeout:       cfg.Timeout,
+		done:          make(chan struct{}),
+	}
+	if s.batchSize <= 0 {
+		s.batchSize = defaultSinkBatchSize
+	}
+	if s.flushInterval <= 0 {
+		s.flushInterval = defaultSinkFlushInterval
+	}
+	if s.maxRetries < 0 {
+		s.maxRetries = 0
+	} else if s.maxRetries == 0 {
+		s.maxRetries = defaultSinkMaxRetries
+	}
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultSinkQueueSize
+	}
+	s.queue = make(chan *AuditEntry, queueSize)
+
+	go s.run()
+	return s
+}
+
+// Name implements AuditSink
+func (s *asyncSink) Name() string {
+	return s.name
+}
+
+// Send implements AuditSink
+func (s *asyncSink) Send(entry *AuditEntry) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.queue <- entry:
+	default:
+		if dropped := s.dropped.Add(1); dropped%100 == 1 {
+			log.Printf("Warning: audit sink %s queue full, dropping entries (%d so far)", s.name, dropped)
+		}
+	}
+}
+
+// Close stops accepting entries and flushes the queue, waiting at most sinkCloseTimeout
+func (s *asyncSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.queue)
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-time.After(sinkCloseTimeout):
+		return fmt.Errorf("audit sink %s: timed out flushing pending entries", s.name)
+	}
+}
+
+func (s *asyncSink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	var batch []*AuditEntry
+	for {
+		select {
+		case entry, ok := <-s.queue:
+			if !ok {
+				s.flush(batch)
+				return
+			}
+			batch = append(batch, entry)
+			if len(batch) >= s.batchSize {
+				s.flush(batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				s.flush(batch)
+				batch = nil
+			}
+		}
+	}
+}
+
+// flush delivers a batch, retrying transient failures
+func (s *asyncSink) flush(batch []*AuditEntry) {
+	if len(batch) == 0 {
+		return
+	}
+
+	var err error
+	for attempt := 0; attempt <= s.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(s.retryBackoff << (attempt - 1))
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		err = s.deliver(ctx, batch)
+		cancel()
+		if err == nil {
+			return
+		}
+		var permanent permanentError
+		if errors.As(err, &permanent) {
+			break
+		}
+	}
+	log.Printf("Warning: audit sink %s failed to deliver %d entries: %v", s.name, len(batch), err)
+}
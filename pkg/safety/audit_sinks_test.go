@@ -0,0 +1,224 @@
+package safety
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func sinkTestEntry(op string) *AuditEntry {
+	return &AuditEntry{
+		Timestamp: time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC),
+		Operation: op,
+		RiskLevel: "HIGH",
+		Arguments: map[string]interface{}{"owner": "acme", "repo": "api"},
+		Result:    "success",
+	}
+}
+
+func TestFormatSyslogMessage(t *testing.T) {
+	entry := sinkTestEntry("github_webhooks:delete")
+	entry.Result = `odd"]\value`
+
+	msg, err := formatSyslogMessage(entry, "host", "github-mcp-server", "42")
+	if err != nil {
+		t.Fatalf("formatSyslogMessage() error = %v", err)
+	}
+
+	// facility 13 (log audit) * 8 + severity 4 (warning) = 108
+	header := regexp.MustCompile(`^<108>1 2026-10-18T09:30:00\.000000Z host github-mcp-server 42 github_webhooks:delete \[mcp@32473 result="odd\\"\\]\\\\value" risk="HIGH" repo="acme/api"\] \{`)
+	if !header.Match(msg) {
+		t.Errorf("unexpected syslog message: %s", msg)
+	}
+
+	var decoded AuditEntry
+	body := msg[strings.Index(string(msg), "] {")+2:]
+	if err := json.Unmarshal(body, &decoded); err != nil || decoded.Operation != entry.Operation {
+		t.Errorf("MSG should be the JSON entry: %v", err)
+	}
+}
+
+func TestSyslogSink_UDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("UDP not available: %v", err)
+	}
+	defer conn.Close()
+
+	sink, err := NewAuditSink(AuditSinkConfig{Type: AuditSinkSyslog, Address: conn.LocalAddr().String(), FlushInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewAuditSink() error = %v", err)
+	}
+	sink.Send(sinkTestEntry("git_sync:push"))
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	buf := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("no syslog datagram received: %v", err)
+	}
+	if !strings.HasPrefix(string(buf[:n]), "<108>1 ") || !strings.Contains(string(buf[:n]), "git_sync:push") {
+		t.Errorf("unexpected datagram: %s", buf[:n])
+	}
+}
+
+func TestHTTPSink_BatchesAndRetries(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]AuditEntry
+	attempts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts == 1 {
+			http.Error(w, "try again", http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			t.Errorf("Authorization header = %q", r.Header.Get("Authorization"))
+		}
+		var batch []AuditEntry
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Errorf("invalid batch: %v", err)
+		}
+		batches = append(batches, batch)
+	}))
+	defer server.Close()
+
+	t.Setenv("TEST_AUDIT_TOKEN", "s3cret")
+	sink, err := NewAuditSink(AuditSinkConfig{
+		Type:          AuditSinkHTTP,
+		URL:           server.URL,
+		Headers:       map[string]string{"Authorization": "Bearer ${TEST_AUDIT_TOKEN}"},
+		BatchSize:     3,
+		FlushInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewAuditSink() error = %v", err)
+	}
+	sink.(*asyncSink).retryBackoff = time.Millisecond
+
+	for _, op := range []string{"a", "b", "c", "d"} {
+		sink.Send(sinkTestEntry(op))
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3 (failed batch retried once, then final flush)", attempts)
+	}
+	if len(batches) != 2 || len(batches[0]) != 3 || len(batches[1]) != 1 || batches[1][0].Operation != "d" {
+		t.Errorf("batches = %+v", batches)
+	}
+}
+
+func TestHTTPSink_ClientErrorNotRetried(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sink, err := NewAuditSink(AuditSinkConfig{Type: AuditSinkHTTP, URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.(*asyncSink).retryBackoff = time.Millisecond
+	sink.Send(sinkTestEntry("a"))
+	sink.Close()
+
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestOTLPSink(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	sink, err := NewAuditSink(AuditSinkConfig{Type: AuditSinkOTLP, URL: server.URL + "/v1/logs", AppName: "mcp-test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.Send(sinkTestEntry("github_admin_repo:delete"))
+	sink.Close()
+
+	var req otlpExportLogsRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		t.Fatalf("invalid OTLP request: %v (%s)", err, body)
+	}
+	if len(req.ResourceLogs) != 1 || req.ResourceLogs[0].Resource["attributes"][0].Value.StringValue != "mcp-test" {
+		t.Fatalf("unexpected resource: %s", body)
+	}
+	records := req.ResourceLogs[0].ScopeLogs[0].LogRecords
+	if len(records) != 1 || records[0].SeverityText != "WARN" || records[0].TimeUnixNano != "1792315800000000000" {
+		t.Errorf("unexpected log records: %+v", records)
+	}
+}
+
+func TestNewAuditSink_InvalidConfig(t *testing.T) {
+	configs := []AuditSinkConfig{
+		{Type: "kafka"},
+		{Type: AuditSinkSyslog, Address: "no-port"},
+		{Type: AuditSinkSyslog, Address: "localhost:514", Network: "unix"},
+		{Type: AuditSinkHTTP, URL: "ftp://example.com"},
+		{Type: AuditSinkOTLP, URL: "/v1/logs"},
+	}
+	for _, cfg := range configs {
+		if _, err := NewAuditSink(cfg); err == nil {
+			t.Errorf("NewAuditSink(%+v) should fail", cfg)
+		}
+	}
+}
+
+func TestAuditLogger_SinkFailureDoesNotBlockWrite(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		http.Error(w, "down", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	sink, err := NewAuditSink(AuditSinkConfig{Type: AuditSinkHTTP, URL: server.URL, BatchSize: 1, QueueSize: 1, MaxRetries: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	logger := NewAuditLogger(logPath, true)
+	logger.SetSinks([]AuditSink{sink})
+	defer logger.Close()
+
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		if err := logger.LogOperation(sinkTestEntry("op")); err != nil {
+			t.Fatalf("LogOperation() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("LogOperation waited on the sink: %v", elapsed)
+	}
+
+	entries, err := ReadAuditLog(logPath)
+	if err != nil || len(entries) != 20 {
+		t.Errorf("local log has %d entries (%v), want 20", len(entries), err)
+	}
+}
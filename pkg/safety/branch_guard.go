@@ -0,0 +1,117 @@
+package safety
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// ProtectedBranchRuleLabel is recorded as the deciding rule when the
+// protected-branch guard (rather than an explicit policy rule) stops an operation
+const ProtectedBranchRuleLabel = "protected_branches"
+
+// remoteProtectionTTL bounds how long a remote branch protection lookup is reused
+const remoteProtectionTTL = 5 * time.Minute
+
+// branchWriteOperations are the local operations that write directly to a branch.
+// The "branch" parameter must carry the branch being written (the merge target for
+// git_branch:merge and git_conflict:safe_merge, the current branch for cherry_pick,
+// revert and rebase_interactive).
+var branchWriteOperations = map[string]bool{
+	"git_sync:push":                 true,
+	"git_sync:force_push":           true,
+	"git_sync:push_upstream":        true,
+	"git_conflict:safe_merge":       true,
+	"gh_push_files":                 true,
+	"git_branch:merge":              true,
+	"git_branch:cherry_pick":        true,
+	"git_branch:revert":             true,
+	"git_branch:rebase_interactive": true,
+}
+
+// RemoteProtectionLookup reports whether a branch is protected on GitHub
+type RemoteProtectionLookup func(ctx context.Context, owner, repo, branch string) (bool, error)
+
+type remoteProtectionEntry struct {
+	protected bool
+	expiresAt time.Time
+}
+
+// branchGuard holds the remote protection lookup and its cache
+type branchGuard struct {
+	mu     sync.Mutex
+	lookup RemoteProtectionLookup
+	cache  map[string]remoteProtectionEntry
+}
+
+// IsBranchWriteOperation reports whether the operation writes directly to a branch
+func IsBranchWriteOperation(operation string) bool {
+	return branchWriteOperations[operation]
+}
+
+// SetRemoteProtectionLookup installs the function used to mirror remote branch
+// protection when MirrorRemoteProtection is enabled
+func (e *Engine) SetRemoteProtectionLookup(lookup RemoteProtectionLookup) {
+	e.guard.mu.Lock()
+	defer e.guard.mu.Unlock()
+	e.guard.lookup = lookup
+	e.guard.cache = make(map[string]remoteProtectionEntry)
+}
+
+// protectedBranchReason explains why a branch write hits a protected branch,
+// or returns "" when the branch is not protected
+func (e *Engine) protectedBranchReason(ctx context.Context, operation string, parameters map[string]interface{}) string {
+	if !IsBranchWriteOperation(operation) {
+		return ""
+	}
+	branch, _ := parameters["branch"].(string)
+	if branch == "" {
+		return ""
+	}
+
+	for _, pattern := range e.config.ProtectedBranches {
+		if matchAnyGlob([]string{pattern}, branch) {
+			return fmt.Sprintf("branch '%s' matches protected pattern '%s'", branch, pattern)
+		}
+	}
+
+	if !e.config.MirrorRemoteProtection {
+		return ""
+	}
+	owner, _ := parameters["owner"].(string)
+	repo, _ := parameters["repo"].(string)
+	if owner == "" || repo == "" {
+		return ""
+	}
+	if e.isRemoteProtected(ctx, owner, repo, branch) {
+		return fmt.Sprintf("branch '%s' is protected on GitHub (%s/%s)", branch, owner, repo)
+	}
+	return ""
+}
+
+// isRemoteProtected consults the cached remote lookup. Lookup failures are
+// logged and treated as unprotected: the local patterns remain the primary guard.
+func (e *Engine) isRemoteProtected(ctx context.Context, owner, repo, branch string) bool {
+	e.guard.mu.Lock()
+	defer e.guard.mu.Unlock()
+
+	if e.guard.lookup == nil {
+		return false
+	}
+
+	key := owner + "/" + repo + ":" + branch
+	if entry, ok := e.guard.cache[key]; ok && time.Now().Before(entry.expiresAt) {
+		return entry.protected
+	}
+
+	protected, err := e.guard.lookup(ctx, owner, repo, branch)
+	if err != nil {
+		log.Printf("Warning: remote branch protection lookup failed for %s: %v", key, err)
+		return false
+	}
+
+	e.guard.cache[key] = remoteProtectionEntry{protected: protected, expiresAt: time.Now().Add(remoteProtectionTTL)}
+	return protected
+}
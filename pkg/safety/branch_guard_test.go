@@ -0,0 +1,158 @@
+package safety
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEngine_CheckOperation_ProtectedBranches(t *testing.T) {
+	ctx := context.Background()
+
+	engine := NewEngine(&SafetyConfig{
+		Mode:              SafetyModeModerate,
+		ProtectedBranches: []string{"main", "release/*"},
+	})
+
+	tests := []struct {
+		name        string
+		operation   string
+		params      map[string]interface{}
+		wantProceed bool
+	}{
+		{"Push to main denied", "git_sync:push", map[string]interface{}{"branch": "main"}, false},
+		{"Force push to release denied", "git_sync:force_push", map[string]interface{}{"branch": "release/2.0"}, false},
+		{"Push files to main denied", "gh_push_files", map[string]interface{}{"branch": "main"}, false},
+		{"Merge into main denied", "git_branch:merge", map[string]interface{}{"branch": "main"}, false},
+		{"Safe merge into main denied", "git_conflict:safe_merge", map[string]interface{}{"branch": "main"}, false},
+		{"Push upstream to main denied", "git_sync:push_upstream", map[string]interface{}{"branch": "main"}, false},
+		{"Cherry-pick onto main denied", "git_branch:cherry_pick", map[string]interface{}{"branch": "main"}, false},
+		{"Revert on release denied", "git_branch:revert", map[string]interface{}{"branch": "release/2.0"}, false},
+		{"Interactive rebase of main denied", "git_branch:rebase_interactive", map[string]interface{}{"branch": "main"}, false},
+		{"Feature branch allowed", "git_sync:push", map[string]interface{}{"branch": "feature/login"}, true},
+		{"Nested release branch not matched", "git_sync:push", map[string]interface{}{"branch": "release/2.0/hotfix"}, true},
+		{"Non-write operation unaffected", "git_sync:pull", map[string]interface{}{"branch": "main"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check, err := engine.CheckOperation(ctx, tt.operation, tt.params)
+			if err != nil {
+				t.Fatalf("CheckOperation() error = %v", err)
+			}
+			if check.CanProceed != tt.wantProceed {
+				t.Errorf("CanProceed = %v, want %v (message: %s)", check.CanProceed, tt.wantProceed, check.Message)
+			}
+			if !tt.wantProceed {
+				if check.PolicyRule != ProtectedBranchRuleLabel {
+					t.Errorf("PolicyRule = %q, want %q", check.PolicyRule, ProtectedBranchRuleLabel)
+				}
+				if !strings.Contains(check.Message, "pull request") {
+					t.Errorf("Message should suggest a pull request: %s", check.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestEngine_CheckOperation_ProtectedBranchesConfirmation(t *testing.T) {
+	ctx := context.Background()
+	ClearAllTokens()
+
+	engine := NewEngine(&SafetyConfig{
+		Mode:                  SafetyModeModerate,
+		ProtectedBranches:     []string{"main"},
+		ProtectedBranchAction: PolicyRequireConfirmation,
+	})
+
+	params := map[string]interface{}{"branch": "main"}
+	check, err := engine.CheckOperation(ctx, "git_sync:push", params)
+	if err != nil {
+		t.Fatalf("CheckOperation() error = %v", err)
+	}
+	if check.CanProceed || !check.RequiresConfirmation {
+		t.Fatalf("push to main should require confirmation, got %+v", check)
+	}
+	if !strings.Contains(check.Message, "main") {
+		t.Errorf("Message should name the protected branch: %s", check.Message)
+	}
+
+	tokenStart := strings.Index(check.Message, TokenPrefix)
+	if tokenStart == -1 {
+		t.Fatalf("token not found in message: %s", check.Message)
+	}
+	params["confirmation_token"] = strings.Fields(check.Message[tokenStart:])[0]
+	check, err = engine.CheckOperation(ctx, "git_sync:push", params)
+	if err != nil || !check.CanProceed {
+		t.Errorf("confirmed push should proceed: err=%v message=%s", err, check.Message)
+	}
+}
+
+func TestEngine_CheckOperation_PolicyRuleBeforeProtectedBranches(t *testing.T) {
+	engine := NewEngine(&SafetyConfig{
+		Mode:              SafetyModeModerate,
+		ProtectedBranches: []string{"main"},
+		Rules: []PolicyRule{
+			{Name: "no-push", Action: PolicyDeny, Operations: []string{"git_sync:push"}},
+		},
+	})
+
+	check, err := engine.CheckOperation(context.Background(), "git_sync:push", map[string]interface{}{"branch": "main"})
+	if err != nil {
+		t.Fatalf("CheckOperation() error = %v", err)
+	}
+	if check.CanProceed || check.PolicyRule != "no-push" {
+		t.Errorf("explicit rule should decide first, got rule %q", check.PolicyRule)
+	}
+}
+
+func TestEngine_RemoteProtection(t *testing.T) {
+	ctx := context.Background()
+
+	engine := NewEngine(&SafetyConfig{
+		Mode:                   SafetyModeModerate,
+		MirrorRemoteProtection: true,
+	})
+
+	calls := 0
+	engine.SetRemoteProtectionLookup(func(ctx context.Context, owner, repo, branch string) (bool, error) {
+		calls++
+		switch branch {
+		case "main":
+			return true, nil
+		case "flaky":
+			return false, errors.New("api unavailable")
+		}
+		return false, nil
+	})
+
+	params := map[string]interface{}{"owner": "acme", "repo": "api", "branch": "main"}
+	for i := 0; i < 2; i++ {
+		check, err := engine.CheckOperation(ctx, "git_sync:push", params)
+		if err != nil {
+			t.Fatalf("CheckOperation() error = %v", err)
+		}
+		if check.CanProceed {
+			t.Error("branch protected on GitHub should be denied")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("lookup calls = %d, want 1 (cached)", calls)
+	}
+
+	check, _ := engine.CheckOperation(ctx, "git_sync:push", map[string]interface{}{"owner": "acme", "repo": "api", "branch": "dev"})
+	if !check.CanProceed {
+		t.Errorf("unprotected branch should proceed: %s", check.Message)
+	}
+
+	check, _ = engine.CheckOperation(ctx, "git_sync:push", map[string]interface{}{"owner": "acme", "repo": "api", "branch": "flaky"})
+	if !check.CanProceed {
+		t.Errorf("lookup failure should fail open: %s", check.Message)
+	}
+
+	check, _ = engine.CheckOperation(ctx, "git_sync:push", map[string]interface{}{"branch": "main"})
+	if !check.CanProceed {
+		t.Errorf("without owner/repo the remote lookup is skipped: %s", check.Message)
+	}
+}
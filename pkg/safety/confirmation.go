@@ -1,116 +1,294 @@
 package safety
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
-	"sync"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
-// ConfirmationToken represents a single-use token for confirming high-risk operations
+// ConfirmationToken represents a single-use token for confirming high-risk operations.
+// The token string is self-contained: it carries the operation, a hash of the
+// request parameters, the expiry and a nonce, signed with the server key.
 type ConfirmationToken struct {
-	Token      string                 // Hex-encoded token (CONF:abc123...)
+	Token      string                 // Signed token (CONF:<payload>.<signature>)
 	Operation  string                 // Tool name
-	Parameters map[string]interface{} // Sanitized parameters
-	ExpiresAt  time.Time              // Token expiration (5 minutes)
+	Parameters map[string]interface{} // Sanitized parameters (for display only)
+	ExpiresAt  time.Time              // Token expiration (per risk level)
 	RiskLevel  RiskLevel              // Risk level of operation
-	Used       bool                   // Whether token has been used
+	Nonce      string                 // Random nonce, recorded on use to prevent replay
 	CreatedAt  time.Time              // Creation timestamp
 }
 
-// tokenStore manages active confirmation tokens
-type tokenStore struct {
-	mu     sync.RWMutex
-	tokens map[string]*ConfirmationToken
-}
-
-var store = &tokenStore{
-	tokens: make(map[string]*ConfirmationToken),
+// tokenPayload is the signed part of a confirmation token
+type tokenPayload struct {
+	Operation string `json:"op"`
+	ParamHash string `json:"ph"` // every parameter except controlKeys
+	Risk      int    `json:"r"`
+	Expires   int64  `json:"exp"`
+	Nonce     string `json:"n"`
 }
 
 const (
-	// TokenExpiration is the duration before a token expires
+	// TokenExpiration is the default duration before a token expires
 	TokenExpiration = 5 * time.Minute
 
 	// TokenPrefix is prepended to all tokens for identification
 	TokenPrefix = "CONF:"
+
+	// TokenKeyEnv overrides the signing key file. Instances that share a key
+	// accept each other's tokens.
+	TokenKeyEnv = "MCP_CONFIRMATION_KEY"
+
+	// DefaultTokenKeyPath is where the signing key is created when safety.json exists
+	DefaultTokenKeyPath = "./.mcp-confirmation.key"
+
+	// DefaultTokenReplayPath is where used nonces are recorded when safety.json exists
+	DefaultTokenReplayPath = "./.mcp-confirmation-nonces.json"
+
+	// minTokenKeyLength is the minimum signing key size in bytes
+	minTokenKeyLength = 32
 )
 
-// GenerateConfirmationToken creates a new confirmation token for a high-risk operation
-func GenerateConfirmationToken(operation string, parameters map[string]interface{}, riskLevel RiskLevel) (*ConfirmationToken, error) {
-	// Generate random bytes for token
-	randomBytes := make([]byte, 16)
-	if _, err := rand.Read(randomBytes); err != nil {
+// controlKeys steer the confirmation flow itself and are left out of the
+// parameter hash; every other argument is bound to the token
+var controlKeys = map[string]bool{"confirmation_token": true, "dry_run": true, "approval_id": true, "workspace": true}
+
+// TokenSigner issues and verifies signed confirmation tokens
+type TokenSigner struct {
+	key         []byte
+	replay      ReplayStore
+	expirations map[RiskLevel]time.Duration
+	approvals   *ApprovalQueue // operations whose tokens need human approval; nil for none
+}
+
+// NewTokenSigner creates a signer. Risk levels missing from expirations use TokenExpiration.
+func NewTokenSigner(key []byte, replay ReplayStore, expirations map[RiskLevel]time.Duration) *TokenSigner {
+	return &TokenSigner{
+		key:         key,
+		replay:      replay,
+		expirations: expirations,
+	}
+}
+
+// processTokenKey signs tokens when no key is configured; such tokens do not survive a restart
+var processTokenKey = mustRandomBytes(minTokenKeyLength)
+
+// defaultReplay records used nonces for signers without a replay file
+var defaultReplay = NewMemoryReplayStore()
+
+var defaultSigner = NewTokenSigner(processTokenKey, defaultReplay, nil)
+
+// SetApprovalQueue gates the queue's operations: their tokens only validate
+// once the matching approval request has been approved
+func (s *TokenSigner) SetApprovalQueue(queue *ApprovalQueue) {
+	s.approvals = queue
+}
+
+// Expiration returns the token lifetime for a risk level
+func (s *TokenSigner) Expiration(level RiskLevel) time.Duration {
+	if d, ok := s.expirations[level]; ok && d > 0 {
+		return d
+	}
+	return TokenExpiration
+}
+
+// Generate creates a new signed confirmation token for a high-risk operation
+func (s *TokenSigner) Generate(operation string, parameters map[string]interface{}, riskLevel RiskLevel) (*ConfirmationToken, error) {
+	nonceBytes := make([]byte, 16)
+	if _, err := rand.Read(nonceBytes); err != nil {
 		return nil, fmt.Errorf("failed to generate random token: %w", err)
 	}
 
-	// Create hash of operation + params + timestamp + random
-	timestamp := time.Now().Unix()
-	hashInput := fmt.Sprintf("%s:%v:%d:%x", operation, parameters, timestamp, randomBytes)
-	hash := sha256.Sum256([]byte(hashInput))
-	tokenID := hex.EncodeToString(hash[:])[:12] // Use first 12 chars
+	now := time.Now()
+	paramHash, err := hashParams(parameters)
+	if err != nil {
+		return nil, err
+	}
 
-	token := &ConfirmationToken{
-		Token:      TokenPrefix + tokenID,
+	// Tokens waiting for a human live as long as the approval request
+	lifetime := s.Expiration(riskLevel)
+	if s.approvals.Requires(operation) {
+		lifetime = s.approvals.Expiration()
+	}
+
+	payload := tokenPayload{
+		Operation: operation,
+		ParamHash: paramHash,
+		Risk:      int(riskLevel),
+		Expires:   now.Add(lifetime).Unix(),
+		Nonce:     hex.EncodeToString(nonceBytes),
+	}
+
+	tokenStr, err := s.encode(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConfirmationToken{
+		Token:      tokenStr,
 		Operation:  operation,
 		Parameters: sanitizeParameters(parameters),
-		ExpiresAt:  time.Now().Add(TokenExpiration),
+		ExpiresAt:  time.Unix(payload.Expires, 0),
 		RiskLevel:  riskLevel,
-		Used:       false,
-		CreatedAt:  time.Now(),
+		Nonce:      payload.Nonce,
+		CreatedAt:  now,
+	}, nil
+}
+
+// Validate verifies the signature, expiry, operation and parameters of a token
+// and records its nonce so it cannot be used again
+func (s *TokenSigner) Validate(tokenStr string, operation string, parameters map[string]interface{}) error {
+	payload, err := s.decode(tokenStr)
+	if err != nil {
+		return err
 	}
 
-	// Store token
-	store.mu.Lock()
-	store.tokens[token.Token] = token
-	store.mu.Unlock()
+	expiresAt := time.Unix(payload.Expires, 0)
+	if time.Now().After(expiresAt) {
+		return fmt.Errorf("confirmation token has expired")
+	}
 
-	// Schedule cleanup
-	go cleanupExpiredToken(token.Token)
+	if payload.Operation != operation {
+		return fmt.Errorf("confirmation token is for a different operation (%s != %s)", payload.Operation, operation)
+	}
 
-	return token, nil
-}
+	if paramHash, err := hashParams(parameters); err != nil || paramHash != payload.ParamHash {
+		return fmt.Errorf("confirmation token parameters do not match current request")
+	}
 
-// ValidateConfirmationToken verifies a confirmation token and marks it as used
-func ValidateConfirmationToken(tokenStr string, operation string, parameters map[string]interface{}) error {
-	store.mu.Lock()
-	defer store.mu.Unlock()
+	if s.approvals.Requires(operation) {
+		approved, err := s.approvals.IsApproved(payload.Nonce)
+		if err != nil {
+			return err
+		}
+		if !approved {
+			return ErrApprovalRequired
+		}
+	}
 
-	token, exists := store.tokens[tokenStr]
-	if !exists {
-		return fmt.Errorf("invalid confirmation token")
+	// Single use: checked last so a rejected attempt does not burn the token
+	if err := s.replay.MarkUsed(payload.Nonce, expiresAt); err != nil {
+		return err
 	}
 
-	// Check if token is expired
-	if time.Now().After(token.ExpiresAt) {
-		delete(store.tokens, tokenStr)
-		return fmt.Errorf("confirmation token has expired")
+	return nil
+}
+
+// approvedToken signs the claims of an approval request again, giving back
+// the token it was created with. It fails if the claims in the queue were
+// changed, since the result would no longer match the recorded hash.
+func (s *TokenSigner) approvedToken(req *ApprovalRequest) (string, error) {
+	tokenStr := TokenPrefix + req.Claims + "." + s.sign(req.Claims)
+	if req.Claims == "" || subtle.ConstantTimeCompare([]byte(hashToken(tokenStr)), []byte(req.TokenHash)) != 1 {
+		return "", fmt.Errorf("approval request %s does not hold a token issued by this server", req.ID)
 	}
+	return tokenStr, nil
+}
 
-	// Check if token was already used
-	if token.Used {
-		return fmt.Errorf("confirmation token has already been used")
+// encode serializes and signs a payload
+func (s *TokenSigner) encode(payload tokenPayload) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode token: %w", err)
 	}
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+	return TokenPrefix + encoded + "." + s.sign(encoded), nil
+}
 
-	// Verify operation matches
-	if token.Operation != operation {
-		return fmt.Errorf("confirmation token is for a different operation (%s != %s)", token.Operation, operation)
+// decode verifies the signature and returns the token payload
+func (s *TokenSigner) decode(tokenStr string) (*tokenPayload, error) {
+	body, ok := strings.CutPrefix(tokenStr, TokenPrefix)
+	if !ok {
+		return nil, fmt.Errorf("invalid confirmation token")
+	}
+	encoded, signature, ok := strings.Cut(body, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.sign(encoded))) {
+		return nil, fmt.Errorf("invalid confirmation token")
 	}
 
-	// Verify critical parameters match
-	if !parametersMatch(token.Parameters, parameters) {
-		return fmt.Errorf("confirmation token parameters do not match current request")
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid confirmation token")
+	}
+	var payload tokenPayload
+	if err := json.Unmarshal(data, &payload); err != nil || payload.Nonce == "" {
+		return nil, fmt.Errorf("invalid confirmation token")
 	}
+	return &payload, nil
+}
+
+func (s *TokenSigner) sign(encoded string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
 
-	// Mark token as used
-	token.Used = true
+// GenerateConfirmationToken creates a token with the process-wide default signer
+func GenerateConfirmationToken(operation string, parameters map[string]interface{}, riskLevel RiskLevel) (*ConfirmationToken, error) {
+	return defaultSigner.Generate(operation, parameters, riskLevel)
+}
 
-	// Delete token (single-use)
-	delete(store.tokens, tokenStr)
+// ValidateConfirmationToken verifies a token issued by the process-wide default signer
+func ValidateConfirmationToken(tokenStr string, operation string, parameters map[string]interface{}) error {
+	return defaultSigner.Validate(tokenStr, operation, parameters)
+}
 
-	return nil
+// LoadTokenKey returns the signing key from TokenKeyEnv, or from path, creating
+// a random key file (0600) when it does not exist yet
+func LoadTokenKey(path string) ([]byte, error) {
+	return loadKey(TokenKeyEnv, path, "confirmation key", true)
+}
+
+// loadKey reads a key from the env variable or a hex-encoded key file.
+// Returns nil without error when neither is set, or when the file does not
+// exist and create is false.
+func loadKey(envName, path, what string, create bool) ([]byte, error) {
+	if env := os.Getenv(envName); env != "" {
+		if len(env) < minTokenKeyLength {
+			return nil, fmt.Errorf("%s must be at least %d characters", envName, minTokenKeyLength)
+		}
+		return []byte(env), nil
+	}
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		key, decodeErr := hex.DecodeString(strings.TrimSpace(string(data)))
+		if decodeErr != nil || len(key) < minTokenKeyLength {
+			return nil, fmt.Errorf("invalid %s file %s: expected at least %d hex-encoded bytes", what, path, minTokenKeyLength)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s file: %w", what, err)
+	}
+	if !create {
+		return nil, nil
+	}
+
+	key := mustRandomBytes(minTokenKeyLength)
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, fmt.Errorf("failed to create %s directory: %w", what, err)
+		}
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(key)+"\n"), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write %s file: %w", what, err)
+	}
+	log.Printf("Created %s: %s", what, path)
+	return key, nil
 }
 
 // GetConfirmationMessage returns a formatted message for requesting confirmation
@@ -132,19 +310,27 @@ func GetConfirmationMessage(token *ConfirmationToken, additionalInfo string) str
 To proceed, call again with:
   confirmation_token=%s
 
-Token expires in %d minutes
+Token expires in %s
 `,
 		riskEmoji,
 		token.RiskLevel.String(),
 		token.Operation,
 		additionalInfo,
 		token.Token,
-		int(TokenExpiration.Minutes()),
+		formatTokenLifetime(time.Until(token.ExpiresAt)),
 	)
 
 	return message
 }
 
+// formatTokenLifetime renders a remaining lifetime in whole minutes, or seconds below one minute
+func formatTokenLifetime(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%d seconds", int(d.Round(time.Second).Seconds()))
+	}
+	return fmt.Sprintf("%d minutes", int(d.Round(time.Minute).Minutes()))
+}
+
 // sanitizeParameters removes sensitive information from parameters
 func sanitizeParameters(params map[string]interface{}) map[string]interface{} {
 	sanitized := make(map[string]interface{})
@@ -170,76 +356,39 @@ func sanitizeParameters(params map[string]interface{}) map[string]interface{} {
 	return sanitized
 }
 
-// parametersMatch checks if critical parameters match between token and request.
-// Hardened: if the token was generated with a critical key, the request MUST also
-// supply it with the same value. Missing-on-request is rejected (prevents replay
-// of a token by stripping params).
-func parametersMatch(tokenParams, requestParams map[string]interface{}) bool {
-	// Critical parameters that must match exactly when present in token
-	criticalKeys := []string{"owner", "repo", "username", "hook_id", "branch", "invitation_id", "team_id", "number", "run_id"}
-
-	for _, key := range criticalKeys {
-		tokenValue, tokenHas := tokenParams[key]
-		if !tokenHas {
-			continue
-		}
-		requestValue, requestHas := requestParams[key]
-		if !requestHas {
-			// Token was bound to this key but request omits it: reject.
-			return false
-		}
-		// Compare by canonical string representation to avoid panics on
-		// non-comparable interface{} values (slices, maps).
-		if fmt.Sprintf("%v", tokenValue) != fmt.Sprintf("%v", requestValue) {
-			return false
+// hashParams hashes every parameter except controlKeys as canonical JSON
+// (object keys sorted), so a token previewed for one payload cannot confirm
+// another on the same target, and adding or dropping a parameter changes the
+// hash. 123 and float64(123) encode, and hash, the same.
+func hashParams(params map[string]interface{}) (string, error) {
+	bound := make(map[string]interface{}, len(params))
+	for key, value := range params {
+		if !controlKeys[key] {
+			bound[key] = value
 		}
 	}
-
-	return true
-}
-
-// cleanupExpiredToken removes a token after it expires
-func cleanupExpiredToken(tokenStr string) {
-	time.Sleep(TokenExpiration + time.Minute) // Wait extra minute for safety
-
-	store.mu.Lock()
-	defer store.mu.Unlock()
-
-	if token, exists := store.tokens[tokenStr]; exists {
-		if time.Now().After(token.ExpiresAt) {
-			delete(store.tokens, tokenStr)
-		}
+	data, err := json.Marshal(bound)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash parameters: %w", err)
 	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
 }
 
-// CleanupAllExpiredTokens removes all expired tokens (for maintenance)
+// CleanupAllExpiredTokens prunes expired nonces from the default replay store
 func CleanupAllExpiredTokens() int {
-	store.mu.Lock()
-	defer store.mu.Unlock()
-
-	now := time.Now()
-	cleaned := 0
-
-	for tokenStr, token := range store.tokens {
-		if now.After(token.ExpiresAt) {
-			delete(store.tokens, tokenStr)
-			cleaned++
-		}
-	}
-
-	return cleaned
+	return defaultReplay.Prune(time.Now())
 }
 
-// GetActiveTokens returns the count of active tokens
-func GetActiveTokens() int {
-	store.mu.RLock()
-	defer store.mu.RUnlock()
-	return len(store.tokens)
+// ClearAllTokens forgets all used nonces of the default replay store (for testing)
+func ClearAllTokens() {
+	defaultReplay.Reset()
 }
 
-// ClearAllTokens removes all tokens (for testing)
-func ClearAllTokens() {
-	store.mu.Lock()
-	defer store.mu.Unlock()
-	store.tokens = make(map[string]*ConfirmationToken)
+func mustRandomBytes(n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("crypto/rand unavailable: %v", err))
+	}
+	return b
 }
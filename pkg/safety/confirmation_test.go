@@ -1,7 +1,13 @@
 package safety
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -61,8 +67,8 @@ func TestGenerateConfirmationToken(t *testing.T) {
 			if token.RiskLevel != tt.riskLevel {
 				t.Errorf("Token risk level = %v, want %v", token.RiskLevel, tt.riskLevel)
 			}
-			if token.Used {
-				t.Error("Token should not be marked as used")
+			if token.Nonce == "" {
+				t.Error("Token should carry a nonce")
 			}
 
 			// Verify expiration (should be ~5 minutes from now)
@@ -168,13 +174,14 @@ func TestValidateConfirmationToken(t *testing.T) {
 				}
 				tokenStr = token.Token
 
-				// Manually expire token if testing expiration (faster than waiting)
+				// Re-sign the payload with a past expiry (faster than waiting)
 				if tt.name == "Expired token" {
-					store.mu.Lock()
-					if t1, exists := store.tokens[tokenStr]; exists {
-						t1.ExpiresAt = time.Now().Add(-1 * time.Minute)
+					payload, err := defaultSigner.decode(tokenStr)
+					if err != nil {
+						t.Fatalf("Failed to decode token: %v", err)
 					}
-					store.mu.Unlock()
+					payload.Expires = time.Now().Add(-1 * time.Minute).Unix()
+					tokenStr, _ = defaultSigner.encode(*payload)
 				}
 
 				// Wait if needed (for other tests)
@@ -211,6 +218,43 @@ func TestValidateConfirmationToken(t *testing.T) {
 	}
 }
 
+func TestValidateConfirmationToken_ChangedPayload(t *testing.T) {
+	ClearAllTokens()
+
+	operation := "github_admin_repo:update_settings"
+	previewed := map[string]interface{}{
+		"owner":    "test-owner",
+		"repo":     "test-repo",
+		"settings": map[string]interface{}{"has_issues": false},
+	}
+	token, err := GenerateConfirmationToken(operation, previewed, RiskHigh)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	changed := map[string]interface{}{
+		"owner":              "test-owner",
+		"repo":               "test-repo",
+		"settings":           map[string]interface{}{"private": false, "has_issues": false},
+		"confirmation_token": token.Token,
+	}
+	err = ValidateConfirmationToken(token.Token, operation, changed)
+	if err == nil || !strings.Contains(err.Error(), "parameters do not match") {
+		t.Fatalf("a token for one payload must not confirm another, got: %v", err)
+	}
+
+	// The rejected attempt does not burn the token for the previewed payload
+	confirmed := map[string]interface{}{
+		"owner":              "test-owner",
+		"repo":               "test-repo",
+		"settings":           map[string]interface{}{"has_issues": false},
+		"confirmation_token": token.Token,
+	}
+	if err := ValidateConfirmationToken(token.Token, operation, confirmed); err != nil {
+		t.Errorf("the previewed payload should be confirmed: %v", err)
+	}
+}
+
 func TestGetConfirmationMessage(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -329,7 +373,7 @@ func TestSanitizeParameters(t *testing.T) {
 	}
 }
 
-func TestParametersMatch(t *testing.T) {
+func TestHashParams(t *testing.T) {
 	tests := []struct {
 		name          string
 		tokenParams   map[string]interface{}
@@ -375,20 +419,24 @@ func TestParametersMatch(t *testing.T) {
 			want: false,
 		},
 		{
-			name: "Extra non-critical parameters OK",
+			name: "Control parameters ignored",
 			tokenParams: map[string]interface{}{
 				"owner": "test",
 				"repo":  "demo",
+				"dry_run": true,
 			},
 			requestParams: map[string]interface{}{
-				"owner":  "test",
-				"repo":   "demo",
-				"dry_run": false,
+				"owner":              "test",
+				"repo":               "demo",
+				"dry_run":            false,
+				"confirmation_token": "CONF:abc",
+				"approval_id":        "appr-1",
+				"workspace":          "other",
 			},
 			want: true,
 		},
 		{
-			name: "Missing critical parameter",
+			name: "Missing parameter",
 			tokenParams: map[string]interface{}{
 				"owner": "test",
 				"repo":  "demo",
@@ -396,78 +444,235 @@ func TestParametersMatch(t *testing.T) {
 			requestParams: map[string]interface{}{
 				"owner": "test",
 			},
-			want: false, // Hardened: token bound to "repo" must have matching "repo" in request
+			want: false, // token bound to "repo" must have matching "repo" in request
+		},
+		{
+			name: "Extra parameter",
+			tokenParams: map[string]interface{}{
+				"owner": "test",
+				"repo":  "demo",
+			},
+			requestParams: map[string]interface{}{
+				"owner":   "test",
+				"repo":    "demo",
+				"private": true,
+			},
+			want: false,
+		},
+		{
+			name: "Different payload",
+			tokenParams: map[string]interface{}{
+				"owner":    "test",
+				"repo":     "demo",
+				"settings": map[string]interface{}{"private": true},
+			},
+			requestParams: map[string]interface{}{
+				"owner":    "test",
+				"repo":     "demo",
+				"settings": map[string]interface{}{"private": false},
+			},
+			want: false,
+		},
+		{
+			name: "Number types",
+			tokenParams: map[string]interface{}{
+				"owner":   "test",
+				"hook_id": 123,
+			},
+			requestParams: map[string]interface{}{
+				"owner":   "test",
+				"hook_id": float64(123),
+			},
+			want: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := parametersMatch(tt.tokenParams, tt.requestParams); got != tt.want {
-				t.Errorf("parametersMatch() = %v, want %v", got, tt.want)
+			tokenHash, err := hashParams(tt.tokenParams)
+			if err != nil {
+				t.Fatal(err)
+			}
+			requestHash, err := hashParams(tt.requestParams)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tokenHash == requestHash; got != tt.want {
+				t.Errorf("hashParams() match = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
 func TestCleanupAllExpiredTokens(t *testing.T) {
-	// Clear tokens before test
+	// Clear nonces before test
 	ClearAllTokens()
 
-	// Create some tokens
-	token1, _ := GenerateConfirmationToken("op1", map[string]interface{}{}, RiskHigh)
-	token2, _ := GenerateConfirmationToken("op2", map[string]interface{}{}, RiskHigh)
+	// MarkUsed prunes before recording, so record the expired nonce last
+	if err := defaultReplay.MarkUsed("active", time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("MarkUsed() error = %v", err)
+	}
+	if err := defaultReplay.MarkUsed("expired", time.Now().Add(-1*time.Minute)); err != nil {
+		t.Fatalf("MarkUsed() error = %v", err)
+	}
 
-	// Manually expire token1
-	store.mu.Lock()
-	if t1, exists := store.tokens[token1.Token]; exists {
-		t1.ExpiresAt = time.Now().Add(-1 * time.Minute)
+	if cleaned := CleanupAllExpiredTokens(); cleaned != 1 {
+		t.Errorf("Expected to clean 1 nonce, cleaned %d", cleaned)
+	}
+	if defaultReplay.Len() != 1 {
+		t.Errorf("Expected 1 nonce after cleanup, got %d", defaultReplay.Len())
 	}
-	store.mu.Unlock()
 
-	// Verify we have 2 tokens
-	if GetActiveTokens() != 2 {
-		t.Errorf("Expected 2 active tokens, got %d", GetActiveTokens())
+	// The active nonce is still recorded
+	if err := defaultReplay.MarkUsed("active", time.Now().Add(time.Minute)); err != ErrTokenReplayed {
+		t.Errorf("active nonce should still be recorded, got %v", err)
 	}
+}
 
-	// Cleanup expired tokens
-	cleaned := CleanupAllExpiredTokens()
+func TestTokenSigner_SignatureAndKey(t *testing.T) {
+	key := []byte(strings.Repeat("k", minTokenKeyLength))
+	params := map[string]interface{}{"owner": "test", "repo": "demo"}
 
-	if cleaned != 1 {
-		t.Errorf("Expected to clean 1 token, cleaned %d", cleaned)
+	issuer := NewTokenSigner(key, NewMemoryReplayStore(), nil)
+	token, err := issuer.Generate("github_admin_repo:delete", params, RiskCritical)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
 	}
 
-	// Verify we have 1 token left
-	if GetActiveTokens() != 1 {
-		t.Errorf("Expected 1 active token after cleanup, got %d", GetActiveTokens())
+	t.Run("Tampered payload rejected", func(t *testing.T) {
+		payload, _ := issuer.decode(token.Token)
+		payload.Operation = "github_admin_repo:archive"
+		forged, _ := NewTokenSigner([]byte(strings.Repeat("x", minTokenKeyLength)), nil, nil).encode(*payload)
+		if err := issuer.Validate(forged, "github_admin_repo:archive", params); err == nil || !strings.Contains(err.Error(), "invalid") {
+			t.Errorf("forged token should be invalid, got %v", err)
+		}
+	})
+
+	t.Run("Other key rejected", func(t *testing.T) {
+		other := NewTokenSigner([]byte(strings.Repeat("o", minTokenKeyLength)), NewMemoryReplayStore(), nil)
+		if err := other.Validate(token.Token, "github_admin_repo:delete", params); err == nil {
+			t.Error("token signed with another key should be rejected")
+		}
+	})
+
+	t.Run("Instance sharing the key and replay store accepts once", func(t *testing.T) {
+		replay := NewFileReplayStore(filepath.Join(t.TempDir(), "nonces.json"))
+		first := NewTokenSigner(key, replay, nil)
+		second := NewTokenSigner(key, NewFileReplayStore(replay.path), nil)
+
+		if err := first.Validate(token.Token, "github_admin_repo:delete", params); err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+		if err := second.Validate(token.Token, "github_admin_repo:delete", params); err != ErrTokenReplayed {
+			t.Errorf("replay through another instance should fail, got %v", err)
+		}
+	})
+
+	t.Run("Concurrent instances accept once", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "nonces.json")
+		const instances = 16
+
+		// Each instance has its own store on the shared file, so only the file
+		// lock orders them
+		var wg sync.WaitGroup
+		var accepted atomic.Int32
+		for i := 0; i < instances; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				signer := NewTokenSigner(key, NewFileReplayStore(path), nil)
+				if signer.Validate(token.Token, "github_admin_repo:delete", params) == nil {
+					accepted.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		if n := accepted.Load(); n != 1 {
+			t.Errorf("token accepted %d times, want once", n)
+		}
+
+		// Distinct nonces recorded concurrently must all survive
+		wg = sync.WaitGroup{}
+		for i := 0; i < instances; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if err := NewFileReplayStore(path).MarkUsed(fmt.Sprintf("nonce-%d", i), time.Now().Add(time.Hour)); err != nil {
+					t.Errorf("MarkUsed() error = %v", err)
+				}
+			}(i)
+		}
+		wg.Wait()
+		store := NewFileReplayStore(path)
+		for i := 0; i < instances; i++ {
+			if err := store.MarkUsed(fmt.Sprintf("nonce-%d", i), time.Now().Add(time.Hour)); err != ErrTokenReplayed {
+				t.Errorf("nonce-%d was lost: %v", i, err)
+			}
+		}
+	})
+}
+
+func TestTokenSigner_Expiration(t *testing.T) {
+	signer := NewTokenSigner([]byte(strings.Repeat("k", minTokenKeyLength)), NewMemoryReplayStore(), map[RiskLevel]time.Duration{
+		RiskCritical: 2 * time.Minute,
+		RiskHigh:     15 * time.Minute,
+	})
+
+	tests := []struct {
+		level RiskLevel
+		want  time.Duration
+	}{
+		{RiskCritical, 2 * time.Minute},
+		{RiskHigh, 15 * time.Minute},
+		{RiskMedium, TokenExpiration},
 	}
 
-	// Verify token2 still exists
-	err := ValidateConfirmationToken(token2.Token, "op2", map[string]interface{}{})
-	if err != nil {
-		t.Errorf("Token2 should still be valid: %v", err)
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			token, err := signer.Generate("op", map[string]interface{}{}, tt.level)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			lifetime := time.Until(token.ExpiresAt)
+			if lifetime > tt.want || lifetime < tt.want-2*time.Second {
+				t.Errorf("lifetime = %v, want ~%v", lifetime, tt.want)
+			}
+		})
 	}
 }
 
-func TestGetActiveTokens(t *testing.T) {
-	// Clear tokens before test
-	ClearAllTokens()
+func TestLoadTokenKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys", "confirmation.key")
 
-	if GetActiveTokens() != 0 {
-		t.Error("Expected 0 active tokens after clear")
+	key, err := LoadTokenKey(path)
+	if err != nil {
+		t.Fatalf("LoadTokenKey() error = %v", err)
+	}
+	if len(key) < minTokenKeyLength {
+		t.Errorf("key length = %d, want >= %d", len(key), minTokenKeyLength)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("key file not created: %v", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("key file mode = %v, want 0600", info.Mode().Perm())
 	}
 
-	// Generate some tokens
-	GenerateConfirmationToken("op1", map[string]interface{}{}, RiskHigh)
-	GenerateConfirmationToken("op2", map[string]interface{}{}, RiskCritical)
+	again, err := LoadTokenKey(path)
+	if err != nil || string(again) != string(key) {
+		t.Errorf("reloaded key differs (err=%v)", err)
+	}
 
-	if GetActiveTokens() != 2 {
-		t.Errorf("Expected 2 active tokens, got %d", GetActiveTokens())
+	t.Setenv(TokenKeyEnv, strings.Repeat("e", minTokenKeyLength))
+	envKey, err := LoadTokenKey(path)
+	if err != nil || string(envKey) != strings.Repeat("e", minTokenKeyLength) {
+		t.Errorf("%s should override the key file (err=%v)", TokenKeyEnv, err)
 	}
 
-	// Clear and verify
-	ClearAllTokens()
-	if GetActiveTokens() != 0 {
-		t.Error("Expected 0 active tokens after clear")
+	t.Setenv(TokenKeyEnv, "short")
+	if _, err := LoadTokenKey(path); err == nil {
+		t.Error("short key should be rejected")
 	}
 }
 
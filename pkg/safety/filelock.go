@@ -0,0 +1,28 @@
+package safety
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// lockStateFile takes an exclusive lock shared by every process that uses the
+// state file at path, and returns the function that releases it. The lock is
+// held on path+".lock": the state file itself is replaced on every save.
+func lockStateFile(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
@@ -0,0 +1,329 @@
+package safety
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FreezeRuleLabelPrefix prefixes the policy rule recorded in the audit log
+// when a change freeze decides an operation
+const FreezeRuleLabelPrefix = "freeze:"
+
+// DefaultFreezeOperations are frozen when a window lists no operations:
+// merges, branch protection edits and repository settings changes
+var DefaultFreezeOperations = []string{
+	"git_branch:merge",
+	"git_conflict:safe_merge",
+	"github_repair:merge_pr",
+	"github_branch_protection:update",
+	"github_branch_protection:delete",
+	"github_admin_repo:update_settings",
+}
+
+// DefaultFreezePushOperations are also frozen when a window lists no
+// operations, but only on the window's branches or, when it lists none, on
+// protected branches; feature branch pushes keep working
+var DefaultFreezePushOperations = []string{
+	"git_sync:push",
+	"git_sync:force_push",
+	"git_sync:push_upstream",
+	"gh_push_files",
+}
+
+// FreezeWindow is a period during which matching operations are denied or
+// need confirmation. It is either a fixed range [Start, End) or recurring:
+// every time Schedule matches, a window of Duration starts.
+//
+// Operations and Repos use the same globs as policy rules. Branches only
+// filters operations that carry a "branch" parameter; the others (repository
+// settings, pull request merges) are frozen regardless.
+type FreezeWindow struct {
+	Name       string
+	Action     PolicyAction // PolicyDeny (default) or PolicyRequireConfirmation
+	Operations []string     // empty uses DefaultFreezeOperations and DefaultFreezePushOperations
+	Repos      []string
+	Branches   []string
+	Start      time.Time
+	End        time.Time
+	Schedule   *CronSchedule
+	Duration   time.Duration
+	Location   *time.Location // zone of Schedule and of displayed times; nil is UTC
+}
+
+// Label returns the window name, or its position when unnamed
+func (w *FreezeWindow) Label(index int) string {
+	if w.Name != "" {
+		return w.Name
+	}
+	return fmt.Sprintf("changeFreezes[%d]", index)
+}
+
+func (w *FreezeWindow) location() *time.Location {
+	if w.Location != nil {
+		return w.Location
+	}
+	return time.UTC
+}
+
+// activeUntil returns when the window ends if it is active at now
+func (w *FreezeWindow) activeUntil(now time.Time) (time.Time, bool) {
+	if w.Schedule == nil {
+		if !now.Before(w.Start) && now.Before(w.End) {
+			return w.End, true
+		}
+		return time.Time{}, false
+	}
+
+	local := now.In(w.location())
+	start, ok := w.Schedule.prev(local, local.Add(-w.Duration))
+	if !ok {
+		return time.Time{}, false
+	}
+	end := start.Add(w.Duration)
+	if !now.Before(end) {
+		return time.Time{}, false
+	}
+	return end, true
+}
+
+func (w *FreezeWindow) matches(operation string, parameters map[string]interface{}, protectedBranch bool) bool {
+	switch {
+	case len(w.Operations) > 0:
+		if !matchAnyGlob(w.Operations, operation) {
+			return false
+		}
+	case matchAnyGlob(DefaultFreezePushOperations, operation):
+		if len(w.Branches) == 0 && !protectedBranch {
+			return false
+		}
+	case !matchAnyGlob(DefaultFreezeOperations, operation):
+		return false
+	}
+
+	if len(w.Repos) > 0 {
+		owner, _ := parameters["owner"].(string)
+		repo, _ := parameters["repo"].(string)
+		if owner == "" || repo == "" || !matchAnyGlob(w.Repos, owner+"/"+repo) {
+			return false
+		}
+	}
+	if branch, _ := parameters["branch"].(string); len(w.Branches) > 0 && branch != "" {
+		return matchAnyGlob(w.Branches, branch)
+	}
+	return true
+}
+
+// describe renders the window, e.g. "0 18 * * 5 for 63h (Europe/Madrid)"
+func (w *FreezeWindow) describe() string {
+	if w.Schedule != nil {
+		return fmt.Sprintf("%s for %s (%s)", w.Schedule, formatWindow(w.Duration), w.location())
+	}
+	return fmt.Sprintf("%s to %s", formatFreezeTime(w.Start, w.location()), formatFreezeTime(w.End, w.location()))
+}
+
+// ValidateFreezeWindows rejects windows that could never be active or never end
+func ValidateFreezeWindows(windows []FreezeWindow) error {
+	for i, w := range windows {
+		switch w.Action {
+		case "", PolicyDeny, PolicyRequireConfirmation:
+		default:
+			return fmt.Errorf("change freeze %s: invalid action %q (allowed: deny, require_confirmation)", w.Label(i), w.Action)
+		}
+		if w.Schedule != nil {
+			if !w.Start.IsZero() || !w.End.IsZero() {
+				return fmt.Errorf("change freeze %s: use either start/end or cron/duration, not both", w.Label(i))
+			}
+			if w.Duration <= 0 {
+				return fmt.Errorf("change freeze %s: a cron window needs a positive duration", w.Label(i))
+			}
+		} else if w.Start.IsZero() || w.End.IsZero() || !w.End.After(w.Start) {
+			return fmt.Errorf("change freeze %s: end must be after start", w.Label(i))
+		}
+		for _, patterns := range [][]string{w.Operations, w.Repos, w.Branches} {
+			for _, pattern := range patterns {
+				if _, err := globToRegexp(pattern); err != nil {
+					return fmt.Errorf("change freeze %s: invalid pattern %q: %w", w.Label(i), pattern, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ActiveFreeze is a window currently freezing an operation
+type ActiveFreeze struct {
+	Label  string
+	Window *FreezeWindow
+	Until  time.Time
+}
+
+// FindActiveFreeze returns the first window freezing the operation at now,
+// or nil. When several apply, the deny ones win over require_confirmation.
+// protectedBranch reports whether the operation writes to a protected branch.
+func FindActiveFreeze(windows []FreezeWindow, operation string, parameters map[string]interface{}, protectedBranch bool, now time.Time) *ActiveFreeze {
+	var found *ActiveFreeze
+	for i := range windows {
+		w := &windows[i]
+		if !w.matches(operation, parameters, protectedBranch) {
+			continue
+		}
+		until, active := w.activeUntil(now)
+		if !active {
+			continue
+		}
+		if w.Action != PolicyRequireConfirmation {
+			return &ActiveFreeze{Label: w.Label(i), Window: w, Until: until}
+		}
+		if found == nil {
+			found = &ActiveFreeze{Label: w.Label(i), Window: w, Until: until}
+		}
+	}
+	return found
+}
+
+// Detail explains the freeze in confirmation prompts
+func (f *ActiveFreeze) Detail() string {
+	return fmt.Sprintf("change freeze '%s' until %s", f.Label, formatFreezeTime(f.Until, f.Window.location()))
+}
+
+// Message is the explanation shown when the freeze denies an operation
+func (f *ActiveFreeze) Message(operation string, now time.Time) string {
+	wait := f.Until.Sub(now).Round(time.Minute)
+	if wait < time.Minute {
+		wait = time.Minute
+	}
+	return fmt.Sprintf("❄️ %s blocked by change freeze '%s' (%s). The freeze ends at %s (in %s).",
+		operation, f.Label, f.Window.describe(), formatFreezeTime(f.Until, f.Window.location()), formatWindow(wait))
+}
+
+func formatFreezeTime(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format("Mon 2006-01-02 15:04 MST")
+}
+
+// ============================================================================
+// Cron schedules
+// ============================================================================
+
+// CronSchedule is a standard five-field cron expression (minute hour
+// day-of-month month day-of-week) with *, lists, ranges and steps. As in cron,
+// when both day fields are restricted a day matching either one matches.
+type CronSchedule struct {
+	expr                         string
+	minute, hour, dom, month     uint64
+	dow                          uint64
+	domRestricted, dowRestricted bool
+}
+
+// ParseCron parses a cron expression such as "0 18 * * 5"
+func ParseCron(expr string) (*CronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron %q: expected 5 fields (minute hour day-of-month month day-of-week)", expr)
+	}
+
+	c := &CronSchedule{expr: strings.Join(fields, " ")}
+	var err error
+	if c.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("cron %q minute: %w", expr, err)
+	}
+	if c.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("cron %q hour: %w", expr, err)
+	}
+	if c.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("cron %q day-of-month: %w", expr, err)
+	}
+	if c.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("cron %q month: %w", expr, err)
+	}
+	if c.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("cron %q day-of-week: %w", expr, err)
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1 // 7 is Sunday too
+	}
+	c.domRestricted = !strings.HasPrefix(fields[2], "*")
+	c.dowRestricted = !strings.HasPrefix(fields[4], "*")
+	return c, nil
+}
+
+func (c *CronSchedule) String() string {
+	return c.expr
+}
+
+// parseCronField returns the bit set of values allowed by one field
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rangePart, step = part[:i], n
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil || lo > hi {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", rangePart)
+			}
+			lo, hi = n, n
+			if step > 1 {
+				hi = max
+			}
+		}
+		if lo < min || hi > max {
+			return 0, fmt.Errorf("%q out of range %d-%d", rangePart, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	if c.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	domOK := c.dom&(1<<uint(t.Day())) != 0
+	dowOK := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domRestricted && c.dowRestricted {
+		return domOK || dowOK
+	}
+	return domOK && dowOK
+}
+
+// prev returns the latest minute at or before t matching the schedule, as
+// long as it is not before limit. t's location decides the wall clock.
+func (c *CronSchedule) prev(t, limit time.Time) (time.Time, bool) {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc)
+	for !t.Before(limit) {
+		switch {
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc).Add(-time.Minute)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc).Add(-time.Minute)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(-time.Minute)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
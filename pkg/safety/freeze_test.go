@@ -0,0 +1,156 @@
+package safety
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustCron(t *testing.T, expr string) *CronSchedule {
+	t.Helper()
+	c, err := ParseCron(expr)
+	if err != nil {
+		t.Fatalf("ParseCron(%q) error = %v", expr, err)
+	}
+	return c
+}
+
+func TestParseCron(t *testing.T) {
+	valid := []string{"0 18 * * 5", "*/15 9-17 * * 1-5", "0 0 1,15 * *", "30 2 * 12 0,7"}
+	for _, expr := range valid {
+		mustCron(t, expr)
+	}
+
+	invalid := []string{"0 18 * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *"}
+	for _, expr := range invalid {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) should fail", expr)
+		}
+	}
+}
+
+func TestFreezeWindow_Recurring(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	// Friday 18:00 to Monday 09:00, Madrid time
+	window := FreezeWindow{Schedule: mustCron(t, "0 18 * * 5"), Duration: 63 * time.Hour, Location: madrid}
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		active    bool
+		wantUntil string
+	}{
+		{"friday before", time.Date(2026, 10, 16, 17, 59, 0, 0, madrid), false, ""},
+		{"friday start", time.Date(2026, 10, 16, 18, 0, 0, 0, madrid), true, "2026-10-19 09:00"},
+		{"sunday", time.Date(2026, 10, 18, 12, 0, 0, 0, madrid), true, "2026-10-19 09:00"},
+		{"sunday in UTC", time.Date(2026, 10, 18, 23, 30, 0, 0, time.UTC), true, "2026-10-19 09:00"},
+		{"monday end", time.Date(2026, 10, 19, 9, 0, 0, 0, madrid), false, ""},
+		{"wednesday", time.Date(2026, 10, 21, 12, 0, 0, 0, madrid), false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			until, active := window.activeUntil(tt.now)
+			if active != tt.active {
+				t.Fatalf("active = %v, want %v", active, tt.active)
+			}
+			if active && until.In(madrid).Format("2006-01-02 15:04") != tt.wantUntil {
+				t.Errorf("until = %s, want %s", until.In(madrid), tt.wantUntil)
+			}
+		})
+	}
+}
+
+func TestFindActiveFreeze(t *testing.T) {
+	now := time.Date(2026, 11, 3, 12, 0, 0, 0, time.UTC)
+	windows := []FreezeWindow{
+		{Name: "soft", Action: PolicyRequireConfirmation, Start: now.Add(-time.Hour), End: now.Add(time.Hour)},
+		{Name: "release", Action: PolicyDeny, Repos: []string{"acme/*"}, Branches: []string{"main", "release/*"}, Start: now.Add(-time.Hour), End: now.Add(48 * time.Hour)},
+		{Name: "past", Action: PolicyDeny, Start: now.Add(-48 * time.Hour), End: now.Add(-time.Hour)},
+	}
+	push := func(repo, branch string) map[string]interface{} {
+		return map[string]interface{}{"owner": "acme", "repo": repo, "branch": branch}
+	}
+
+	if f := FindActiveFreeze(windows, "git_sync:push", push("api", "main"), true, now); f == nil || f.Label != "release" {
+		t.Errorf("deny window should win: %+v", f)
+	}
+	if f := FindActiveFreeze(windows, "git_sync:push", push("api", "release/4.1"), false, now); f == nil || f.Label != "release" {
+		t.Errorf("window branches apply whether or not they are protected: %+v", f)
+	}
+	if f := FindActiveFreeze(windows, "git_sync:push", push("api", "hotfix"), true, now); f == nil || f.Label != "soft" {
+		t.Errorf("protected branch outside the release freeze should hit the soft one: %+v", f)
+	}
+	if f := FindActiveFreeze(windows, "git_sync:push", push("api", "feature/x"), false, now); f != nil {
+		t.Errorf("default freeze should not stop feature branch pushes: %+v", f)
+	}
+	if f := FindActiveFreeze(windows, "git_branch:merge", push("api", "feature/x"), false, now); f == nil || f.Label != "soft" {
+		t.Errorf("default freeze should stop merges on any branch: %+v", f)
+	}
+	if f := FindActiveFreeze(windows, "github_admin_repo:update_settings", map[string]interface{}{"owner": "acme", "repo": "api"}, false, now); f == nil || f.Label != "release" {
+		t.Errorf("operations without a branch ignore the branch filter: %+v", f)
+	}
+	if f := FindActiveFreeze(windows, "git_commit", push("api", "main"), true, now); f != nil {
+		t.Errorf("operations outside the default list are not frozen: %+v", f)
+	}
+	if f := FindActiveFreeze(windows[2:], "git_sync:push", push("api", "main"), true, now); f != nil {
+		t.Errorf("past window should not apply: %+v", f)
+	}
+}
+
+func TestEngine_CheckOperation_ChangeFreeze(t *testing.T) {
+	now := time.Now()
+	engine := NewEngine(&SafetyConfig{
+		Mode:                     SafetyModeModerate,
+		RequireConfirmationAbove: RiskHigh,
+		Freezes: []FreezeWindow{
+			{Name: "release-4.1", Action: PolicyDeny, Operations: []string{"git_sync:*", "git_branch:merge"}, Branches: []string{"main"}, Start: now.Add(-time.Hour), End: now.Add(26 * time.Hour)},
+			{Name: "settings", Action: PolicyRequireConfirmation, Operations: []string{"github_admin_repo:update_settings"}, Start: now.Add(-time.Hour), End: now.Add(time.Hour)},
+		},
+	})
+	ctx := context.Background()
+
+	check, err := engine.CheckOperation(ctx, "git_sync:push", map[string]interface{}{"branch": "main"})
+	if err != nil {
+		t.Fatalf("CheckOperation() error = %v", err)
+	}
+	if check.CanProceed || check.PolicyAction != PolicyDeny || check.PolicyRule != FreezeRuleLabelPrefix+"release-4.1" {
+		t.Fatalf("push to main during the freeze should be denied: %+v", check)
+	}
+	if !strings.Contains(check.Message, "ends at") || !strings.Contains(check.Message, "in 26h") {
+		t.Errorf("message should say when the freeze ends: %s", check.Message)
+	}
+
+	if check, _ := engine.CheckOperation(ctx, "git_sync:push", map[string]interface{}{"branch": "feature/x"}); !check.CanProceed {
+		t.Errorf("push to a feature branch should pass: %+v", check)
+	}
+
+	params := map[string]interface{}{"owner": "acme", "repo": "api", "has_wiki": false, "dry_run": false}
+	check, err = engine.CheckOperation(ctx, "github_admin_repo:update_settings", params)
+	if err != nil {
+		t.Fatalf("CheckOperation() error = %v", err)
+	}
+	if check.CanProceed || !check.RequiresConfirmation || !strings.Contains(check.Message, "change freeze 'settings' until") {
+		t.Errorf("settings change during a soft freeze should need confirmation: %+v", check)
+	}
+
+	t.Run("Default window freezes pushes to protected branches only", func(t *testing.T) {
+		engine := NewEngine(&SafetyConfig{
+			Mode:                     SafetyModeModerate,
+			RequireConfirmationAbove: RiskHigh,
+			ProtectedBranches:        []string{"main"},
+			ProtectedBranchAction:    PolicyRequireConfirmation,
+			Freezes:                  []FreezeWindow{{Name: "weekend", Start: now.Add(-time.Hour), End: now.Add(time.Hour)}},
+		})
+		check, _ := engine.CheckOperation(ctx, "git_sync:push", map[string]interface{}{"branch": "main"})
+		if check.CanProceed || check.PolicyRule != FreezeRuleLabelPrefix+"weekend" {
+			t.Errorf("push to main should be frozen: %+v", check)
+		}
+		if check, _ := engine.CheckOperation(ctx, "gh_push_files", map[string]interface{}{"branch": "feature/x"}); !check.CanProceed {
+			t.Errorf("push to a feature branch should pass: %+v", check)
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package safety
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PolicyAction is the decision a policy rule applies when it matches
+type PolicyAction string
+
+const (
+	// PolicyAllow stops rule evaluation; the mode safeguards still apply
+	PolicyAllow PolicyAction = "allow"
+
+	// PolicyDeny blocks the operation outright
+	PolicyDeny PolicyAction = "deny"
+
+	// PolicyRequireConfirmation forces a confirmation token on top of the mode safeguards
+	PolicyRequireConfirmation PolicyAction = "require_confirmation"
+)
+
+// PolicyRule is one entry of the ordered rules list. Every non-empty matcher
+// must match for the rule to apply; the first matching rule decides.
+//
+// Globs: "*" matches within one path segment, "**" matches across "/".
+// Operations are matched against composite keys ("github_admin_repo:delete",
+// "git_sync:push"), repos against "owner/repo" and branches against the
+// "branch" parameter.
+type PolicyRule struct {
+	Name       string
+	Action     PolicyAction
+	Operations []string
+	Repos      []string
+	Branches   []string
+	MinRisk    RiskLevel // 0 matches any level, including unclassified operations
+}
+
+// Label returns the rule name, or its position when unnamed
+func (r *PolicyRule) Label(index int) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return fmt.Sprintf("rules[%d]", index)
+}
+
+// ValidatePolicyRules checks actions and glob syntax so a typo in the config
+// fails at load time instead of silently never matching.
+func ValidatePolicyRules(rules []PolicyRule) error {
+	for i, rule := range rules {
+		switch rule.Action {
+		case PolicyAllow, PolicyDeny, PolicyRequireConfirmation:
+		default:
+			return fmt.Errorf("rule %s: invalid action %q (allowed: allow, deny, require_confirmation)", rule.Label(i), rule.Action)
+		}
+		for _, patterns := range [][]string{rule.Operations, rule.Repos, rule.Branches} {
+			for _, pattern := range patterns {
+				if _, err := globToRegexp(pattern); err != nil {
+					return fmt.Errorf("rule %s: invalid pattern %q: %w", rule.Label(i), pattern, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// MatchPolicyRule returns the first rule matching the operation, or nil and -1
+func MatchPolicyRule(rules []PolicyRule, operation string, parameters map[string]interface{}, level RiskLevel) (*PolicyRule, int) {
+	owner, _ := parameters["owner"].(string)
+	repo, _ := parameters["repo"].(string)
+	branch, _ := parameters["branch"].(string)
+
+	fullRepo := ""
+	if owner != "" && repo != "" {
+		fullRepo = owner + "/" + repo
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		if rule.MinRisk > 0 && level < rule.MinRisk {
+			continue
+		}
+		if len(rule.Operations) > 0 && !matchAnyGlob(rule.Operations, operation) {
+			continue
+		}
+		if len(rule.Repos) > 0 && (fullRepo == "" || !matchAnyGlob(rule.Repos, fullRepo)) {
+			continue
+		}
+		if len(rule.Branches) > 0 && (branch == "" || !matchAnyGlob(rule.Branches, branch)) {
+			continue
+		}
+		return rule, i
+	}
+	return nil, -1
+}
+
+func matchAnyGlob(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		re, err := globToRegexp(pattern)
+		if err != nil {
+			continue
+		}
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// globToRegexp translates a glob into an anchored regular expression
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+
+	return regexp.Compile(b.String())
+}
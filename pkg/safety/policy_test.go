@@ -0,0 +1,222 @@
+package safety
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGlobToRegexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"main", "main", true},
+		{"main", "maint", false},
+		{"release/*", "release/1.0", true},
+		{"release/*", "release/1.0/hotfix", false},
+		{"release/**", "release/1.0/hotfix", true},
+		{"acme/*", "acme/api", true},
+		{"acme/*", "acme-labs/api", false},
+		{"*:delete", "github_admin_repo:delete", true},
+		{"*:delete", "github_webhooks:update", false},
+		{"github_repair:dismiss_alert:*", "github_repair:dismiss_alert:secret", true},
+		{"v?.x", "v1.x", true},
+		{"a.b", "axb", false}, // dots are literal
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"~"+tt.value, func(t *testing.T) {
+			re, err := globToRegexp(tt.pattern)
+			if err != nil {
+				t.Fatalf("globToRegexp(%q) error = %v", tt.pattern, err)
+			}
+			if got := re.MatchString(tt.value); got != tt.want {
+				t.Errorf("match(%q, %q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchPolicyRule(t *testing.T) {
+	rules := []PolicyRule{
+		{Name: "allow-sandbox", Action: PolicyAllow, Repos: []string{"acme/sandbox"}},
+		{Name: "no-deletes-acme", Action: PolicyDeny, Operations: []string{"*:delete"}, Repos: []string{"acme/*"}},
+		{Name: "confirm-main", Action: PolicyRequireConfirmation, Operations: []string{"git_sync:*"}, Branches: []string{"main", "release/*"}},
+		{Action: PolicyRequireConfirmation, MinRisk: RiskHigh},
+	}
+
+	tests := []struct {
+		name      string
+		operation string
+		params    map[string]interface{}
+		level     RiskLevel
+		wantLabel string
+	}{
+		{
+			name:      "First match wins over later deny",
+			operation: "github_admin_repo:delete",
+			params:    map[string]interface{}{"owner": "acme", "repo": "sandbox"},
+			level:     RiskCritical,
+			wantLabel: "allow-sandbox",
+		},
+		{
+			name:      "Deny by repo glob",
+			operation: "github_admin_repo:delete",
+			params:    map[string]interface{}{"owner": "acme", "repo": "api"},
+			level:     RiskCritical,
+			wantLabel: "no-deletes-acme",
+		},
+		{
+			name:      "Branch glob",
+			operation: "git_sync:push",
+			params:    map[string]interface{}{"branch": "release/2.0"},
+			level:     RiskMedium,
+			wantLabel: "confirm-main",
+		},
+		{
+			name:      "Branch matcher requires a branch",
+			operation: "git_sync:push",
+			params:    map[string]interface{}{},
+			level:     RiskMedium,
+			wantLabel: "",
+		},
+		{
+			name:      "Unnamed rule labelled by index",
+			operation: "github_webhooks:delete",
+			params:    map[string]interface{}{"owner": "other", "repo": "x"},
+			level:     RiskHigh,
+			wantLabel: "rules[3]",
+		},
+		{
+			name:      "Below minRisk",
+			operation: "github_webhooks:create",
+			params:    map[string]interface{}{"owner": "other", "repo": "x"},
+			level:     RiskMedium,
+			wantLabel: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, idx := MatchPolicyRule(rules, tt.operation, tt.params, tt.level)
+			got := ""
+			if rule != nil {
+				got = rule.Label(idx)
+			}
+			if got != tt.wantLabel {
+				t.Errorf("MatchPolicyRule() = %q, want %q", got, tt.wantLabel)
+			}
+		})
+	}
+}
+
+func TestValidatePolicyRules(t *testing.T) {
+	if err := ValidatePolicyRules([]PolicyRule{{Action: PolicyDeny, Operations: []string{"*:delete"}}}); err != nil {
+		t.Errorf("valid rule rejected: %v", err)
+	}
+	if err := ValidatePolicyRules([]PolicyRule{{Name: "typo", Action: "block"}}); err == nil {
+		t.Error("invalid action should be rejected")
+	}
+	if err := ValidatePolicyRules([]PolicyRule{{Action: PolicyDeny, Repos: []string{""}}}); err == nil {
+		t.Error("empty pattern should be rejected")
+	}
+}
+
+func TestEngine_CheckOperation_PolicyRules(t *testing.T) {
+	ctx := context.Background()
+	logPath := filepath.Join(t.TempDir(), "policy-audit.log")
+
+	engine := NewEngine(&SafetyConfig{
+		Mode:                     SafetyModeModerate,
+		EnableAuditLog:           true,
+		AuditLogPath:             logPath,
+		RequireConfirmationAbove: RiskHigh,
+		Rules: []PolicyRule{
+			{Name: "no-deletes-acme", Action: PolicyDeny, Operations: []string{"*:delete"}, Repos: []string{"acme/*"}},
+			{Name: "confirm-main-push", Action: PolicyRequireConfirmation, Operations: []string{"git_sync:push"}, Branches: []string{"main"}},
+			{Name: "confirm-settings", Action: PolicyRequireConfirmation, Operations: []string{"github_admin_repo:update_settings"}},
+		},
+	})
+
+	t.Run("Deny rule blocks admin operation", func(t *testing.T) {
+		check, err := engine.CheckOperation(ctx, "github_admin_repo:delete", map[string]interface{}{
+			"owner": "acme", "repo": "api", "dry_run": false,
+		})
+		if err != nil {
+			t.Fatalf("CheckOperation() error = %v", err)
+		}
+		if check.CanProceed {
+			t.Error("deny rule should block")
+		}
+		if check.PolicyRule != "no-deletes-acme" || check.PolicyAction != PolicyDeny {
+			t.Errorf("PolicyRule = %q (%s), want no-deletes-acme (deny)", check.PolicyRule, check.PolicyAction)
+		}
+		if !strings.Contains(check.Message, "no-deletes-acme") {
+			t.Errorf("Message should name the rule: %s", check.Message)
+		}
+	})
+
+	t.Run("Require confirmation on non-admin operation", func(t *testing.T) {
+		ClearAllTokens()
+		params := map[string]interface{}{"branch": "main"}
+		check, err := engine.CheckOperation(ctx, "git_sync:push", params)
+		if err != nil {
+			t.Fatalf("CheckOperation() error = %v", err)
+		}
+		if check.CanProceed || !check.RequiresConfirmation {
+			t.Fatalf("push to main should require confirmation, got %+v", check)
+		}
+
+		tokenStart := strings.Index(check.Message, TokenPrefix)
+		if tokenStart == -1 {
+			t.Fatalf("token not found in message: %s", check.Message)
+		}
+		params["confirmation_token"] = strings.Fields(check.Message[tokenStart:])[0]
+		check, err = engine.CheckOperation(ctx, "git_sync:push", params)
+		if err != nil || !check.CanProceed {
+			t.Errorf("confirmed push should proceed: err=%v message=%s", err, check.Message)
+		}
+	})
+
+	t.Run("Push to feature branch unaffected", func(t *testing.T) {
+		check, err := engine.CheckOperation(ctx, "git_sync:push", map[string]interface{}{"branch": "feature/x"})
+		if err != nil || !check.CanProceed || check.PolicyRule != "" {
+			t.Errorf("feature push should pass without rule: err=%v check=%+v", err, check)
+		}
+	})
+
+	t.Run("Require confirmation raises MEDIUM admin operation", func(t *testing.T) {
+		ClearAllTokens()
+		check, err := engine.CheckOperation(ctx, "github_admin_repo:update_settings", map[string]interface{}{
+			"owner": "test", "repo": "demo", "dry_run": false,
+		})
+		if err != nil {
+			t.Fatalf("CheckOperation() error = %v", err)
+		}
+		if check.CanProceed || !check.RequiresConfirmation {
+			t.Error("rule should force confirmation on MEDIUM operation")
+		}
+		if !strings.Contains(check.Message, "confirm-settings") {
+			t.Errorf("Message should name the rule: %s", check.Message)
+		}
+	})
+
+	t.Run("Audit entry records deciding rule", func(t *testing.T) {
+		check, _ := engine.CheckOperation(ctx, "github_admin_repo:delete", map[string]interface{}{"owner": "acme", "repo": "api"})
+		if err := engine.LogCheckResult(check, map[string]interface{}{"owner": "acme", "repo": "api"}, "denied", nil, "", time.Millisecond, nil); err != nil {
+			t.Fatalf("LogCheckResult() error = %v", err)
+		}
+		entries, err := ReadAuditLog(logPath)
+		if err != nil {
+			t.Fatalf("ReadAuditLog() error = %v", err)
+		}
+		last := entries[len(entries)-1]
+		if last.PolicyRule != "no-deletes-acme" || last.Result != "denied" {
+			t.Errorf("audit entry = %+v, want policy_rule no-deletes-acme and result denied", last)
+		}
+	})
+}
@@ -0,0 +1,265 @@
+package safety
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRateLimitStatePath is where rate limit counters are kept when safety.json exists
+const DefaultRateLimitStatePath = "./.mcp-rate-limits.json"
+
+// RateLimitRuleLabelPrefix prefixes the policy rule recorded in the audit log
+// when a budget blocks an operation
+const RateLimitRuleLabelPrefix = "rate_limit:"
+
+// RateLimit is a budget of operations per sliding time window. Operations
+// uses the same globs as policy rules; Level restricts the budget to one risk
+// level. Both empty counts every checked operation.
+type RateLimit struct {
+	Name       string
+	Operations []string
+	Level      RiskLevel // 0 matches any level
+	Max        int
+	Window     time.Duration
+}
+
+// Label returns the limit name, or its position when unnamed
+func (l *RateLimit) Label(index int) string {
+	if l.Name != "" {
+		return l.Name
+	}
+	return fmt.Sprintf("rateLimits[%d]", index)
+}
+
+func (l *RateLimit) matches(operation string, level RiskLevel) bool {
+	if l.Level > 0 && level != l.Level {
+		return false
+	}
+	return len(l.Operations) == 0 || matchAnyGlob(l.Operations, operation)
+}
+
+// describe renders the budget, e.g. "3 CRITICAL operations per 1h"
+func (l *RateLimit) describe() string {
+	what := "operations"
+	if l.Level > 0 {
+		what = l.Level.String() + " operations"
+	}
+	if len(l.Operations) > 0 {
+		what += " matching " + strings.Join(l.Operations, ", ")
+	}
+	return fmt.Sprintf("%d %s per %s", l.Max, what, formatWindow(l.Window))
+}
+
+// ValidateRateLimits rejects budgets that could never allow or never expire
+func ValidateRateLimits(limits []RateLimit) error {
+	for i, limit := range limits {
+		if limit.Max < 1 {
+			return fmt.Errorf("rate limit %s: max must be at least 1", limit.Label(i))
+		}
+		if limit.Window <= 0 {
+			return fmt.Errorf("rate limit %s: window must be positive", limit.Label(i))
+		}
+		for _, pattern := range limit.Operations {
+			if _, err := globToRegexp(pattern); err != nil {
+				return fmt.Errorf("rate limit %s: invalid pattern %q: %w", limit.Label(i), pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// RateLimitExceeded explains which budget blocked an operation
+type RateLimitExceeded struct {
+	Label   string
+	Limit   RateLimit
+	Used    int
+	FreesAt time.Time // when the oldest counted operation leaves the window
+}
+
+// Message is the explanation shown to the caller
+func (x *RateLimitExceeded) Message(operation string, now time.Time) string {
+	wait := x.FreesAt.Sub(now).Round(time.Second)
+	if wait < time.Second {
+		wait = time.Second
+	}
+	return fmt.Sprintf("⏳ %s blocked by rate limit '%s': budget of %s used up (%d in the current window). Next slot frees at %s (in %s).",
+		operation, x.Label, x.Limit.describe(), x.Used, x.FreesAt.Format("15:04:05"), formatWindow(wait))
+}
+
+// RateLimiter counts operations against the configured budgets. Counters are
+// timestamps per limit; with a state path they are re-read and written on
+// every use under an exclusive file lock, so restarts and concurrent
+// instances share them without overwriting each other's counts.
+type RateLimiter struct {
+	mu     sync.Mutex
+	limits []RateLimit
+	path   string
+	memory map[string][]time.Time
+}
+
+// NewRateLimiter creates a limiter; an empty path keeps counters in memory
+func NewRateLimiter(limits []RateLimit, path string) *RateLimiter {
+	return &RateLimiter{
+		limits: limits,
+		path:   path,
+		memory: make(map[string][]time.Time),
+	}
+}
+
+// Check reports the first exhausted budget for the operation without counting it
+func (r *RateLimiter) Check(operation string, level RiskLevel, now time.Time) (*RateLimitExceeded, error) {
+	if len(r.limits) == 0 {
+		return nil, nil
+	}
+
+	unlock, err := r.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	counters, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	return r.exceeded(counters, operation, level, now), nil
+}
+
+// Consume counts the operation against every matching budget, unless one of
+// them is exhausted, in which case nothing is counted
+func (r *RateLimiter) Consume(operation string, level RiskLevel, now time.Time) (*RateLimitExceeded, error) {
+	if len(r.limits) == 0 {
+		return nil, nil
+	}
+
+	// The check and the count happen under one lock, on state re-read under it
+	unlock, err := r.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	counters, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	if exceeded := r.exceeded(counters, operation, level, now); exceeded != nil {
+		return exceeded, nil
+	}
+
+	for i := range r.limits {
+		limit := &r.limits[i]
+		if limit.matches(operation, level) {
+			label := limit.Label(i)
+			counters[label] = append(counters[label], now)
+		}
+	}
+	return nil, r.save(counters)
+}
+
+// exceeded prunes expired timestamps and returns the first exhausted budget
+func (r *RateLimiter) exceeded(counters map[string][]time.Time, operation string, level RiskLevel, now time.Time) *RateLimitExceeded {
+	for i := range r.limits {
+		limit := &r.limits[i]
+		label := limit.Label(i)
+
+		var recent []time.Time
+		for _, t := range counters[label] {
+			if now.Sub(t) < limit.Window {
+				recent = append(recent, t)
+			}
+		}
+		counters[label] = recent
+
+		if limit.matches(operation, level) && len(recent) >= limit.Max {
+			// Timestamps are appended in order, so the oldest frees the next slot
+			return &RateLimitExceeded{
+				Label:   label,
+				Limit:   *limit,
+				Used:    len(recent),
+				FreesAt: recent[len(recent)-limit.Max].Add(limit.Window),
+			}
+		}
+	}
+	return nil
+}
+
+// lock serializes access within the process and, with a state path, across
+// every process sharing the state file
+func (r *RateLimiter) lock() (func(), error) {
+	r.mu.Lock()
+	if r.path == "" {
+		return r.mu.Unlock, nil
+	}
+	unlockFile, err := lockStateFile(r.path)
+	if err != nil {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("failed to lock rate limit state: %w", err)
+	}
+	return func() {
+		unlockFile()
+		r.mu.Unlock()
+	}, nil
+}
+
+func (r *RateLimiter) load() (map[string][]time.Time, error) {
+	if r.path == "" {
+		return r.memory, nil
+	}
+
+	counters := make(map[string][]time.Time)
+	data, err := os.ReadFile(r.path)
+	if os.IsNotExist(err) || (err == nil && len(data) == 0) {
+		return counters, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rate limit state: %w", err)
+	}
+	if err := json.Unmarshal(data, &counters); err != nil {
+		return nil, fmt.Errorf("failed to parse rate limit state %s: %w", r.path, err)
+	}
+	return counters, nil
+}
+
+func (r *RateLimiter) save(counters map[string][]time.Time) error {
+	if r.path == "" {
+		r.memory = counters
+		return nil
+	}
+
+	// Drop counters of limits no longer configured
+	known := make(map[string]bool, len(r.limits))
+	for i := range r.limits {
+		known[r.limits[i].Label(i)] = true
+	}
+	for label, times := range counters {
+		if !known[label] || len(times) == 0 {
+			delete(counters, label)
+		}
+	}
+
+	data, err := json.Marshal(counters)
+	if err != nil {
+		return fmt.Errorf("failed to encode rate limit state: %w", err)
+	}
+	if err := writeFileAtomic(r.path, data, ".rate-limits-*"); err != nil {
+		return fmt.Errorf("failed to write rate limit state: %w", err)
+	}
+	return nil
+}
+
+// formatWindow renders a duration without zero units: 1h, 10m, 1h30m, 45s
+func formatWindow(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
@@ -0,0 +1,167 @@
+package safety
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_SlidingWindow(t *testing.T) {
+	limiter := NewRateLimiter([]RateLimit{
+		{Name: "critical-hourly", Level: RiskCritical, Max: 2, Window: time.Hour},
+	}, "")
+	start := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 2; i++ {
+		if exceeded, err := limiter.Consume("github_admin_repo:delete", RiskCritical, start.Add(time.Duration(i)*10*time.Minute)); err != nil || exceeded != nil {
+			t.Fatalf("operation %d should be within budget: %+v, %v", i, exceeded, err)
+		}
+	}
+
+	// Other levels are not counted by a level-specific budget
+	if exceeded, _ := limiter.Consume("github_webhooks:delete", RiskHigh, start.Add(20*time.Minute)); exceeded != nil {
+		t.Error("HIGH operation should not be limited by a CRITICAL budget")
+	}
+
+	exceeded, _ := limiter.Check("github_admin_repo:archive", RiskCritical, start.Add(30*time.Minute))
+	if exceeded == nil {
+		t.Fatal("third CRITICAL operation within the hour should be refused")
+	}
+	if !exceeded.FreesAt.Equal(start.Add(time.Hour)) || exceeded.Used != 2 {
+		t.Errorf("exceeded = %+v, want slot freeing at %v", exceeded, start.Add(time.Hour))
+	}
+	msg := exceeded.Message("github_admin_repo:archive", start.Add(30*time.Minute))
+	for _, want := range []string{"critical-hourly", "2 CRITICAL operations per 1h", "11:00:00", "in 30m"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q should contain %q", msg, want)
+		}
+	}
+
+	// A refused operation is not counted; the oldest slot frees after the window
+	if exceeded, _ := limiter.Consume("github_admin_repo:delete", RiskCritical, start.Add(time.Hour)); exceeded != nil {
+		t.Errorf("slot should be free once the first operation left the window: %+v", exceeded)
+	}
+}
+
+func TestRateLimiter_OperationGlobs(t *testing.T) {
+	limiter := NewRateLimiter([]RateLimit{
+		{Operations: []string{"github_collaborators:remove", "github_webhooks:*"}, Max: 1, Window: time.Minute},
+	}, "")
+	now := time.Now()
+
+	if exceeded, _ := limiter.Consume("github_webhooks:delete", RiskHigh, now); exceeded != nil {
+		t.Fatal("first operation should pass")
+	}
+	if exceeded, _ := limiter.Consume("github_webhooks:create", RiskMedium, now); exceeded == nil || exceeded.Label != "rateLimits[0]" {
+		t.Errorf("budget is shared by all matching operations: %+v", exceeded)
+	}
+	if exceeded, _ := limiter.Consume("github_collaborators:add", RiskMedium, now); exceeded != nil {
+		t.Error("non-matching operation should not be limited")
+	}
+}
+
+func TestRateLimiter_PersistsAcrossRestarts(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "rate-limits.json")
+	limits := []RateLimit{{Name: "pushes", Operations: []string{"git_sync:push"}, Max: 1, Window: time.Hour}}
+	now := time.Now()
+
+	if exceeded, err := NewRateLimiter(limits, statePath).Consume("git_sync:push", RiskMedium, now); err != nil || exceeded != nil {
+		t.Fatalf("first push: %+v, %v", exceeded, err)
+	}
+
+	exceeded, err := NewRateLimiter(limits, statePath).Check("git_sync:push", RiskMedium, now.Add(time.Minute))
+	if err != nil || exceeded == nil {
+		t.Errorf("counter should survive a restart: %+v, %v", exceeded, err)
+	}
+}
+
+func TestRateLimiter_ConcurrentInstances(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "rate-limits.json")
+	limits := []RateLimit{{Name: "deletes", Max: 5, Window: time.Hour}}
+	now := time.Now()
+
+	// Each instance has its own limiter on the shared file, so only the file
+	// lock keeps them from overwriting each other's counters
+	var wg sync.WaitGroup
+	var allowed atomic.Int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			exceeded, err := NewRateLimiter(limits, statePath).Consume("github_admin_repo:delete", RiskCritical, now)
+			if err != nil {
+				t.Errorf("Consume() error = %v", err)
+				return
+			}
+			if exceeded == nil {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := allowed.Load(); n != 5 {
+		t.Errorf("%d operations allowed, want the budget of 5", n)
+	}
+}
+
+func TestValidateRateLimits(t *testing.T) {
+	invalid := []RateLimit{
+		{Max: 0, Window: time.Minute},
+		{Max: 1, Window: 0},
+		{Max: 1, Window: time.Minute, Operations: []string{""}},
+	}
+	for _, limit := range invalid {
+		if err := ValidateRateLimits([]RateLimit{limit}); err == nil {
+			t.Errorf("ValidateRateLimits(%+v) should fail", limit)
+		}
+	}
+}
+
+func TestEngine_CheckOperation_RateLimits(t *testing.T) {
+	ctx := context.Background()
+	engine := NewEngine(&SafetyConfig{
+		Mode:                     SafetyModeModerate,
+		RequireConfirmationAbove: RiskCritical,
+		RateLimits: []RateLimit{
+			{Name: "webhook-deletes", Operations: []string{"github_webhooks:delete"}, Max: 2, Window: 10 * time.Minute},
+		},
+	})
+
+	params := func(dryRun bool) map[string]interface{} {
+		return map[string]interface{}{"owner": "acme", "repo": "api", "hook_id": 1, "dry_run": dryRun}
+	}
+
+	// Dry runs do not use the budget
+	for i := 0; i < 3; i++ {
+		check, err := engine.CheckOperation(ctx, "github_webhooks:delete", params(true))
+		if err != nil || !check.DryRun {
+			t.Fatalf("dry run %d: %+v, %v", i, check, err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		check, err := engine.CheckOperation(ctx, "github_webhooks:delete", params(false))
+		if err != nil || !check.CanProceed {
+			t.Fatalf("delete %d should proceed: %v (%s)", i, err, check.Message)
+		}
+	}
+
+	check, err := engine.CheckOperation(ctx, "github_webhooks:delete", params(false))
+	if err != nil {
+		t.Fatalf("CheckOperation() error = %v", err)
+	}
+	if check.CanProceed || check.PolicyRule != "rate_limit:webhook-deletes" || !strings.Contains(check.Message, "Next slot frees") {
+		t.Errorf("third delete should be refused by the budget: %+v", check)
+	}
+
+	// Refused before the dry-run gate too
+	check, _ = engine.CheckOperation(ctx, "github_webhooks:delete", params(true))
+	if check.DryRun || check.PolicyRule != "rate_limit:webhook-deletes" {
+		t.Errorf("exhausted budget should refuse before the dry-run: %+v", check)
+	}
+}
@@ -0,0 +1,175 @@
+package safety
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrTokenReplayed is returned when a confirmation token nonce was already used
+var ErrTokenReplayed = errors.New("confirmation token has already been used")
+
+// ReplayStore records the nonces of used confirmation tokens until they expire
+type ReplayStore interface {
+	// MarkUsed records the nonce, or returns ErrTokenReplayed if it was already recorded
+	MarkUsed(nonce string, expiresAt time.Time) error
+}
+
+// MemoryReplayStore keeps used nonces in process memory
+type MemoryReplayStore struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time
+}
+
+// NewMemoryReplayStore creates an empty in-memory replay store
+func NewMemoryReplayStore() *MemoryReplayStore {
+	return &MemoryReplayStore{nonces: make(map[string]time.Time)}
+}
+
+// MarkUsed implements ReplayStore
+func (s *MemoryReplayStore) MarkUsed(nonce string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pruneNonces(s.nonces, time.Now())
+	if _, used := s.nonces[nonce]; used {
+		return ErrTokenReplayed
+	}
+	s.nonces[nonce] = expiresAt
+	return nil
+}
+
+// Prune removes nonces whose token has expired and returns how many were removed
+func (s *MemoryReplayStore) Prune(now time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return pruneNonces(s.nonces, now)
+}
+
+// Len returns the number of recorded nonces
+func (s *MemoryReplayStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.nonces)
+}
+
+// Reset forgets all nonces
+func (s *MemoryReplayStore) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nonces = make(map[string]time.Time)
+}
+
+// FileReplayStore persists used nonces to a JSON file so a token cannot be
+// replayed after a restart. The file is re-read on every use under an
+// exclusive file lock, so instances sharing the file see each other's nonces
+// and only one of them can accept a given token.
+type FileReplayStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileReplayStore creates a replay store backed by path
+func NewFileReplayStore(path string) *FileReplayStore {
+	return &FileReplayStore{path: path}
+}
+
+// MarkUsed implements ReplayStore
+func (s *FileReplayStore) MarkUsed(nonce string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Held across load and save: another instance must not read the file
+	// between our check and our write
+	unlock, err := lockStateFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to lock replay store: %w", err)
+	}
+	defer unlock()
+
+	nonces, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	pruneNonces(nonces, time.Now())
+	if _, used := nonces[nonce]; used {
+		return ErrTokenReplayed
+	}
+	nonces[nonce] = expiresAt
+
+	return s.save(nonces)
+}
+
+func (s *FileReplayStore) load() (map[string]time.Time, error) {
+	nonces := make(map[string]time.Time)
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nonces, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay store: %w", err)
+	}
+	if len(data) == 0 {
+		return nonces, nil
+	}
+	if err := json.Unmarshal(data, &nonces); err != nil {
+		return nil, fmt.Errorf("failed to parse replay store %s: %w", s.path, err)
+	}
+	return nonces, nil
+}
+
+// save writes through a temp file and rename so readers never see a partial file
+func (s *FileReplayStore) save(nonces map[string]time.Time) error {
+	data, err := json.Marshal(nonces)
+	if err != nil {
+		return fmt.Errorf("failed to encode replay store: %w", err)
+	}
+
+	if err := writeFileAtomic(s.path, data, ".nonces-*"); err != nil {
+		return fmt.Errorf("failed to write replay store: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes data through a temp file in the same directory and a
+// rename, so readers never see a partial file
+func writeFileAtomic(path string, data []byte, tempPattern string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, tempPattern)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+func pruneNonces(nonces map[string]time.Time, now time.Time) int {
+	pruned := 0
+	for nonce, expiresAt := range nonces {
+		if now.After(expiresAt) {
+			delete(nonces, nonce)
+			pruned++
+		}
+	}
+	return pruned
+}
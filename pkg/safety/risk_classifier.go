@@ -47,12 +47,16 @@ type OperationRisk struct {
 	Description          string
 }
 
-// adminToolNames lists the consolidated admin tool names for IsAdminOperation checks
+// adminToolNames lists the consolidated tool names routed through the safety engine
+// for IsAdminOperation checks. github_repair and github_respond are not admin tools
+// strictly speaking, but they mutate remote state and get the same safeguards.
 var adminToolNames = map[string]bool{
-	"github_admin_repo":       true,
+	"github_admin_repo":        true,
 	"github_branch_protection": true,
 	"github_webhooks":          true,
 	"github_collaborators":     true,
+	"github_repair":            true,
+	"github_respond":           true,
 }
 
 // operationRiskMap defines the risk classification using composite keys "tool:operation"
@@ -94,6 +98,15 @@ var operationRiskMap = map[string]OperationRisk{
 		Category:             "repository_lifecycle",
 		Description:          "Delete repository PERMANENTLY",
 	},
+	"github_admin_repo:restore": {
+		Level:                RiskHigh,
+		RequiresDryRun:       true,
+		RequiresConfirmation: true,
+		RequiresBackup:       false,
+		RequiresAudit:        true,
+		Category:             "repository_settings",
+		Description:          "Re-apply a pre-state backup (settings, branch protection, webhook or collaborator)",
+	},
 
 	// github_branch_protection operations
 	"github_branch_protection:get": {
@@ -262,6 +275,175 @@ var operationRiskMap = map[string]OperationRisk{
 		Category:             "teams",
 		Description:          "Grant team access to repository",
 	},
+
+	// github_repair operations
+	"github_repair:close_issue": {
+		Level:                RiskMedium,
+		RequiresDryRun:       true,
+		RequiresConfirmation: false,
+		RequiresBackup:       false,
+		RequiresAudit:        true,
+		Category:             "issues",
+		Description:          "Close issue with optional comment",
+	},
+	"github_repair:merge_pr": {
+		Level:                RiskHigh,
+		RequiresDryRun:       true,
+		RequiresConfirmation: true,
+		RequiresBackup:       false,
+		RequiresAudit:        true,
+		Category:             "pull_requests",
+		Description:          "Merge pull request into base branch (requires revert to undo)",
+	},
+	"github_repair:rerun_workflow": {
+		Level:                RiskMedium,
+		RequiresDryRun:       true,
+		RequiresConfirmation: false,
+		RequiresBackup:       false,
+		RequiresAudit:        true,
+		Category:             "workflows",
+		Description:          "Re-run GitHub Actions workflow (may trigger deployments)",
+	},
+	"github_repair:dismiss_alert:dependabot": {
+		Level:                RiskHigh,
+		RequiresDryRun:       true,
+		RequiresConfirmation: true,
+		RequiresBackup:       false,
+		RequiresAudit:        true,
+		Category:             "security_alerts",
+		Description:          "Dismiss Dependabot vulnerability alert",
+	},
+	"github_repair:dismiss_alert:code": {
+		Level:                RiskHigh,
+		RequiresDryRun:       true,
+		RequiresConfirmation: true,
+		RequiresBackup:       false,
+		RequiresAudit:        true,
+		Category:             "security_alerts",
+		Description:          "Dismiss code scanning alert",
+	},
+	"github_repair:dismiss_alert:secret": {
+		Level:                RiskCritical,
+		RequiresDryRun:       true,
+		RequiresConfirmation: true,
+		RequiresBackup:       false,
+		RequiresAudit:        true,
+		Category:             "security_alerts",
+		Description:          "Resolve secret scanning alert (leaked credential may remain valid)",
+	},
+
+	// github_respond operations
+	"github_respond:comment_issue": {
+		Level:                RiskLow,
+		RequiresDryRun:       false,
+		RequiresConfirmation: false,
+		RequiresBackup:       false,
+		RequiresAudit:        true,
+		Category:             "issues",
+		Description:          "Add comment to issue",
+	},
+	"github_respond:comment_pr": {
+		Level:                RiskLow,
+		RequiresDryRun:       false,
+		RequiresConfirmation: false,
+		RequiresBackup:       false,
+		RequiresAudit:        true,
+		Category:             "pull_requests",
+		Description:          "Add comment to pull request",
+	},
+	"github_respond:review_pr": {
+		Level:                RiskMedium,
+		RequiresDryRun:       false,
+		RequiresConfirmation: false,
+		RequiresBackup:       false,
+		RequiresAudit:        true,
+		Category:             "pull_requests",
+		Description:          "Submit pull request review (approvals count toward merge requirements)",
+	},
+
+	// Local git writes. These are not admin tools, so the mode safeguards are
+	// not applied; the classification feeds policy rules (minRisk) and the audit log.
+	"git_sync:push": {
+		Level:                RiskMedium,
+		RequiresDryRun:       false,
+		RequiresConfirmation: false,
+		RequiresBackup:       false,
+		RequiresAudit:        true,
+		Category:             "git_push",
+		Description:          "Push local commits to remote",
+	},
+	"git_sync:force_push": {
+		Level:                RiskHigh,
+		RequiresDryRun:       false,
+		RequiresConfirmation: false,
+		RequiresBackup:       false,
+		RequiresAudit:        true,
+		Category:             "git_push",
+		Description:          "Force push with --force-with-lease (rewrites remote history)",
+	},
+	"git_sync:push_upstream": {
+		Level:                RiskMedium,
+		RequiresDryRun:       false,
+		RequiresConfirmation: false,
+		RequiresBackup:       false,
+		RequiresAudit:        true,
+		Category:             "git_push",
+		Description:          "Push a branch and set its upstream",
+	},
+	"gh_push_files": {
+		Level:                RiskMedium,
+		RequiresDryRun:       false,
+		RequiresConfirmation: false,
+		RequiresBackup:       false,
+		RequiresAudit:        true,
+		Category:             "git_push",
+		Description:          "Write files, commit and push in one call",
+	},
+	"git_branch:merge": {
+		Level:                RiskMedium,
+		RequiresDryRun:       false,
+		RequiresConfirmation: false,
+		RequiresBackup:       false,
+		RequiresAudit:        true,
+		Category:             "git_branch",
+		Description:          "Merge a branch into the target branch",
+	},
+	"git_conflict:safe_merge": {
+		Level:                RiskMedium,
+		RequiresDryRun:       false,
+		RequiresConfirmation: false,
+		RequiresBackup:       false,
+		RequiresAudit:        true,
+		Category:             "git_branch",
+		Description:          "Back up, check out the target branch and merge into it",
+	},
+	"git_branch:cherry_pick": {
+		Level:                RiskMedium,
+		RequiresDryRun:       false,
+		RequiresConfirmation: false,
+		RequiresBackup:       false,
+		RequiresAudit:        true,
+		Category:             "git_branch",
+		Description:          "Apply commits from another branch onto the current branch",
+	},
+	"git_branch:revert": {
+		Level:                RiskMedium,
+		RequiresDryRun:       false,
+		RequiresConfirmation: false,
+		RequiresBackup:       false,
+		RequiresAudit:        true,
+		Category:             "git_branch",
+		Description:          "Create commits that undo earlier commits on the current branch",
+	},
+	"git_branch:rebase_interactive": {
+		Level:                RiskHigh,
+		RequiresDryRun:       false,
+		RequiresConfirmation: false,
+		RequiresBackup:       false,
+		RequiresAudit:        true,
+		Category:             "git_branch",
+		Description:          "Rewrite the current branch's history (reorder, reword, squash or drop commits)",
+	},
 }
 
 // ClassifyOperation returns the risk profile for a given operation.
@@ -299,7 +481,7 @@ func GetOperationsByRiskLevel(level RiskLevel) []string {
 // GetOperationsByCategory returns all operations in a specific category
 func GetOperationsByCategory(category string) []string {
 	var operations []string
-	for op, risk := range operationRiskMap{
+	for op, risk := range operationRiskMap {
 		if risk.Category == category {
 			operations = append(operations, op)
 		}
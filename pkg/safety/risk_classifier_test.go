@@ -72,6 +72,33 @@ func TestClassifyOperation(t *testing.T) {
 			wantDryRun:   true,
 			wantConfirm:  true,
 		},
+		{
+			name:         "Secret alert dismissal is critical",
+			operation:    "github_repair:dismiss_alert:secret",
+			wantExists:   true,
+			wantLevel:    RiskCritical,
+			wantCategory: "security_alerts",
+			wantDryRun:   true,
+			wantConfirm:  true,
+		},
+		{
+			name:         "PR merge",
+			operation:    "github_repair:merge_pr",
+			wantExists:   true,
+			wantLevel:    RiskHigh,
+			wantCategory: "pull_requests",
+			wantDryRun:   true,
+			wantConfirm:  true,
+		},
+		{
+			name:         "Issue comment",
+			operation:    "github_respond:comment_issue",
+			wantExists:   true,
+			wantLevel:    RiskLow,
+			wantCategory: "issues",
+			wantDryRun:   false,
+			wantConfirm:  false,
+		},
 		{
 			name:       "Unknown operation",
 			operation:  "github_unknown:operation",
@@ -123,6 +150,9 @@ func TestIsAdminOperation(t *testing.T) {
 		{"Admin composite key", "github_collaborators:add", true},
 		{"Admin composite key - webhook", "github_webhooks:create", true},
 		{"Admin composite key - branch protection", "github_branch_protection:delete", true},
+		{"Repair composite key", "github_repair:merge_pr", true},
+		{"Repair alert composite key", "github_repair:dismiss_alert:secret", true},
+		{"Respond composite key", "github_respond:review_pr", true},
 		{"Non-admin operation - git", "git_status", false},
 		{"Non-admin operation - github repo", "github_repo", false},
 		{"Unknown operation", "unknown_operation", false},
@@ -291,6 +321,19 @@ func TestOperationRiskCompleteness(t *testing.T) {
 		// Teams
 		"github_collaborators:list_teams",
 		"github_collaborators:add_team",
+
+		// Repair
+		"github_repair:close_issue",
+		"github_repair:merge_pr",
+		"github_repair:rerun_workflow",
+		"github_repair:dismiss_alert:dependabot",
+		"github_repair:dismiss_alert:code",
+		"github_repair:dismiss_alert:secret",
+
+		// Respond
+		"github_respond:comment_issue",
+		"github_respond:comment_pr",
+		"github_respond:review_pr",
 	}
 
 	for _, op := range expectedOperations {
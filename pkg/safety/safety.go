@@ -7,9 +7,10 @@ package safety
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
+	"log"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -39,6 +40,25 @@ type SafetyConfig struct {
 	RequireDryRunAbove       RiskLevel
 	EnableAutoBackup         bool
 	BackupPath               string
+	Rules                    []PolicyRule                // ordered; first match decides
+	ProtectedBranches        []string                    // branch globs refused for direct local writes
+	ProtectedBranchAction    PolicyAction                // PolicyDeny (default) or PolicyRequireConfirmation
+	MirrorRemoteProtection   bool                        // also treat branches protected on GitHub as protected
+	TokenKeyPath             string                      // confirmation token signing key; empty uses a per-process key
+	TokenReplayPath          string                      // used token nonces; empty keeps them in memory
+	TokenExpirations         map[RiskLevel]time.Duration // per risk level; missing levels use TokenExpiration
+	AuditKeyPath             string                      // audit entry signing key; empty leaves entries unsigned
+	AuditSinks               []AuditSinkConfig           // external audit destinations (syslog, http, otlp)
+	RateLimits               []RateLimit                 // operation budgets per sliding window
+	RateLimitStatePath       string                      // rate limit counters; empty keeps them in memory
+	ApprovalOperations       []string                    // operations whose confirmation needs human approval
+	ApprovalQueuePath        string                      // pending approval requests; empty keeps them in memory
+	ApprovalExpiration       time.Duration               // how long a request waits for a decision
+	ApprovalHTTPAddr         string                      // loopback address of the approval endpoint; empty disables it
+	SecretScan               SecretScanConfig            // content checks before local commits and API uploads
+	Freezes                  []FreezeWindow              // change-freeze windows; deny ones win over require_confirmation
+	AllowedRoots             []string                    // directories local file access is confined to; empty allows any
+	WebhookAllowlist         URLAllowlist                // internal webhook receivers exempt from the SSRF check
 }
 
 // DefaultConfig returns the default safety configuration (moderate mode)
@@ -63,13 +83,24 @@ type SafetyCheck struct {
 	RequiresBackup       bool
 	ValidationErrors     []error
 	CanProceed           bool
+	DryRun               bool         // stopped at the dry-run gate (requested or required)
+	PolicyRule           string       // label of the policy rule that decided, if any
+	PolicyAction         PolicyAction // action of that rule
 	Message              string
+
+	policyDetail string // why the deciding rule matched, shown in confirmation prompts
 }
 
 // Engine is the main safety engine that orchestrates all safety checks
 type Engine struct {
-	config *SafetyConfig
-	logger *AuditLogger
+	config    *SafetyConfig
+	logger    *AuditLogger
+	guard     branchGuard
+	tokens    *TokenSigner
+	limiter   *RateLimiter
+	approvals *ApprovalQueue
+	secrets   *SecretScanner
+	sandbox   *Sandbox
 }
 
 // NewEngine creates a new safety engine with the given configuration
@@ -78,16 +109,107 @@ func NewEngine(config *SafetyConfig) *Engine {
 		config = DefaultConfig()
 	}
 
-	logger := NewAuditLogger(config.AuditLogPath, config.EnableAuditLog)
+	engine := &Engine{
+		config:  config,
+		logger:  newEngineLogger(config, config.EnableAuditLog),
+		tokens:  newEngineTokenSigner(config),
+		limiter: NewRateLimiter(config.RateLimits, config.RateLimitStatePath),
+		secrets: NewSecretScanner(config.SecretScan),
+		sandbox: NewSandbox(config.AllowedRoots),
+	}
+	engine.setApprovals(config)
+	return engine
+}
 
-	return &Engine{
-		config: config,
-		logger: logger,
+// setApprovals builds the approval queue and hands it to the token signer
+func (e *Engine) setApprovals(config *SafetyConfig) {
+	e.approvals = nil
+	if len(config.ApprovalOperations) > 0 {
+		e.approvals = NewApprovalQueue(config.ApprovalQueuePath, config.ApprovalOperations, config.ApprovalExpiration)
 	}
+	e.tokens.SetApprovalQueue(e.approvals)
 }
 
-// CheckOperation performs a comprehensive safety check for an operation
+// newEngineLogger builds the audit logger, signing entries when a key is
+// configured. A key that cannot be loaded leaves entries hash-chained only.
+func newEngineLogger(config *SafetyConfig, enabled bool) *AuditLogger {
+	logger := NewAuditLogger(config.AuditLogPath, enabled)
+	if !enabled {
+		return logger
+	}
+
+	key, err := LoadAuditKey(config.AuditKeyPath, true)
+	if err != nil {
+		log.Printf("Warning: %v; audit entries will not be signed", err)
+	}
+	if len(key) > 0 {
+		logger.SetSigningKey(key)
+	}
+
+	var sinks []AuditSink
+	for _, sinkConfig := range config.AuditSinks {
+		sink, err := NewAuditSink(sinkConfig)
+		if err != nil {
+			log.Printf("Warning: audit sink disabled: %v", err)
+			continue
+		}
+		sinks = append(sinks, sink)
+	}
+	if len(sinks) > 0 {
+		logger.SetSinks(sinks)
+	}
+	return logger
+}
+
+// newEngineTokenSigner builds the confirmation token signer from the config.
+// A key that cannot be loaded falls back to the per-process key, so tokens
+// still work but do not survive a restart.
+func newEngineTokenSigner(config *SafetyConfig) *TokenSigner {
+	key, err := LoadTokenKey(config.TokenKeyPath)
+	if err != nil {
+		log.Printf("Warning: %v; confirmation tokens will not survive a restart", err)
+	}
+	if len(key) == 0 {
+		key = processTokenKey
+	}
+
+	var replay ReplayStore = defaultReplay
+	if config.TokenReplayPath != "" {
+		replay = NewFileReplayStore(config.TokenReplayPath)
+	}
+
+	return NewTokenSigner(key, replay, config.TokenExpirations)
+}
+
+// CheckOperation performs a comprehensive safety check for an operation. An
+// operation that is authorized to run is counted against the rate limits.
 func (e *Engine) CheckOperation(ctx context.Context, operation string, parameters map[string]interface{}) (*SafetyCheck, error) {
+	check, err := e.checkOperation(ctx, operation, parameters)
+	if err != nil || !check.CanProceed || e.config.Mode == SafetyModeDisabled {
+		return check, err
+	}
+
+	exceeded, limitErr := e.limiter.Consume(operation, check.Risk.Level, time.Now())
+	if limitErr != nil {
+		log.Printf("Warning: rate limits not enforced: %v", limitErr)
+	}
+	if exceeded != nil {
+		e.denyRateLimited(check, exceeded)
+	}
+	return check, nil
+}
+
+// denyRateLimited turns a check into a refusal explaining the exhausted budget
+func (e *Engine) denyRateLimited(check *SafetyCheck, exceeded *RateLimitExceeded) {
+	check.CanProceed = false
+	check.DryRun = false
+	check.RequiresConfirmation = false
+	check.PolicyRule = RateLimitRuleLabelPrefix + exceeded.Label
+	check.PolicyAction = PolicyDeny
+	check.Message = exceeded.Message(check.Operation, time.Now())
+}
+
+func (e *Engine) checkOperation(ctx context.Context, operation string, parameters map[string]interface{}) (*SafetyCheck, error) {
 	check := &SafetyCheck{
 		Operation:        operation,
 		CanProceed:       true,
@@ -100,21 +222,80 @@ func (e *Engine) CheckOperation(ctx context.Context, operation string, parameter
 		return check, nil
 	}
 
+	risk, classified := ClassifyOperation(operation)
+	check.Risk = risk
+
+	// Policy rules apply to every operation, admin or not
+	rule, ruleIndex := MatchPolicyRule(e.config.Rules, operation, parameters, risk.Level)
+	if rule != nil {
+		check.PolicyRule = rule.Label(ruleIndex)
+		check.PolicyAction = rule.Action
+		if rule.Action == PolicyDeny {
+			check.CanProceed = false
+			check.Message = fmt.Sprintf("⛔ %s denied by policy rule '%s'", operation, check.PolicyRule)
+			return check, nil
+		}
+	}
+	forceConfirmation := rule != nil && rule.Action == PolicyRequireConfirmation
+
+	// Change freezes apply to git and GitHub tools alike
+	now := time.Now()
+	protectedReason := e.protectedBranchReason(ctx, operation, parameters)
+	if freeze := FindActiveFreeze(e.config.Freezes, operation, parameters, protectedReason != "", now); freeze != nil {
+		check.PolicyRule = FreezeRuleLabelPrefix + freeze.Label
+		check.policyDetail = freeze.Detail()
+		if freeze.Window.Action != PolicyRequireConfirmation {
+			check.PolicyAction = PolicyDeny
+			check.CanProceed = false
+			check.Message = freeze.Message(operation, now)
+			return check, nil
+		}
+		check.PolicyAction = PolicyRequireConfirmation
+		forceConfirmation = true
+	}
+
+	// Protected-branch guard for direct local writes
+	if protectedReason != "" {
+		check.PolicyRule = ProtectedBranchRuleLabel
+		check.policyDetail = protectedReason
+		if e.config.ProtectedBranchAction != PolicyRequireConfirmation {
+			check.PolicyAction = PolicyDeny
+			check.CanProceed = false
+			check.Message = fmt.Sprintf("🛡️ %s refused: %s. Push to a feature branch and open a pull request instead.", operation, protectedReason)
+			return check, nil
+		}
+		check.PolicyAction = PolicyRequireConfirmation
+		forceConfirmation = true
+	}
+
+	// Refuse early when a budget is used up, before a dry-run or a confirmation
+	// token is issued for an operation that could not run anyway
+	exceeded, limitErr := e.limiter.Check(operation, risk.Level, time.Now())
+	if limitErr != nil {
+		log.Printf("Warning: rate limits not enforced: %v", limitErr)
+	}
+	if exceeded != nil {
+		e.denyRateLimited(check, exceeded)
+		return check, nil
+	}
+
 	// Check if this is an admin operation
 	if !IsAdminOperation(operation) {
-		// Not an admin operation - no safety checks needed
+		// Not an admin operation - only policy rules apply
+		if forceConfirmation {
+			check.RequiresConfirmation = true
+			return e.checkConfirmation(check, operation, parameters)
+		}
 		return check, nil
 	}
 
 	// Get risk classification
-	risk, exists := ClassifyOperation(operation)
-	if !exists {
+	if !classified {
 		return nil, fmt.Errorf("unknown operation: %s", operation)
 	}
-	check.Risk = risk
 
 	// Validate parameters
-	if err := ValidateParameters(operation, parameters); err != nil {
+	if err := validateParameters(operation, parameters, e.config.WebhookAllowlist); err != nil {
 		check.ValidationErrors = append(check.ValidationErrors, err)
 		check.CanProceed = false
 		check.Message = fmt.Sprintf("Parameter validation failed: %v", err)
@@ -142,18 +323,24 @@ func (e *Engine) CheckOperation(ctx context.Context, operation string, parameter
 		check.RequiresBackup = risk.Level >= RiskCritical
 	}
 
+	if forceConfirmation || e.approvals.Requires(operation) {
+		check.RequiresConfirmation = true
+	}
+
 	// Check if dry-run parameter is present
 	if check.RequiresDryRun {
 		dryRun, exists := parameters["dry_run"]
 		if !exists {
 			// Default to dry-run if not specified
 			check.CanProceed = false
+			check.DryRun = true
 			check.Message = fmt.Sprintf("🔍 Dry-run required for %s operation (risk: %s)", operation, risk.Level)
 			return check, nil
 		}
 
 		if dryRunBool, ok := dryRun.(bool); ok && dryRunBool {
 			check.CanProceed = false
+			check.DryRun = true
 			check.Message = "Dry-run mode - preview only"
 			return check, nil
 		}
@@ -161,33 +348,132 @@ func (e *Engine) CheckOperation(ctx context.Context, operation string, parameter
 
 	// Check for confirmation token if required
 	if check.RequiresConfirmation {
-		tokenStr, hasToken := parameters["confirmation_token"].(string)
-		if !hasToken || tokenStr == "" {
-			// Generate confirmation token
-			token, err := GenerateConfirmationToken(operation, parameters, risk.Level)
-			if err != nil {
-				return nil, fmt.Errorf("failed to generate confirmation token: %w", err)
-			}
+		return e.checkConfirmation(check, operation, parameters)
+	}
 
-			check.CanProceed = false
-			check.Message = GetConfirmationMessage(token, risk.Description)
-			return check, nil
+	check.Message = "✅ Safety checks passed - operation authorized"
+	return check, nil
+}
+
+// checkConfirmation issues a confirmation token, or validates the one supplied
+func (e *Engine) checkConfirmation(check *SafetyCheck, operation string, parameters map[string]interface{}) (*SafetyCheck, error) {
+	tokenStr, hasToken := parameters["confirmation_token"].(string)
+	if e.approvals.Requires(operation) && (!hasToken || tokenStr == "") {
+		return e.checkApproval(check, operation, parameters)
+	}
+	if !hasToken || tokenStr == "" {
+		// Generate confirmation token
+		token, err := e.tokens.Generate(operation, parameters, check.Risk.Level)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate confirmation token: %w", err)
 		}
 
-		// Validate confirmation token
-		if err := ValidateConfirmationToken(tokenStr, operation, parameters); err != nil {
-			check.CanProceed = false
-			check.Message = fmt.Sprintf("❌ Confirmation token validation failed: %v", err)
-			return check, fmt.Errorf("invalid confirmation token: %w", err)
+		info := check.Risk.Description
+		if check.PolicyAction == PolicyRequireConfirmation {
+			if info != "" {
+				info += "\n"
+			}
+			info += fmt.Sprintf("Confirmation required by policy rule '%s'", check.PolicyRule)
+			if check.policyDetail != "" {
+				info += fmt.Sprintf(" (%s)", check.policyDetail)
+			}
 		}
+
+		check.CanProceed = false
+		check.Message = GetConfirmationMessage(token, info)
+		return check, nil
+	}
+
+	// Validate confirmation token
+	if err := e.tokens.Validate(tokenStr, operation, parameters); err != nil {
+		check.CanProceed = false
+		check.Message = fmt.Sprintf("❌ Confirmation token validation failed: %v", err)
+		return check, fmt.Errorf("invalid confirmation token: %w", err)
 	}
 
 	check.Message = "✅ Safety checks passed - operation authorized"
 	return check, nil
 }
 
+// checkApproval queues a human approval request for the operation, or, when
+// approval_id is supplied, runs the approved request. The confirmation token
+// stays in the queue and is never shown to the caller.
+func (e *Engine) checkApproval(check *SafetyCheck, operation string, parameters map[string]interface{}) (*SafetyCheck, error) {
+	check.CanProceed = false
+
+	id, _ := parameters["approval_id"].(string)
+	if id == "" {
+		token, err := e.tokens.Generate(operation, parameters, check.Risk.Level)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate confirmation token: %w", err)
+		}
+		req, err := e.approvals.Submit(token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to queue approval request: %w", err)
+		}
+		check.Message = GetApprovalMessage(req, check.Risk.Description, e.config.ApprovalHTTPAddr)
+		return check, nil
+	}
+
+	req, err := e.approvals.Get(id)
+	if err != nil {
+		check.Message = fmt.Sprintf("❌ %v", err)
+		return check, nil
+	}
+	if req.Operation != operation {
+		check.Message = fmt.Sprintf("❌ Approval %s is for %s, not %s", id, req.Operation, operation)
+		return check, nil
+	}
+
+	switch {
+	case req.Status == ApprovalPending && req.Expired(time.Now()):
+		check.Message = fmt.Sprintf("⌛ Approval %s expired at %s without a decision. Call the operation again without approval_id to request a new approval.", id, req.ExpiresAt.Format(time.RFC3339))
+		return check, nil
+	case req.Status == ApprovalPending:
+		check.Message = fmt.Sprintf("⏳ Approval %s is still pending. Retry with approval_id=%s once it has been approved.", id, id)
+		return check, nil
+	case req.Status == ApprovalDenied:
+		check.PolicyRule = ApprovalRuleLabelPrefix + id
+		check.PolicyAction = PolicyDeny
+		check.Message = fmt.Sprintf("⛔ %s denied by %s (approval %s)", operation, req.DecidedBy, id)
+		return check, nil
+	case req.Status == ApprovalUsed:
+		check.Message = fmt.Sprintf("❌ Approval %s has already been used", id)
+		return check, nil
+	}
+
+	// Approved: the token is signed again from the queued claims and must
+	// still match the current parameters
+	tokenStr, err := e.tokens.approvedToken(req)
+	if err == nil {
+		err = e.tokens.Validate(tokenStr, operation, parameters)
+	}
+	if err != nil {
+		check.Message = fmt.Sprintf("❌ Approved request %s cannot be used: %v", id, err)
+		return check, fmt.Errorf("invalid approval: %w", err)
+	}
+	if err := e.approvals.MarkUsed(id); err != nil {
+		log.Printf("Warning: failed to mark approval %s as used: %v", id, err)
+	}
+
+	check.CanProceed = true
+	check.PolicyRule = ApprovalRuleLabelPrefix + id
+	check.Message = fmt.Sprintf("✅ Approved by %s - operation authorized", req.DecidedBy)
+	return check, nil
+}
+
 // LogOperationResult logs the result of an operation to the audit trail
 func (e *Engine) LogOperationResult(operation string, risk OperationRisk, parameters map[string]interface{}, result string, changes []string, rollbackCmd string, executionTime time.Duration, err error) error {
+	return e.logResult(operation, risk, "", parameters, result, changes, rollbackCmd, executionTime, err)
+}
+
+// LogCheckResult logs the result of a checked operation, including the policy
+// rule that decided it
+func (e *Engine) LogCheckResult(check *SafetyCheck, parameters map[string]interface{}, result string, changes []string, rollbackCmd string, executionTime time.Duration, err error) error {
+	return e.logResult(check.Operation, check.Risk, check.PolicyRule, parameters, result, changes, rollbackCmd, executionTime, err)
+}
+
+func (e *Engine) logResult(operation string, risk OperationRisk, policyRule string, parameters map[string]interface{}, result string, changes []string, rollbackCmd string, executionTime time.Duration, err error) error {
 	if !e.config.EnableAuditLog {
 		return nil
 	}
@@ -201,6 +487,7 @@ func (e *Engine) LogOperationResult(operation string, risk OperationRisk, parame
 		Changes:         changes,
 		RollbackCommand: rollbackCmd,
 		ExecutionTimeMs: executionTime.Milliseconds(),
+		PolicyRule:      policyRule,
 	}
 
 	if confirmToken, exists := parameters["confirmation_token"].(string); exists {
@@ -219,6 +506,21 @@ func (e *Engine) GetConfig() *SafetyConfig {
 	return e.config
 }
 
+// GetApprovals returns the approval queue, nil when no operation needs approval
+func (e *Engine) GetApprovals() *ApprovalQueue {
+	return e.approvals
+}
+
+// GetSecretScanner returns the scanner for content about to be committed or uploaded
+func (e *Engine) GetSecretScanner() *SecretScanner {
+	return e.secrets
+}
+
+// GetSandbox returns the sandbox confining local file access
+func (e *Engine) GetSandbox() *Sandbox {
+	return e.sandbox
+}
+
 // GetLogger returns the audit logger
 func (e *Engine) GetLogger() *AuditLogger {
 	return e.logger
@@ -227,8 +529,18 @@ func (e *Engine) GetLogger() *AuditLogger {
 // UpdateConfig updates the safety configuration
 func (e *Engine) UpdateConfig(config *SafetyConfig) {
 	e.config = config
+	e.limiter = NewRateLimiter(config.RateLimits, config.RateLimitStatePath)
+	e.setApprovals(config)
+	e.secrets = NewSecretScanner(config.SecretScan)
+	e.sandbox = NewSandbox(config.AllowedRoots)
 	if config.EnableAuditLog {
-		e.logger = NewAuditLogger(config.AuditLogPath, true)
+		previous := e.logger
+		e.logger = newEngineLogger(config, true)
+		if previous != nil {
+			if err := previous.Close(); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		}
 	}
 }
 
@@ -268,43 +580,13 @@ func (e *Engine) PreviewOperation(ctx context.Context, operation string, paramet
 	return preview, nil
 }
 
-// CreateBackup creates a backup of operation parameters before a destructive operation
-func (e *Engine) CreateBackup(operation string, data interface{}) (string, error) {
-	if !e.config.EnableAutoBackup {
-		return "", nil
-	}
-
-	if err := os.MkdirAll(e.config.BackupPath, 0750); err != nil {
-		return "", fmt.Errorf("failed to create backup directory: %w", err)
-	}
-
-	backupID := fmt.Sprintf("%s-%d", operation, time.Now().Unix())
-	backupPath := fmt.Sprintf("%s/%s.json", e.config.BackupPath, backupID)
-
-	payload := map[string]interface{}{
-		"operation": operation,
-		"timestamp": time.Now().Format(time.RFC3339),
-		"data":      data,
-	}
-
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal backup data: %w", err)
-	}
-
-	if err := os.WriteFile(backupPath, jsonData, 0640); err != nil {
-		return "", fmt.Errorf("failed to write backup file: %w", err)
-	}
-
-	return backupPath, nil
-}
-
 // FormatRollbackCommand generates a rollback command for an operation.
 //
 // For operations whose rollback requires the original state (update_settings,
 // delete*), we cannot generate an executable command from the current parameters
 // alone — instead we point to the backup file in .mcp-backups/ that was written
-// by the safety engine before the destructive operation ran.
+// by the safety engine before the destructive operation ran. When that snapshot
+// is known, FormatRestoreCommand gives the runnable restore call instead.
 //
 // For symmetric operations (add ↔ remove, create ↔ delete with self-contained
 // payload), we emit a runnable composite command.
@@ -315,17 +597,6 @@ func FormatRollbackCommand(operation string, originalParams map[string]interface
 		"github_webhooks:create":   "github_webhooks:delete",
 	}
 
-	// Stateful operations: rollback requires restoring from backup.
-	statefulRollback := map[string]bool{
-		"github_admin_repo:update_settings": true,
-		"github_admin_repo:archive":         true, // unarchive needs original state
-		"github_admin_repo:delete":          true, // backup contains config; recreate is manual
-		"github_webhooks:delete":            true, // recreate needs original config
-		"github_webhooks:update":            true,
-		"github_branch_protection:update":   true,
-		"github_branch_protection:delete":   true,
-	}
-
 	if reverseOp, ok := symmetricReverse[operation]; ok {
 		cmd := reverseOp
 		for key, value := range originalParams {
@@ -337,9 +608,14 @@ func FormatRollbackCommand(operation string, originalParams map[string]interface
 		return cmd
 	}
 
-	if statefulRollback[operation] {
-		return fmt.Sprintf("# Rollback requires manual restore from .mcp-backups/%s-*.json", operation)
+	if IsStatefulOperation(operation) {
+		return fmt.Sprintf("# Rollback requires manual restore from .mcp-backups/%s-*.json", strings.ReplaceAll(operation, ":", "_"))
 	}
 
 	return "# No automatic rollback available"
 }
+
+// FormatRestoreCommand returns the restore call that re-applies a snapshot
+func FormatRestoreCommand(owner, repo, backupPath string) string {
+	return fmt.Sprintf("github_admin_repo:restore --owner=%s --repo=%s --backup=%s", owner, repo, filepath.Base(backupPath))
+}
@@ -234,6 +234,30 @@ func TestEngine_CheckOperation_ModeModerate(t *testing.T) {
 			wantConfirmation: true,
 			wantCanProceed:   false, // Needs confirmation token
 		},
+		{
+			name:      "Secret alert dismissal without token",
+			operation: "github_repair:dismiss_alert:secret",
+			params: map[string]interface{}{
+				"owner":      "test",
+				"repo":       "demo",
+				"number":     float64(3),
+				"resolution": "false_positive",
+				"dry_run":    false,
+			},
+			wantConfirmation: true,
+			wantCanProceed:   false,
+		},
+		{
+			name:      "Issue comment is audited only",
+			operation: "github_respond:comment_issue",
+			params: map[string]interface{}{
+				"owner":  "test",
+				"repo":   "demo",
+				"number": float64(3),
+			},
+			wantConfirmation: false,
+			wantCanProceed:   true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -566,12 +590,21 @@ func TestEngine_CreateBackup(t *testing.T) {
 	}
 	engine := NewEngine(config)
 
-	data := map[string]interface{}{
-		"id":  123,
-		"url": "https://example.com/webhook",
+	snapshot := &Snapshot{
+		Operation: "github_webhooks:delete",
+		Kind:      SnapshotWebhook,
+		Owner:     "test",
+		Repo:      "demo",
+		Target:    "123",
+		Exists:    true,
+		State: map[string]interface{}{
+			"url":    "https://example.com/webhook",
+			"events": []string{"push"},
+		},
+		Parameters: map[string]interface{}{"hook_id": 123, "confirmation_token": "CONF:abc"},
 	}
 
-	backupPath, err := engine.CreateBackup("github_webhooks:delete", data)
+	backupPath, err := engine.CreateBackup(snapshot)
 	if err != nil {
 		t.Fatalf("CreateBackup() error = %v", err)
 	}
@@ -587,6 +620,130 @@ func TestEngine_CreateBackup(t *testing.T) {
 	if !strings.HasSuffix(backupPath, ".json") {
 		t.Error("Backup path should end with .json")
 	}
+
+	loaded, err := engine.LoadBackup(filepath.Base(backupPath))
+	if err != nil {
+		t.Fatalf("LoadBackup() error = %v", err)
+	}
+	if loaded.Kind != SnapshotWebhook || loaded.Target != "123" || !loaded.Exists {
+		t.Errorf("loaded snapshot = %+v", loaded)
+	}
+	if loaded.State["url"] != "https://example.com/webhook" {
+		t.Errorf("loaded state = %v", loaded.State)
+	}
+	if loaded.Parameters["confirmation_token"] != "[REDACTED]" {
+		t.Error("confirmation_token should be redacted in backups")
+	}
+
+	names, err := engine.ListBackups()
+	if err != nil || len(names) != 1 || names[0] != filepath.Base(backupPath) {
+		t.Errorf("ListBackups() = %v, %v", names, err)
+	}
+}
+
+func TestEngine_CreateBackup_Disabled(t *testing.T) {
+	engine := NewEngine(&SafetyConfig{Mode: SafetyModeModerate, BackupPath: t.TempDir()})
+
+	backupPath, err := engine.CreateBackup(&Snapshot{Operation: "github_webhooks:delete"})
+	if err != nil || backupPath != "" {
+		t.Errorf("CreateBackup() with backups disabled = %q, %v", backupPath, err)
+	}
+}
+
+func TestEngine_LoadBackup_StaysInBackupDir(t *testing.T) {
+	root := t.TempDir()
+	backupDir := filepath.Join(root, "backups")
+	if err := os.MkdirAll(backupDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "outside.json"), []byte(`{"operation":"x"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := NewEngine(&SafetyConfig{Mode: SafetyModeModerate, BackupPath: backupDir})
+
+	for _, name := range []string{"../outside.json", filepath.Join(root, "outside.json"), "settings.txt", ""} {
+		if _, err := engine.LoadBackup(name); err == nil {
+			t.Errorf("LoadBackup(%q) should fail", name)
+		}
+	}
+}
+
+func TestDiffState(t *testing.T) {
+	current := map[string]interface{}{
+		"has_wiki":    false,
+		"description": "new",
+		"events":      []string{"push"},
+		"count":       2,
+	}
+	// Values as read back from a JSON backup file
+	snapshot := map[string]interface{}{
+		"has_wiki":    true,
+		"description": "new",
+		"events":      []interface{}{"push"},
+		"count":       float64(1),
+		"homepage":    "https://example.com",
+	}
+
+	got := DiffState(current, snapshot)
+	want := []string{
+		"count: 2 → 1",
+		"has_wiki: false → true",
+		`homepage: (unset) → "https://example.com"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("DiffState() = %q, want %q", got, want)
+	}
+}
+
+func TestStateChanges(t *testing.T) {
+	before := &Snapshot{
+		Kind:   SnapshotBranchProtection,
+		Exists: true,
+		State:  map[string]interface{}{"required_approving_review_count": 1, "enforce_admins": true},
+	}
+
+	got := StateChanges(before, true, map[string]interface{}{"required_approving_review_count": 2, "enforce_admins": true})
+	if strings.Join(got, "\n") != "required_approving_review_count: 1 → 2" {
+		t.Errorf("update changes = %q", got)
+	}
+
+	got = StateChanges(before, false, nil)
+	want := []string{"branch_protection: removed", "enforce_admins: true → (unset)", "required_approving_review_count: 1 → (unset)"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("removal changes = %q, want %q", got, want)
+	}
+
+	added := &Snapshot{Kind: SnapshotCollaborator}
+	got = StateChanges(added, true, map[string]interface{}{"permission": "push"})
+	want = []string{"collaborator: created", `permission: (unset) → "push"`}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("creation changes = %q, want %q", got, want)
+	}
+
+	if got := StateChanges(&Snapshot{Kind: SnapshotCollaborator}, false, nil); len(got) != 0 {
+		t.Errorf("no-op changes = %q", got)
+	}
+}
+
+func TestIsRestorable(t *testing.T) {
+	tests := []struct {
+		operation string
+		want      bool
+	}{
+		{"github_admin_repo:update_settings", true},
+		{"github_branch_protection:delete", true},
+		{"github_webhooks:delete", true},
+		{"github_collaborators:remove", true},
+		{"github_admin_repo:delete", false},
+		{"github_webhooks:create", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRestorable(tt.operation); got != tt.want {
+			t.Errorf("IsRestorable(%s) = %v, want %v", tt.operation, got, tt.want)
+		}
+	}
 }
 
 func TestFormatRollbackCommand(t *testing.T) {
@@ -661,3 +818,11 @@ func TestFormatRollbackCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatRestoreCommand(t *testing.T) {
+	cmd := FormatRestoreCommand("test", "demo", ".mcp-backups/github_webhooks_delete-1.json")
+	want := "github_admin_repo:restore --owner=test --repo=demo --backup=github_webhooks_delete-1.json"
+	if cmd != want {
+		t.Errorf("FormatRestoreCommand() = %q, want %q", cmd, want)
+	}
+}
@@ -0,0 +1,169 @@
+package safety
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// encodedTraversal are escaped forms of ".." and of path separators; they
+// never appear in legitimate local paths
+var encodedTraversal = []string{"..%2f", "..%5c", "%2e%2e", "%252e%252e"}
+
+// Sandbox confines filesystem access to a set of allowed root directories.
+// Paths are resolved through symlinks before the check, so a link inside a
+// root pointing elsewhere does not escape it. A sandbox without roots (or a
+// nil one) does not restrict where paths resolve, but still rejects traversal.
+type Sandbox struct {
+	roots []string // absolute, symlinks resolved
+}
+
+// NewSandbox creates a sandbox limited to roots. Roots that do not exist yet
+// are kept as given; they only match once created.
+func NewSandbox(roots []string) *Sandbox {
+	s := &Sandbox{}
+	for _, root := range roots {
+		resolved, err := realPath(root)
+		if err != nil {
+			resolved = filepath.Clean(root)
+		}
+		s.roots = append(s.roots, resolved)
+	}
+	return s
+}
+
+// Enabled reports whether the sandbox restricts paths to its roots
+func (s *Sandbox) Enabled() bool {
+	return s != nil && len(s.roots) > 0
+}
+
+// Roots returns the resolved allowed roots
+func (s *Sandbox) Roots() []string {
+	if s == nil {
+		return nil
+	}
+	return s.roots
+}
+
+// Resolve returns the absolute, symlink-resolved form of path, which may be
+// absolute or relative to the working directory. Paths containing ".." or
+// encoded traversal, and paths resolving outside the roots, are rejected.
+func (s *Sandbox) Resolve(path string) (string, error) {
+	if err := checkPathSyntax(path, true); err != nil {
+		return "", err
+	}
+	resolved, err := realPath(path)
+	if err != nil {
+		return "", err
+	}
+	return resolved, s.check(path, resolved)
+}
+
+// ResolveIn resolves path relative to base and requires the result to stay
+// inside base (after following symlinks) as well as inside the roots. It is
+// used for repository paths coming from tool arguments or remote trees.
+func (s *Sandbox) ResolveIn(base, path string) (string, error) {
+	if err := checkPathSyntax(path, false); err != nil {
+		return "", err
+	}
+	realBase, err := realPath(base)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := realPath(filepath.Join(base, path))
+	if err != nil {
+		return "", err
+	}
+	if !pathWithin(realBase, resolved) {
+		return "", fmt.Errorf("path %s resolves outside %s", path, base)
+	}
+	return resolved, s.check(path, resolved)
+}
+
+// ResolveSafePath resolves a relative path inside base without any root
+// restriction: traversal, absolute paths and symlinks leaving base are rejected
+func ResolveSafePath(base, path string) (string, error) {
+	return (*Sandbox)(nil).ResolveIn(base, path)
+}
+
+func (s *Sandbox) check(path, resolved string) error {
+	if !s.Enabled() {
+		return nil
+	}
+	for _, root := range s.roots {
+		if pathWithin(root, resolved) {
+			return nil
+		}
+	}
+	return fmt.Errorf("path %s is outside the allowed roots (%s)", path, strings.Join(s.roots, ", "))
+}
+
+// checkPathSyntax rejects empty paths, NUL bytes, ".." components, encoded
+// traversal, doubled separators and, unless allowed, absolute paths
+func checkPathSyntax(path string, allowAbsolute bool) error {
+	if path == "" {
+		return fmt.Errorf("empty path")
+	}
+	if strings.ContainsRune(path, 0) {
+		return fmt.Errorf("invalid path (NUL byte): %q", path)
+	}
+
+	lowered := strings.ToLower(path)
+	for _, pattern := range encodedTraversal {
+		if strings.Contains(lowered, pattern) {
+			return fmt.Errorf("path traversal attempt detected: %s", path)
+		}
+	}
+	if strings.Contains(path, "//") || strings.Contains(path, `\\`) {
+		return fmt.Errorf("path traversal attempt detected: %s", path)
+	}
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if part == ".." {
+			return fmt.Errorf("path traversal attempt detected: %s", path)
+		}
+	}
+
+	if !allowAbsolute && (strings.HasPrefix(path, "/") || strings.HasPrefix(path, `\`) ||
+		(len(path) > 1 && path[1] == ':') || filepath.IsAbs(path)) {
+		return fmt.Errorf("absolute paths not allowed: %s", path)
+	}
+	return nil
+}
+
+// realPath makes path absolute and resolves symlinks in its longest existing
+// prefix; the components that do not exist yet are appended unchanged
+func realPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve %s: %w", path, err)
+	}
+
+	existing, rest := abs, ""
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve %s: %w", existing, err)
+	}
+	return filepath.Join(resolved, rest), nil
+}
+
+// pathWithin reports whether path is root or below it
+func pathWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,119 @@
+package safety
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveSafePath(t *testing.T) {
+	base := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(base, "escape")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "missing"), filepath.Join(base, "dangling")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(base, "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("dir", filepath.Join(base, "inner")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+		errMsg  string
+	}{
+		// Valid paths
+		{"Simple file", "file.txt", false, ""},
+		{"Nested file", "dir/file.txt", false, ""},
+		{"Deep nested", "a/b/c/file.txt", false, ""},
+		{"Dotted name", "dir/..hidden", false, ""},
+		{"Symlink inside base", "inner/file.txt", false, ""},
+
+		// Invalid paths - path traversal
+		{"Parent directory", "../etc/passwd", true, "path traversal"},
+		{"Inner parent", "dir/../../etc/passwd", true, "path traversal"},
+		{"Windows parent", "..\\windows\\system32", true, "path traversal"},
+		{"URL encoded", "..%2fetc%2fpasswd", true, "path traversal"},
+		{"Double URL encoded", "%252e%252e/etc", true, "path traversal"},
+		{"Double slash", "//etc/passwd", true, "path traversal"},
+		{"Double backslash", "\\\\windows", true, "path traversal"},
+		{"NUL byte", "file.txt\x00.png", true, "NUL"},
+		{"Empty", "", true, "empty path"},
+
+		// Invalid paths - absolute
+		{"Absolute Unix", "/etc/passwd", true, "absolute paths not allowed"},
+		{"Absolute Windows", "C:\\windows", true, "absolute paths not allowed"},
+
+		// Invalid paths - symlinks leaving the base
+		{"Symlink escape", "escape/secret.txt", true, "resolves outside"},
+		{"Dangling symlink", "dangling", true, "cannot resolve"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := ResolveSafePath(base, tt.path)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ResolveSafePath() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil && tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("ResolveSafePath() error = %v, should contain %q", err, tt.errMsg)
+			}
+			if err == nil && !filepath.IsAbs(resolved) {
+				t.Errorf("ResolveSafePath() = %q, want an absolute path", resolved)
+			}
+		})
+	}
+}
+
+func TestSandbox_Resolve(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	sandbox := NewSandbox([]string{root})
+
+	if _, err := sandbox.Resolve(filepath.Join(root, "project", "new.txt")); err != nil {
+		t.Errorf("path inside the root should resolve: %v", err)
+	}
+	if _, err := sandbox.Resolve(outside); err == nil || !strings.Contains(err.Error(), "outside the allowed roots") {
+		t.Errorf("path outside the roots should fail: %v", err)
+	}
+	if _, err := sandbox.Resolve(filepath.Join(root, "link", "file.txt")); err == nil {
+		t.Error("symlink pointing outside the roots should fail")
+	}
+	if _, err := sandbox.Resolve(root + "/../" + filepath.Base(outside)); err == nil {
+		t.Error("traversal out of the root should fail")
+	}
+	if _, err := sandbox.Resolve(root + "-sibling"); err == nil {
+		t.Error("a sibling sharing the root's prefix is not inside it")
+	}
+
+	if _, err := sandbox.ResolveIn(root, "link/file.txt"); err == nil {
+		t.Error("ResolveIn should reject symlinks leaving the base")
+	}
+	if _, err := NewSandbox([]string{root}).ResolveIn(outside, "file.txt"); err == nil {
+		t.Error("ResolveIn should enforce the roots too")
+	}
+
+	var unrestricted *Sandbox
+	if unrestricted.Enabled() {
+		t.Error("nil sandbox should not restrict")
+	}
+	if _, err := unrestricted.Resolve(outside); err != nil {
+		t.Errorf("nil sandbox should accept any path: %v", err)
+	}
+	if _, err := unrestricted.Resolve(outside + "/../etc"); err == nil {
+		t.Error("nil sandbox should still reject traversal")
+	}
+}
@@ -0,0 +1,184 @@
+package safety
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Secret scan actions
+const (
+	SecretScanBlock = "block" // refuse the write (default)
+	SecretScanWarn  = "warn"  // write, but report the findings
+	SecretScanOff   = "off"   // do not scan
+)
+
+// DefaultSecretEntropyThreshold is the minimum Shannon entropy (bits per
+// character) of a value assigned to a secret-looking key to be reported
+const DefaultSecretEntropyThreshold = 3.5
+
+// SecretRule is a named pattern. When the pattern has a capture group, the
+// first group is the secret; otherwise the whole match is.
+type SecretRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// builtinSecretRules detect credentials with a recognizable shape
+var builtinSecretRules = []SecretRule{
+	{"aws_access_key_id", regexp.MustCompile(`\b((?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16})\b`)},
+	{"aws_secret_access_key", regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})\b`)},
+	{"github_token", regexp.MustCompile(`\b((?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36,255})\b`)},
+	{"github_fine_grained_token", regexp.MustCompile(`\b(github_pat_[A-Za-z0-9_]{50,255})\b`)},
+	{"slack_token", regexp.MustCompile(`\b(xox[abprs]-[0-9A-Za-z-]{10,})\b`)},
+	{"private_key", regexp.MustCompile(`-----BEGIN (?:[A-Z0-9]+ )*PRIVATE KEY(?: BLOCK)?-----`)},
+}
+
+// secretAssignment finds values assigned to secret-looking keys; they are
+// reported only when their entropy reaches the threshold, so placeholders
+// like "changeme" or "your-token-here" pass
+var secretAssignment = regexp.MustCompile(`(?i)(?:secret|token|passw(?:or)?d|pwd|api_?key|access_?key|private_?key|credential|auth)[\w.-]*["']?\s*[:=]\s*["']?([A-Za-z0-9+/_\-.=]{16,})`)
+
+// SecretScanConfig configures the secret scanner. The zero value blocks on the
+// built-in rules with the default entropy threshold.
+type SecretScanConfig struct {
+	Action           string           // SecretScanBlock (default), SecretScanWarn or SecretScanOff
+	Rules            []SecretRule     // extra patterns, checked after the built-in ones
+	Allowlist        []*regexp.Regexp // findings whose secret or line matches are ignored
+	AllowPaths       []string         // file globs never scanned, e.g. "testdata/**"
+	EntropyThreshold float64          // 0 uses DefaultSecretEntropyThreshold
+}
+
+// SecretFinding is one potential secret
+type SecretFinding struct {
+	File   string
+	Line   int
+	Rule   string
+	Secret string // redacted
+}
+
+// SecretScanner finds credentials in content before it is committed or uploaded
+type SecretScanner struct {
+	config SecretScanConfig
+	rules  []SecretRule
+}
+
+// NewSecretScanner creates a scanner from its configuration
+func NewSecretScanner(config SecretScanConfig) *SecretScanner {
+	if config.Action == "" {
+		config.Action = SecretScanBlock
+	}
+	if config.EntropyThreshold <= 0 {
+		config.EntropyThreshold = DefaultSecretEntropyThreshold
+	}
+	rules := append(append([]SecretRule{}, builtinSecretRules...), config.Rules...)
+	return &SecretScanner{config: config, rules: rules}
+}
+
+// Action returns what happens when secrets are found
+func (s *SecretScanner) Action() string {
+	return s.config.Action
+}
+
+// Scan returns the potential secrets in a file's content. Binary content and
+// allowlisted paths are skipped.
+func (s *SecretScanner) Scan(file string, content []byte) []SecretFinding {
+	if s == nil || s.config.Action == SecretScanOff || bytes.IndexByte(content, 0) >= 0 {
+		return nil
+	}
+	if matchAnyGlob(s.config.AllowPaths, strings.TrimPrefix(file, "./")) {
+		return nil
+	}
+
+	var findings []SecretFinding
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		reported := make(map[string]bool)
+
+		report := func(rule, secret string) {
+			if reported[secret] || s.allowed(secret, line) {
+				return
+			}
+			reported[secret] = true
+			findings = append(findings, SecretFinding{File: file, Line: i + 1, Rule: rule, Secret: redactSecret(secret)})
+		}
+
+		for _, rule := range s.rules {
+			for _, m := range rule.Pattern.FindAllStringSubmatch(line, -1) {
+				secret := m[0]
+				if len(m) > 1 && m[1] != "" {
+					secret = m[1]
+				}
+				report(rule.Name, secret)
+			}
+		}
+		for _, m := range secretAssignment.FindAllStringSubmatch(line, -1) {
+			if !reported[m[1]] && shannonEntropy(m[1]) >= s.config.EntropyThreshold {
+				report("high_entropy_secret", m[1])
+			}
+		}
+	}
+	return findings
+}
+
+func (s *SecretScanner) allowed(secret, line string) bool {
+	for _, re := range s.config.Allowlist {
+		if re.MatchString(secret) || re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatSecretReport lists the findings by file and line
+func FormatSecretReport(findings []SecretFinding, blocked bool) string {
+	var b strings.Builder
+	if blocked {
+		fmt.Fprintf(&b, "🔐 Secret scan blocked the write: %d potential secret(s) found\n", len(findings))
+	} else {
+		fmt.Fprintf(&b, "⚠️ Secret scan: %d potential secret(s) found\n", len(findings))
+	}
+	for _, f := range findings {
+		fmt.Fprintf(&b, "  - %s:%d %s (%s)\n", f.File, f.Line, f.Rule, f.Secret)
+	}
+	b.WriteString("Remove the secrets (use environment variables or a secret store), or allowlist false positives in safety.json (secretScanning.allowlist, secretScanning.allowPaths).")
+	return b.String()
+}
+
+// SecretsFoundError is returned when a scan blocks a write
+type SecretsFoundError struct {
+	Findings []SecretFinding
+}
+
+func (e *SecretsFoundError) Error() string {
+	return FormatSecretReport(e.Findings, true)
+}
+
+// redactSecret keeps the first four characters, enough to recognize the
+// credential type without repeating it
+func redactSecret(secret string) string {
+	if len(secret) <= 8 {
+		return fmt.Sprintf("%d chars", len(secret))
+	}
+	return fmt.Sprintf("%s…, %d chars", secret[:4], len(secret))
+}
+
+// shannonEntropy returns the entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+	n := float64(len([]rune(s)))
+	var entropy float64
+	for _, c := range counts {
+		p := float64(c) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
@@ -0,0 +1,108 @@
+package safety
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// Test credentials are assembled at runtime so this file does not trip scanners itself
+var (
+	testAWSKeyID   = "AKIA" + "IOSFODNN7EXAMPLE"
+	testAWSSecret  = "wJalrXUtnFEMI/K7MDENG/" + "bPxRfiCYEXAMPLEKEY"
+	testGitHubPAT  = "ghp_" + "a1B2c3D4e5F6g7H8i9J0k1L2m3N4o5P6q7R8"
+	testPrivateKey = "-----BEGIN " + "RSA PRIVATE KEY-----"
+)
+
+func TestSecretScanner_BuiltinRules(t *testing.T) {
+	scanner := NewSecretScanner(SecretScanConfig{})
+
+	tests := []struct {
+		name     string
+		content  string
+		wantRule string
+		wantLine int
+	}{
+		{"AWS access key", "region=eu-west-1\nAWS_ACCESS_KEY_ID=" + testAWSKeyID, "aws_access_key_id", 2},
+		{"AWS secret key", "aws_secret_access_key = " + testAWSSecret, "aws_secret_access_key", 1},
+		{"GitHub token", `const token = "` + testGitHubPAT + `"`, "github_token", 1},
+		{"Private key", "\n\n" + testPrivateKey + "\nMIIEow...", "private_key", 3},
+		{"High entropy assignment", `api_key: "Zx9Qm2Lr7Tv4Kp1Wn8Hs"`, "high_entropy_secret", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := scanner.Scan("config/.env", []byte(tt.content))
+			if len(findings) == 0 {
+				t.Fatalf("no findings in %q", tt.content)
+			}
+			f := findings[0]
+			if f.Rule != tt.wantRule || f.Line != tt.wantLine || f.File != "config/.env" {
+				t.Errorf("finding = %+v, want %s at line %d", f, tt.wantRule, tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestSecretScanner_NoFalsePositives(t *testing.T) {
+	scanner := NewSecretScanner(SecretScanConfig{})
+
+	clean := []string{
+		`password: "changeme"`,
+		`api_key = "your-api-key-here"`,
+		`commit 9fceb02d0ae598e95dc970b74767f19372d61af8`,
+		`token := os.Getenv("GITHUB_TOKEN")`,
+		"func main() {\n\tfmt.Println(\"hello\")\n}",
+	}
+	for _, content := range clean {
+		if findings := scanner.Scan("main.go", []byte(content)); len(findings) > 0 {
+			t.Errorf("unexpected findings in %q: %+v", content, findings)
+		}
+	}
+
+	if findings := scanner.Scan("logo.png", append([]byte(testAWSKeyID), 0, 1, 2)); len(findings) > 0 {
+		t.Error("binary content should not be scanned")
+	}
+}
+
+func TestSecretScanner_ConfigurableRulesAndAllowlist(t *testing.T) {
+	scanner := NewSecretScanner(SecretScanConfig{
+		Rules:      []SecretRule{{Name: "internal_token", Pattern: regexp.MustCompile(`\b(acme_[a-z0-9]{12})\b`)}},
+		Allowlist:  []*regexp.Regexp{regexp.MustCompile(`EXAMPLE$`)},
+		AllowPaths: []string{"testdata/**"},
+	})
+
+	findings := scanner.Scan("app.yml", []byte("token: acme_0123456789ab"))
+	if len(findings) != 1 || findings[0].Rule != "internal_token" {
+		t.Errorf("custom rule findings = %+v", findings)
+	}
+	if findings[0].Secret != "acme…, 17 chars" {
+		t.Errorf("secret should be redacted, got %q", findings[0].Secret)
+	}
+
+	if findings := scanner.Scan("docs/aws.md", []byte(testAWSKeyID)); len(findings) != 0 {
+		t.Errorf("allowlisted secret reported: %+v", findings)
+	}
+	if findings := scanner.Scan("testdata/keys/id_rsa", []byte(testPrivateKey)); len(findings) != 0 {
+		t.Errorf("allowlisted path scanned: %+v", findings)
+	}
+
+	off := NewSecretScanner(SecretScanConfig{Action: SecretScanOff})
+	if findings := off.Scan(".env", []byte(testGitHubPAT)); len(findings) != 0 {
+		t.Error("scanner with action off should report nothing")
+	}
+}
+
+func TestFormatSecretReport(t *testing.T) {
+	findings := NewSecretScanner(SecretScanConfig{}).Scan(".env", []byte("A=1\nGITHUB_TOKEN="+testGitHubPAT))
+	err := &SecretsFoundError{Findings: findings}
+
+	report := err.Error()
+	for _, want := range []string{"blocked", ".env:2 github_token", "ghp_…"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report should contain %q:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, testGitHubPAT) {
+		t.Error("report must not repeat the secret")
+	}
+}
@@ -0,0 +1,235 @@
+package safety
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Snapshot kinds: which piece of GitHub state a backup holds
+const (
+	SnapshotRepoSettings     = "repo_settings"
+	SnapshotBranchProtection = "branch_protection"
+	SnapshotWebhook          = "webhook"
+	SnapshotCollaborator     = "collaborator"
+)
+
+// Snapshot is the pre-state captured before a stateful operation. State holds
+// the same keys the corresponding update operation accepts, so a restore can
+// re-apply it. Exists is false when there was nothing to capture (an unprotected
+// branch, a user without access); restoring such a snapshot removes the state.
+type Snapshot struct {
+	Operation  string                 `json:"operation"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Kind       string                 `json:"kind,omitempty"`
+	Owner      string                 `json:"owner,omitempty"`
+	Repo       string                 `json:"repo,omitempty"`
+	Target     string                 `json:"target,omitempty"` // branch, hook ID or username
+	Exists     bool                   `json:"exists"`
+	State      map[string]interface{} `json:"state,omitempty"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"` // sanitized call parameters
+}
+
+// statefulOperations change existing state; their rollback needs the captured
+// pre-state. Delete of a repository is captured for the record but cannot be
+// restored.
+var statefulOperations = map[string]string{
+	"github_admin_repo:update_settings":      SnapshotRepoSettings,
+	"github_admin_repo:archive":              SnapshotRepoSettings,
+	"github_admin_repo:delete":               SnapshotRepoSettings,
+	"github_branch_protection:update":        SnapshotBranchProtection,
+	"github_branch_protection:delete":        SnapshotBranchProtection,
+	"github_webhooks:update":                 SnapshotWebhook,
+	"github_webhooks:delete":                 SnapshotWebhook,
+	"github_collaborators:update_permission": SnapshotCollaborator,
+	"github_collaborators:remove":            SnapshotCollaborator,
+}
+
+// SnapshotKind returns the kind of state an operation changes, or "" when the
+// operation is not stateful
+func SnapshotKind(operation string) string {
+	return statefulOperations[operation]
+}
+
+// diffOnlyOperations create state that can be read back afterwards. Their
+// changes are recorded, but a rollback needs no pre-state.
+var diffOnlyOperations = map[string]string{
+	"github_collaborators:add": SnapshotCollaborator,
+}
+
+// DiffKind returns the kind of state read before and after an operation to
+// record its changes, or "" when the result cannot be read back
+func DiffKind(operation string) string {
+	if kind := SnapshotKind(operation); kind != "" {
+		return kind
+	}
+	return diffOnlyOperations[operation]
+}
+
+// IsStatefulOperation reports whether an operation's rollback needs a pre-state snapshot
+func IsStatefulOperation(operation string) bool {
+	_, ok := statefulOperations[operation]
+	return ok
+}
+
+// IsRestorable reports whether a snapshot taken before operation can be re-applied
+func IsRestorable(operation string) bool {
+	return IsStatefulOperation(operation) && operation != "github_admin_repo:delete"
+}
+
+// CreateBackup writes a snapshot to the backup directory and returns its path.
+// Returns "" without error when automatic backups are disabled.
+func (e *Engine) CreateBackup(snapshot *Snapshot) (string, error) {
+	if !e.config.EnableAutoBackup {
+		return "", nil
+	}
+
+	if err := os.MkdirAll(e.config.BackupPath, 0750); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	if snapshot.Timestamp.IsZero() {
+		snapshot.Timestamp = time.Now()
+	}
+	snapshot.Parameters = sanitizeParameters(snapshot.Parameters)
+
+	// ':' is not valid in Windows file names
+	backupID := fmt.Sprintf("%s-%d", strings.ReplaceAll(snapshot.Operation, ":", "_"), snapshot.Timestamp.UnixNano())
+	backupPath := filepath.Join(e.config.BackupPath, backupID+".json")
+
+	jsonData, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal backup data: %w", err)
+	}
+
+	if err := os.WriteFile(backupPath, jsonData, 0640); err != nil {
+		return "", fmt.Errorf("failed to write backup file: %w", err)
+	}
+
+	return backupPath, nil
+}
+
+// LoadBackup reads a snapshot from the backup directory. Only the file name is
+// used, so a backup can never be read from outside the directory.
+func (e *Engine) LoadBackup(name string) (*Snapshot, error) {
+	base := filepath.Base(filepath.Clean(name))
+	if base == "." || base == string(filepath.Separator) || !strings.HasSuffix(base, ".json") {
+		return nil, fmt.Errorf("invalid backup name: %s", name)
+	}
+
+	data, err := os.ReadFile(filepath.Join(e.config.BackupPath, base))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("backup not found: %s", base)
+		}
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse backup %s: %w", base, err)
+	}
+	return &snapshot, nil
+}
+
+// ListBackups returns the backup file names, newest first
+func (e *Engine) ListBackups() ([]string, error) {
+	entries, err := os.ReadDir(e.config.BackupPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	type backupFile struct {
+		name    string
+		modTime time.Time
+	}
+	var files []backupFile
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, backupFile{name: entry.Name(), modTime: info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.After(files[j].modTime) })
+
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = f.name
+	}
+	return names, nil
+}
+
+// DiffState lists the fields that differ between the current state and a
+// snapshot, as "field: current → snapshot" lines sorted by field name
+func DiffState(current, snapshot map[string]interface{}) []string {
+	keys := make(map[string]bool)
+	for k := range current {
+		keys[k] = true
+	}
+	for k := range snapshot {
+		keys[k] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var diff []string
+	for _, k := range sorted {
+		cur, curOK := current[k]
+		old, oldOK := snapshot[k]
+		curStr, oldStr := formatStateValue(cur, curOK), formatStateValue(old, oldOK)
+		if curStr != oldStr {
+			diff = append(diff, fmt.Sprintf("%s: %s → %s", k, curStr, oldStr))
+		}
+	}
+	return diff
+}
+
+// StateChanges lists what an operation changed, from the snapshot taken before
+// it and the state read back after it, as "field: before → after" lines.
+// Creating or removing the whole state is reported first.
+func StateChanges(before *Snapshot, afterExists bool, after map[string]interface{}) []string {
+	var old map[string]interface{}
+	if before.Exists {
+		old = before.State
+	}
+	if !afterExists {
+		after = nil
+	}
+
+	var changes []string
+	switch {
+	case before.Exists && !afterExists:
+		changes = append(changes, before.Kind+": removed")
+	case !before.Exists && afterExists:
+		changes = append(changes, before.Kind+": created")
+	}
+	return append(changes, DiffState(old, after)...)
+}
+
+// formatStateValue renders values canonically so a value read back from JSON
+// (float64, []interface{}) compares equal to the freshly captured one
+func formatStateValue(value interface{}, present bool) string {
+	if !present || value == nil {
+		return "(unset)"
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
@@ -0,0 +1,176 @@
+package safety
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// internalNetworks are the ranges a webhook receiver must not resolve to:
+// GitHub Enterprise Server delivers webhooks from inside the network, so a
+// hook pointing at them reaches internal services (SSRF)
+var internalNetworks = []struct {
+	prefix netip.Prefix
+	name   string
+}{
+	{netip.MustParsePrefix("0.0.0.0/8"), "unspecified"},
+	{netip.MustParsePrefix("10.0.0.0/8"), "private (RFC 1918)"},
+	{netip.MustParsePrefix("100.64.0.0/10"), "carrier-grade NAT"},
+	{netip.MustParsePrefix("127.0.0.0/8"), "loopback"},
+	{netip.MustParsePrefix("169.254.0.0/16"), "link-local / cloud metadata"},
+	{netip.MustParsePrefix("172.16.0.0/12"), "private (RFC 1918)"},
+	{netip.MustParsePrefix("192.0.0.0/24"), "IETF protocol assignments"},
+	{netip.MustParsePrefix("192.0.2.0/24"), "documentation (TEST-NET-1)"},
+	{netip.MustParsePrefix("192.168.0.0/16"), "private (RFC 1918)"},
+	{netip.MustParsePrefix("198.18.0.0/15"), "benchmarking"},
+	{netip.MustParsePrefix("198.51.100.0/24"), "documentation (TEST-NET-2)"},
+	{netip.MustParsePrefix("203.0.113.0/24"), "documentation (TEST-NET-3)"},
+	{netip.MustParsePrefix("224.0.0.0/4"), "multicast"},
+	{netip.MustParsePrefix("240.0.0.0/4"), "reserved"},
+	{netip.MustParsePrefix("::/128"), "unspecified"},
+	{netip.MustParsePrefix("::1/128"), "loopback"},
+	{netip.MustParsePrefix("64:ff9b::/96"), "NAT64"},
+	{netip.MustParsePrefix("2001:db8::/32"), "documentation"},
+	{netip.MustParsePrefix("fc00::/7"), "unique local (ULA)"},
+	{netip.MustParsePrefix("fe80::/10"), "link-local"},
+	{netip.MustParsePrefix("ff00::/8"), "multicast"},
+}
+
+// IPv6 ranges that carry an IPv4 address and route to it: IPv4-compatible
+// ::a.b.c.d (deprecated, but still parsed), 6to4 2002:aabb:ccdd::/48 and
+// Teredo 2001::/32, whose last 32 bits are the client IPv4 with every bit
+// flipped
+var (
+	ipv4Compatible = netip.MustParsePrefix("::/96")
+	sixToFour      = netip.MustParsePrefix("2002::/16")
+	teredo         = netip.MustParsePrefix("2001::/32")
+)
+
+// lookupNetIP resolves webhook hosts; tests replace it
+var lookupNetIP = net.DefaultResolver.LookupNetIP
+
+// urlLookupTimeout bounds the DNS resolution of a webhook host
+const urlLookupTimeout = 5 * time.Second
+
+// URLAllowlist lets webhooks reach known internal receivers, such as a
+// GitHub Enterprise integration service on the private network
+type URLAllowlist struct {
+	Hosts    []string       // host globs, e.g. "hooks.corp.example" or "*.ci.corp.example"
+	Networks []netip.Prefix // resolved addresses inside these ranges are accepted
+}
+
+// CheckWebhookURL parses a webhook URL and rejects it when the host is, or
+// resolves to, a loopback, private, link-local, ULA or metadata address not
+// covered by the allowlist. Hostnames are resolved, so names pointing at
+// internal ranges are caught too; the check cannot prevent a later DNS change.
+func CheckWebhookURL(raw string, allow URLAllowlist) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be a valid HTTP/HTTPS URL")
+	}
+	if u.User != nil {
+		return fmt.Errorf("must not contain credentials")
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "" {
+		return fmt.Errorf("must be a valid HTTP/HTTPS URL")
+	}
+	if matchAnyGlob(allow.Hosts, host) {
+		return nil
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("cannot use localhost or internal IPs")
+	}
+
+	var addrs []netip.Addr
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = []netip.Addr{addr}
+	} else {
+		labels := strings.Split(host, ".")
+		if last := labels[len(labels)-1]; isDigits(last) || strings.HasPrefix(last, "0x") {
+			return fmt.Errorf("cannot use localhost or internal IPs (non-canonical IP address %s)", host)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), urlLookupTimeout)
+		defer cancel()
+		addrs, err = lookupNetIP(ctx, "ip", host)
+		if err != nil {
+			return fmt.Errorf("cannot resolve host %s: %v", host, err)
+		}
+		if len(addrs) == 0 {
+			return fmt.Errorf("cannot resolve host %s: no addresses", host)
+		}
+	}
+
+	for _, addr := range addrs {
+		addr = addr.Unmap().WithZone("")
+		if allowedAddr(allow.Networks, addr) {
+			continue
+		}
+		if name, internal := internalRange(addr); internal {
+			if addr.String() == host {
+				return fmt.Errorf("cannot use localhost or internal IPs (%s is %s)", addr, name)
+			}
+			return fmt.Errorf("cannot use localhost or internal IPs (%s resolves to %s, %s)", host, addr, name)
+		}
+	}
+	return nil
+}
+
+// internalRange names the internal range containing addr, if any. IPv6
+// addresses embedding an IPv4 address are checked against that address too.
+func internalRange(addr netip.Addr) (string, bool) {
+	for _, network := range internalNetworks {
+		if network.prefix.Contains(addr) {
+			return network.name, true
+		}
+	}
+	if embedded, ok := embeddedIPv4(addr); ok {
+		if name, internal := internalRange(embedded); internal {
+			return fmt.Sprintf("%s, embedded %s", name, embedded), true
+		}
+	}
+	return "", false
+}
+
+// embeddedIPv4 extracts the IPv4 address of an IPv4-compatible, 6to4 or
+// Teredo address
+func embeddedIPv4(addr netip.Addr) (netip.Addr, bool) {
+	b := addr.As16()
+	switch {
+	case !addr.Is6():
+		return netip.Addr{}, false
+	case ipv4Compatible.Contains(addr):
+		return netip.AddrFrom4([4]byte{b[12], b[13], b[14], b[15]}), true
+	case sixToFour.Contains(addr):
+		return netip.AddrFrom4([4]byte{b[2], b[3], b[4], b[5]}), true
+	case teredo.Contains(addr):
+		return netip.AddrFrom4([4]byte{^b[12], ^b[13], ^b[14], ^b[15]}), true
+	}
+	return netip.Addr{}, false
+}
+
+func allowedAddr(networks []netip.Prefix, addr netip.Addr) bool {
+	for _, network := range networks {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -19,8 +19,14 @@ func (e *ValidationError) Error() string {
 
 // ValidateParameters validates all parameters for an operation
 func ValidateParameters(operation string, parameters map[string]interface{}) error {
+	return validateParameters(operation, parameters, URLAllowlist{})
+}
+
+// validateParameters validates parameters, letting webhook URLs reach the
+// internal receivers in allow
+func validateParameters(operation string, parameters map[string]interface{}, allow URLAllowlist) error {
 	// Get operation-specific validation rules
-	validators, err := getValidatorsForOperation(operation)
+	validators, err := getValidatorsForOperation(operation, allow)
 	if err != nil {
 		return err
 	}
@@ -41,7 +47,7 @@ func ValidateParameters(operation string, parameters map[string]interface{}) err
 type Validator func(param string, value interface{}) error
 
 // getValidatorsForOperation returns validation functions for an operation
-func getValidatorsForOperation(operation string) (map[string]Validator, error) {
+func getValidatorsForOperation(operation string, allow URLAllowlist) (map[string]Validator, error) {
 	validators := make(map[string]Validator)
 
 	// Common validators for all GitHub operations
@@ -58,7 +64,7 @@ func getValidatorsForOperation(operation string) (map[string]Validator, error) {
 		validators["permission"] = validatePermission
 
 	case "github_webhooks:create", "github_webhooks:update":
-		validators["url"] = validateURL
+		validators["url"] = urlValidator(allow)
 		validators["content_type"] = validateContentType
 		validators["events"] = validateEvents
 
@@ -74,6 +80,36 @@ func getValidatorsForOperation(operation string) (map[string]Validator, error) {
 		validators["has_issues"] = validateBoolean
 		validators["has_wiki"] = validateBoolean
 		validators["has_projects"] = validateBoolean
+
+	case "github_admin_repo:restore":
+		validators["backup"] = validateBackupName
+
+	case "github_repair:close_issue", "github_respond:comment_issue", "github_respond:comment_pr":
+		validators["number"] = validatePositiveInteger
+
+	case "github_repair:merge_pr":
+		validators["number"] = validatePositiveInteger
+		validators["merge_method"] = validateMergeMethod
+
+	case "github_repair:rerun_workflow":
+		validators["run_id"] = validatePositiveInteger
+		validators["failed_jobs_only"] = validateBoolean
+
+	case "github_repair:dismiss_alert:dependabot":
+		validators["number"] = validatePositiveInteger
+		validators["reason"] = validateDependabotDismissReason
+
+	case "github_repair:dismiss_alert:code":
+		validators["number"] = validatePositiveInteger
+		validators["reason"] = validateCodeScanningDismissReason
+
+	case "github_repair:dismiss_alert:secret":
+		validators["number"] = validatePositiveInteger
+		validators["resolution"] = validateSecretScanningResolution
+
+	case "github_respond:review_pr":
+		validators["number"] = validatePositiveInteger
+		validators["event"] = validateReviewEvent
 	}
 
 	return validators, nil
@@ -185,25 +221,44 @@ func validatePermission(param string, value interface{}) error {
 	return nil
 }
 
-// validateURL validates webhook URL format
+// validateURL validates webhook URL format and rejects internal targets
 func validateURL(param string, value interface{}) error {
+	return urlValidator(URLAllowlist{})(param, value)
+}
+
+// urlValidator validates webhook URLs, accepting the internal receivers in allow
+func urlValidator(allow URLAllowlist) Validator {
+	return func(param string, value interface{}) error {
+		str, ok := value.(string)
+		if !ok {
+			return &ValidationError{param, value, "must be a string"}
+		}
+
+		if len(str) > 2000 {
+			return &ValidationError{param, value, "too long (max 2000 characters)"}
+		}
+
+		// SSRF prevention: the host must not be or resolve to an internal address
+		if err := CheckWebhookURL(str, allow); err != nil {
+			return &ValidationError{param, value, err.Error()}
+		}
+
+		return nil
+	}
+}
+
+// validateBackupName validates a backup file name (a bare name inside the backup directory)
+func validateBackupName(param string, value interface{}) error {
 	str, ok := value.(string)
 	if !ok {
 		return &ValidationError{param, value, "must be a string"}
 	}
 
-	if !strings.HasPrefix(str, "http://") && !strings.HasPrefix(str, "https://") {
-		return &ValidationError{param, value, "must be a valid HTTP/HTTPS URL"}
+	if !strings.HasSuffix(str, ".json") {
+		return &ValidationError{param, value, "must be a .json backup file name"}
 	}
-
-	// Check for localhost/internal IPs (SSRF prevention)
-	lowered := strings.ToLower(str)
-	if strings.Contains(lowered, "localhost") || strings.Contains(lowered, "127.0.0.1") || strings.Contains(lowered, "0.0.0.0") {
-		return &ValidationError{param, value, "cannot use localhost or internal IPs"}
-	}
-
-	if len(str) > 2000 {
-		return &ValidationError{param, value, "too long (max 2000 characters)"}
+	if strings.ContainsAny(str, `/\`) || strings.Contains(str, "..") {
+		return &ValidationError{param, value, "must be a file name inside the backup directory, not a path"}
 	}
 
 	return nil
@@ -264,23 +319,23 @@ func validateEvents(param string, value interface{}) error {
 func validateSingleEvent(param string, event string) error {
 	// Common GitHub webhook events
 	validEvents := map[string]bool{
-		"*":                       true,
-		"push":                    true,
-		"pull_request":            true,
-		"issues":                  true,
-		"issue_comment":           true,
-		"release":                 true,
-		"create":                  true,
-		"delete":                  true,
-		"fork":                    true,
-		"watch":                   true,
-		"star":                    true,
-		"workflow_run":            true,
-		"check_run":               true,
-		"check_suite":             true,
-		"deployment":              true,
-		"deployment_status":       true,
-		"repository":              true,
+		"*":                              true,
+		"push":                           true,
+		"pull_request":                   true,
+		"issues":                         true,
+		"issue_comment":                  true,
+		"release":                        true,
+		"create":                         true,
+		"delete":                         true,
+		"fork":                           true,
+		"watch":                          true,
+		"star":                           true,
+		"workflow_run":                   true,
+		"check_run":                      true,
+		"check_suite":                    true,
+		"deployment":                     true,
+		"deployment_status":              true,
+		"repository":                     true,
 		"repository_vulnerability_alert": true,
 	}
 
@@ -365,20 +420,104 @@ func validateVisibility(param string, value interface{}) error {
 	return nil
 }
 
-// ValidateSafePath validates file path for security (prevents path traversal)
-func ValidateSafePath(path string) error {
-	dangerous := []string{"../", "..\\", "..%2f", "..%5c", "//", "\\\\", "%2e%2e", "%252e%252e"}
-	lowered := strings.ToLower(path)
+// validateMergeMethod validates pull request merge method
+func validateMergeMethod(param string, value interface{}) error {
+	str, ok := value.(string)
+	if !ok {
+		return &ValidationError{param, value, "must be a string"}
+	}
 
-	for _, pattern := range dangerous {
-		if strings.Contains(lowered, pattern) {
-			return fmt.Errorf("path traversal attempt detected: %s", path)
-		}
+	validMethods := map[string]bool{
+		"merge":  true,
+		"squash": true,
+		"rebase": true,
+	}
+
+	if !validMethods[str] {
+		return &ValidationError{param, value, "invalid merge method (allowed: merge, squash, rebase)"}
+	}
+
+	return nil
+}
+
+// validateReviewEvent validates pull request review event
+func validateReviewEvent(param string, value interface{}) error {
+	str, ok := value.(string)
+	if !ok {
+		return &ValidationError{param, value, "must be a string"}
+	}
+
+	validEvents := map[string]bool{
+		"APPROVE":         true,
+		"REQUEST_CHANGES": true,
+		"COMMENT":         true,
+	}
+
+	if !validEvents[str] {
+		return &ValidationError{param, value, "invalid review event (allowed: APPROVE, REQUEST_CHANGES, COMMENT)"}
+	}
+
+	return nil
+}
+
+// validateDependabotDismissReason validates Dependabot alert dismissal reason
+func validateDependabotDismissReason(param string, value interface{}) error {
+	str, ok := value.(string)
+	if !ok {
+		return &ValidationError{param, value, "must be a string"}
+	}
+
+	validReasons := map[string]bool{
+		"fix_started":    true,
+		"inaccurate":     true,
+		"no_bandwidth":   true,
+		"not_used":       true,
+		"tolerable_risk": true,
+	}
+
+	if !validReasons[str] {
+		return &ValidationError{param, value, "invalid reason (allowed: fix_started, inaccurate, no_bandwidth, not_used, tolerable_risk)"}
+	}
+
+	return nil
+}
+
+// validateCodeScanningDismissReason validates code scanning alert dismissal reason
+func validateCodeScanningDismissReason(param string, value interface{}) error {
+	str, ok := value.(string)
+	if !ok {
+		return &ValidationError{param, value, "must be a string"}
+	}
+
+	validReasons := map[string]bool{
+		"false positive": true,
+		"won't fix":      true,
+		"used in tests":  true,
+	}
+
+	if !validReasons[str] {
+		return &ValidationError{param, value, "invalid reason (allowed: false positive, won't fix, used in tests)"}
+	}
+
+	return nil
+}
+
+// validateSecretScanningResolution validates secret scanning alert resolution
+func validateSecretScanningResolution(param string, value interface{}) error {
+	str, ok := value.(string)
+	if !ok {
+		return &ValidationError{param, value, "must be a string"}
+	}
+
+	validResolutions := map[string]bool{
+		"false_positive": true,
+		"wont_fix":       true,
+		"revoked":        true,
+		"used_in_tests":  true,
 	}
 
-	// Check for absolute paths (should be relative)
-	if strings.HasPrefix(path, "/") || (len(path) > 1 && path[1] == ':') {
-		return fmt.Errorf("absolute paths not allowed: %s", path)
+	if !validResolutions[str] {
+		return &ValidationError{param, value, "invalid resolution (allowed: false_positive, wont_fix, revoked, used_in_tests)"}
 	}
 
 	return nil
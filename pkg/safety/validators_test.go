@@ -157,7 +157,61 @@ func TestValidatePermission(t *testing.T) {
 	}
 }
 
+func TestValidateMergeMethod(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		wantErr bool
+	}{
+		{"Valid merge", "merge", false},
+		{"Valid squash", "squash", false},
+		{"Valid rebase", "rebase", false},
+		{"Invalid method", "octopus", true},
+		{"Invalid type", 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMergeMethod("merge_method", tt.value)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateMergeMethod() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateAlertDismissal(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator Validator
+		value     interface{}
+		wantErr   bool
+	}{
+		{"Dependabot valid", validateDependabotDismissReason, "tolerable_risk", false},
+		{"Dependabot code-scanning reason", validateDependabotDismissReason, "false positive", true},
+		{"Code scanning valid", validateCodeScanningDismissReason, "won't fix", false},
+		{"Code scanning underscore form", validateCodeScanningDismissReason, "wont_fix", true},
+		{"Secret valid", validateSecretScanningResolution, "revoked", false},
+		{"Secret invalid", validateSecretScanningResolution, "ignored", true},
+		{"Review event valid", validateReviewEvent, "APPROVE", false},
+		{"Review event lowercase", validateReviewEvent, "approve", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validator("reason", tt.value)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validator error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestValidateURL(t *testing.T) {
+	stubLookup(t, map[string][]string{"example.com": {"93.184.216.34"}, "api.example.com": {"93.184.216.35"}})
+
 	tests := []struct {
 		name    string
 		value   interface{}
@@ -174,6 +228,9 @@ func TestValidateURL(t *testing.T) {
 		{"127.0.0.1 forbidden", "http://127.0.0.1/hook", true, "cannot use localhost"},
 		{"0.0.0.0 forbidden", "http://0.0.0.0/hook", true, "cannot use localhost"},
 		{"Localhost uppercase", "http://LOCALHOST/hook", true, "cannot use localhost"},
+		{"Private range", "http://10.0.0.5/hook", true, "cannot use localhost"},
+		{"IPv6 loopback", "http://[::1]/hook", true, "cannot use localhost"},
+		{"Metadata endpoint", "http://169.254.169.254/latest", true, "cannot use localhost"},
 
 		// Invalid cases - format
 		{"Not HTTP", "ftp://example.com", true, "must be a valid HTTP/HTTPS URL"},
@@ -370,47 +427,6 @@ func TestValidateVisibility(t *testing.T) {
 	}
 }
 
-func TestValidateSafePath(t *testing.T) {
-	tests := []struct {
-		name    string
-		path    string
-		wantErr bool
-		errMsg  string
-	}{
-		// Valid paths
-		{"Simple file", "file.txt", false, ""},
-		{"Nested file", "dir/file.txt", false, ""},
-		{"Deep nested", "a/b/c/file.txt", false, ""},
-
-		// Invalid paths - path traversal
-		{"Parent directory", "../etc/passwd", true, "path traversal"},
-		{"Windows parent", "..\\windows\\system32", true, "path traversal"},
-		{"URL encoded", "..%2fetc%2fpasswd", true, "path traversal"},
-		{"Double URL encoded", "%252e%252e/etc", true, "path traversal"},
-		{"Double slash", "//etc/passwd", true, "path traversal"},
-		{"Double backslash", "\\\\windows", true, "path traversal"},
-
-		// Invalid paths - absolute
-		{"Absolute Unix", "/etc/passwd", true, "absolute paths not allowed"},
-		{"Absolute Windows", "C:\\windows", true, "absolute paths not allowed"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			err := ValidateSafePath(tt.path)
-
-			if (err != nil) != tt.wantErr {
-				t.Errorf("ValidateSafePath() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-
-			if err != nil && tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
-				t.Errorf("ValidateSafePath() error = %v, should contain %q", err, tt.errMsg)
-			}
-		})
-	}
-}
-
 func TestValidateSafeInput(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -454,6 +470,8 @@ func TestValidateSafeInput(t *testing.T) {
 }
 
 func TestValidateParameters(t *testing.T) {
+	stubLookup(t, map[string][]string{"example.com": {"93.184.216.34"}})
+
 	tests := []struct {
 		name      string
 		operation string
@@ -519,6 +537,49 @@ func TestValidateParameters(t *testing.T) {
 			wantErr: true,
 			errMsg:  "cannot use localhost",
 		},
+		{
+			name:      "Invalid repo on merge_pr",
+			operation: "github_repair:merge_pr",
+			params: map[string]interface{}{
+				"owner":  "test-owner",
+				"repo":   "a/b",
+				"number": float64(1),
+			},
+			wantErr: true,
+			errMsg:  "path traversal",
+		},
+		{
+			name:      "Invalid run_id on rerun_workflow",
+			operation: "github_repair:rerun_workflow",
+			params: map[string]interface{}{
+				"owner":  "test-owner",
+				"repo":   "test-repo",
+				"run_id": float64(0),
+			},
+			wantErr: true,
+			errMsg:  "positive integer",
+		},
+		{
+			name:      "Valid restore backup name",
+			operation: "github_admin_repo:restore",
+			params: map[string]interface{}{
+				"owner":  "test-owner",
+				"repo":   "test-repo",
+				"backup": "github_admin_repo_update_settings-1760000000000000000.json",
+			},
+			wantErr: false,
+		},
+		{
+			name:      "Restore backup path rejected",
+			operation: "github_admin_repo:restore",
+			params: map[string]interface{}{
+				"owner":  "test-owner",
+				"repo":   "test-repo",
+				"backup": "../../etc/passwd.json",
+			},
+			wantErr: true,
+			errMsg:  "not a path",
+		},
 	}
 
 	for _, tt := range tests {
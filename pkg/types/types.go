@@ -18,6 +18,106 @@ type BranchInfo struct {
 	CommitDate string `json:"commitDate"`
 }
 
+// RepoStatus es el estado del repositorio leído de git status --porcelain=v2
+type RepoStatus struct {
+	Branch     string        `json:"branch"` // vacío con HEAD separado
+	Detached   bool          `json:"detached,omitempty"`
+	Commit     string        `json:"commit,omitempty"` // vacío antes del primer commit
+	Upstream   string        `json:"upstream,omitempty"`
+	Ahead      int           `json:"ahead"`
+	Behind     int           `json:"behind"`
+	Staged     []StatusEntry `json:"staged"`
+	Unstaged   []StatusEntry `json:"unstaged"`
+	Untracked  []string      `json:"untracked"`
+	Conflicted []StatusEntry `json:"conflicted"`
+	Clean      bool          `json:"clean"` // sin cambios, conflictos ni archivos sin seguimiento
+}
+
+// StatusEntry es un archivo con cambios. Index y WorkTree son los códigos XY
+// de git (M, T, A, D, R, C, U o "." sin cambios).
+type StatusEntry struct {
+	Path      string           `json:"path"`
+	OrigPath  string           `json:"origPath,omitempty"` // origen de un renombrado o copia
+	Index     string           `json:"index"`
+	WorkTree  string           `json:"workTree"`
+	Score     string           `json:"score,omitempty"` // similitud del renombrado, p. ej. R100
+	Submodule *SubmoduleStatus `json:"submodule,omitempty"`
+}
+
+// SubmoduleStatus es el estado de un submódulo con cambios
+type SubmoduleStatus struct {
+	CommitChanged    bool `json:"commitChanged"`
+	TrackedChanges   bool `json:"trackedChanges"`
+	UntrackedChanges bool `json:"untrackedChanges"`
+}
+
+// DiffOptions configura un diff con parches completos paginados
+type DiffOptions struct {
+	Staged   bool     // compara el índice en lugar del árbol de trabajo
+	Range    string   // "ref", "a..b" o "a...b"; vacío compara con el índice o HEAD
+	Paths    []string // limita el diff a estas rutas (pathspecs)
+	Context  int      // líneas de contexto (-U); negativo usa el valor por defecto de git
+	WordDiff bool     // diff por palabras (--word-diff=plain)
+	PageSize int      // bytes máximos por página; 0 usa el valor por defecto
+	Cursor   string   // continuación devuelta por la página anterior
+}
+
+// LogOptions filtra y pagina el historial de commits
+type LogOptions struct {
+	Range         string   // "ref", "a..b" o "a...b"; vacío usa HEAD
+	Paths         []string // solo commits que tocan estas rutas
+	Author        string   // patrón de autor (--author)
+	Since         string   // fecha inicial (--since), p. ej. "2026-01-01" o "2 weeks ago"
+	Until         string   // fecha final (--until)
+	Grep          string   // patrón en el mensaje (--grep)
+	PickaxeString string   // commits que cambian el número de apariciones del texto (-S)
+	PickaxeRegex  string   // commits cuyo diff contiene la expresión (-G)
+	FirstParent   bool     // sigue solo el primer padre de los merges
+	Limit         int      // commits por página; 0 usa el valor por defecto
+	Cursor        string   // continuación devuelta por la página anterior
+}
+
+// BlameOptions configura un blame por líneas
+type BlameOptions struct {
+	Path             string // archivo a analizar
+	Ref              string // revisión; vacío usa el árbol de trabajo
+	StartLine        int    // primera línea (1-based); 0 empieza en la primera
+	EndLine          int    // última línea; 0 llega al final del archivo
+	IgnoreWhitespace bool   // ignora cambios de espacios (-w)
+	DetectMoves      bool   // detecta líneas movidas dentro del archivo (-M)
+	DetectCopies     bool   // detecta líneas movidas o copiadas de otros archivos (-C)
+}
+
+// ShowOptions configura la vista de un commit o de un archivo en una revisión
+type ShowOptions struct {
+	Ref      string   // commit, o "ref:ruta" para leer un archivo en esa revisión
+	Paths    []string // limita el parche a estas rutas
+	Context  int      // líneas de contexto (-U); negativo usa el valor por defecto de git
+	WordDiff bool     // diff por palabras (--word-diff=plain)
+	PageSize int      // bytes máximos de parche por página; 0 usa el valor por defecto
+	Cursor   string   // continuación devuelta por la página anterior
+}
+
+// RebaseStep es una línea de la lista de tareas de un rebase interactivo
+type RebaseStep struct {
+	Action  string `json:"action"`            // pick, reword, edit, squash, fixup, drop
+	Commit  string `json:"commit"`            // SHA o ref del commit
+	Message string `json:"message,omitempty"` // nuevo mensaje (reword, squash)
+}
+
+// CherryPickOptions configura un cherry-pick
+type CherryPickOptions struct {
+	Commits      string // SHA o rango "a..b"
+	RecordOrigin bool   // añade "(cherry picked from commit ...)" al mensaje (-x)
+	Mainline     int    // padre de referencia (1, 2...) si el commit es un merge (-m)
+}
+
+// RevertOptions configura un revert
+type RevertOptions struct {
+	Commits  string // SHA o rango "a..b"
+	Mainline int    // padre de referencia (1, 2...) si el commit es un merge (-m)
+}
+
 // Estructuras del protocolo JSON-RPC 2.0
 type JSONRPCRequest struct {
 	JSONRPC string                 `json:"jsonrpc"`